@@ -2,31 +2,68 @@ package main
 
 import (
 	"context"
+	"flag"
+	"fmt"
+	"net/http"
 	"os"
 	"os/signal"
 	"strings"
 	"syscall"
 	"time"
 
+	"github.com/Azure/open-service-broker-azure/pkg/admin"
 	"github.com/Azure/open-service-broker-azure/pkg/api"
 	apiFilters "github.com/Azure/open-service-broker-azure/pkg/api/filters"
+	async "github.com/Azure/open-service-broker-azure/pkg/async/redis"
+	"github.com/Azure/open-service-broker-azure/pkg/audit"
 	"github.com/Azure/open-service-broker-azure/pkg/azure"
 	"github.com/Azure/open-service-broker-azure/pkg/boot"
 	"github.com/Azure/open-service-broker-azure/pkg/broker"
 	"github.com/Azure/open-service-broker-azure/pkg/crypto"
 	"github.com/Azure/open-service-broker-azure/pkg/crypto/aes256"
+	"github.com/Azure/open-service-broker-azure/pkg/crypto/keyvault"
 	"github.com/Azure/open-service-broker-azure/pkg/crypto/noop"
+	"github.com/Azure/open-service-broker-azure/pkg/file"
+	"github.com/Azure/open-service-broker-azure/pkg/health"
 	"github.com/Azure/open-service-broker-azure/pkg/http/filter"
 	"github.com/Azure/open-service-broker-azure/pkg/http/filters"
+	"github.com/Azure/open-service-broker-azure/pkg/jwks"
 	brokerLog "github.com/Azure/open-service-broker-azure/pkg/log"
+	"github.com/Azure/open-service-broker-azure/pkg/metrics"
+	"github.com/Azure/open-service-broker-azure/pkg/policy"
+	"github.com/Azure/open-service-broker-azure/pkg/quota"
 	"github.com/Azure/open-service-broker-azure/pkg/service"
-	storage "github.com/Azure/open-service-broker-azure/pkg/storage/redis"
+	"github.com/Azure/open-service-broker-azure/pkg/storage"
+	boltStorage "github.com/Azure/open-service-broker-azure/pkg/storage/bolt"
+	redisStorage "github.com/Azure/open-service-broker-azure/pkg/storage/redis"
+	"github.com/Azure/open-service-broker-azure/pkg/tracing"
 	"github.com/Azure/open-service-broker-azure/pkg/version"
 	log "github.com/Sirupsen/logrus"
-	async "github.com/deis/async/redis"
 )
 
+const reencryptCommand = "reencrypt"
+
 func main() {
+	flag.Usage = func() {
+		fmt.Fprintf(
+			flag.CommandLine.Output(),
+			"Usage: %s [%s]\n\n"+
+				"With no command, the broker is started.\n\n"+
+				"The %s command re-encrypts all instances and bindings using the\n"+
+				"active encryption key, then exits. It should only be run while no\n"+
+				"broker is running against the same storage.\n",
+			os.Args[0],
+			reencryptCommand,
+			reencryptCommand,
+		)
+	}
+	flag.Parse()
+	if flag.NArg() > 1 ||
+		(flag.NArg() == 1 && flag.Arg(0) != reencryptCommand) {
+		flag.Usage()
+		os.Exit(2)
+	}
+
 	// Initialize logging
 	// Split log output across stdout and stderr, depending on severity
 	// krancour: This functionality is currently dependent on a fork of
@@ -59,6 +96,59 @@ func main() {
 		strings.ToUpper(logLevel.String()),
 	).Info("Setting log level")
 	log.SetLevel(logLevel)
+	log.SetFormatter(logConfig.GetFormatter())
+
+	// Initialize auditing
+	auditConfig, err := audit.GetConfigFromEnvironment()
+	if err != nil {
+		log.Fatal(err)
+	}
+	auditSink, err := audit.NewSink(auditConfig)
+	if err != nil {
+		log.Fatal(err)
+	}
+	auditLogger, err := audit.NewLogger(
+		auditSink,
+		[]byte(auditConfig.HMACKey),
+		auditConfig.QueueSize,
+		auditConfig.ChainStatePath,
+	)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer auditLogger.Close()
+	audit.InitializeGlobalLogger(auditLogger)
+	if auditConfig.SinkType == audit.SinkNone {
+		log.Warn("Auditing is disabled")
+	} else {
+		log.WithField(
+			"sink",
+			auditConfig.SinkType,
+		).Info("Mutating operations will be audited")
+		if auditConfig.ChainStatePath == "" {
+			log.Warn(
+				"AUDIT_CHAIN_STATE_PATH is not set; a new chain of audit events " +
+					"will begin each time the broker starts",
+			)
+		}
+	}
+
+	// Initialize tracing
+	tracingConfig, err := tracing.GetConfigFromEnvironment()
+	if err != nil {
+		log.Fatal(err)
+	}
+	traceExporter, err := tracing.Initialize(tracingConfig)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if traceExporter != nil {
+		defer traceExporter.Stop()
+		log.WithFields(log.Fields{
+			"exporter":   tracingConfig.Exporter,
+			"sampleRate": tracingConfig.SampleRate,
+		}).Info("Traces will be exported")
+	}
 
 	// Initialize catalog
 	catalogConfig, err := service.GetCatalogConfigFromEnvironment()
@@ -69,10 +159,18 @@ func main() {
 	if err != nil {
 		log.Fatal(err)
 	}
-	catalog, err := boot.GetCatalog(catalogConfig, azureConfig)
+	catalogConfig.Environment = azureConfig.Environment.Name
+	modules, err := boot.GetModules(azureConfig)
+	if err != nil {
+		log.Fatal(err)
+	}
+	initialCatalog, err := boot.GetCatalog(catalogConfig, modules)
 	if err != nil {
 		log.Fatal(err)
 	}
+	// Everything that resolves services and plans does so through a catalog
+	// that can be reloaded without restarting the broker
+	catalog := service.NewReloadableCatalog(initialCatalog)
 
 	// Initialize encryption
 	cryptoConfig, err := crypto.GetConfigFromEnvironment()
@@ -95,6 +193,40 @@ func main() {
 			"encryptionScheme",
 			cryptoConfig.EncryptionScheme,
 		).Info("Sensitive instance and binding details will be encrypted")
+	case crypto.KEYVAULT:
+		var keyvaultConfig keyvault.Config
+		keyvaultConfig, err = keyvault.GetConfigFromEnvironment()
+		if err != nil {
+			log.Fatal(err)
+		}
+		// If an AES256 key is also configured, values encrypted before the
+		// switch to Key Vault remain readable and can be re-encrypted
+		var fallbackCodec crypto.Codec
+		var aes256Config aes256.Config
+		aes256Config, err = aes256.GetConfigFromEnvironment()
+		if err != nil {
+			log.Fatal(err)
+		}
+		if aes256Config.Key != "" || len(aes256Config.Keys) > 0 {
+			fallbackCodec, err = aes256.NewCodec(aes256Config)
+			if err != nil {
+				log.Fatal(err)
+			}
+		}
+		codec, err = keyvault.NewCodec(
+			keyvaultConfig,
+			azureConfig,
+			fallbackCodec,
+		)
+		if err != nil {
+			log.Fatal(err)
+		}
+		log.WithFields(log.Fields{
+			"encryptionScheme": cryptoConfig.EncryptionScheme,
+			"vaultURL":         keyvaultConfig.VaultURL,
+			"keyName":          keyvaultConfig.KeyName,
+			"aes256Fallback":   fallbackCodec != nil,
+		}).Info("Sensitive instance and binding details will be encrypted")
 	case crypto.NOOP:
 		codec = noop.NewCodec()
 		log.Warn(
@@ -115,9 +247,47 @@ func main() {
 	if err != nil {
 		log.Fatal(err)
 	}
-	store, err := storage.NewStore(catalog, storageConfig)
-	if err != nil {
-		log.Fatal(err)
+	var store storage.Store
+	switch storageConfig.StorageType {
+	case storage.Redis:
+		var redisConfig redisStorage.Config
+		redisConfig, err = redisStorage.GetConfigFromEnvironment()
+		if err != nil {
+			log.Fatal(err)
+		}
+		store, err = redisStorage.NewStore(catalog, redisConfig)
+		if err != nil {
+			log.Fatal(err)
+		}
+	case storage.Bolt:
+		var boltConfig boltStorage.Config
+		boltConfig, err = boltStorage.GetConfigFromEnvironment()
+		if err != nil {
+			log.Fatal(err)
+		}
+		store, err = boltStorage.NewStore(catalog, boltConfig)
+		if err != nil {
+			log.Fatal(err)
+		}
+		log.WithField(
+			"path",
+			boltConfig.BoltPath,
+		).Warn(
+			"Using embedded storage -- THIS IS ONLY SUITABLE FOR SINGLE-NODE " +
+				"DEPLOYMENTS",
+		)
+	default:
+		log.Fatalf(
+			`unrecognized storage type "%s"`,
+			storageConfig.StorageType,
+		)
+	}
+
+	if flag.Arg(0) == reencryptCommand {
+		if err = storage.Reencrypt(store); err != nil {
+			log.Fatal(err)
+		}
+		return
 	}
 
 	// Async
@@ -125,25 +295,180 @@ func main() {
 	if err != nil {
 		log.Fatal(err)
 	}
-	asyncEngine := async.NewEngine(asyncConfig)
+	asyncEngine, err := async.NewEngine(asyncConfig)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	// Health checks determine the broker's readiness
+	healthChecks, err := boot.GetHealthChecks(azureConfig, modules)
+	if err != nil {
+		log.Fatal(err)
+	}
+	healthChecks = append(
+		healthChecks,
+		health.NewCheck("async", asyncEngine.TestConnection),
+	)
 
 	// Assemble the filter chain
-	basicAuthConfig, err := api.GetBasicAuthConfig()
+	authConfig, err := api.GetAuthConfigFromEnvironment()
+	if err != nil {
+		log.Fatal(err)
+	}
+	authFilters := map[string]filter.Filter{}
+	for _, scheme := range authConfig.Schemes {
+		switch scheme {
+		case api.AuthSchemeBasic:
+			var credentials []api.BasicAuthCredential
+			credentials, err = api.GetBasicAuthCredentials()
+			if err != nil {
+				log.Fatal(err)
+			}
+			users := map[string]filters.BasicAuthUser{}
+			for _, credential := range credentials {
+				users[credential.Username] = filters.BasicAuthUser{
+					Password:     credential.Password,
+					Entitlements: credential.GetEntitlements(),
+				}
+			}
+			authFilters["Basic"] = filters.NewMultiUserBasicAuthFilter(users)
+		case api.AuthSchemeJWT:
+			var jwtAuthConfig api.JWTAuthConfig
+			jwtAuthConfig, err = api.GetJWTAuthConfigFromEnvironment()
+			if err != nil {
+				log.Fatal(err)
+			}
+			var keySet jwks.KeySet
+			if jwtAuthConfig.JWKSPath != "" {
+				keySet, err = jwks.NewKeySetFromFile(jwtAuthConfig.JWKSPath)
+				if err != nil {
+					log.Fatal(err)
+				}
+			} else {
+				jwksURL := jwtAuthConfig.JWKSURL
+				if jwksURL == "" {
+					jwksURL, err = jwks.DiscoverURL(jwtAuthConfig.Issuer)
+					if err != nil {
+						log.Fatal(err)
+					}
+				}
+				keySet = jwks.NewRemoteKeySet(
+					jwksURL,
+					jwtAuthConfig.JWKSRefreshInterval,
+				)
+			}
+			authFilters["Bearer"] = filters.NewJWTFilter(
+				keySet,
+				jwtAuthConfig.Issuer,
+				jwtAuthConfig.Audience,
+				jwtAuthConfig.ClockSkew,
+			)
+		}
+	}
+	log.WithField(
+		"schemes",
+		authConfig.Schemes,
+	).Info("Requests will be authenticated")
+	apiServerConfig, err := api.GetConfigFromEnvironment()
 	if err != nil {
 		log.Fatal(err)
 	}
+	chainFilters := []filter.Filter{
+		filters.NewTracingFilter(),
+		apiFilters.NewRequestIdentityFilter(),
+	}
+	// The API server only verifies client certificates that are presented, so
+	// it is this filter that requires one. Since the filter chain is applied to
+	// OSB routes only, health and metrics endpoints remain reachable by probes
+	// and scrapers that have no certificate.
+	if apiServerConfig.TLSClientCAPath != "" {
+		chainFilters = append(
+			chainFilters,
+			filters.NewClientCertFilter(
+				apiServerConfig.TLSClientAllowedSubjects,
+			),
+		)
+	}
+	chainFilters = append(
+		chainFilters,
+		filters.NewAuthSchemeFilter(authFilters),
+	)
+	if apiServerConfig.RateLimit > 0 {
+		chainFilters = append(
+			chainFilters,
+			filters.NewRateLimitFilter(
+				apiServerConfig.RateLimit,
+				apiServerConfig.RateLimitBurst,
+			),
+		)
+	}
 	filterChain := filter.NewChain(
-		filters.NewBasicAuthFilter(
-			basicAuthConfig.GetUsername(),
-			basicAuthConfig.GetPassword(),
-		),
-		apiFilters.NewAPIVersionFilter(),
+		append(chainFilters, apiFilters.NewAPIVersionFilter())...,
 	)
 
-	apiServerConfig, err := api.GetConfigFromEnvironment()
+	// Metrics are served by the API server unless a separate port has been
+	// configured for them
+	metricsConfig, err := metrics.GetConfigFromEnvironment()
 	if err != nil {
 		log.Fatal(err)
 	}
+	metricsHandler, err := metrics.NewHandler()
+	if err != nil {
+		log.Fatal(err)
+	}
+	var apiMetricsHandler http.Handler
+	if metricsConfig.Port == 0 {
+		apiMetricsHandler = metricsHandler
+	}
+
+	// Load the policy that requests are evaluated against, if any
+	policyConfig, err := policy.GetConfigFromEnvironment()
+	if err != nil {
+		log.Fatal(err)
+	}
+	var brokerPolicy *policy.Policy
+	if len(policyConfig.Files) > 0 {
+		brokerPolicy, err = policy.Load(policyConfig.Files...)
+		if err != nil {
+			log.Fatal(err)
+		}
+		log.WithField(
+			"rules",
+			len(brokerPolicy.Rules),
+		).Info("Requests will be evaluated against policy")
+	}
+
+	// Load the quotas that provisioning requests are subject to, if any
+	quotaConfig, err := quota.GetConfigFromEnvironment()
+	if err != nil {
+		log.Fatal(err)
+	}
+	var quotas *quota.Quotas
+	if quotaConfig.File != "" {
+		quotas, err = quota.Load(quotaConfig.File)
+		if err != nil {
+			log.Fatal(err)
+		}
+		if err = quotas.Validate(catalog); err != nil {
+			log.Fatal(err)
+		}
+		log.WithField(
+			"limits",
+			len(quotas.Limits),
+		).Info("Provisioning requests will be subject to quotas")
+	}
+
+	catalogReloader, err := boot.NewCatalogReloader(
+		catalogConfig,
+		catalog,
+		modules,
+		store,
+		quotas,
+	)
+	if err != nil {
+		log.Fatal(err)
+	}
+
 	// Create API server
 	apiServer, err := api.NewServer(
 		apiServerConfig,
@@ -151,11 +476,29 @@ func main() {
 		asyncEngine,
 		filterChain,
 		catalog,
+		apiMetricsHandler,
+		healthChecks,
+		brokerPolicy,
+		quotas,
 	)
 	if err != nil {
 		log.Fatal(err)
 	}
 
+	// The admin API is only served if a port has been configured for it
+	adminConfig, err := admin.GetConfigFromEnvironment()
+	if err != nil {
+		log.Fatal(err)
+	}
+	if apiServerConfig.TLSCertPath != "" &&
+		apiServerConfig.TLSKeyPath != "" &&
+		file.Exists(apiServerConfig.TLSCertPath) &&
+		file.Exists(apiServerConfig.TLSKeyPath) {
+		adminConfig.TLSCertPath = apiServerConfig.TLSCertPath
+		adminConfig.TLSKeyPath = apiServerConfig.TLSKeyPath
+		adminConfig.TLSReloadInterval = apiServerConfig.TLSReloadInterval
+	}
+
 	// Create broker
 	broker, err := broker.NewBroker(
 		apiServer,
@@ -181,6 +524,77 @@ func main() {
 		cancel()
 	}()
 
+	// Reload the catalog on SIGHUP and, if the catalog is configured using
+	// files, whenever those files change
+	hupChan := make(chan os.Signal, 1)
+	signal.Notify(hupChan, syscall.SIGHUP)
+	go func() {
+		for range hupChan {
+			if err := catalogReloader.Reload(); err != nil {
+				log.WithField("error", err).Error(
+					"error reloading catalog; the catalog already in effect remains so",
+				)
+			} else {
+				log.Info("reloaded catalog")
+			}
+		}
+	}()
+	if catalogConfig.ReloadInterval > 0 &&
+		(catalogConfig.ConfigFile != "" || catalogConfig.OverridesFile != "") {
+		log.WithField(
+			"interval",
+			catalogConfig.ReloadInterval,
+		).Info("Catalog will be reloaded when its configuration files change")
+		go func() {
+			err := catalogReloader.Run(ctx, catalogConfig.ReloadInterval)
+			if err != ctx.Err() {
+				log.Fatal(err)
+			}
+		}()
+	}
+
+	// Purge deleted instances and bindings once they have exceeded their
+	// retention period
+	log.WithField(
+		"retention",
+		storageConfig.DeletedRetention,
+	).Info("Deleted instances and bindings will be retained")
+	purger := storage.NewPurger(store, storageConfig)
+	go func() {
+		if err := purger.Run(ctx); err != ctx.Err() {
+			log.Fatal(err)
+		}
+	}()
+
+	instanceRecorder := metrics.NewInstanceRecorder(store, metricsConfig)
+	go func() {
+		if err := instanceRecorder.Run(ctx); err != ctx.Err() {
+			log.Fatal(err)
+		}
+	}()
+	if metricsConfig.Port != 0 {
+		metricsServer := metrics.NewServer(metricsConfig, metricsHandler)
+		go func() {
+			if err := metricsServer.Run(ctx); err != ctx.Err() {
+				log.Fatal(err)
+			}
+		}()
+	}
+
+	if adminConfig.Port != 0 {
+		adminServer := admin.NewServer(
+			adminConfig,
+			store,
+			asyncEngine,
+			quotas,
+		)
+		go func() {
+			if err := adminServer.Run(ctx); err != ctx.Err() {
+				log.Fatal(err)
+			}
+		}()
+	}
+
 	// Run broker
 	if err := broker.Run(ctx); err != nil {
 		if err == ctx.Err() {
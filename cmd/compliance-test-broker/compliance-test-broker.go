@@ -43,6 +43,10 @@ func main() {
 		fakeAsync.NewEngine(),
 		filterChain,
 		fakeCatalog,
+		nil,
+		nil,
+		nil,
+		nil,
 	)
 
 	if err != nil {
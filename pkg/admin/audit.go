@@ -0,0 +1,74 @@
+package admin
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/Azure/open-service-broker-azure/pkg/audit"
+	brokerLog "github.com/Azure/open-service-broker-azure/pkg/log"
+	"github.com/Azure/open-service-broker-azure/pkg/service"
+	"github.com/gorilla/mux"
+)
+
+type auditEventContextKey struct{}
+
+// statusRecorder is an http.ResponseWriter that remembers the status code
+// written to it
+type statusRecorder struct {
+	http.ResponseWriter
+	statusCode int
+}
+
+func (s *statusRecorder) WriteHeader(statusCode int) {
+	s.statusCode = statusCode
+	s.ResponseWriter.WriteHeader(statusCode)
+}
+
+// audited wraps the given handler so that an audit event is recorded for every
+// request it handles, once a response has been written. The operator is
+// recorded as the event's actor. The handler may add details to the event
+// using annotateAuditEvent(). Since identity is established by the filter
+// chain, the filter chain must be applied to the handler this function
+// returns.
+func audited(operation string, handle http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		vars := mux.Vars(r)
+		event := &audit.Event{
+			Operation:  operation,
+			Phase:      audit.PhaseRequest,
+			InstanceID: vars["instance_id"],
+			BindingID:  vars["binding_id"],
+		}
+		ctx := audit.NewContext(r.Context(), "")
+		ctx = context.WithValue(ctx, auditEventContextKey{}, event)
+		rec := &statusRecorder{
+			ResponseWriter: w,
+			statusCode:     http.StatusOK,
+		}
+		handle(rec, r.WithContext(ctx))
+		event.Actor = audit.ActorFromContext(ctx)
+		event.RequestID, _ = brokerLog.RequestIDFromContext(ctx)
+		event.StatusCode = rec.statusCode
+		event.Outcome = audit.GetOutcome(rec.statusCode)
+		audit.Log(*event)
+	}
+}
+
+// annotateAuditEvent records the service and plan of the given instance and
+// the instance's status before and after the request in the request's audit
+// event. It is a no-op if the request isn't being audited.
+func annotateAuditEvent(
+	r *http.Request,
+	instance service.Instance,
+	previousStatus string,
+	status string,
+) {
+	event, ok := r.Context().Value(auditEventContextKey{}).(*audit.Event)
+	if !ok {
+		return
+	}
+	event.ServiceID = instance.ServiceID
+	event.PlanID = instance.PlanID
+	event.PreviousStatus = previousStatus
+	event.Status = status
+}
@@ -0,0 +1,123 @@
+package admin
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	brokerLog "github.com/Azure/open-service-broker-azure/pkg/log"
+	"github.com/Azure/open-service-broker-azure/pkg/service"
+	log "github.com/Sirupsen/logrus"
+	"github.com/gorilla/mux"
+)
+
+// bindingSummary is an abridged representation of a binding, suitable for
+// listing
+type bindingSummary struct {
+	BindingID    string     `json:"bindingId"`
+	InstanceID   string     `json:"instanceId"`
+	ServiceID    string     `json:"serviceId"`
+	Status       string     `json:"status"`
+	StatusReason string     `json:"statusReason,omitempty"`
+	Created      time.Time  `json:"created"`
+	Deleted      *time.Time `json:"deleted,omitempty"`
+}
+
+func newBindingSummary(binding service.Binding) bindingSummary {
+	return bindingSummary{
+		BindingID:    binding.BindingID,
+		InstanceID:   binding.InstanceID,
+		ServiceID:    binding.ServiceID,
+		Status:       binding.Status,
+		StatusReason: binding.StatusReason,
+		Created:      binding.Created,
+		Deleted:      binding.Deleted,
+	}
+}
+
+// getBindings lists bindings, optionally filtered by the instanceID,
+// serviceID and status query parameters
+func (s *server) getBindings(w http.ResponseWriter, r *http.Request) {
+	logFields := brokerLog.FieldsFromContext(r.Context())
+	bindings, err := s.store.GetBindings()
+	if err != nil {
+		logFields["error"] = err
+		log.WithFields(logFields).Error("admin error: error listing bindings")
+		s.writeResponse(w, http.StatusInternalServerError, struct{}{})
+		return
+	}
+	query := r.URL.Query()
+	filters := map[string]func(service.Binding) string{
+		"instanceID": func(b service.Binding) string { return b.InstanceID },
+		"serviceID":  func(b service.Binding) string { return b.ServiceID },
+		"status":     func(b service.Binding) string { return b.Status },
+	}
+	summaries := []bindingSummary{}
+nextBinding:
+	for _, binding := range bindings {
+		for param, getField := range filters {
+			if value := query.Get(param); value != "" && getField(binding) != value {
+				continue nextBinding
+			}
+		}
+		summaries = append(summaries, newBindingSummary(binding))
+	}
+	s.writeResponse(
+		w,
+		http.StatusOK,
+		map[string]interface{}{"bindings": summaries},
+	)
+}
+
+// getBinding shows a binding's full record, with all secrets redacted
+func (s *server) getBinding(w http.ResponseWriter, r *http.Request) {
+	bindingID := mux.Vars(r)["binding_id"]
+	logFields := brokerLog.FieldsFromContext(r.Context())
+	logFields["bindingID"] = bindingID
+	binding, ok, err := s.store.GetBinding(bindingID)
+	if err != nil {
+		logFields["error"] = err
+		log.WithFields(logFields).Error("admin error: error retrieving binding")
+		s.writeResponse(w, http.StatusInternalServerError, struct{}{})
+		return
+	}
+	if !ok {
+		s.writeBindingNotFound(w, bindingID)
+		return
+	}
+	s.writeResponse(
+		w,
+		http.StatusOK,
+		map[string]interface{}{"binding": service.Redact(binding)},
+	)
+}
+
+// deleteBinding deletes a binding's record without unbinding it from any of
+// the underlying Azure resources
+func (s *server) deleteBinding(w http.ResponseWriter, r *http.Request) {
+	bindingID := mux.Vars(r)["binding_id"]
+	logFields := brokerLog.FieldsFromContext(r.Context())
+	logFields["bindingID"] = bindingID
+	ok, err := s.store.DeleteBinding(bindingID)
+	if err != nil {
+		logFields["error"] = err
+		log.WithFields(logFields).Error("admin error: error deleting binding")
+		s.writeResponse(w, http.StatusInternalServerError, struct{}{})
+		return
+	}
+	if !ok {
+		s.writeBindingNotFound(w, bindingID)
+		return
+	}
+	log.WithFields(logFields).Warn("admin force-deleted binding record")
+	s.writeResponse(w, http.StatusOK, struct{}{})
+}
+
+func (s *server) writeBindingNotFound(w http.ResponseWriter, bindingID string) {
+	s.writeError(
+		w,
+		http.StatusNotFound,
+		"BindingNotFound",
+		fmt.Sprintf(`binding "%s" does not exist`, bindingID),
+	)
+}
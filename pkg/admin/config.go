@@ -0,0 +1,55 @@
+package admin
+
+import (
+	"errors"
+	"time"
+
+	"github.com/kelseyhightower/envconfig"
+)
+
+const envconfigPrefix = "ADMIN_API"
+
+// Config represents configuration options for the admin API server
+type Config struct {
+	// Port is the port on which the admin API is served. If it is 0, the admin
+	// API is disabled.
+	Port int `envconfig:"PORT"`
+	// Username and Password are the basic auth credentials that operators must
+	// present to access the admin API. They are deliberately distinct from the
+	// credentials used by platforms to access the broker.
+	Username string `envconfig:"USERNAME"`
+	Password string `envconfig:"PASSWORD"`
+	// TLSCertPath and TLSKeyPath are the paths to the TLS certificate and key
+	// with which the admin API is served. They are not read from the
+	// environment; the broker's own API server certificate and key are used.
+	// If they are not set, the admin API is served without TLS and listens
+	// only on the loopback interface.
+	TLSCertPath string `ignored:"true"`
+	TLSKeyPath  string `ignored:"true"`
+	// TLSReloadInterval is how often the TLS certificate and key are checked
+	// for changes. If it is 0, they are never reloaded.
+	TLSReloadInterval time.Duration `ignored:"true"`
+}
+
+// NewConfigWithDefaults returns a Config object with default values already
+// applied. Callers are then free to set custom values for the remaining fields
+// and/or override default values.
+func NewConfigWithDefaults() Config {
+	return Config{}
+}
+
+// GetConfigFromEnvironment returns configuration derived from environment
+// variables
+func GetConfigFromEnvironment() (Config, error) {
+	c := NewConfigWithDefaults()
+	if err := envconfig.Process(envconfigPrefix, &c); err != nil {
+		return c, err
+	}
+	if c.Port != 0 && (c.Username == "" || c.Password == "") {
+		return c, errors.New(
+			"ADMIN_API_USERNAME and ADMIN_API_PASSWORD must be specified when " +
+				"the admin API is enabled",
+		)
+	}
+	return c, nil
+}
@@ -0,0 +1,426 @@
+package admin
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"time"
+
+	"github.com/Azure/open-service-broker-azure/pkg/audit"
+	brokerLog "github.com/Azure/open-service-broker-azure/pkg/log"
+	"github.com/Azure/open-service-broker-azure/pkg/service"
+	"github.com/Azure/open-service-broker-azure/pkg/tracing"
+	log "github.com/Sirupsen/logrus"
+	"github.com/deis/async"
+	"github.com/gorilla/mux"
+)
+
+// instanceStates are the states an operator may force an instance into
+var instanceStates = map[string]bool{
+	service.InstanceStateProvisioningDeferred:   true,
+	service.InstanceStateProvisioning:           true,
+	service.InstanceStateProvisioned:            true,
+	service.InstanceStateProvisioningFailed:     true,
+	service.InstanceStateUpdating:               true,
+	service.InstanceStateUpdatingFailed:         true,
+	service.InstanceStateDeprovisioningDeferred: true,
+	service.InstanceStateDeprovisioning:         true,
+	service.InstanceStateDeprovisioningFailed:   true,
+}
+
+// retryableInstanceStates maps states in which an instance's current step may
+// be re-enqueued to the job that executes that step and the state the
+// instance is returned to
+var retryableInstanceStates = map[string]struct {
+	jobName string
+	status  string
+}{
+	service.InstanceStateProvisioning: {
+		"executeProvisioningStep",
+		service.InstanceStateProvisioning,
+	},
+	service.InstanceStateProvisioningFailed: {
+		"executeProvisioningStep",
+		service.InstanceStateProvisioning,
+	},
+	service.InstanceStateUpdating: {
+		"executeUpdatingStep",
+		service.InstanceStateUpdating,
+	},
+	service.InstanceStateUpdatingFailed: {
+		"executeUpdatingStep",
+		service.InstanceStateUpdating,
+	},
+	service.InstanceStateDeprovisioning: {
+		"executeDeprovisioningStep",
+		service.InstanceStateDeprovisioning,
+	},
+	service.InstanceStateDeprovisioningFailed: {
+		"executeDeprovisioningStep",
+		service.InstanceStateDeprovisioning,
+	},
+}
+
+// instanceSummary is an abridged representation of an instance, suitable for
+// listing
+type instanceSummary struct {
+	InstanceID   string     `json:"instanceId"`
+	Alias        string     `json:"alias,omitempty"`
+	ServiceID    string     `json:"serviceId"`
+	PlanID       string     `json:"planId"`
+	Status       string     `json:"status"`
+	StatusReason string     `json:"statusReason,omitempty"`
+	Step         string     `json:"step,omitempty"`
+	ParentAlias  string     `json:"parentAlias,omitempty"`
+	Created      time.Time  `json:"created"`
+	Deleted      *time.Time `json:"deleted,omitempty"`
+}
+
+func newInstanceSummary(instance service.Instance) instanceSummary {
+	return instanceSummary{
+		InstanceID:   instance.InstanceID,
+		Alias:        instance.Alias,
+		ServiceID:    instance.ServiceID,
+		PlanID:       instance.PlanID,
+		Status:       instance.Status,
+		StatusReason: instance.StatusReason,
+		Step:         instance.Step,
+		ParentAlias:  instance.ParentAlias,
+		Created:      instance.Created,
+		Deleted:      instance.Deleted,
+	}
+}
+
+// instanceStatusRequest represents a request to force an instance into a
+// given state
+type instanceStatusRequest struct {
+	Status       string `json:"status"`
+	StatusReason string `json:"statusReason"`
+}
+
+// getInstances lists instances, optionally filtered by the serviceID, planID,
+// status and parentAlias query parameters. If the deleted query parameter is
+// "true", deleted instances that have not yet been purged are listed instead.
+func (s *server) getInstances(w http.ResponseWriter, r *http.Request) {
+	logFields := brokerLog.FieldsFromContext(r.Context())
+	query := r.URL.Query()
+	var instances []service.Instance
+	var err error
+	if query.Get("deleted") == "true" {
+		instances, err = s.store.GetDeletedInstances()
+	} else {
+		instances, err = s.store.GetInstances()
+	}
+	if err != nil {
+		logFields["error"] = err
+		log.WithFields(logFields).Error("admin error: error listing instances")
+		s.writeResponse(w, http.StatusInternalServerError, struct{}{})
+		return
+	}
+	filters := map[string]func(service.Instance) string{
+		"serviceID":   func(i service.Instance) string { return i.ServiceID },
+		"planID":      func(i service.Instance) string { return i.PlanID },
+		"status":      func(i service.Instance) string { return i.Status },
+		"parentAlias": func(i service.Instance) string { return i.ParentAlias },
+	}
+	summaries := []instanceSummary{}
+nextInstance:
+	for _, instance := range instances {
+		for param, getField := range filters {
+			if value := query.Get(param); value != "" && getField(instance) != value {
+				continue nextInstance
+			}
+		}
+		summaries = append(summaries, newInstanceSummary(instance))
+	}
+	s.writeResponse(
+		w,
+		http.StatusOK,
+		map[string]interface{}{"instances": summaries},
+	)
+}
+
+// getInstance shows an instance's full record, with all secrets redacted,
+// along with summaries of its parent, children and bindings
+func (s *server) getInstance(w http.ResponseWriter, r *http.Request) {
+	instanceID := mux.Vars(r)["instance_id"]
+	logFields := brokerLog.FieldsFromContext(r.Context())
+	logFields["instanceID"] = instanceID
+	instance, ok := s.loadInstance(w, r, instanceID)
+	if !ok {
+		return
+	}
+	response := map[string]interface{}{
+		"instance": service.Redact(instance),
+		"parent":   nil,
+	}
+	if instance.ParentAlias != "" {
+		parent, ok, err := s.store.GetInstanceByAlias(instance.ParentAlias)
+		if err != nil {
+			logFields["error"] = err
+			log.WithFields(logFields).Error(
+				"admin error: error retrieving parent instance",
+			)
+			s.writeResponse(w, http.StatusInternalServerError, struct{}{})
+			return
+		}
+		if ok {
+			response["parent"] = newInstanceSummary(parent)
+		}
+	}
+	children, bindings, ok := s.loadDependents(w, r, instance)
+	if !ok {
+		return
+	}
+	response["children"] = children
+	response["bindings"] = bindings
+	s.writeResponse(w, http.StatusOK, response)
+}
+
+// setInstanceStatus forces an instance into the requested state-- for
+// instance, to mark an instance that is stuck provisioning as failed
+func (s *server) setInstanceStatus(w http.ResponseWriter, r *http.Request) {
+	instanceID := mux.Vars(r)["instance_id"]
+	logFields := brokerLog.FieldsFromContext(r.Context())
+	logFields["instanceID"] = instanceID
+	bodyBytes, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		logFields["error"] = err
+		log.WithFields(logFields).Error(
+			"admin error: error reading request body",
+		)
+		s.writeResponse(w, http.StatusInternalServerError, struct{}{})
+		return
+	}
+	defer r.Body.Close() // nolint: errcheck
+	statusRequest := instanceStatusRequest{}
+	if err = json.Unmarshal(bodyBytes, &statusRequest); err != nil {
+		s.writeError(
+			w,
+			http.StatusBadRequest,
+			"MalformedRequestBody",
+			"The request body did not contain valid, well-formed JSON",
+		)
+		return
+	}
+	if !instanceStates[statusRequest.Status] {
+		s.writeError(
+			w,
+			http.StatusBadRequest,
+			"InvalidStatus",
+			fmt.Sprintf(`"%s" is not a valid instance status`, statusRequest.Status),
+		)
+		return
+	}
+	instance, ok := s.loadInstance(w, r, instanceID)
+	if !ok {
+		return
+	}
+	logFields["previousStatus"] = instance.Status
+	logFields["status"] = statusRequest.Status
+	annotateAuditEvent(r, instance, instance.Status, statusRequest.Status)
+	instance.Status = statusRequest.Status
+	instance.StatusReason = statusRequest.StatusReason
+	if err = s.store.WriteInstance(instance); err != nil {
+		logFields["error"] = err
+		log.WithFields(logFields).Error("admin error: error persisting instance")
+		s.writeResponse(w, http.StatusInternalServerError, struct{}{})
+		return
+	}
+	log.WithFields(logFields).Warn("admin forced instance status")
+	s.writeResponse(w, http.StatusOK, newInstanceSummary(instance))
+}
+
+// deleteInstance deletes an instance's record without deprovisioning any of
+// the underlying Azure resources. An instance that still has bindings or child
+// instances cannot be deleted, since their records would be left referring to
+// an instance that no longer exists. The conflicting bindings and children are
+// listed in the response so the operator can delete them first.
+func (s *server) deleteInstance(w http.ResponseWriter, r *http.Request) {
+	instanceID := mux.Vars(r)["instance_id"]
+	logFields := brokerLog.FieldsFromContext(r.Context())
+	logFields["instanceID"] = instanceID
+	instance, ok := s.loadInstance(w, r, instanceID)
+	if !ok {
+		return
+	}
+	annotateAuditEvent(r, instance, instance.Status, "")
+	children, bindings, ok := s.loadDependents(w, r, instance)
+	if !ok {
+		return
+	}
+	if len(children) > 0 || len(bindings) > 0 {
+		s.writeResponse(
+			w,
+			http.StatusConflict,
+			instanceHasDependentsResponse{
+				errorResponse: errorResponse{
+					Error: "InstanceHasDependents",
+					Description: fmt.Sprintf(
+						`instance "%s" has %d child instance(s) and %d binding(s) `+
+							`that must be deleted first`,
+						instanceID,
+						len(children),
+						len(bindings),
+					),
+				},
+				Children: children,
+				Bindings: bindings,
+			},
+		)
+		return
+	}
+	ok, err := s.store.DeleteInstance(instanceID)
+	if err != nil {
+		logFields["error"] = err
+		log.WithFields(logFields).Error("admin error: error deleting instance")
+		s.writeResponse(w, http.StatusInternalServerError, struct{}{})
+		return
+	}
+	if !ok {
+		s.writeInstanceNotFound(w, instanceID)
+		return
+	}
+	log.WithFields(logFields).Warn("admin force-deleted instance record")
+	s.writeResponse(w, http.StatusOK, struct{}{})
+}
+
+// instanceHasDependentsResponse is the response to a request to delete an
+// instance that still has bindings or child instances
+type instanceHasDependentsResponse struct {
+	errorResponse
+	Children []instanceSummary `json:"children"`
+	Bindings []bindingSummary  `json:"bindings"`
+}
+
+// retryInstanceStep re-enqueues the provisioning, updating or deprovisioning
+// step that an instance is currently executing or that most recently failed
+func (s *server) retryInstanceStep(w http.ResponseWriter, r *http.Request) {
+	instanceID := mux.Vars(r)["instance_id"]
+	logFields := brokerLog.FieldsFromContext(r.Context())
+	logFields["instanceID"] = instanceID
+	instance, ok := s.loadInstance(w, r, instanceID)
+	if !ok {
+		return
+	}
+	retryable, ok := retryableInstanceStates[instance.Status]
+	if !ok || instance.Step == "" {
+		s.writeError(
+			w,
+			http.StatusConflict,
+			"NotRetryable",
+			fmt.Sprintf(
+				`instance "%s" with status "%s" has no step that can be retried`,
+				instanceID,
+				instance.Status,
+			),
+		)
+		return
+	}
+	logFields["step"] = instance.Step
+	annotateAuditEvent(r, instance, instance.Status, retryable.status)
+	instance.Status = retryable.status
+	instance.StatusReason = ""
+	if err := s.store.WriteInstance(instance); err != nil {
+		logFields["error"] = err
+		log.WithFields(logFields).Error("admin error: error persisting instance")
+		s.writeResponse(w, http.StatusInternalServerError, struct{}{})
+		return
+	}
+	task := async.NewTask(
+		retryable.jobName,
+		map[string]string{
+			"stepName":   instance.Step,
+			"instanceID": instanceID,
+		},
+	)
+	brokerLog.InjectIntoTaskArgs(r.Context(), task.GetArgs())
+	// Outcomes of the retried step are attributed to the operator
+	audit.InjectIntoTaskArgs(r.Context(), task.GetArgs())
+	tracing.InjectIntoTaskArgs(r.Context(), task.GetArgs())
+	if err := s.asyncEngine.SubmitTask(task); err != nil {
+		logFields["error"] = err
+		log.WithFields(logFields).Error("admin error: error submitting task")
+		s.writeResponse(w, http.StatusInternalServerError, struct{}{})
+		return
+	}
+	log.WithFields(logFields).Warn("admin re-enqueued instance step")
+	s.writeResponse(w, http.StatusAccepted, newInstanceSummary(instance))
+}
+
+// loadInstance retrieves the instance with the given ID. If it cannot be
+// retrieved, an appropriate response is written and false is returned.
+func (s *server) loadInstance(
+	w http.ResponseWriter,
+	r *http.Request,
+	instanceID string,
+) (service.Instance, bool) {
+	instance, ok, err := s.store.GetInstance(instanceID)
+	if err != nil {
+		logFields := brokerLog.FieldsFromContext(r.Context())
+		logFields["instanceID"] = instanceID
+		logFields["error"] = err
+		log.WithFields(logFields).Error("admin error: error retrieving instance")
+		s.writeResponse(w, http.StatusInternalServerError, struct{}{})
+		return instance, false
+	}
+	if !ok {
+		s.writeInstanceNotFound(w, instanceID)
+		return instance, false
+	}
+	return instance, true
+}
+
+// loadDependents returns summaries of the given instance's child instances
+// and bindings. If they cannot be retrieved, an error response is written and
+// false is returned.
+func (s *server) loadDependents(
+	w http.ResponseWriter,
+	r *http.Request,
+	instance service.Instance,
+) ([]instanceSummary, []bindingSummary, bool) {
+	logFields := brokerLog.FieldsFromContext(r.Context())
+	logFields["instanceID"] = instance.InstanceID
+	children := []instanceSummary{}
+	if instance.Alias != "" {
+		instances, err := s.store.GetInstances()
+		if err != nil {
+			logFields["error"] = err
+			log.WithFields(logFields).Error("admin error: error listing instances")
+			s.writeResponse(w, http.StatusInternalServerError, struct{}{})
+			return nil, nil, false
+		}
+		for _, child := range instances {
+			if child.ParentAlias == instance.Alias {
+				children = append(children, newInstanceSummary(child))
+			}
+		}
+	}
+	bindings, err := s.store.GetBindings()
+	if err != nil {
+		logFields["error"] = err
+		log.WithFields(logFields).Error("admin error: error listing bindings")
+		s.writeResponse(w, http.StatusInternalServerError, struct{}{})
+		return nil, nil, false
+	}
+	bindingSummaries := []bindingSummary{}
+	for _, binding := range bindings {
+		if binding.InstanceID == instance.InstanceID {
+			bindingSummaries = append(bindingSummaries, newBindingSummary(binding))
+		}
+	}
+	return children, bindingSummaries, true
+}
+
+func (s *server) writeInstanceNotFound(
+	w http.ResponseWriter,
+	instanceID string,
+) {
+	s.writeError(
+		w,
+		http.StatusNotFound,
+		"InstanceNotFound",
+		fmt.Sprintf(`instance "%s" does not exist`, instanceID),
+	)
+}
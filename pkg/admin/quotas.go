@@ -0,0 +1,26 @@
+package admin
+
+import (
+	"net/http"
+
+	brokerLog "github.com/Azure/open-service-broker-azure/pkg/log"
+	log "github.com/Sirupsen/logrus"
+)
+
+// getQuotaUsage lists, for each quota limit, the number of instances counting
+// toward the limit for every tenant that has at least one such instance
+func (s *server) getQuotaUsage(w http.ResponseWriter, r *http.Request) {
+	logFields := brokerLog.FieldsFromContext(r.Context())
+	instances, err := s.store.GetInstances()
+	if err != nil {
+		logFields["error"] = err
+		log.WithFields(logFields).Error("admin error: error listing instances")
+		s.writeResponse(w, http.StatusInternalServerError, struct{}{})
+		return
+	}
+	s.writeResponse(
+		w,
+		http.StatusOK,
+		map[string]interface{}{"usage": s.quotas.GetUsage(instances)},
+	)
+}
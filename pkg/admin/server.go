@@ -0,0 +1,215 @@
+package admin
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	apiFilters "github.com/Azure/open-service-broker-azure/pkg/api/filters"
+	"github.com/Azure/open-service-broker-azure/pkg/audit"
+	"github.com/Azure/open-service-broker-azure/pkg/certs"
+	"github.com/Azure/open-service-broker-azure/pkg/http/filter"
+	"github.com/Azure/open-service-broker-azure/pkg/http/filters"
+	"github.com/Azure/open-service-broker-azure/pkg/quota"
+	"github.com/Azure/open-service-broker-azure/pkg/storage"
+	log "github.com/Sirupsen/logrus"
+	"github.com/deis/async"
+	"github.com/gorilla/mux"
+)
+
+// Server is an interface for components that serve the admin API on a port of
+// their own
+type Server interface {
+	// Run causes the admin API server to start serving HTTP requests. It will
+	// block until an error occurs or the context passed to it has been canceled
+	// and will return that error.
+	Run(context.Context) error
+}
+
+type server struct {
+	config      Config
+	store       storage.Store
+	asyncEngine async.Engine
+	quotas      *quota.Quotas
+	router      *mux.Router
+}
+
+// NewServer returns a new Server that permits operators to inspect and repair
+// the broker's persisted state and the usage of the given quotas, if any
+func NewServer(
+	config Config,
+	store storage.Store,
+	asyncEngine async.Engine,
+	quotas *quota.Quotas,
+) Server {
+	s := &server{
+		config:      config,
+		store:       store,
+		asyncEngine: asyncEngine,
+		quotas:      quotas,
+	}
+	filterChain := filter.NewChain(
+		filters.NewTracingFilter(),
+		apiFilters.NewRequestIdentityFilter(),
+		filters.NewBasicAuthFilter(config.Username, config.Password),
+	)
+	router := mux.NewRouter()
+	router.StrictSlash(true)
+	router.HandleFunc(
+		"/admin/instances",
+		filterChain.GetHandler(s.getInstances),
+	).Methods(http.MethodGet)
+	router.HandleFunc(
+		"/admin/instances/{instance_id}",
+		filterChain.GetHandler(s.getInstance),
+	).Methods(http.MethodGet)
+	router.HandleFunc(
+		"/admin/instances/{instance_id}",
+		filterChain.GetHandler(
+			audited(audit.OperationAdminDeleteInstance, s.deleteInstance),
+		),
+	).Methods(http.MethodDelete)
+	router.HandleFunc(
+		"/admin/instances/{instance_id}/status",
+		filterChain.GetHandler(
+			audited(audit.OperationAdminSetStatus, s.setInstanceStatus),
+		),
+	).Methods(http.MethodPut)
+	router.HandleFunc(
+		"/admin/instances/{instance_id}/retry",
+		filterChain.GetHandler(
+			audited(audit.OperationAdminRetry, s.retryInstanceStep),
+		),
+	).Methods(http.MethodPost)
+	router.HandleFunc(
+		"/admin/bindings",
+		filterChain.GetHandler(s.getBindings),
+	).Methods(http.MethodGet)
+	router.HandleFunc(
+		"/admin/bindings/{binding_id}",
+		filterChain.GetHandler(s.getBinding),
+	).Methods(http.MethodGet)
+	router.HandleFunc(
+		"/admin/bindings/{binding_id}",
+		filterChain.GetHandler(
+			audited(audit.OperationAdminDeleteBinding, s.deleteBinding),
+		),
+	).Methods(http.MethodDelete)
+	router.HandleFunc(
+		"/admin/quotas",
+		filterChain.GetHandler(s.getQuotaUsage),
+	).Methods(http.MethodGet)
+	s.router = router
+	return s
+}
+
+func (s *server) Run(ctx context.Context) error {
+	svr := http.Server{
+		Handler: s.router,
+	}
+	errChan := make(chan error)
+	if s.config.TLSCertPath != "" && s.config.TLSKeyPath != "" {
+		reloader, err := certs.NewReloader(
+			s.config.TLSCertPath,
+			s.config.TLSKeyPath,
+		)
+		if err != nil {
+			return err
+		}
+		if s.config.TLSReloadInterval > 0 {
+			go reloader.Watch(ctx, s.config.TLSReloadInterval)
+		}
+		svr.Addr = fmt.Sprintf(":%d", s.config.Port)
+		svr.TLSConfig = &tls.Config{
+			GetCertificate: reloader.GetCertificate,
+		}
+		log.WithField(
+			"address",
+			fmt.Sprintf("https://0.0.0.0:%d/admin", s.config.Port),
+		).Info("Admin API server is listening with TLS enabled")
+		go func() {
+			select {
+			// The certificate and key are obtained from svr.TLSConfig
+			case errChan <- svr.ListenAndServeTLS("", ""):
+			case <-ctx.Done():
+			}
+		}()
+	} else {
+		// Without TLS, the admin API's credentials would be exposed to anyone
+		// who can observe traffic to the broker, so it is only made reachable
+		// from the host on which the broker is running
+		svr.Addr = fmt.Sprintf("127.0.0.1:%d", s.config.Port)
+		log.WithField(
+			"address",
+			fmt.Sprintf("http://127.0.0.1:%d/admin", s.config.Port),
+		).Warn(
+			"Admin API server is listening with TLS disabled; it is reachable " +
+				"only via the loopback interface",
+		)
+		go func() {
+			select {
+			case errChan <- svr.ListenAndServe():
+			case <-ctx.Done():
+			}
+		}()
+	}
+	select {
+	case err := <-errChan:
+		return err
+	case <-ctx.Done():
+		log.Debug("context canceled; admin API server shutting down")
+		shutdownCtx, cancel := context.WithTimeout(
+			context.Background(),
+			time.Second*5,
+		)
+		defer cancel()
+		svr.Shutdown(shutdownCtx) // nolint: errcheck
+		return ctx.Err()
+	}
+}
+
+type errorResponse struct {
+	Error       string `json:"error"`
+	Description string `json:"description"`
+}
+
+func (s *server) writeResponse(
+	w http.ResponseWriter,
+	statusCode int,
+	response interface{},
+) {
+	responseBody, err := json.Marshal(response)
+	if err != nil {
+		log.WithField("error", err).Error(
+			"admin API server error: error marshaling response",
+		)
+		statusCode = http.StatusInternalServerError
+		responseBody = []byte("{}")
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	if _, err := w.Write(responseBody); err != nil {
+		log.WithField("error", err).Error(
+			"admin API server error: error writing response",
+		)
+	}
+}
+
+func (s *server) writeError(
+	w http.ResponseWriter,
+	statusCode int,
+	errorCode string,
+	description string,
+) {
+	s.writeResponse(
+		w,
+		statusCode,
+		errorResponse{
+			Error:       errorCode,
+			Description: description,
+		},
+	)
+}
@@ -0,0 +1,447 @@
+package admin
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Azure/open-service-broker-azure/pkg/audit"
+	"github.com/Azure/open-service-broker-azure/pkg/http/filters"
+	"github.com/Azure/open-service-broker-azure/pkg/quota"
+	"github.com/Azure/open-service-broker-azure/pkg/service"
+	"github.com/Azure/open-service-broker-azure/pkg/services/fake"
+	memoryStorage "github.com/Azure/open-service-broker-azure/pkg/storage/memory"
+	fakeAsync "github.com/deis/async/fake"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	testUsername = "admin"
+	testPassword = "password"
+)
+
+func TestAdminAPIRequiresCredentials(t *testing.T) {
+	s, _ := getTestServer(t)
+	req, err := http.NewRequest(http.MethodGet, "/admin/instances", nil)
+	assert.Nil(t, err)
+	rr := httptest.NewRecorder()
+	s.router.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusUnauthorized, rr.Code)
+}
+
+func TestGetInstances(t *testing.T) {
+	s, _ := getTestServer(t)
+	writeTestInstance(t, s, "foo", "", service.InstanceStateProvisioned, "")
+	writeTestInstance(t, s, "bar", "", service.InstanceStateProvisioningFailed, "")
+	rr := doTestRequest(t, s, http.MethodGet, "/admin/instances", nil)
+	assert.Equal(t, http.StatusOK, rr.Code)
+	response := struct {
+		Instances []instanceSummary `json:"instances"`
+	}{}
+	assert.Nil(t, json.Unmarshal(rr.Body.Bytes(), &response))
+	assert.Len(t, response.Instances, 2)
+	rr = doTestRequest(
+		t,
+		s,
+		http.MethodGet,
+		"/admin/instances?status="+service.InstanceStateProvisioningFailed,
+		nil,
+	)
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Nil(t, json.Unmarshal(rr.Body.Bytes(), &response))
+	assert.Len(t, response.Instances, 1)
+	assert.Equal(t, "bar", response.Instances[0].InstanceID)
+}
+
+func TestGetInstanceWithParentAndChildren(t *testing.T) {
+	s, _ := getTestServer(t)
+	writeTestInstance(t, s, "parent", "", service.InstanceStateProvisioned, "")
+	parent, _, err := s.store.GetInstance("parent")
+	assert.Nil(t, err)
+	parent.Alias = "parent-alias"
+	assert.Nil(t, s.store.WriteInstance(parent))
+	writeTestInstance(
+		t,
+		s,
+		"child",
+		"parent-alias",
+		service.InstanceStateProvisioned,
+		"",
+	)
+	rr := doTestRequest(t, s, http.MethodGet, "/admin/instances/parent", nil)
+	assert.Equal(t, http.StatusOK, rr.Code)
+	response := struct {
+		Instance map[string]interface{} `json:"instance"`
+		Parent   *instanceSummary       `json:"parent"`
+		Children []instanceSummary      `json:"children"`
+	}{}
+	assert.Nil(t, json.Unmarshal(rr.Body.Bytes(), &response))
+	assert.Equal(t, "parent", response.Instance["instanceId"])
+	assert.Nil(t, response.Parent)
+	assert.Len(t, response.Children, 1)
+	assert.Equal(t, "child", response.Children[0].InstanceID)
+
+	rr = doTestRequest(t, s, http.MethodGet, "/admin/instances/child", nil)
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Nil(t, json.Unmarshal(rr.Body.Bytes(), &response))
+	assert.NotNil(t, response.Parent)
+	assert.Equal(t, "parent", response.Parent.InstanceID)
+
+	rr = doTestRequest(t, s, http.MethodGet, "/admin/instances/nope", nil)
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+}
+
+func TestSetInstanceStatus(t *testing.T) {
+	s, _ := getTestServer(t)
+	writeTestInstance(t, s, "foo", "", service.InstanceStateProvisioning, "")
+	rr := doTestRequest(
+		t,
+		s,
+		http.MethodPut,
+		"/admin/instances/foo/status",
+		instanceStatusRequest{Status: "BOGUS"},
+	)
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	rr = doTestRequest(
+		t,
+		s,
+		http.MethodPut,
+		"/admin/instances/foo/status",
+		instanceStatusRequest{
+			Status:       service.InstanceStateProvisioningFailed,
+			StatusReason: "stuck",
+		},
+	)
+	assert.Equal(t, http.StatusOK, rr.Code)
+	instance, _, err := s.store.GetInstance("foo")
+	assert.Nil(t, err)
+	assert.Equal(t, service.InstanceStateProvisioningFailed, instance.Status)
+	assert.Equal(t, "stuck", instance.StatusReason)
+}
+
+func TestDeleteInstance(t *testing.T) {
+	s, _ := getTestServer(t)
+	writeTestInstance(t, s, "foo", "", service.InstanceStateProvisioned, "")
+	rr := doTestRequest(t, s, http.MethodDelete, "/admin/instances/foo", nil)
+	assert.Equal(t, http.StatusOK, rr.Code)
+	_, ok, err := s.store.GetInstance("foo")
+	assert.Nil(t, err)
+	assert.False(t, ok)
+	rr = doTestRequest(t, s, http.MethodDelete, "/admin/instances/foo", nil)
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+}
+
+func TestDeleteInstanceWithDependents(t *testing.T) {
+	s, _ := getTestServer(t)
+	writeTestInstance(t, s, "parent", "", service.InstanceStateProvisioned, "")
+	parent, _, err := s.store.GetInstance("parent")
+	assert.Nil(t, err)
+	parent.Alias = "parent-alias"
+	assert.Nil(t, s.store.WriteInstance(parent))
+	writeTestInstance(
+		t,
+		s,
+		"child",
+		"parent-alias",
+		service.InstanceStateProvisioned,
+		"",
+	)
+	err = s.store.WriteBinding(service.Binding{
+		BindingID:  "binding",
+		InstanceID: "parent",
+		ServiceID:  fake.ServiceID,
+		Status:     service.BindingStateBound,
+		Created:    time.Now(),
+	})
+	assert.Nil(t, err)
+	rr := doTestRequest(t, s, http.MethodDelete, "/admin/instances/parent", nil)
+	assert.Equal(t, http.StatusConflict, rr.Code)
+	response := instanceHasDependentsResponse{}
+	assert.Nil(t, json.Unmarshal(rr.Body.Bytes(), &response))
+	assert.Equal(t, "InstanceHasDependents", response.Error)
+	assert.Len(t, response.Children, 1)
+	assert.Equal(t, "child", response.Children[0].InstanceID)
+	assert.Len(t, response.Bindings, 1)
+	assert.Equal(t, "binding", response.Bindings[0].BindingID)
+	_, ok, err := s.store.GetInstance("parent")
+	assert.Nil(t, err)
+	assert.True(t, ok)
+	// Once its dependents are gone, the instance can be deleted
+	rr = doTestRequest(t, s, http.MethodDelete, "/admin/bindings/binding", nil)
+	assert.Equal(t, http.StatusOK, rr.Code)
+	rr = doTestRequest(t, s, http.MethodDelete, "/admin/instances/child", nil)
+	assert.Equal(t, http.StatusOK, rr.Code)
+	rr = doTestRequest(t, s, http.MethodDelete, "/admin/instances/parent", nil)
+	assert.Equal(t, http.StatusOK, rr.Code)
+}
+
+func TestRetryInstanceStep(t *testing.T) {
+	s, asyncEngine := getTestServer(t)
+	writeTestInstance(
+		t,
+		s,
+		"foo",
+		"",
+		service.InstanceStateProvisioningFailed,
+		"deployARMTemplate",
+	)
+	rr := doTestRequest(t, s, http.MethodPost, "/admin/instances/foo/retry", nil)
+	assert.Equal(t, http.StatusAccepted, rr.Code)
+	instance, _, err := s.store.GetInstance("foo")
+	assert.Nil(t, err)
+	assert.Equal(t, service.InstanceStateProvisioning, instance.Status)
+	assert.Len(t, asyncEngine.SubmittedTasks, 1)
+	for _, task := range asyncEngine.SubmittedTasks {
+		assert.Equal(t, "executeProvisioningStep", task.GetJobName())
+		assert.Equal(t, "deployARMTemplate", task.GetArgs()["stepName"])
+		assert.Equal(t, "foo", task.GetArgs()["instanceID"])
+	}
+}
+
+func TestRetryInstanceStepWithNoStep(t *testing.T) {
+	s, asyncEngine := getTestServer(t)
+	writeTestInstance(t, s, "foo", "", service.InstanceStateProvisioned, "")
+	rr := doTestRequest(t, s, http.MethodPost, "/admin/instances/foo/retry", nil)
+	assert.Equal(t, http.StatusConflict, rr.Code)
+	assert.Empty(t, asyncEngine.SubmittedTasks)
+}
+
+func TestAdminMutationsAreAudited(t *testing.T) {
+	buf := &bytes.Buffer{}
+	auditKey := []byte("AUDITHMACKey-1234567890123456789")
+	auditLogger, err := audit.NewLogger(audit.NewWriterSink(buf), auditKey, 10, "")
+	assert.Nil(t, err)
+	audit.InitializeGlobalLogger(auditLogger)
+	defer audit.InitializeGlobalLogger(audit.NewDiscardLogger())
+	s, _ := getTestServer(t)
+	writeTestInstance(
+		t,
+		s,
+		"foo",
+		"",
+		service.InstanceStateProvisioning,
+		"deployARMTemplate",
+	)
+	rr := doTestRequest(
+		t,
+		s,
+		http.MethodPut,
+		"/admin/instances/foo/status",
+		instanceStatusRequest{Status: service.InstanceStateProvisioningFailed},
+	)
+	assert.Equal(t, http.StatusOK, rr.Code)
+	rr = doTestRequest(t, s, http.MethodPost, "/admin/instances/foo/retry", nil)
+	assert.Equal(t, http.StatusAccepted, rr.Code)
+	rr = doTestRequest(t, s, http.MethodDelete, "/admin/instances/foo", nil)
+	assert.Equal(t, http.StatusOK, rr.Code)
+	rr = doTestRequest(t, s, http.MethodDelete, "/admin/bindings/bar", nil)
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+
+	// Wait for the events to be recorded
+	auditLogger.Close()
+	events := []audit.Event{}
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		event := audit.Event{}
+		assert.Nil(t, json.Unmarshal([]byte(line), &event))
+		events = append(events, event)
+	}
+	assert.Len(t, events, 4)
+	assert.Equal(t, uint64(0), audit.VerifyChain(events, auditKey))
+	expected := []struct {
+		operation      string
+		outcome        string
+		previousStatus string
+		status         string
+	}{
+		{
+			audit.OperationAdminSetStatus,
+			audit.OutcomeSucceeded,
+			service.InstanceStateProvisioning,
+			service.InstanceStateProvisioningFailed,
+		},
+		{
+			audit.OperationAdminRetry,
+			audit.OutcomeAccepted,
+			service.InstanceStateProvisioningFailed,
+			service.InstanceStateProvisioning,
+		},
+		{
+			audit.OperationAdminDeleteInstance,
+			audit.OutcomeSucceeded,
+			service.InstanceStateProvisioning,
+			"",
+		},
+		{audit.OperationAdminDeleteBinding, audit.OutcomeRejected, "", ""},
+	}
+	for i, e := range expected {
+		event := events[i]
+		assert.Equal(t, e.operation, event.Operation)
+		assert.Equal(t, e.outcome, event.Outcome)
+		assert.Equal(t, e.previousStatus, event.PreviousStatus)
+		assert.Equal(t, e.status, event.Status)
+		assert.Equal(t, testUsername, event.Actor.Name)
+		assert.Equal(t, filters.BasicAuthScheme, event.Actor.Scheme)
+	}
+	assert.Equal(t, "foo", events[0].InstanceID)
+	assert.Equal(t, fake.ServiceID, events[0].ServiceID)
+	assert.Equal(t, "bar", events[3].BindingID)
+}
+
+func TestGetAndDeleteBinding(t *testing.T) {
+	s, _ := getTestServer(t)
+	err := s.store.WriteBinding(service.Binding{
+		BindingID:  "bar",
+		InstanceID: "foo",
+		ServiceID:  fake.ServiceID,
+		Status:     service.BindingStateBound,
+		Created:    time.Now(),
+	})
+	assert.Nil(t, err)
+	rr := doTestRequest(
+		t,
+		s,
+		http.MethodGet,
+		"/admin/bindings?instanceID=foo",
+		nil,
+	)
+	assert.Equal(t, http.StatusOK, rr.Code)
+	response := struct {
+		Bindings []bindingSummary `json:"bindings"`
+	}{}
+	assert.Nil(t, json.Unmarshal(rr.Body.Bytes(), &response))
+	assert.Len(t, response.Bindings, 1)
+	rr = doTestRequest(t, s, http.MethodGet, "/admin/bindings/bar", nil)
+	assert.Equal(t, http.StatusOK, rr.Code)
+	rr = doTestRequest(t, s, http.MethodDelete, "/admin/bindings/bar", nil)
+	assert.Equal(t, http.StatusOK, rr.Code)
+	rr = doTestRequest(t, s, http.MethodGet, "/admin/bindings/bar", nil)
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+}
+
+func TestGetQuotaUsage(t *testing.T) {
+	s, _ := getTestServer(t)
+	s.quotas = &quota.Quotas{
+		Limits: []quota.Limit{
+			{
+				Name:      "standard-per-principal",
+				ServiceID: fake.ServiceID,
+				PlanID:    fake.StandardPlanID,
+				Key:       quota.KeyPrincipal,
+				Max:       5,
+			},
+		},
+	}
+	for _, instanceID := range []string{"foo", "bar"} {
+		err := s.store.WriteInstance(service.Instance{
+			InstanceID: instanceID,
+			ServiceID:  fake.ServiceID,
+			PlanID:     fake.StandardPlanID,
+			Status:     service.InstanceStateProvisioned,
+			Created:    time.Now(),
+			Principal:  "k8s",
+		})
+		assert.Nil(t, err)
+	}
+	// An instance provisioned before the principal was recorded
+	err := s.store.WriteInstance(service.Instance{
+		InstanceID: "baz",
+		ServiceID:  fake.ServiceID,
+		PlanID:     fake.StandardPlanID,
+		Status:     service.InstanceStateProvisioned,
+		Created:    time.Now(),
+	})
+	assert.Nil(t, err)
+	rr := doTestRequest(t, s, http.MethodGet, "/admin/quotas", nil)
+	assert.Equal(t, http.StatusOK, rr.Code)
+	response := struct {
+		Usage []quota.Usage `json:"usage"`
+	}{}
+	assert.Nil(t, json.Unmarshal(rr.Body.Bytes(), &response))
+	assert.Equal(
+		t,
+		[]quota.Usage{
+			{
+				Limit:     "standard-per-principal",
+				ServiceID: fake.ServiceID,
+				PlanID:    fake.StandardPlanID,
+				Key:       quota.KeyPrincipal,
+				Tenant:    "k8s",
+				Used:      2,
+				Max:       5,
+			},
+			{
+				Limit:        "standard-per-principal",
+				ServiceID:    fake.ServiceID,
+				PlanID:       fake.StandardPlanID,
+				Key:          quota.KeyPrincipal,
+				Unattributed: true,
+				Used:         1,
+				Max:          5,
+			},
+		},
+		response.Usage,
+	)
+}
+
+func getTestServer(t *testing.T) (*server, *fakeAsync.Engine) {
+	fakeModule, err := fake.New()
+	assert.Nil(t, err)
+	fakeCatalog, err := fakeModule.GetCatalog()
+	assert.Nil(t, err)
+	asyncEngine := fakeAsync.NewEngine()
+	config := NewConfigWithDefaults()
+	config.Username = testUsername
+	config.Password = testPassword
+	s := NewServer(
+		config,
+		memoryStorage.NewStore(fakeCatalog),
+		asyncEngine,
+		nil,
+	)
+	return s.(*server), asyncEngine
+}
+
+func writeTestInstance(
+	t *testing.T,
+	s *server,
+	instanceID string,
+	parentAlias string,
+	status string,
+	step string,
+) {
+	err := s.store.WriteInstance(service.Instance{
+		InstanceID:  instanceID,
+		ServiceID:   fake.ServiceID,
+		PlanID:      fake.StandardPlanID,
+		ParentAlias: parentAlias,
+		Status:      status,
+		Step:        step,
+		Created:     time.Now(),
+	})
+	assert.Nil(t, err)
+}
+
+func doTestRequest(
+	t *testing.T,
+	s *server,
+	method string,
+	path string,
+	body interface{},
+) *httptest.ResponseRecorder {
+	var bodyBytes []byte
+	if body != nil {
+		var err error
+		bodyBytes, err = json.Marshal(body)
+		assert.Nil(t, err)
+	}
+	req, err := http.NewRequest(method, path, bytes.NewBuffer(bodyBytes))
+	assert.Nil(t, err)
+	req.SetBasicAuth(testUsername, testPassword)
+	rr := httptest.NewRecorder()
+	s.router.ServeHTTP(rr, req)
+	return rr
+}
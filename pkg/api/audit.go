@@ -0,0 +1,77 @@
+package api
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/Azure/open-service-broker-azure/pkg/audit"
+	brokerLog "github.com/Azure/open-service-broker-azure/pkg/log"
+	"github.com/Azure/open-service-broker-azure/pkg/service"
+	"github.com/gorilla/mux"
+)
+
+type auditEventContextKey struct{}
+
+// audited wraps the given handler so that an audit event is recorded for every
+// request it handles, once a response has been written. The handler may add
+// details to the event using annotateAuditEvent(). Since identity is
+// established by the filter chain, the filter chain must be applied to the
+// handler this function returns.
+func audited(operation string, handle http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		vars := mux.Vars(r)
+		event := &audit.Event{
+			Operation:  operation,
+			Phase:      audit.PhaseRequest,
+			InstanceID: vars["instance_id"],
+			BindingID:  vars["binding_id"],
+		}
+		ctx := audit.NewContext(
+			r.Context(),
+			r.Header.Get(audit.OriginatingIdentityHeader),
+		)
+		ctx = context.WithValue(ctx, auditEventContextKey{}, event)
+		rec := &statusRecorder{
+			ResponseWriter: w,
+			statusCode:     http.StatusOK,
+		}
+		handle(rec, r.WithContext(ctx))
+		event.Actor = audit.ActorFromContext(ctx)
+		event.RequestID, _ = brokerLog.RequestIDFromContext(ctx)
+		event.StatusCode = rec.statusCode
+		event.Outcome = audit.GetOutcome(rec.statusCode)
+		audit.Log(*event)
+	}
+}
+
+// annotateAuditEvent records the service and plan a request pertains to in
+// the request's audit event. It is a no-op if the request isn't being audited.
+func annotateAuditEvent(r *http.Request, serviceID string, planID string) {
+	if event, ok := getAuditEvent(r); ok {
+		event.ServiceID = serviceID
+		event.PlanID = planID
+	}
+}
+
+// annotateAuditEventParameters records a request's parameters, with secure
+// parameters (as identified by the given schema) redacted, in the request's
+// audit event. It is a no-op if the request isn't being audited.
+func annotateAuditEventParameters(
+	r *http.Request,
+	schema service.InputParametersSchema,
+	parameters map[string]interface{},
+) {
+	if event, ok := getAuditEvent(r); ok {
+		event.Parameters = service.Redact(
+			service.Parameters{
+				Schema: &schema,
+				Data:   parameters,
+			},
+		)
+	}
+}
+
+func getAuditEvent(r *http.Request) (*audit.Event, bool) {
+	event, ok := r.Context().Value(auditEventContextKey{}).(*audit.Event)
+	return event, ok
+}
@@ -0,0 +1,115 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/Azure/open-service-broker-azure/pkg/audit"
+	"github.com/Azure/open-service-broker-azure/pkg/service"
+	"github.com/Azure/open-service-broker-azure/pkg/services/fake"
+	fakeAsync "github.com/deis/async/fake"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProvisioningIsAudited(t *testing.T) {
+	buf := &bytes.Buffer{}
+	auditLogger, err := audit.NewLogger(
+		audit.NewWriterSink(buf),
+		[]byte("AUDITHMACKey-1234567890123456789"),
+		10,
+		"",
+	)
+	assert.Nil(t, err)
+	audit.InitializeGlobalLogger(auditLogger)
+	defer audit.InitializeGlobalLogger(audit.NewDiscardLogger())
+	s, _, err := getTestServer()
+	assert.Nil(t, err)
+	instanceID := getDisposableInstanceID()
+	req, err := getProvisionRequest(
+		instanceID,
+		map[string]string{
+			"accepts_incomplete": "true",
+		},
+		&ProvisioningRequest{
+			ServiceID: fake.ServiceID,
+			PlanID:    fake.StandardPlanID,
+			Parameters: map[string]interface{}{
+				"someParameter": "foo",
+			},
+		},
+	)
+	assert.Nil(t, err)
+	req = withTestIdentity(req, nil)
+	req.Header.Set(
+		audit.OriginatingIdentityHeader,
+		"kubernetes "+
+			base64.StdEncoding.EncodeToString([]byte(`{"username":"jane"}`)),
+	)
+	rr := httptest.NewRecorder()
+	s.router.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusAccepted, rr.Code)
+
+	// Wait for the event to be recorded
+	auditLogger.Close()
+	event := audit.Event{}
+	err = json.Unmarshal([]byte(strings.TrimSpace(buf.String())), &event)
+	assert.Nil(t, err)
+	assert.Equal(t, audit.OperationProvision, event.Operation)
+	assert.Equal(t, audit.PhaseRequest, event.Phase)
+	assert.Equal(t, audit.OutcomeAccepted, event.Outcome)
+	assert.Equal(t, http.StatusAccepted, event.StatusCode)
+	assert.Equal(t, instanceID, event.InstanceID)
+	assert.Equal(t, fake.ServiceID, event.ServiceID)
+	assert.Equal(t, fake.StandardPlanID, event.PlanID)
+	assert.Equal(t, "test", event.Actor.Name)
+	assert.Equal(t, "kubernetes", event.Actor.OriginatingIdentity.Platform)
+	assert.Equal(
+		t,
+		map[string]interface{}{"someParameter": "foo"},
+		event.Parameters,
+	)
+
+	// The actor is passed along to the async engine so the outcome of
+	// provisioning can be attributed to them as well
+	e := s.asyncEngine.(*fakeAsync.Engine)
+	assert.Len(t, e.SubmittedTasks, 1)
+	for _, task := range e.SubmittedTasks {
+		actor := audit.ActorFromContext(
+			audit.NewContextFromTaskArgs(context.Background(), task.GetArgs()),
+		)
+		assert.Equal(t, event.Actor, actor)
+	}
+}
+
+func TestAuditEventParametersAreRedacted(t *testing.T) {
+	event := &audit.Event{}
+	req, err := http.NewRequest(http.MethodPut, "/", nil)
+	assert.Nil(t, err)
+	req = req.WithContext(
+		context.WithValue(req.Context(), auditEventContextKey{}, event),
+	)
+	annotateAuditEventParameters(
+		req,
+		service.InputParametersSchema{
+			SecureProperties: []string{"password"},
+		},
+		map[string]interface{}{
+			"username": "jane",
+			"password": "secret",
+		},
+	)
+	assert.Equal(
+		t,
+		map[string]interface{}{
+			"username": "jane",
+			"password": service.Redacted,
+		},
+		event.Parameters,
+	)
+}
@@ -0,0 +1,55 @@
+package api
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/kelseyhightower/envconfig"
+)
+
+const (
+	// AuthSchemeBasic represents authentication using Basic Auth
+	AuthSchemeBasic = "BASIC"
+	// AuthSchemeJWT represents authentication using JWT bearer tokens
+	AuthSchemeJWT = "JWT"
+)
+
+// AuthConfig represents configuration options that determine how requests to
+// the broker are authenticated
+type AuthConfig struct {
+	// Schemes lists the authentication schemes accepted by the broker. If more
+	// than one is listed, a request may authenticate using any of them.
+	Schemes []string `envconfig:"AUTH_SCHEMES"`
+}
+
+// NewAuthConfigWithDefaults returns an AuthConfig object with default values
+// already applied. Callers are then free to set custom values for the
+// remaining fields and/or override default values.
+func NewAuthConfigWithDefaults() AuthConfig {
+	return AuthConfig{
+		Schemes: []string{AuthSchemeBasic},
+	}
+}
+
+// GetAuthConfigFromEnvironment returns authentication configuration derived
+// from environment variables
+func GetAuthConfigFromEnvironment() (AuthConfig, error) {
+	c := NewAuthConfigWithDefaults()
+	err := envconfig.Process("", &c)
+	if err != nil {
+		return c, err
+	}
+	if len(c.Schemes) == 0 {
+		return c, fmt.Errorf("no authentication schemes were specified")
+	}
+	for i, scheme := range c.Schemes {
+		scheme = strings.ToUpper(strings.TrimSpace(scheme))
+		switch scheme {
+		case AuthSchemeBasic, AuthSchemeJWT:
+		default:
+			return c, fmt.Errorf(`unrecognized authentication scheme "%s"`, scheme)
+		}
+		c.Schemes[i] = scheme
+	}
+	return c, nil
+}
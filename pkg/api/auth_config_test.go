@@ -0,0 +1,59 @@
+package api
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAuthConfigDefaultsToBasic(t *testing.T) {
+	err := os.Unsetenv("AUTH_SCHEMES")
+	assert.Nil(t, err)
+	c, err := GetAuthConfigFromEnvironment()
+	assert.Nil(t, err)
+	assert.Equal(t, []string{AuthSchemeBasic}, c.Schemes)
+}
+
+func TestAuthConfigWithMultipleSchemes(t *testing.T) {
+	err := os.Setenv("AUTH_SCHEMES", "basic, jwt")
+	assert.Nil(t, err)
+	defer os.Unsetenv("AUTH_SCHEMES") // nolint: errcheck
+	c, err := GetAuthConfigFromEnvironment()
+	assert.Nil(t, err)
+	assert.Equal(t, []string{AuthSchemeBasic, AuthSchemeJWT}, c.Schemes)
+}
+
+func TestAuthConfigWithUnrecognizedScheme(t *testing.T) {
+	err := os.Setenv("AUTH_SCHEMES", "digest")
+	assert.Nil(t, err)
+	defer os.Unsetenv("AUTH_SCHEMES") // nolint: errcheck
+	_, err = GetAuthConfigFromEnvironment()
+	assert.NotNil(t, err)
+}
+
+func TestJWTAuthConfigIssuerNotSpecified(t *testing.T) {
+	err := os.Setenv("JWT_AUTH_ISSUER", "")
+	assert.Nil(t, err)
+	err = os.Setenv("JWT_AUTH_AUDIENCE", "broker")
+	assert.Nil(t, err)
+	defer os.Unsetenv("JWT_AUTH_AUDIENCE") // nolint: errcheck
+	_, err = GetJWTAuthConfigFromEnvironment()
+	assert.NotNil(t, err)
+}
+
+func TestJWTAuthConfigWithJWKSURLAndPath(t *testing.T) {
+	env := map[string]string{
+		"JWT_AUTH_ISSUER":    "https://issuer.example.com",
+		"JWT_AUTH_AUDIENCE":  "broker",
+		"JWT_AUTH_JWKS_URL":  "https://issuer.example.com/keys",
+		"JWT_AUTH_JWKS_PATH": "/etc/osba/jwks.json",
+	}
+	for k, v := range env {
+		err := os.Setenv(k, v)
+		assert.Nil(t, err)
+		defer os.Unsetenv(k) // nolint: errcheck
+	}
+	_, err := GetJWTAuthConfigFromEnvironment()
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,127 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+
+	"github.com/Azure/open-service-broker-azure/pkg/identity"
+	"github.com/kelseyhightower/envconfig"
+)
+
+// BasicAuthCredential represents a single user that may access the broker
+// using basic auth, along with the services and plans that user is entitled
+// to
+type BasicAuthCredential struct {
+	Username string `json:"username"`
+	Password string `json:"password"`
+	// Services lists the services (and, optionally, plans) that the user may
+	// see and use. If omitted, the user may see and use all services.
+	Services []ServiceEntitlement `json:"services"`
+}
+
+// ServiceEntitlement represents a single service, and optionally a subset of
+// its plans, that a user is entitled to
+type ServiceEntitlement struct {
+	ServiceID string `json:"serviceID"`
+	// PlanIDs lists the plans of the service that the user may see and use. If
+	// omitted, the user may see and use all of the service's plans.
+	PlanIDs []string `json:"planIDs"`
+}
+
+type basicAuthCredentialsConfig struct {
+	CredentialsPath string `envconfig:"BASIC_AUTH_CREDENTIALS_PATH"`
+}
+
+// GetBasicAuthCredentials returns the credentials of all users that may
+// access the broker using basic auth. If the BASIC_AUTH_CREDENTIALS_PATH
+// environment variable names a credentials file, users are loaded from that
+// file. Otherwise, a single unrestricted user is derived from the basic auth
+// configuration.
+func GetBasicAuthCredentials() ([]BasicAuthCredential, error) {
+	bacc := basicAuthCredentialsConfig{}
+	if err := envconfig.Process("", &bacc); err != nil {
+		return nil, err
+	}
+	if bacc.CredentialsPath != "" {
+		return GetBasicAuthCredentialsFromFile(bacc.CredentialsPath)
+	}
+	basicAuthConfig, err := GetBasicAuthConfig()
+	if err != nil {
+		return nil, err
+	}
+	return []BasicAuthCredential{
+		{
+			Username: basicAuthConfig.GetUsername(),
+			Password: basicAuthConfig.GetPassword(),
+		},
+	}, nil
+}
+
+// GetBasicAuthCredentialsFromFile loads the credentials of all users that may
+// access the broker using basic auth from the JSON file at the given path
+func GetBasicAuthCredentialsFromFile(
+	path string,
+) ([]BasicAuthCredential, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading credentials file: %s", err)
+	}
+	credentials := []BasicAuthCredential{}
+	if err := json.Unmarshal(data, &credentials); err != nil {
+		return nil, fmt.Errorf("error parsing credentials file: %s", err)
+	}
+	if len(credentials) == 0 {
+		return nil, fmt.Errorf(`credentials file "%s" defines no users`, path)
+	}
+	usernames := map[string]bool{}
+	for _, credential := range credentials {
+		if credential.Username == "" || credential.Password == "" {
+			return nil, fmt.Errorf(
+				`credentials file "%s" includes a user without a username or password`,
+				path,
+			)
+		}
+		if usernames[credential.Username] {
+			return nil, fmt.Errorf(
+				`credentials file "%s" defines user "%s" more than once`,
+				path,
+				credential.Username,
+			)
+		}
+		usernames[credential.Username] = true
+		for _, svc := range credential.Services {
+			if svc.ServiceID == "" {
+				return nil, fmt.Errorf(
+					`credentials file "%s" includes a service without a serviceID for `+
+						`user "%s"`,
+					path,
+					credential.Username,
+				)
+			}
+		}
+	}
+	return credentials, nil
+}
+
+// GetEntitlements returns the services and plans the user is entitled to. If
+// the user is not restricted, nil is returned.
+func (b BasicAuthCredential) GetEntitlements() identity.Entitlements {
+	if b.Services == nil {
+		return nil
+	}
+	entitlements := identity.Entitlements{}
+	for _, svc := range b.Services {
+		planIDs, ok := entitlements[svc.ServiceID]
+		if ok && len(planIDs) == 0 {
+			// The user is already entitled to all of the service's plans
+			continue
+		}
+		if len(svc.PlanIDs) == 0 {
+			entitlements[svc.ServiceID] = []string{}
+			continue
+		}
+		entitlements[svc.ServiceID] = append(planIDs, svc.PlanIDs...)
+	}
+	return entitlements
+}
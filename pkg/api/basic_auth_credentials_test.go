@@ -0,0 +1,78 @@
+package api
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/Azure/open-service-broker-azure/pkg/identity"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetBasicAuthCredentialsFromFile(t *testing.T) {
+	path := writeTestCredentialsFile(t, `[
+		{ "username": "k8s-east", "password": "foo" },
+		{
+			"username": "cf",
+			"password": "bar",
+			"services": [
+				{ "serviceID": "svc-a" },
+				{ "serviceID": "svc-b", "planIDs": [ "plan-1", "plan-2" ] }
+			]
+		}
+	]`)
+	defer os.Remove(path)
+	credentials, err := GetBasicAuthCredentialsFromFile(path)
+	assert.Nil(t, err)
+	assert.Len(t, credentials, 2)
+	assert.Nil(t, credentials[0].GetEntitlements())
+	assert.Equal(
+		t,
+		identity.Entitlements{
+			"svc-a": {},
+			"svc-b": {"plan-1", "plan-2"},
+		},
+		credentials[1].GetEntitlements(),
+	)
+}
+
+func TestGetBasicAuthCredentialsFromFileWithDuplicateUser(t *testing.T) {
+	path := writeTestCredentialsFile(t, `[
+		{ "username": "foo", "password": "foo" },
+		{ "username": "foo", "password": "bar" }
+	]`)
+	defer os.Remove(path)
+	_, err := GetBasicAuthCredentialsFromFile(path)
+	assert.NotNil(t, err)
+}
+
+func TestGetBasicAuthCredentialsFromFileWithMissingPassword(t *testing.T) {
+	path := writeTestCredentialsFile(t, `[ { "username": "foo" } ]`)
+	defer os.Remove(path)
+	_, err := GetBasicAuthCredentialsFromFile(path)
+	assert.NotNil(t, err)
+}
+
+func TestBasicAuthCredentialEntitledToAllPlansOfService(t *testing.T) {
+	credential := BasicAuthCredential{
+		Services: []ServiceEntitlement{
+			{ServiceID: "svc-a", PlanIDs: []string{"plan-1"}},
+			{ServiceID: "svc-a"},
+			{ServiceID: "svc-a", PlanIDs: []string{"plan-2"}},
+		},
+	}
+	assert.Equal(
+		t,
+		identity.Entitlements{"svc-a": {}},
+		credential.GetEntitlements(),
+	)
+}
+
+func writeTestCredentialsFile(t *testing.T, contents string) string {
+	file, err := ioutil.TempFile("", "credentials")
+	assert.Nil(t, err)
+	_, err = file.WriteString(contents)
+	assert.Nil(t, err)
+	assert.Nil(t, file.Close())
+	return file.Name()
+}
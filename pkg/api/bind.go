@@ -1,12 +1,14 @@
 package api
 
 import (
+	"context"
 	"fmt"
-	"io/ioutil"
 	"net/http"
 	"reflect"
 	"time"
 
+	brokerLog "github.com/Azure/open-service-broker-azure/pkg/log"
+	"github.com/Azure/open-service-broker-azure/pkg/policy"
 	"github.com/Azure/open-service-broker-azure/pkg/service"
 	log "github.com/Sirupsen/logrus"
 	"github.com/gorilla/mux"
@@ -16,10 +18,14 @@ func (s *server) bind(w http.ResponseWriter, r *http.Request) {
 	instanceID := mux.Vars(r)["instance_id"]
 	bindingID := mux.Vars(r)["binding_id"]
 
-	logFields := log.Fields{
-		"instanceID": instanceID,
-		"bindingID":  bindingID,
-	}
+	ctx := brokerLog.NewContext(
+		r.Context(),
+		log.Fields{
+			"instanceID": instanceID,
+			"bindingID":  bindingID,
+		},
+	)
+	logFields := brokerLog.FieldsFromContext(ctx)
 
 	log.WithFields(logFields).Debug("received binding request")
 
@@ -42,6 +48,24 @@ func (s *server) bind(w http.ResponseWriter, r *http.Request) {
 		s.writeResponse(w, http.StatusBadRequest, generateEmptyResponse())
 		return
 	}
+	ctx = brokerLog.NewContext(
+		ctx,
+		log.Fields{
+			"serviceID": instance.ServiceID,
+			"planID":    instance.PlanID,
+		},
+	)
+	logFields["serviceID"] = instance.ServiceID
+	logFields["planID"] = instance.PlanID
+	annotateAuditEvent(r, instance.ServiceID, instance.PlanID)
+
+	if !isEntitled(r, instance.ServiceID, instance.PlanID) {
+		log.WithFields(logFields).Debug(
+			"bad binding request: not entitled to the service and plan",
+		)
+		s.writeResponse(w, http.StatusForbidden, generateForbiddenResponse())
+		return
+	}
 
 	if instance.Status != service.InstanceStateProvisioned {
 		log.WithFields(logFields).Debug(
@@ -54,7 +78,19 @@ func (s *server) bind(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	bodyBytes, err := ioutil.ReadAll(r.Body)
+	bodyBytes, err := s.readRequestBody(r)
+	if isRequestBodyTooLarge(err) {
+		logFields["error"] = err
+		log.WithFields(logFields).Debug(
+			"bad binding request: request body too large",
+		)
+		s.writeResponse(
+			w,
+			http.StatusRequestEntityTooLarge,
+			generateRequestBodyTooLargeResponse(),
+		)
+		return
+	}
 	if err != nil {
 		logFields["error"] = err
 		log.WithFields(logFields).Error(
@@ -76,6 +112,11 @@ func (s *server) bind(w http.ResponseWriter, r *http.Request) {
 		s.writeResponse(w, http.StatusBadRequest, generateMalformedRequestResponse())
 		return
 	}
+	annotateAuditEventParameters(
+		r,
+		instance.Plan.GetSchemas().ServiceBindings.BindingParametersSchema,
+		bindingRequest.Parameters,
+	)
 
 	// Our broker doesn't actually require the serviceID and planID that, per
 	// spec, are passed to us in the request body (since this broker is stateful,
@@ -86,9 +127,7 @@ func (s *server) bind(w http.ResponseWriter, r *http.Request) {
 		bindingRequest.ServiceID != instance.ServiceID) ||
 		(bindingRequest.PlanID != "" &&
 			bindingRequest.PlanID != instance.PlanID) {
-		logFields["serviceID"] = instance.ServiceID
 		logFields["requestServiceID"] = bindingRequest.ServiceID
-		logFields["planID"] = instance.PlanID
 		logFields["requestPlanID"] = bindingRequest.PlanID
 		log.WithFields(logFields).Debug(
 			"bad binding request: serviceID or planID does not match serviceID or " +
@@ -99,23 +138,25 @@ func (s *server) bind(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Normalize the binding parameters (coercing values to the types called for
+	// by the schema and applying defaults). It is the normalized parameters that
+	// are validated and persisted.
+	bps := instance.Plan.GetSchemas().ServiceBindings.BindingParametersSchema
+	bindingRequest.Parameters = bps.Normalize(bindingRequest.Parameters)
+
 	// Start by carrying out plan-specific binding request parameters validation
-	if err =
-		instance.Plan.GetSchemas().ServiceBindings.BindingParametersSchema.Validate(
-			bindingRequest.Parameters,
-		); err != nil {
-		var validationErr *service.ValidationError
-		validationErr, ok = err.(*service.ValidationError)
+	if err = bps.Validate(bindingRequest.Parameters); err != nil {
+		var validationErrs service.ValidationErrors
+		validationErrs, ok = service.GetValidationErrors(err)
 		if ok {
-			logFields["field"] = validationErr.Field
-			logFields["issue"] = validationErr.Issue
+			logFields["validationErrors"] = validationErrs.Error()
 			log.WithFields(logFields).Debug(
 				"bad binding request: validation error",
 			)
 			s.writeResponse(
 				w,
 				http.StatusBadRequest,
-				generateValidationFailedResponse(validationErr),
+				generateValidationFailedResponse(validationErrs),
 			)
 			return
 		}
@@ -123,11 +164,35 @@ func (s *server) bind(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Evaluate the request against the operator's policy
+	if violations := s.evaluatePolicy(
+		r,
+		policy.Input{
+			Operation:   policy.OperationBind,
+			ServiceID:   instance.ServiceID,
+			ServiceName: instance.Service.GetName(),
+			PlanID:      instance.PlanID,
+			PlanName:    instance.Plan.GetName(),
+			Parameters:  bindingRequest.Parameters,
+			Context:     bindingRequest.Context,
+		},
+	); len(violations) > 0 {
+		logFields["policyViolations"] = violations
+		log.WithFields(logFields).Debug(
+			"bad binding request: policy violation",
+		)
+		s.writeResponse(
+			w,
+			http.StatusBadRequest,
+			generatePolicyViolationResponse(violations),
+		)
+		return
+	}
+
 	serviceManager := instance.Service.GetServiceManager()
 
 	// Wrap the binding parameters with a "params" object that guides access to
 	// the parameters using schema
-	bps := instance.Plan.GetSchemas().ServiceBindings.BindingParametersSchema
 	bindingParameters := &service.BindingParameters{
 		Parameters: service.Parameters{
 			Schema: &bps,
@@ -162,8 +227,16 @@ func (s *server) bind(w http.ResponseWriter, r *http.Request) {
 			return
 		}
 
-		if (binding.BindingParameters == nil && len(bindingRequest.Parameters) == 0) || // nolint: lll
-			(binding.BindingParameters != nil && reflect.DeepEqual(binding.BindingParameters.Data, bindingRequest.Parameters)) { // nolint: lll
+		// The existing binding's parameters are normalized in case they were
+		// persisted before parameters were normalized upon receipt.
+		var existingParams map[string]interface{}
+		if binding.BindingParameters != nil {
+			existingParams = binding.BindingParameters.Data
+		}
+		if reflect.DeepEqual(
+			bps.Normalize(existingParams),
+			bindingRequest.Parameters,
+		) {
 			// Per the spec, if bound, respond with a 200
 			// Filling in a gap in the spec-- if the status is anything else, we'll
 			// choose to respond with a 409
@@ -218,11 +291,13 @@ func (s *server) bind(w http.ResponseWriter, r *http.Request) {
 	// specific code has left us in, so we'll attempt to record the error in
 	// the datastore.
 	bindingDetails, err := serviceManager.Bind(
+		ctx,
 		instance,
 		*bindingParameters,
 	)
 	if err != nil {
 		s.handleBindingError(
+			ctx,
 			binding,
 			err,
 			"error executing service-specific binding logic",
@@ -246,6 +321,7 @@ func (s *server) bind(w http.ResponseWriter, r *http.Request) {
 	binding.Status = service.BindingStateBound
 	if err = s.store.WriteBinding(binding); err != nil {
 		s.handleBindingError(
+			ctx,
 			binding,
 			err,
 			"error persisting binding",
@@ -293,6 +369,7 @@ func (s *server) bind(w http.ResponseWriter, r *http.Request) {
 // so we log that failure and kill the process. Barring such a failure, a nicely
 // formatted error message is logged.
 func (s *server) handleBindingError(
+	ctx context.Context,
 	binding service.Binding,
 	e error,
 	msg string,
@@ -304,11 +381,8 @@ func (s *server) handleBindingError(
 	} else {
 		binding.StatusReason = fmt.Sprintf(`binding error: %s: %s`, msg, e)
 	}
-	logFields := log.Fields{
-		"bindingID":  binding.BindingID,
-		"instanceID": binding.InstanceID,
-		"status":     binding.Status,
-	}
+	logFields := brokerLog.FieldsFromContext(ctx)
+	logFields["status"] = binding.Status
 	if err := s.store.WriteBinding(binding); err != nil {
 		logFields["originalError"] = binding.StatusReason
 		logFields["persistenceError"] = err
@@ -9,6 +9,8 @@ type BindingRequest struct {
 	ServiceID  string                 `json:"service_id"`
 	PlanID     string                 `json:"plan_id"`
 	Parameters map[string]interface{} `json:"parameters"`
+	// Context is contextual data about the request provided by the platform
+	Context map[string]interface{} `json:"context,omitempty"`
 }
 
 // NewBindingRequestFromJSON returns a new BindingRequest unmarshaled from the
@@ -1,10 +1,63 @@
 package api
 
-import "net/http"
+import (
+	"encoding/json"
+	"net/http"
+
+	brokerLog "github.com/Azure/open-service-broker-azure/pkg/log"
+	"github.com/Azure/open-service-broker-azure/pkg/service"
+	log "github.com/Sirupsen/logrus"
+)
 
 func (s *server) getCatalog(
 	w http.ResponseWriter,
-	_ *http.Request,
+	r *http.Request,
 ) {
-	s.writeResponse(w, http.StatusOK, s.catalogResponse)
+	var catalogJSON []byte
+	var err error
+	if entitlements := getEntitlements(r); entitlements == nil {
+		catalogJSON, err = s.getCatalogResponse()
+	} else {
+		catalogJSON, err = json.Marshal(filterCatalog(s.catalog, entitlements))
+	}
+	if err != nil {
+		logFields := brokerLog.FieldsFromContext(r.Context())
+		logFields["error"] = err
+		log.WithFields(logFields).Error("error marshaling catalog")
+		s.writeResponse(w, http.StatusInternalServerError, generateEmptyResponse())
+		return
+	}
+	s.writeResponse(w, http.StatusOK, catalogJSON)
+}
+
+// catalogResponse is the marshaled form of a catalog
+type catalogResponse struct {
+	catalog service.Catalog
+	json    []byte
+}
+
+// getCatalogResponse returns the marshaled form of the catalog currently in
+// effect. Because the catalog may be reloaded at any time, the marshaled form
+// is cached only for as long as the catalog it was marshaled from remains in
+// effect.
+func (s *server) getCatalogResponse() ([]byte, error) {
+	catalog := s.catalog
+	if reloadableCatalog, ok := catalog.(service.ReloadableCatalog); ok {
+		catalog = reloadableCatalog.GetCatalog()
+	}
+	cached, ok := s.catalogResponse.Load().(*catalogResponse)
+	if ok && cached.catalog == catalog {
+		return cached.json, nil
+	}
+	catalogJSON, err := json.Marshal(catalog)
+	if err != nil {
+		return nil, err
+	}
+	s.catalogResponse.Store(
+		&catalogResponse{
+			catalog: catalog,
+			json:    catalogJSON,
+		},
+	)
+	return catalogJSON, nil
 }
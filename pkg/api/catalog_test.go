@@ -0,0 +1,35 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Azure/open-service-broker-azure/pkg/service"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCatalogReflectsReload(t *testing.T) {
+	s, _, err := getTestServer()
+	assert.Nil(t, err)
+	catalog := service.NewReloadableCatalog(s.catalog)
+	s.catalog = catalog
+	assert.Len(t, getCatalogServices(t, s), 1)
+	catalog.SetCatalog(service.NewCatalog(nil))
+	assert.Len(t, getCatalogServices(t, s), 0)
+}
+
+func getCatalogServices(t *testing.T, s *server) []interface{} {
+	req, err := http.NewRequest(http.MethodGet, "/v2/catalog", nil)
+	assert.Nil(t, err)
+	rr := httptest.NewRecorder()
+	s.router.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusOK, rr.Code)
+	catalog := struct {
+		Services []interface{} `json:"services"`
+	}{}
+	err = json.Unmarshal(rr.Body.Bytes(), &catalog)
+	assert.Nil(t, err)
+	return catalog.Services
+}
@@ -1,6 +1,9 @@
 package api
 
 import (
+	"errors"
+	"io"
+	"io/ioutil"
 	"net/http"
 
 	log "github.com/Sirupsen/logrus"
@@ -19,3 +22,33 @@ func (s *server) writeResponse(
 		)
 	}
 }
+
+// errRequestBodyTooLarge is returned by readRequestBody when a request body
+// exceeds the configured maximum size
+var errRequestBodyTooLarge = errors.New("request body too large")
+
+// readRequestBody reads the body of the given request, refusing to read more
+// than the configured maximum number of bytes. If the body is too large, the
+// error returned can be identified using isRequestBodyTooLarge().
+func (s *server) readRequestBody(r *http.Request) ([]byte, error) {
+	maxBytes := s.apiServerConfig.MaxRequestBodyBytes
+	if maxBytes <= 0 {
+		return ioutil.ReadAll(r.Body)
+	}
+	// Read one byte more than the maximum so that a body of exactly the
+	// maximum size can be distinguished from one that is too large
+	bodyBytes, err := ioutil.ReadAll(io.LimitReader(r.Body, maxBytes+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(bodyBytes)) > maxBytes {
+		return nil, errRequestBodyTooLarge
+	}
+	return bodyBytes, nil
+}
+
+// isRequestBodyTooLarge returns a bool indicating whether the given error
+// indicates that a request body exceeded the configured maximum size
+func isRequestBodyTooLarge(err error) bool {
+	return err == errRequestBodyTooLarge
+}
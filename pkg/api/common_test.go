@@ -58,6 +58,10 @@ func getTestServer() (*server, *fake.Module, error) {
 		fakeAsync.NewEngine(),
 		filter.NewChain(),
 		fakeCatalog,
+		nil,
+		nil,
+		nil,
+		nil,
 	)
 	if err != nil {
 		return nil, nil, err
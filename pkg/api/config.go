@@ -1,6 +1,12 @@
 package api
 
 import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io/ioutil"
+	"time"
+
 	"github.com/kelseyhightower/envconfig"
 )
 
@@ -11,13 +17,43 @@ type Config struct {
 	Port        int    `envconfig:"PORT"`
 	TLSCertPath string `envconfig:"TLS_CERT_PATH"`
 	TLSKeyPath  string `envconfig:"TLS_KEY_PATH"`
+	// TLSReloadInterval is how often the TLS certificate and key are checked
+	// for changes. Zero disables reloading.
+	TLSReloadInterval time.Duration `envconfig:"TLS_RELOAD_INTERVAL"`
+	// TLSClientCAPath is the path to a PEM encoded bundle of CA certificates.
+	// When set, clients must present a certificate signed by one of these CAs
+	// to use the OSB API. Health and metrics endpoints don't require one.
+	TLSClientCAPath string `envconfig:"TLS_CLIENT_CA_PATH"`
+	// TLSClientAllowedSubjects optionally restricts which client certificates
+	// are accepted to those whose subject, common name or one of whose subject
+	// alternative names is listed
+	TLSClientAllowedSubjects []string `envconfig:"TLS_CLIENT_ALLOWED_SUBJECTS"`
+	// MaxRequestBodyBytes is the largest request body, in bytes, that will be
+	// read by the provision, update and bind handlers
+	MaxRequestBodyBytes int64 `envconfig:"MAX_REQUEST_BODY_BYTES"`
+	// RateLimit is the sustained number of requests per second permitted for
+	// each client on each route. Zero disables rate limiting.
+	RateLimit float64 `envconfig:"RATE_LIMIT"`
+	// RateLimitBurst is the number of requests each client may make on each
+	// route in excess of the sustained rate
+	RateLimitBurst int `envconfig:"RATE_LIMIT_BURST"`
+	// HealthCheckTimeout is how long the readiness endpoint waits for each of
+	// the broker's dependencies to be checked before reporting it unhealthy
+	HealthCheckTimeout time.Duration `envconfig:"HEALTH_CHECK_TIMEOUT"`
 }
 
 // NewConfigWithDefaults returns a Config object with default values already
 // applied. Callers are then free to set custom values for the remaining fields
 // and/or override default values.
 func NewConfigWithDefaults() Config {
-	return Config{Port: 8080}
+	return Config{
+		Port:                8080,
+		TLSReloadInterval:   30 * time.Second,
+		MaxRequestBodyBytes: 1024 * 1024,
+		RateLimit:           10,
+		RateLimitBurst:      20,
+		HealthCheckTimeout:  5 * time.Second,
+	}
 }
 
 // GetConfigFromEnvironment returns configuration derived from environment
@@ -27,3 +63,30 @@ func GetConfigFromEnvironment() (Config, error) {
 	err := envconfig.Process(envconfigPrefix, &c)
 	return c, err
 }
+
+// GetTLSConfig returns TLS configuration for the API server. If a client CA
+// bundle has been configured, the returned configuration verifies that any
+// certificate a client presents is signed by one of those CAs. Otherwise, nil
+// is returned. A certificate is not required during the handshake so that
+// health probes and metrics scrapers can connect without one. Requiring a
+// certificate for the OSB API is left to the client certificate filter.
+func (c Config) GetTLSConfig() (*tls.Config, error) {
+	if c.TLSClientCAPath == "" {
+		return nil, nil
+	}
+	caPEM, err := ioutil.ReadFile(c.TLSClientCAPath)
+	if err != nil {
+		return nil, fmt.Errorf("error reading client CA bundle: %s", err)
+	}
+	clientCAs := x509.NewCertPool()
+	if !clientCAs.AppendCertsFromPEM(caPEM) {
+		return nil, fmt.Errorf(
+			`no certificates found in client CA bundle "%s"`,
+			c.TLSClientCAPath,
+		)
+	}
+	return &tls.Config{
+		ClientCAs:  clientCAs,
+		ClientAuth: tls.VerifyClientCertIfGiven,
+	}, nil
+}
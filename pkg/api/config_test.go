@@ -0,0 +1,132 @@
+package api
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io/ioutil"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/Azure/open-service-broker-azure/pkg/http/filter"
+	"github.com/Azure/open-service-broker-azure/pkg/http/filters"
+	"github.com/Azure/open-service-broker-azure/pkg/services/fake"
+	memoryStorage "github.com/Azure/open-service-broker-azure/pkg/storage/memory"
+	fakeAsync "github.com/deis/async/fake"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetTLSConfigWithoutClientCA(t *testing.T) {
+	tlsConfig, err := NewConfigWithDefaults().GetTLSConfig()
+	assert.Nil(t, err)
+	assert.Nil(t, tlsConfig)
+}
+
+func TestGetTLSConfigWithClientCA(t *testing.T) {
+	caFile, err := ioutil.TempFile("", "client-ca")
+	assert.Nil(t, err)
+	defer os.Remove(caFile.Name())
+	_, err = caFile.Write(getTestCAPEM(t))
+	assert.Nil(t, err)
+	assert.Nil(t, caFile.Close())
+	c := NewConfigWithDefaults()
+	c.TLSClientCAPath = caFile.Name()
+	tlsConfig, err := c.GetTLSConfig()
+	assert.Nil(t, err)
+	assert.Equal(t, tls.VerifyClientCertIfGiven, tlsConfig.ClientAuth)
+	assert.NotNil(t, tlsConfig.ClientCAs)
+}
+
+func TestGetTLSConfigWithInvalidClientCA(t *testing.T) {
+	caFile, err := ioutil.TempFile("", "client-ca")
+	assert.Nil(t, err)
+	defer os.Remove(caFile.Name())
+	assert.Nil(t, caFile.Close())
+	c := NewConfigWithDefaults()
+	c.TLSClientCAPath = caFile.Name()
+	_, err = c.GetTLSConfig()
+	assert.NotNil(t, err)
+}
+
+func TestClientCertOnlyRequiredByOSBRoutes(t *testing.T) {
+	caFile, err := ioutil.TempFile("", "client-ca")
+	assert.Nil(t, err)
+	defer os.Remove(caFile.Name())
+	_, err = caFile.Write(getTestCAPEM(t))
+	assert.Nil(t, err)
+	assert.Nil(t, caFile.Close())
+	c := NewConfigWithDefaults()
+	c.TLSClientCAPath = caFile.Name()
+	tlsConfig, err := c.GetTLSConfig()
+	assert.Nil(t, err)
+	fakeModule, err := fake.New()
+	assert.Nil(t, err)
+	fakeCatalog, err := fakeModule.GetCatalog()
+	assert.Nil(t, err)
+	s, err := NewServer(
+		c,
+		memoryStorage.NewStore(fakeCatalog),
+		fakeAsync.NewEngine(),
+		filter.NewChain(filters.NewClientCertFilter(nil)),
+		fakeCatalog,
+		http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(http.StatusOK)
+		}),
+		nil,
+		nil,
+		nil,
+	)
+	assert.Nil(t, err)
+	svr := httptest.NewUnstartedServer(s.(*server).router)
+	svr.TLS = tlsConfig
+	svr.StartTLS()
+	defer svr.Close()
+	// A client without a certificate, like a kubelet probe or a metrics
+	// scraper, completes the handshake and can reach health and metrics
+	// endpoints, but not the OSB API
+	client := svr.Client()
+	for path, expectedStatusCode := range map[string]int{
+		"/healthz/live": http.StatusOK,
+		"/metrics":      http.StatusOK,
+		"/v2/catalog":   http.StatusUnauthorized,
+	} {
+		var resp *http.Response
+		resp, err = client.Get(svr.URL + path)
+		assert.Nil(t, err, path)
+		if err == nil {
+			resp.Body.Close() // nolint: errcheck
+			assert.Equal(t, expectedStatusCode, resp.StatusCode, path)
+		}
+	}
+}
+
+func getTestCAPEM(t *testing.T) []byte {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.Nil(t, err)
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "test-ca"},
+		NotBefore:             time.Now(),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	}
+	certDER, err := x509.CreateCertificate(
+		rand.Reader,
+		template,
+		template,
+		&key.PublicKey,
+		key,
+	)
+	assert.Nil(t, err)
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: certDER})
+}
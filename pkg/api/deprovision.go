@@ -6,7 +6,10 @@ import (
 	"strconv"
 	"time"
 
+	"github.com/Azure/open-service-broker-azure/pkg/audit"
+	brokerLog "github.com/Azure/open-service-broker-azure/pkg/log"
 	"github.com/Azure/open-service-broker-azure/pkg/service"
+	"github.com/Azure/open-service-broker-azure/pkg/tracing"
 	log "github.com/Sirupsen/logrus"
 	"github.com/deis/async"
 	"github.com/gorilla/mux"
@@ -15,9 +18,8 @@ import (
 func (s *server) deprovision(w http.ResponseWriter, r *http.Request) {
 	instanceID := mux.Vars(r)["instance_id"]
 
-	logFields := log.Fields{
-		"instanceID": instanceID,
-	}
+	logFields := brokerLog.FieldsFromContext(r.Context())
+	logFields["instanceID"] = instanceID
 
 	log.WithFields(logFields).Debug("received deprovisioning request")
 
@@ -69,6 +71,16 @@ func (s *server) deprovision(w http.ResponseWriter, r *http.Request) {
 		s.writeResponse(w, http.StatusGone, generateEmptyResponse())
 		return
 	}
+	logFields["serviceID"] = instance.ServiceID
+	logFields["planID"] = instance.PlanID
+	annotateAuditEvent(r, instance.ServiceID, instance.PlanID)
+	if !isEntitled(r, instance.ServiceID, instance.PlanID) {
+		log.WithFields(logFields).Debug(
+			"bad deprovisioning request: not entitled to the service and plan",
+		)
+		s.writeResponse(w, http.StatusForbidden, generateForbiddenResponse())
+		return
+	}
 	if instance.Details == nil {
 		// If we get to here, we're dealing with an orphan -- the instance
 		// detail is nil for some reason. We simply delete the record from
@@ -124,8 +136,6 @@ func (s *server) deprovision(w http.ResponseWriter, r *http.Request) {
 
 	deprovisioner, err := serviceManager.GetDeprovisioner(instance.Plan)
 	if err != nil {
-		logFields["serviceID"] = instance.ServiceID
-		logFields["planID"] = instance.PlanID
 		logFields["error"] = err
 		log.WithFields(logFields).Error(
 			"pre-deprovisioning error: error retrieving deprovisioner for service " +
@@ -136,8 +146,6 @@ func (s *server) deprovision(w http.ResponseWriter, r *http.Request) {
 	}
 	firstStepName, ok := deprovisioner.GetFirstStepName()
 	if !ok {
-		logFields["serviceID"] = instance.ServiceID
-		logFields["planID"] = instance.PlanID
 		log.WithFields(logFields).Error(
 			"pre-deprovisioning error: no steps found for deprovisioning service " +
 				"and plan",
@@ -146,6 +154,7 @@ func (s *server) deprovision(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	instance.Step = firstStepName
 	var task async.Task
 	if childCount, err :=
 		s.store.GetInstanceChildCountByAlias(instance.Alias); err != nil {
@@ -190,6 +199,9 @@ func (s *server) deprovision(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	brokerLog.InjectIntoTaskArgs(r.Context(), task.GetArgs())
+	audit.InjectIntoTaskArgs(r.Context(), task.GetArgs())
+	tracing.InjectIntoTaskArgs(r.Context(), task.GetArgs())
 	if err = s.asyncEngine.SubmitTask(task); err != nil {
 		logFields["step"] = firstStepName
 		logFields["error"] = err
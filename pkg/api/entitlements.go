@@ -0,0 +1,53 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/Azure/open-service-broker-azure/pkg/identity"
+	"github.com/Azure/open-service-broker-azure/pkg/service"
+)
+
+// getEntitlements returns the entitlements of the principal making the given
+// request. If the principal is not restricted, nil is returned.
+func getEntitlements(r *http.Request) identity.Entitlements {
+	id, ok := identity.FromContext(r.Context())
+	if !ok {
+		return nil
+	}
+	return id.Entitlements
+}
+
+// isEntitled returns a bool indicating whether the principal making the given
+// request is entitled to use the given plan of the given service
+func isEntitled(r *http.Request, serviceID, planID string) bool {
+	return getEntitlements(r).AllowsPlan(serviceID, planID)
+}
+
+// filterCatalog returns a catalog containing only those services and plans
+// from the given catalog that the given entitlements permit
+func filterCatalog(
+	catalog service.Catalog,
+	entitlements identity.Entitlements,
+) service.Catalog {
+	services := []service.Service{}
+	for _, svc := range catalog.GetServices() {
+		if !entitlements.AllowsService(svc.GetID()) {
+			continue
+		}
+		plans := []service.Plan{}
+		for _, plan := range svc.GetPlans() {
+			if entitlements.AllowsPlan(svc.GetID(), plan.GetID()) {
+				plans = append(plans, plan)
+			}
+		}
+		services = append(
+			services,
+			service.NewService(
+				svc.GetProperties(),
+				svc.GetServiceManager(),
+				plans...,
+			),
+		)
+	}
+	return service.NewCatalog(services)
+}
@@ -0,0 +1,85 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Azure/open-service-broker-azure/pkg/identity"
+	"github.com/Azure/open-service-broker-azure/pkg/services/fake"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCatalogFilteredByEntitlements(t *testing.T) {
+	s, _, err := getTestServer()
+	assert.Nil(t, err)
+	testCases := []struct {
+		entitlements     identity.Entitlements
+		expectedServices int
+		expectedPlans    int
+	}{
+		{nil, 1, len(s.catalog.GetServices()[0].GetPlans())},
+		{identity.Entitlements{"foo": nil}, 0, 0},
+		{identity.Entitlements{fake.ServiceID: {fake.StandardPlanID}}, 1, 1},
+	}
+	for _, testCase := range testCases {
+		req, err := http.NewRequest(http.MethodGet, "/v2/catalog", nil)
+		assert.Nil(t, err)
+		req = withTestIdentity(req, testCase.entitlements)
+		rr := httptest.NewRecorder()
+		s.router.ServeHTTP(rr, req)
+		assert.Equal(t, http.StatusOK, rr.Code)
+		catalog := struct {
+			Services []struct {
+				Plans []interface{} `json:"plans"`
+			} `json:"services"`
+		}{}
+		err = json.Unmarshal(rr.Body.Bytes(), &catalog)
+		assert.Nil(t, err)
+		assert.Len(t, catalog.Services, testCase.expectedServices)
+		if testCase.expectedServices > 0 {
+			assert.Len(t, catalog.Services[0].Plans, testCase.expectedPlans)
+		}
+	}
+}
+
+func TestProvisioningWithPlanNotEntitled(t *testing.T) {
+	s, _, err := getTestServer()
+	assert.Nil(t, err)
+	req, err := getProvisionRequest(
+		getDisposableInstanceID(),
+		map[string]string{
+			"accepts_incomplete": "true",
+		},
+		&ProvisioningRequest{
+			ServiceID: fake.ServiceID,
+			PlanID:    fake.StandardPlanID,
+		},
+	)
+	assert.Nil(t, err)
+	req = withTestIdentity(
+		req,
+		identity.Entitlements{fake.ServiceID: {"some-other-plan"}},
+	)
+	rr := httptest.NewRecorder()
+	s.router.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusForbidden, rr.Code)
+	assert.Equal(t, responseForbidden, rr.Body.Bytes())
+}
+
+func withTestIdentity(
+	req *http.Request,
+	entitlements identity.Entitlements,
+) *http.Request {
+	return req.WithContext(
+		identity.NewContext(
+			req.Context(),
+			identity.Identity{
+				Scheme:       "Basic",
+				Name:         "test",
+				Entitlements: entitlements,
+			},
+		),
+	)
+}
@@ -0,0 +1,36 @@
+package filters
+
+import (
+	"net/http"
+
+	"github.com/Azure/open-service-broker-azure/pkg/http/filter"
+	brokerLog "github.com/Azure/open-service-broker-azure/pkg/log"
+	uuid "github.com/satori/go.uuid"
+)
+
+const requestIdentityHeader = "X-Broker-API-Request-Identity"
+
+// NewRequestIdentityFilter returns an implementation of the filter.Filter
+// interface that identifies each request using the value of its
+// X-Broker-API-Request-Identity header or, if that is absent, a newly
+// generated ID. The ID is carried on the request's context as a log field so
+// that all log entries pertaining to the request can be correlated, and is
+// echoed in the response's X-Broker-API-Request-Identity header.
+func NewRequestIdentityFilter() filter.Filter {
+	return filter.NewGenericFilter(
+		func(handle http.HandlerFunc) http.HandlerFunc {
+			return func(w http.ResponseWriter, r *http.Request) {
+				requestID := r.Header.Get(requestIdentityHeader)
+				if requestID == "" {
+					requestID = uuid.NewV4().String()
+				}
+				w.Header().Set(requestIdentityHeader, requestID)
+				// Call the original handler
+				handle(
+					w,
+					r.WithContext(brokerLog.NewRequestContext(r.Context(), requestID)),
+				)
+			}
+		},
+	)
+}
@@ -0,0 +1,39 @@
+package filters
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	brokerLog "github.com/Azure/open-service-broker-azure/pkg/log"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRequestIdentityFilterWithHeaderMissing(t *testing.T) {
+	req, err := http.NewRequest(http.MethodGet, "/", nil)
+	assert.Nil(t, err)
+	rr := httptest.NewRecorder()
+	var requestID string
+	NewRequestIdentityFilter().GetHandler(
+		func(_ http.ResponseWriter, r *http.Request) {
+			requestID, _ = brokerLog.RequestIDFromContext(r.Context())
+		},
+	)(rr, req)
+	assert.NotEmpty(t, requestID)
+	assert.Equal(t, requestID, rr.Header().Get("X-Broker-API-Request-Identity"))
+}
+
+func TestRequestIdentityFilterWithHeaderPresent(t *testing.T) {
+	req, err := http.NewRequest(http.MethodGet, "/", nil)
+	assert.Nil(t, err)
+	req.Header.Add("X-Broker-API-Request-Identity", "foo")
+	rr := httptest.NewRecorder()
+	var requestID string
+	NewRequestIdentityFilter().GetHandler(
+		func(_ http.ResponseWriter, r *http.Request) {
+			requestID, _ = brokerLog.RequestIDFromContext(r.Context())
+		},
+	)(rr, req)
+	assert.Equal(t, "foo", requestID)
+	assert.Equal(t, "foo", rr.Header().Get("X-Broker-API-Request-Identity"))
+}
@@ -1,14 +1,52 @@
 package api
 
-import "net/http"
+import (
+	"context"
+	"encoding/json"
+	"net/http"
 
-func (s *server) healthCheck(
+	"github.com/Azure/open-service-broker-azure/pkg/health"
+)
+
+// liveness reports whether the API server is able to respond to requests. It
+// deliberately does not check any of the components the broker depends upon,
+// since restarting the broker won't remedy problems with those.
+func (s *server) liveness(
 	w http.ResponseWriter,
 	_ *http.Request,
 ) {
-	if err := s.store.TestConnection(); err != nil {
+	s.writeResponse(w, http.StatusOK, responseEmptyJSON)
+}
+
+// readiness reports whether the broker is able to do useful work by checking
+// each of the components it depends upon
+func (s *server) readiness(
+	w http.ResponseWriter,
+	r *http.Request,
+) {
+	checks := append(
+		[]health.Check{
+			health.NewCheck("store", s.checkStore),
+		},
+		s.healthChecks...,
+	)
+	report := health.Run(
+		r.Context(),
+		checks,
+		s.apiServerConfig.HealthCheckTimeout,
+	)
+	reportJSON, err := json.Marshal(report)
+	if err != nil {
 		s.writeResponse(w, http.StatusInternalServerError, responseEmptyJSON)
 		return
 	}
-	s.writeResponse(w, http.StatusOK, responseEmptyJSON)
+	if !report.Healthy {
+		s.writeResponse(w, http.StatusServiceUnavailable, reportJSON)
+		return
+	}
+	s.writeResponse(w, http.StatusOK, reportJSON)
+}
+
+func (s *server) checkStore(ctx context.Context) error {
+	return s.store.TestConnection(ctx)
 }
@@ -1,10 +1,14 @@
 package api
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
 	"net/http"
 	"net/http/httptest"
 	"testing"
 
+	"github.com/Azure/open-service-broker-azure/pkg/health"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -18,6 +22,59 @@ func TestHealthEndpoint(t *testing.T) {
 	assert.Equal(t, http.StatusOK, rr.Code)
 }
 
+func TestLivenessEndpointIgnoresUnhealthyComponents(t *testing.T) {
+	s, _, err := getTestServer()
+	assert.Nil(t, err)
+	s.healthChecks = []health.Check{getUnhealthyCheck()}
+	req, err := http.NewRequest(http.MethodGet, "/healthz/live", nil)
+	assert.Nil(t, err)
+	rr := httptest.NewRecorder()
+	s.router.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusOK, rr.Code)
+}
+
+func TestReadinessEndpointWithHealthyComponents(t *testing.T) {
+	s, _, err := getTestServer()
+	assert.Nil(t, err)
+	req, err := http.NewRequest(http.MethodGet, "/healthz/ready", nil)
+	assert.Nil(t, err)
+	rr := httptest.NewRecorder()
+	s.router.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusOK, rr.Code)
+	report := health.Report{}
+	err = json.Unmarshal(rr.Body.Bytes(), &report)
+	assert.Nil(t, err)
+	assert.True(t, report.Healthy)
+	assert.Len(t, report.Components, 1)
+	assert.Equal(t, "store", report.Components[0].Name)
+}
+
+func TestReadinessEndpointWithUnhealthyComponent(t *testing.T) {
+	s, _, err := getTestServer()
+	assert.Nil(t, err)
+	s.healthChecks = []health.Check{getUnhealthyCheck()}
+	req, err := http.NewRequest(http.MethodGet, "/healthz/ready", nil)
+	assert.Nil(t, err)
+	rr := httptest.NewRecorder()
+	s.router.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusServiceUnavailable, rr.Code)
+	report := health.Report{}
+	err = json.Unmarshal(rr.Body.Bytes(), &report)
+	assert.Nil(t, err)
+	assert.False(t, report.Healthy)
+	assert.Len(t, report.Components, 2)
+	assert.True(t, report.Components[0].Healthy)
+	assert.Equal(t, "azure", report.Components[1].Name)
+	assert.False(t, report.Components[1].Healthy)
+	assert.Equal(t, "token expired", report.Components[1].Error)
+}
+
 func getHealthRequest() (*http.Request, error) {
 	return http.NewRequest(http.MethodGet, "/healthz", nil)
 }
+
+func getUnhealthyCheck() health.Check {
+	return health.NewCheck("azure", func(context.Context) error {
+		return errors.New("token expired")
+	})
+}
@@ -0,0 +1,72 @@
+package api
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/kelseyhightower/envconfig"
+)
+
+const jwtAuthEnvconfigPrefix = "JWT_AUTH"
+
+// JWTAuthConfig represents configuration options for authenticating requests
+// to the broker using JWT bearer tokens
+type JWTAuthConfig struct {
+	// Issuer is the issuer that tokens must have been issued by
+	Issuer string `envconfig:"ISSUER"`
+	// Audience is the audience that tokens must have been issued for
+	Audience string `envconfig:"AUDIENCE"`
+	// JWKSURL is the URL of the JWKS document containing the keys that tokens
+	// may be signed with. If neither it nor JWKSPath is specified, the URL is
+	// determined using OpenID Connect discovery.
+	JWKSURL string `envconfig:"JWKS_URL"`
+	// JWKSPath is the path to a file containing the JWKS document containing
+	// the keys that tokens may be signed with
+	JWKSPath string `envconfig:"JWKS_PATH"`
+	// JWKSRefreshInterval is how often keys fetched from JWKSURL are refreshed
+	JWKSRefreshInterval time.Duration `envconfig:"JWKS_REFRESH_INTERVAL"`
+	// ClockSkew is the tolerance allowed when checking whether a token has
+	// expired or is not yet valid
+	ClockSkew time.Duration `envconfig:"CLOCK_SKEW"`
+}
+
+// NewJWTAuthConfigWithDefaults returns a JWTAuthConfig object with default
+// values already applied. Callers are then free to set custom values for the
+// remaining fields and/or override default values.
+func NewJWTAuthConfigWithDefaults() JWTAuthConfig {
+	return JWTAuthConfig{
+		JWKSRefreshInterval: time.Hour,
+		ClockSkew:           time.Minute,
+	}
+}
+
+// GetJWTAuthConfigFromEnvironment returns JWT authentication configuration
+// derived from environment variables
+func GetJWTAuthConfigFromEnvironment() (JWTAuthConfig, error) {
+	c := NewJWTAuthConfigWithDefaults()
+	err := envconfig.Process(jwtAuthEnvconfigPrefix, &c)
+	if err != nil {
+		return c, err
+	}
+	if c.Issuer == "" {
+		return c, fmt.Errorf(
+			"environment variable %s_ISSUER was not specified",
+			jwtAuthEnvconfigPrefix,
+		)
+	}
+	if c.Audience == "" {
+		return c, fmt.Errorf(
+			"environment variable %s_AUDIENCE was not specified",
+			jwtAuthEnvconfigPrefix,
+		)
+	}
+	if c.JWKSURL != "" && c.JWKSPath != "" {
+		return c, fmt.Errorf(
+			"environment variables %s_JWKS_URL and %s_JWKS_PATH are mutually "+
+				"exclusive; please specify only one",
+			jwtAuthEnvconfigPrefix,
+			jwtAuthEnvconfigPrefix,
+		)
+	}
+	return c, nil
+}
@@ -0,0 +1,46 @@
+package api
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/Azure/open-service-broker-azure/pkg/metrics"
+	"github.com/gorilla/mux"
+)
+
+// statusRecorder is an http.ResponseWriter that remembers the status code
+// written to it
+type statusRecorder struct {
+	http.ResponseWriter
+	statusCode int
+}
+
+func (s *statusRecorder) WriteHeader(statusCode int) {
+	s.statusCode = statusCode
+	s.ResponseWriter.WriteHeader(statusCode)
+}
+
+// instrument is middleware that records the count and latency of requests
+// handled by each route
+func instrument(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		rec := &statusRecorder{
+			ResponseWriter: w,
+			statusCode:     http.StatusOK,
+		}
+		next.ServeHTTP(rec, r)
+		route := r.URL.Path
+		if currentRoute := mux.CurrentRoute(r); currentRoute != nil {
+			if tpl, err := currentRoute.GetPathTemplate(); err == nil {
+				route = tpl
+			}
+		}
+		metrics.RecordHTTPRequest(
+			route,
+			r.Method,
+			rec.statusCode,
+			time.Since(start),
+		)
+	})
+}
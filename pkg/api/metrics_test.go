@@ -0,0 +1,91 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Azure/open-service-broker-azure/pkg/http/filter"
+	"github.com/Azure/open-service-broker-azure/pkg/services/fake"
+	memoryStorage "github.com/Azure/open-service-broker-azure/pkg/storage/memory"
+	fakeAsync "github.com/deis/async/fake"
+	"github.com/stretchr/testify/assert"
+	"go.opencensus.io/stats/view"
+)
+
+func TestMetricsEndpointNotServedWithoutHandler(t *testing.T) {
+	s, _, err := getTestServer()
+	assert.Nil(t, err)
+	req, err := http.NewRequest(http.MethodGet, "/metrics", nil)
+	assert.Nil(t, err)
+	rr := httptest.NewRecorder()
+	s.router.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+}
+
+func TestMetricsEndpoint(t *testing.T) {
+	fakeModule, err := fake.New()
+	assert.Nil(t, err)
+	fakeCatalog, err := fakeModule.GetCatalog()
+	assert.Nil(t, err)
+	// The filter chain rejects everything to prove it isn't applied to
+	// the metrics endpoint
+	filterChain := filter.NewChain(
+		filter.NewGenericFilter(
+			func(http.HandlerFunc) http.HandlerFunc {
+				return func(w http.ResponseWriter, _ *http.Request) {
+					w.WriteHeader(http.StatusUnauthorized)
+				}
+			},
+		),
+	)
+	metricsHandlerCalled := false
+	s, err := NewServer(
+		NewConfigWithDefaults(),
+		memoryStorage.NewStore(fakeCatalog),
+		fakeAsync.NewEngine(),
+		filterChain,
+		fakeCatalog,
+		http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			metricsHandlerCalled = true
+			w.WriteHeader(http.StatusOK)
+		}),
+		nil,
+		nil,
+		nil,
+	)
+	assert.Nil(t, err)
+	req, err := http.NewRequest(http.MethodGet, "/metrics", nil)
+	assert.Nil(t, err)
+	rr := httptest.NewRecorder()
+	s.(*server).router.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.True(t, metricsHandlerCalled)
+}
+
+func TestRequestsAreInstrumented(t *testing.T) {
+	s, _, err := getTestServer()
+	assert.Nil(t, err)
+	req, err := http.NewRequest(
+		http.MethodGet,
+		"/v2/service_instances/"+getDisposableInstanceID()+"/last_operation",
+		nil,
+	)
+	assert.Nil(t, err)
+	rr := httptest.NewRecorder()
+	s.router.ServeHTTP(rr, req)
+	rows, err := view.RetrieveData("http_requests_total")
+	assert.Nil(t, err)
+	found := false
+	for _, row := range rows {
+		tags := map[string]string{}
+		for _, tag := range row.Tags {
+			tags[tag.Key.Name()] = tag.Value
+		}
+		if tags["route"] == "/v2/service_instances/{instance_id}/last_operation" &&
+			tags["method"] == http.MethodGet {
+			found = true
+		}
+	}
+	assert.True(t, found)
+}
@@ -0,0 +1,29 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/Azure/open-service-broker-azure/pkg/audit"
+	"github.com/Azure/open-service-broker-azure/pkg/identity"
+	"github.com/Azure/open-service-broker-azure/pkg/policy"
+)
+
+// evaluatePolicy evaluates the given input against the server's policy and
+// returns any violations. The identity of the principal making the given
+// request and the originating identity it conveys are added to the input.
+func (s *server) evaluatePolicy(
+	r *http.Request,
+	input policy.Input,
+) []policy.Violation {
+	if id, ok := identity.FromContext(r.Context()); ok {
+		input.IdentityScheme = id.Scheme
+		input.IdentityName = id.Name
+	}
+	if oi := audit.ParseOriginatingIdentity(
+		r.Header.Get(audit.OriginatingIdentityHeader),
+	); oi != nil {
+		input.OriginatingIdentityPlatform = oi.Platform
+		input.OriginatingIdentityValue = oi.Value
+	}
+	return s.policy.Evaluate(input)
+}
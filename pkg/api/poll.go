@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"net/http"
 
+	brokerLog "github.com/Azure/open-service-broker-azure/pkg/log"
 	"github.com/Azure/open-service-broker-azure/pkg/service"
 	log "github.com/Sirupsen/logrus"
 	"github.com/gorilla/mux"
@@ -15,9 +16,8 @@ func (s *server) poll(
 ) {
 	instanceID := mux.Vars(r)["instance_id"]
 
-	logFields := log.Fields{
-		"instanceID": instanceID,
-	}
+	logFields := brokerLog.FieldsFromContext(r.Context())
+	logFields["instanceID"] = instanceID
 
 	log.WithFields(logFields).Debug("received polling request")
 
@@ -66,6 +66,16 @@ func (s *server) poll(
 		s.writeResponse(w, http.StatusNotFound, generateEmptyResponse())
 		return
 	}
+	logFields["serviceID"] = instance.ServiceID
+	logFields["planID"] = instance.PlanID
+
+	if !isEntitled(r, instance.ServiceID, instance.PlanID) {
+		log.WithFields(logFields).Debug(
+			"bad polling request: not entitled to the service and plan",
+		)
+		s.writeResponse(w, http.StatusForbidden, generateForbiddenResponse())
+		return
+	}
 
 	logFields["status"] = instance.Status
 
@@ -1,14 +1,18 @@
 package api
 
 import (
+	"context"
 	"fmt"
-	"io/ioutil"
 	"net/http"
 	"reflect"
 	"strconv"
 	"time"
 
+	"github.com/Azure/open-service-broker-azure/pkg/audit"
+	brokerLog "github.com/Azure/open-service-broker-azure/pkg/log"
+	"github.com/Azure/open-service-broker-azure/pkg/policy"
 	"github.com/Azure/open-service-broker-azure/pkg/service"
+	"github.com/Azure/open-service-broker-azure/pkg/tracing"
 	log "github.com/Sirupsen/logrus"
 	"github.com/deis/async"
 	"github.com/gorilla/mux"
@@ -17,9 +21,8 @@ import (
 func (s *server) provision(w http.ResponseWriter, r *http.Request) {
 	instanceID := mux.Vars(r)["instance_id"]
 
-	logFields := log.Fields{
-		"instanceID": instanceID,
-	}
+	logFields := brokerLog.FieldsFromContext(r.Context())
+	logFields["instanceID"] = instanceID
 
 	log.WithFields(logFields).Debug("received provisioning request")
 
@@ -55,7 +58,19 @@ func (s *server) provision(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	bodyBytes, err := ioutil.ReadAll(r.Body)
+	bodyBytes, err := s.readRequestBody(r)
+	if isRequestBodyTooLarge(err) {
+		logFields["error"] = err
+		log.WithFields(logFields).Debug(
+			"bad provisioning request: request body too large",
+		)
+		s.writeResponse(
+			w,
+			http.StatusRequestEntityTooLarge,
+			generateRequestBodyTooLargeResponse(),
+		)
+		return
+	}
 	if err != nil {
 		logFields["error"] = err
 		log.WithFields(logFields).Error(
@@ -119,24 +134,42 @@ func (s *server) provision(w http.ResponseWriter, r *http.Request) {
 		s.writeResponse(w, http.StatusBadRequest, generateInvalidPlanIDResponse())
 		return
 	}
+	logFields["serviceID"] = serviceID
+	logFields["planID"] = planID
+	annotateAuditEvent(r, serviceID, planID)
+	annotateAuditEventParameters(
+		r,
+		plan.GetSchemas().ServiceInstances.ProvisioningParametersSchema,
+		provisioningRequest.Parameters,
+	)
+
+	if !isEntitled(r, serviceID, planID) {
+		log.WithFields(logFields).Debug(
+			"bad provisioning request: not entitled to the service and plan",
+		)
+		s.writeResponse(w, http.StatusForbidden, generateForbiddenResponse())
+		return
+	}
+
+	// Normalize the provisioning parameters (coercing values to the types called
+	// for by the schema and applying defaults) before validating them. It is
+	// the normalized parameters that are persisted.
+	pps := plan.GetSchemas().ServiceInstances.ProvisioningParametersSchema
+	provisioningRequest.Parameters = pps.Normalize(provisioningRequest.Parameters)
 
 	// Validate the provisioning parameters
-	if err :=
-		plan.GetSchemas().ServiceInstances.ProvisioningParametersSchema.Validate(
-			provisioningRequest.Parameters,
-		); err != nil {
-		var validationErr *service.ValidationError
-		validationErr, ok = err.(*service.ValidationError)
+	if err := pps.Validate(provisioningRequest.Parameters); err != nil {
+		var validationErrs service.ValidationErrors
+		validationErrs, ok = service.GetValidationErrors(err)
 		if ok {
-			logFields["field"] = validationErr.Field
-			logFields["issue"] = validationErr.Issue
+			logFields["validationErrors"] = validationErrs.Error()
 			log.WithFields(logFields).Debug(
 				"bad provisioning request: validation error",
 			)
 			s.writeResponse(
 				w,
 				http.StatusBadRequest,
-				generateValidationFailedResponse(validationErr),
+				generateValidationFailedResponse(validationErrs),
 			)
 			return
 		}
@@ -144,9 +177,33 @@ func (s *server) provision(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Evaluate the request against the operator's policy
+	if violations := s.evaluatePolicy(
+		r,
+		policy.Input{
+			Operation:   policy.OperationProvision,
+			ServiceID:   serviceID,
+			ServiceName: svc.GetName(),
+			PlanID:      planID,
+			PlanName:    plan.GetName(),
+			Parameters:  provisioningRequest.Parameters,
+			Context:     provisioningRequest.Context,
+		},
+	); len(violations) > 0 {
+		logFields["policyViolations"] = violations
+		log.WithFields(logFields).Debug(
+			"bad provisioning request: policy violation",
+		)
+		s.writeResponse(
+			w,
+			http.StatusBadRequest,
+			generatePolicyViolationResponse(violations),
+		)
+		return
+	}
+
 	// Wrap the provisioning parameters with a "params" object that guides access
 	// to the parameters using schema
-	pps := plan.GetSchemas().ServiceInstances.ProvisioningParametersSchema
 	provisioningParameters := &service.ProvisioningParameters{
 		Parameters: service.Parameters{
 			Schema: &pps,
@@ -179,11 +236,19 @@ func (s *server) provision(w http.ResponseWriter, r *http.Request) {
 		// current request.
 		//
 		// Two requests are the same if they are for the same serviceID, the same,
-		// planID, and all other relevant fields are equal.
+		// planID, and all other relevant fields are equal. The existing instance's
+		// parameters are normalized in case they were persisted before parameters
+		// were normalized upon receipt.
+		var existingParams map[string]interface{}
+		if instance.ProvisioningParameters != nil {
+			existingParams = instance.ProvisioningParameters.Data
+		}
 		if instance.ServiceID == serviceID &&
 			instance.PlanID == planID &&
-			((instance.ProvisioningParameters == nil && len(provisioningRequest.Parameters) == 0) || // nolint: lll
-				(instance.ProvisioningParameters != nil && reflect.DeepEqual(instance.ProvisioningParameters.Data, provisioningRequest.Parameters))) { // nolint: lll
+			reflect.DeepEqual(
+				pps.Normalize(existingParams),
+				provisioningRequest.Parameters,
+			) {
 			// Per the spec, if fully provisioned, respond with a 200, else a 202.
 			// Filling in a gap in the spec-- if the status is anything else, we'll
 			// choose to respond with a 409
@@ -210,6 +275,45 @@ func (s *server) provision(w http.ResponseWriter, r *http.Request) {
 
 	// If we get to here, we need to provision a new instance.
 
+	// Enforce the operator's quotas. Existing instances are only retrieved if
+	// there are quotas to enforce. This loads (and decrypts) every instance, and
+	// the lock only serializes requests handled by this replica of the broker,
+	// so limits are enforced on a best-effort basis when there are several.
+	tenant := getQuotaTenant(r, provisioningRequest.Context)
+	if !s.quotas.IsEmpty() {
+		s.quotaMutex.Lock()
+		defer s.quotaMutex.Unlock()
+		var instances []service.Instance
+		instances, err = s.store.GetInstances()
+		if err != nil {
+			logFields["error"] = err
+			log.WithFields(logFields).Error(
+				"pre-provisioning error: error retrieving instances to enforce quotas",
+			)
+			s.writeResponse(w, http.StatusInternalServerError, generateEmptyResponse())
+			return
+		}
+		exceeded, isExceeded := s.quotas.Check(
+			instances,
+			serviceID,
+			planID,
+			tenant,
+		)
+		if isExceeded {
+			logFields["quota"] = exceeded.Limit.Name
+			logFields["tenant"] = exceeded.Tenant
+			log.WithFields(logFields).Debug(
+				"bad provisioning request: quota exceeded",
+			)
+			s.writeResponse(
+				w,
+				http.StatusForbidden,
+				generateQuotaExceededResponse(exceeded),
+			)
+			return
+		}
+	}
+
 	serviceManager := svc.GetServiceManager()
 
 	provisioner, err := serviceManager.GetProvisioner(plan)
@@ -244,13 +348,16 @@ func (s *server) provision(w http.ResponseWriter, r *http.Request) {
 		PlanID:                 provisioningRequest.PlanID,
 		ProvisioningParameters: provisioningParameters,
 		Status:                 service.InstanceStateProvisioning,
+		Step:                   firstStepName,
 		ParentAlias:            parentAlias,
 		Created:                time.Now(),
+		Context:                provisioningRequest.Context,
+		Principal:              tenant.Principal,
 	}
 
 	var task async.Task
 	var waitForParent bool
-	if waitForParent, err = s.isParentProvisioning(instance); err != nil {
+	if waitForParent, err = s.isParentProvisioning(r.Context(), instance); err != nil {
 		logFields["error"] = err
 		log.WithFields(logFields).Error(
 			"provisioning error: error related to parent instance",
@@ -289,6 +396,9 @@ func (s *server) provision(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	brokerLog.InjectIntoTaskArgs(r.Context(), task.GetArgs())
+	audit.InjectIntoTaskArgs(r.Context(), task.GetArgs())
+	tracing.InjectIntoTaskArgs(r.Context(), task.GetArgs())
 	if err = s.asyncEngine.SubmitTask(task); err != nil {
 		logFields["step"] = firstStepName
 		logFields["error"] = err
@@ -305,7 +415,10 @@ func (s *server) provision(w http.ResponseWriter, r *http.Request) {
 	log.WithFields(logFields).Debug("asynchronous provisioning initiated")
 }
 
-func (s *server) isParentProvisioning(instance service.Instance) (bool, error) {
+func (s *server) isParentProvisioning(
+	ctx context.Context,
+	instance service.Instance,
+) (bool, error) {
 	//No parent, so no need to wait
 	if instance.ParentAlias == "" {
 		return false, nil
@@ -314,7 +427,7 @@ func (s *server) isParentProvisioning(instance service.Instance) (bool, error) {
 	parent, parentFound, err := s.store.GetInstanceByAlias(instance.ParentAlias)
 
 	if err != nil {
-		log.WithFields(log.Fields{
+		brokerLog.FromContext(ctx).WithFields(log.Fields{
 			"error":       "waitforParent",
 			"instanceID":  instance.InstanceID,
 			"parentAlias": instance.ParentAlias,
@@ -331,7 +444,7 @@ func (s *server) isParentProvisioning(instance service.Instance) (bool, error) {
 
 	//If parent failed, we should not even attempt to provision this
 	if parent.Status == service.InstanceStateProvisioningFailed {
-		log.WithFields(log.Fields{
+		brokerLog.FromContext(ctx).WithFields(log.Fields{
 			"error":      "waitforParent",
 			"instanceID": instance.InstanceID,
 			"parentID":   instance.Parent.InstanceID,
@@ -343,7 +456,7 @@ func (s *server) isParentProvisioning(instance service.Instance) (bool, error) {
 
 	//If parent is deprovisioning, we should not even attempt to provision this
 	if parent.Status == service.InstanceStateDeprovisioning {
-		log.WithFields(log.Fields{
+		brokerLog.FromContext(ctx).WithFields(log.Fields{
 			"error":      "waitforParent",
 			"instanceID": instance.InstanceID,
 			"parentID":   instance.Parent.InstanceID,
@@ -5,10 +5,16 @@ import (
 	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 
+	"github.com/Azure/open-service-broker-azure/pkg/policy"
+	"github.com/Azure/open-service-broker-azure/pkg/quota"
 	"github.com/Azure/open-service-broker-azure/pkg/service"
 	"github.com/Azure/open-service-broker-azure/pkg/services/fake"
+	"github.com/Azure/open-service-broker-azure/pkg/storage"
 	fakeAsync "github.com/deis/async/fake"
 	"github.com/stretchr/testify/assert"
 )
@@ -272,6 +278,8 @@ func TestValidatingParametersFails(t *testing.T) {
 				// Fake service/plan supports "someParameter" as a string.
 				// We'll provide a non-string value.
 				"someParameter": 42,
+				// Fake service/plan doesn't support this parameter at all
+				"bogusParameter": "foo",
 			},
 		},
 	)
@@ -282,14 +290,202 @@ func TestValidatingParametersFails(t *testing.T) {
 	rr := httptest.NewRecorder()
 	s.router.ServeHTTP(rr, req)
 	assert.Equal(t, http.StatusBadRequest, rr.Code)
-	locationError := service.NewValidationError(
-		"someParameter",
-		"field value is not of type string",
+	// Every validation error should be reported
+	responseError := generateValidationFailedResponse(
+		service.ValidationErrors{
+			{
+				Field:  "/bogusParameter",
+				Reason: service.ValidationReasonUnrecognized,
+				Issue:  "unrecognized field",
+			},
+			{
+				Field:  "/someParameter",
+				Reason: service.ValidationReasonType,
+				Issue:  "field value is not of type string",
+			},
+		},
 	)
-	responseError := generateValidationFailedResponse(locationError)
 	assert.Equal(t, responseError, rr.Body.Bytes())
 }
 
+func TestProvisioningViolatingPolicyFails(t *testing.T) {
+	s, _, err := getTestServer()
+	assert.Nil(t, err)
+	s.policy, err = policy.New(
+		&policy.Rule{
+			Name: "kubernetes-only",
+			Require: &policy.Condition{
+				Field:  "/context/platform",
+				Equals: "kubernetes",
+			},
+			Message: "instances may only be provisioned from kubernetes",
+		},
+	)
+	assert.Nil(t, err)
+	instanceID := getDisposableInstanceID()
+	req, err := getProvisionRequest(
+		instanceID,
+		map[string]string{
+			"accepts_incomplete": "true",
+		},
+		&ProvisioningRequest{
+			ServiceID: fake.ServiceID,
+			PlanID:    fake.StandardPlanID,
+			Context: map[string]interface{}{
+				"platform": "cloudfoundry",
+			},
+		},
+	)
+	assert.Nil(t, err)
+	e := s.asyncEngine.(*fakeAsync.Engine)
+	rr := httptest.NewRecorder()
+	s.router.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	assert.Empty(t, e.SubmittedTasks)
+	responseError := generatePolicyViolationResponse(
+		[]policy.Violation{
+			{
+				Rule:    "kubernetes-only",
+				Message: "instances may only be provisioned from kubernetes",
+			},
+		},
+	)
+	assert.Equal(t, responseError, rr.Body.Bytes())
+}
+
+func TestProvisioningExceedingQuotaFails(t *testing.T) {
+	s, _, err := getTestServer()
+	assert.Nil(t, err)
+	s.quotas = &quota.Quotas{
+		Limits: []quota.Limit{
+			{
+				Name:      "standard-per-namespace",
+				ServiceID: fake.ServiceID,
+				PlanID:    fake.StandardPlanID,
+				Key:       quota.KeyNamespace,
+				Max:       1,
+			},
+		},
+	}
+	provisioningContext := map[string]interface{}{
+		"platform":  "kubernetes",
+		"namespace": "team-a",
+	}
+	e := s.asyncEngine.(*fakeAsync.Engine)
+	// The first instance is within the quota
+	req, err := getProvisionRequest(
+		getDisposableInstanceID(),
+		map[string]string{
+			"accepts_incomplete": "true",
+		},
+		&ProvisioningRequest{
+			ServiceID: fake.ServiceID,
+			PlanID:    fake.StandardPlanID,
+			Context:   provisioningContext,
+		},
+	)
+	assert.Nil(t, err)
+	rr := httptest.NewRecorder()
+	s.router.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusAccepted, rr.Code)
+	assert.Equal(t, 1, len(e.SubmittedTasks))
+	// The second is not
+	req, err = getProvisionRequest(
+		getDisposableInstanceID(),
+		map[string]string{
+			"accepts_incomplete": "true",
+		},
+		&ProvisioningRequest{
+			ServiceID: fake.ServiceID,
+			PlanID:    fake.StandardPlanID,
+			Context:   provisioningContext,
+		},
+	)
+	assert.Nil(t, err)
+	rr = httptest.NewRecorder()
+	s.router.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusForbidden, rr.Code)
+	assert.Equal(t, 1, len(e.SubmittedTasks))
+	assert.Equal(
+		t,
+		generateQuotaExceededResponse(
+			quota.Exceeded{
+				Limit:  s.quotas.Limits[0],
+				Tenant: "team-a",
+				Used:   1,
+			},
+		),
+		rr.Body.Bytes(),
+	)
+}
+
+func TestConcurrentProvisioningDoesNotExceedQuota(t *testing.T) {
+	s, _, err := getTestServer()
+	assert.Nil(t, err)
+	s.quotas = &quota.Quotas{
+		Limits: []quota.Limit{
+			{
+				Name:      "standard-per-namespace",
+				ServiceID: fake.ServiceID,
+				PlanID:    fake.StandardPlanID,
+				Key:       quota.KeyNamespace,
+				Max:       1,
+			},
+		},
+	}
+	// Retrieving instances is slowed so that, were the requests not serialized,
+	// each would retrieve instances before any had written its own
+	s.store = slowGetInstancesStore{Store: s.store}
+	const requests = 10
+	codes := make(chan int, requests)
+	wg := sync.WaitGroup{}
+	for i := 0; i < requests; i++ {
+		req, err := getProvisionRequest(
+			getDisposableInstanceID(),
+			map[string]string{
+				"accepts_incomplete": "true",
+			},
+			&ProvisioningRequest{
+				ServiceID: fake.ServiceID,
+				PlanID:    fake.StandardPlanID,
+				Context: map[string]interface{}{
+					"platform":  "kubernetes",
+					"namespace": "team-a",
+				},
+			},
+		)
+		assert.Nil(t, err)
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			rr := httptest.NewRecorder()
+			s.router.ServeHTTP(rr, req)
+			codes <- rr.Code
+		}()
+	}
+	wg.Wait()
+	close(codes)
+	var accepted int
+	for code := range codes {
+		if code == http.StatusAccepted {
+			accepted++
+		} else {
+			assert.Equal(t, http.StatusForbidden, code)
+		}
+	}
+	assert.Equal(t, 1, accepted)
+}
+
+type slowGetInstancesStore struct {
+	storage.Store
+}
+
+func (s slowGetInstancesStore) GetInstances() ([]service.Instance, error) {
+	instances, err := s.Store.GetInstances()
+	time.Sleep(10 * time.Millisecond)
+	return instances, err
+}
+
 func TestKickOffNewAsyncProvisioning(t *testing.T) {
 	s, _, err := getTestServer()
 	assert.Nil(t, err)
@@ -315,6 +511,40 @@ func TestKickOffNewAsyncProvisioning(t *testing.T) {
 	assert.Equal(t, responseProvisioningAccepted, rr.Body.Bytes())
 }
 
+func TestProvisioningWithRequestBodyTooLarge(t *testing.T) {
+	s, _, err := getTestServer()
+	assert.Nil(t, err)
+	s.apiServerConfig.MaxRequestBodyBytes = 16
+	req, err := getProvisionRequest(
+		getDisposableInstanceID(),
+		map[string]string{
+			"accepts_incomplete": "true",
+		},
+		&ProvisioningRequest{
+			ServiceID: fake.ServiceID,
+			PlanID:    fake.StandardPlanID,
+		},
+	)
+	assert.Nil(t, err)
+	rr := httptest.NewRecorder()
+	s.router.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusRequestEntityTooLarge, rr.Code)
+	assert.Equal(t, responseRequestBodyTooLarge, rr.Body.Bytes())
+}
+
+func TestReadRequestBodyWithMaximumSize(t *testing.T) {
+	s, _, err := getTestServer()
+	assert.Nil(t, err)
+	s.apiServerConfig.MaxRequestBodyBytes = 4
+	req := httptest.NewRequest(http.MethodPut, "/", strings.NewReader("abcd"))
+	bodyBytes, err := s.readRequestBody(req)
+	assert.Nil(t, err)
+	assert.Equal(t, "abcd", string(bodyBytes))
+	req = httptest.NewRequest(http.MethodPut, "/", strings.NewReader("abcde"))
+	_, err = s.readRequestBody(req)
+	assert.True(t, isRequestBodyTooLarge(err))
+}
+
 func getProvisionRequest(
 	instanceID string,
 	queryParams map[string]string,
@@ -9,6 +9,8 @@ type ProvisioningRequest struct {
 	ServiceID  string                 `json:"service_id"`
 	PlanID     string                 `json:"plan_id"`
 	Parameters map[string]interface{} `json:"parameters"`
+	// Context is contextual data about the request provided by the platform
+	Context map[string]interface{} `json:"context,omitempty"`
 }
 
 // NewProvisioningRequestFromJSON returns a new ProvisioningRequest unmarshaled
@@ -0,0 +1,23 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/Azure/open-service-broker-azure/pkg/identity"
+	"github.com/Azure/open-service-broker-azure/pkg/quota"
+)
+
+// getQuotaTenant returns the tenant on whose behalf the given request, with
+// the given OSB context, is made
+func getQuotaTenant(
+	r *http.Request,
+	context map[string]interface{},
+) quota.Tenant {
+	tenant := quota.Tenant{
+		Context: context,
+	}
+	if id, ok := identity.FromContext(r.Context()); ok {
+		tenant.Principal = id.Name
+	}
+	return tenant
+}
@@ -3,7 +3,10 @@ package api
 import (
 	"encoding/json"
 	"fmt"
+	"strings"
 
+	"github.com/Azure/open-service-broker-azure/pkg/policy"
+	"github.com/Azure/open-service-broker-azure/pkg/quota"
 	"github.com/Azure/open-service-broker-azure/pkg/service"
 	log "github.com/Sirupsen/logrus"
 )
@@ -153,26 +156,37 @@ var validationFailedGenericResponse = []byte(
 		`"description" : "Failed to validate request" }`,
 )
 var responseValidationFailedTemplate = `The value provided for %s is ` +
-	`invalid: %s`
+	`invalid (%s): %s`
+
+type validationFailedResponse struct {
+	errorResponse
+	ValidationErrors service.ValidationErrors `json:"validationErrors"`
+}
 
 func generateValidationFailedResponse(
-	validationErr *service.ValidationError,
+	validationErrs service.ValidationErrors,
 ) []byte {
-
-	response := errorResponse{
-		Error: "ValidationError",
-		Description: fmt.Sprintf(
+	descriptions := make([]string, len(validationErrs))
+	for i, validationErr := range validationErrs {
+		descriptions[i] = fmt.Sprintf(
 			responseValidationFailedTemplate,
 			validationErr.Field,
+			validationErr.Reason,
 			validationErr.Issue,
-		),
+		)
+	}
+	response := validationFailedResponse{
+		errorResponse: errorResponse{
+			Error:       "ValidationError",
+			Description: strings.Join(descriptions, "; "),
+		},
+		ValidationErrors: validationErrs,
 	}
 	responseBody, err := json.Marshal(response)
 	if err != nil {
 		log.WithFields(
 			log.Fields{
-				"field": validationErr.Field,
-				"issue": validationErr.Issue,
+				"validationErrors": validationErrs.Error(),
 			},
 		).Error("Error generating validation error response")
 		// There was a failure marshalling the body, so return
@@ -182,6 +196,107 @@ func generateValidationFailedResponse(
 	return responseBody
 }
 
+var policyViolationGenericResponse = []byte(
+	`{ "error" : "PolicyViolation", ` +
+		`"description" : "The request violates the broker's policy" }`,
+)
+
+type policyViolationResponse struct {
+	errorResponse
+	Violations []policy.Violation `json:"violations"`
+}
+
+func generatePolicyViolationResponse(violations []policy.Violation) []byte {
+	messages := make([]string, len(violations))
+	for i, violation := range violations {
+		messages[i] = violation.Message
+	}
+	response := policyViolationResponse{
+		errorResponse: errorResponse{
+			Error:       "PolicyViolation",
+			Description: strings.Join(messages, "; "),
+		},
+		Violations: violations,
+	}
+	responseBody, err := json.Marshal(response)
+	if err != nil {
+		log.WithFields(
+			log.Fields{
+				"error": err,
+			},
+		).Error("Error generating policy violation response")
+		return policyViolationGenericResponse
+	}
+	return responseBody
+}
+
+var quotaExceededGenericResponse = []byte(
+	`{ "error" : "QuotaExceeded", ` +
+		`"description" : "The request would exceed a quota" }`,
+)
+
+// quotaKeyDescriptions describe the tenants identified by each quota key
+var quotaKeyDescriptions = map[string]string{
+	quota.KeyOrganization: "organization",
+	quota.KeySpace:        "space",
+	quota.KeyNamespace:    "namespace",
+	quota.KeyPrincipal:    "broker credential",
+}
+
+func generateQuotaExceededResponse(exceeded quota.Exceeded) []byte {
+	var scope string
+	switch {
+	case exceeded.Limit.PlanID != "":
+		scope = " of the requested plan"
+	case exceeded.Limit.ServiceID != "":
+		scope = " of the requested service"
+	}
+	tenantDescription := quotaKeyDescriptions[exceeded.Limit.Key]
+	responseBody, err := json.Marshal(
+		errorResponse{
+			Error: "QuotaExceeded",
+			Description: fmt.Sprintf(
+				`Quota "%s" permits each %s at most %d instance(s)%s, and %s "%s" `+
+					`already has %d`,
+				exceeded.Limit.Name,
+				tenantDescription,
+				exceeded.Limit.Max,
+				scope,
+				tenantDescription,
+				exceeded.Tenant,
+				exceeded.Used,
+			),
+		},
+	)
+	if err != nil {
+		log.WithFields(
+			log.Fields{
+				"error": err,
+			},
+		).Error("Error generating quota exceeded response")
+		return quotaExceededGenericResponse
+	}
+	return responseBody
+}
+
+var responseRequestBodyTooLarge = []byte(
+	`{ "error": "RequestBodyTooLarge", "description": "The request body ` +
+		`exceeded the maximum permitted size" }`,
+)
+
+func generateRequestBodyTooLargeResponse() []byte {
+	return responseRequestBodyTooLarge
+}
+
+var responseForbidden = []byte(
+	`{ "error": "Forbidden", "description": "The provided credentials are not ` +
+		`entitled to the requested service or plan" }`,
+)
+
+func generateForbiddenResponse() []byte {
+	return responseForbidden
+}
+
 var responseParentInvalid = []byte(
 	`{ "error": "InvalidParent", "description": "The parentAlias provided ` +
 		`refers to a service instance that failed to provision or is currently ` +
@@ -2,13 +2,20 @@ package api
 
 import (
 	"context"
-	"encoding/json"
+	"crypto/tls"
 	"fmt"
 	"net/http"
+	"sync"
+	"sync/atomic"
 	"time"
 
+	"github.com/Azure/open-service-broker-azure/pkg/audit"
+	"github.com/Azure/open-service-broker-azure/pkg/certs"
 	"github.com/Azure/open-service-broker-azure/pkg/file"
+	"github.com/Azure/open-service-broker-azure/pkg/health"
 	"github.com/Azure/open-service-broker-azure/pkg/http/filter"
+	"github.com/Azure/open-service-broker-azure/pkg/policy"
+	"github.com/Azure/open-service-broker-azure/pkg/quota"
 	"github.com/Azure/open-service-broker-azure/pkg/service"
 	"github.com/Azure/open-service-broker-azure/pkg/storage"
 	log "github.com/Sirupsen/logrus"
@@ -42,18 +49,34 @@ type server struct {
 	filterChain     filter.Filter
 	router          *mux.Router
 	catalog         service.Catalog
-	catalogResponse []byte
+	// catalogResponse holds a *catalogResponse
+	catalogResponse atomic.Value
+	healthChecks    []health.Check
+	policy          *policy.Policy
+	quotas          *quota.Quotas
+	// quotaMutex serializes the enforcement of quotas with the writing of the
+	// instances that count toward them so that concurrent requests handled by
+	// this server cannot, together, exceed a limit
+	quotaMutex sync.Mutex
 	// This allows tests to inject an alternative implementation of this function
 	listenAndServe func(context.Context) error
 }
 
-// NewServer returns an HTTP router
+// NewServer returns an HTTP router. If metricsHandler is non-nil, it is
+// served at /metrics. The given health checks are executed, along with a check
+// of the store, to determine the broker's readiness. Provisioning, updating,
+// and binding requests are evaluated against the given policy, if any, and
+// provisioning requests are subject to the given quotas, if any.
 func NewServer(
 	apiServerConfig Config,
 	store storage.Store,
 	asyncEngine async.Engine,
 	filterChain filter.Filter,
 	catalog service.Catalog,
+	metricsHandler http.Handler,
+	healthChecks []health.Check,
+	policy *policy.Policy,
+	quotas *quota.Quotas,
 ) (Server, error) {
 	s := &server{
 		apiServerConfig: apiServerConfig,
@@ -61,6 +84,9 @@ func NewServer(
 		asyncEngine:     asyncEngine,
 		filterChain:     filterChain,
 		catalog:         catalog,
+		healthChecks:    healthChecks,
+		policy:          policy,
+		quotas:          quotas,
 	}
 
 	router := mux.NewRouter()
@@ -71,11 +97,11 @@ func NewServer(
 	).Methods(http.MethodGet)
 	router.HandleFunc(
 		"/v2/service_instances/{instance_id}",
-		filterChain.GetHandler(s.provision),
+		filterChain.GetHandler(audited(audit.OperationProvision, s.provision)),
 	).Methods(http.MethodPut)
 	router.HandleFunc(
 		"/v2/service_instances/{instance_id}",
-		filterChain.GetHandler(s.update),
+		filterChain.GetHandler(audited(audit.OperationUpdate, s.update)),
 	).Methods(http.MethodPatch)
 	router.HandleFunc(
 		"/v2/service_instances/{instance_id}/last_operation",
@@ -83,27 +109,33 @@ func NewServer(
 	).Methods(http.MethodGet)
 	router.HandleFunc(
 		"/v2/service_instances/{instance_id}/service_bindings/{binding_id}",
-		filterChain.GetHandler(s.bind),
+		filterChain.GetHandler(audited(audit.OperationBind, s.bind)),
 	).Methods(http.MethodPut)
 	router.HandleFunc(
 		"/v2/service_instances/{instance_id}/service_bindings/{binding_id}",
-		filterChain.GetHandler(s.unbind),
+		filterChain.GetHandler(audited(audit.OperationUnbind, s.unbind)),
 	).Methods(http.MethodDelete)
 	router.HandleFunc(
 		"/v2/service_instances/{instance_id}",
-		filterChain.GetHandler(s.deprovision),
+		filterChain.GetHandler(audited(audit.OperationDeprovision, s.deprovision)),
 	).Methods(http.MethodDelete)
-	router.HandleFunc(
-		"/healthz",
-		s.healthCheck, // Filter chain not applied to this reqeust
-	).Methods(http.MethodGet)
+	// The filter chain is not applied to health endpoints. /healthz is retained
+	// as an alias for /healthz/live for compatibility with existing probes.
+	router.HandleFunc("/healthz", s.liveness).Methods(http.MethodGet)
+	router.HandleFunc("/healthz/live", s.liveness).Methods(http.MethodGet)
+	router.HandleFunc("/healthz/ready", s.readiness).Methods(http.MethodGet)
+	if metricsHandler != nil {
+		router.Handle(
+			"/metrics",
+			metricsHandler, // Filter chain not applied to this request
+		).Methods(http.MethodGet)
+	}
+	router.Use(instrument)
 	s.router = router
 
-	catalogJSON, err := json.Marshal(catalog)
-	if err != nil {
+	if _, err := s.getCatalogResponse(); err != nil {
 		return nil, err
 	}
-	s.catalogResponse = catalogJSON
 
 	s.listenAndServe = s.defaultListenAndServe
 
@@ -135,23 +167,49 @@ func (s *server) defaultListenAndServe(ctx context.Context) error {
 		Addr:    fmt.Sprintf(":%d", s.apiServerConfig.Port),
 		Handler: s.router,
 	}
+	tlsConfig, err := s.apiServerConfig.GetTLSConfig()
+	if err != nil {
+		return err
+	}
 	if s.apiServerConfig.TLSCertPath != "" &&
 		s.apiServerConfig.TLSKeyPath != "" &&
 		file.Exists(s.apiServerConfig.TLSCertPath) &&
 		file.Exists(s.apiServerConfig.TLSKeyPath) {
+		var reloader *certs.Reloader
+		reloader, err = certs.NewReloader(
+			s.apiServerConfig.TLSCertPath,
+			s.apiServerConfig.TLSKeyPath,
+		)
+		if err != nil {
+			return err
+		}
+		if s.apiServerConfig.TLSReloadInterval > 0 {
+			go reloader.Watch(ctx, s.apiServerConfig.TLSReloadInterval)
+		}
+		if tlsConfig != nil {
+			log.Info(
+				"API server verifies certificates presented by clients",
+			)
+		} else {
+			tlsConfig = &tls.Config{}
+		}
+		tlsConfig.GetCertificate = reloader.GetCertificate
+		svr.TLSConfig = tlsConfig
 		log.WithField(
 			"address",
 			fmt.Sprintf("https://0.0.0.0:%d", s.apiServerConfig.Port),
 		).Info("API server is listening with TLS enabled")
 		go func() {
 			select {
-			case errChan <- svr.ListenAndServeTLS(
-				s.apiServerConfig.TLSCertPath,
-				s.apiServerConfig.TLSKeyPath,
-			):
+			// The certificate and key are obtained from svr.TLSConfig
+			case errChan <- svr.ListenAndServeTLS("", ""):
 			case <-ctx.Done():
 			}
 		}()
+	} else if tlsConfig != nil {
+		return fmt.Errorf(
+			"a client CA bundle was configured, but TLS is not enabled",
+		)
 	} else {
 		log.WithField(
 			"address",
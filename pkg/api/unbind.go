@@ -1,9 +1,11 @@
 package api
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 
+	brokerLog "github.com/Azure/open-service-broker-azure/pkg/log"
 	"github.com/Azure/open-service-broker-azure/pkg/service"
 	log "github.com/Sirupsen/logrus"
 	"github.com/gorilla/mux"
@@ -13,10 +15,14 @@ func (s *server) unbind(w http.ResponseWriter, r *http.Request) {
 	instanceID := mux.Vars(r)["instance_id"]
 	bindingID := mux.Vars(r)["binding_id"]
 
-	logFields := log.Fields{
-		"instanceID": instanceID,
-		"bindingID":  bindingID,
-	}
+	ctx := brokerLog.NewContext(
+		r.Context(),
+		log.Fields{
+			"instanceID": instanceID,
+			"bindingID":  bindingID,
+		},
+	)
+	logFields := brokerLog.FieldsFromContext(ctx)
 
 	log.WithFields(logFields).Debug("received unbinding request")
 
@@ -77,14 +83,32 @@ func (s *server) unbind(w http.ResponseWriter, r *http.Request) {
 			"unbinding an orphaned binding",
 		)
 	} else {
+		annotateAuditEvent(r, instance.ServiceID, instance.PlanID)
+		if !isEntitled(r, instance.ServiceID, instance.PlanID) {
+			logFields["serviceID"] = instance.ServiceID
+			logFields["planID"] = instance.PlanID
+			log.WithFields(logFields).Debug(
+				"bad unbinding request: not entitled to the service and plan",
+			)
+			s.writeResponse(w, http.StatusForbidden, generateForbiddenResponse())
+			return
+		}
+		ctx = brokerLog.NewContext(
+			ctx,
+			log.Fields{
+				"serviceID": instance.ServiceID,
+				"planID":    instance.PlanID,
+			},
+		)
 		serviceManager := instance.Service.GetServiceManager()
 
 		// Starting here, if something goes wrong, we don't know what state service-
 		// specific code has left us in, so we'll attempt to record the error in
 		// the datastore.
-		err = serviceManager.Unbind(instance, binding)
+		err = serviceManager.Unbind(ctx, instance, binding)
 		if err != nil {
 			s.handleUnbindingError(
+				ctx,
 				binding,
 				err,
 				"error executing service-specific unbinding logic",
@@ -97,6 +121,7 @@ func (s *server) unbind(w http.ResponseWriter, r *http.Request) {
 
 	if _, err = s.store.DeleteBinding(bindingID); err != nil {
 		s.handleUnbindingError(
+			ctx,
 			binding,
 			err,
 			"error deleting binding",
@@ -114,6 +139,7 @@ func (s *server) unbind(w http.ResponseWriter, r *http.Request) {
 // so we log that failure and kill the process. Barring such a failure, a nicely
 // formatted error message is logged.
 func (s *server) handleUnbindingError(
+	ctx context.Context,
 	binding service.Binding,
 	e error,
 	msg string,
@@ -125,11 +151,8 @@ func (s *server) handleUnbindingError(
 	} else {
 		binding.StatusReason = fmt.Sprintf(`unbinding error: %s: %s`, msg, e)
 	}
-	logFields := log.Fields{
-		"bindingID":  binding.BindingID,
-		"instanceID": binding.InstanceID,
-		"status":     binding.Status,
-	}
+	logFields := brokerLog.FieldsFromContext(ctx)
+	logFields["status"] = binding.Status
 	err := s.store.WriteBinding(binding)
 	if err != nil {
 		logFields["originalError"] = binding.StatusReason
@@ -1,12 +1,15 @@
 package api
 
 import (
-	"io/ioutil"
 	"net/http"
 	"reflect"
 	"strconv"
 
+	"github.com/Azure/open-service-broker-azure/pkg/audit"
+	brokerLog "github.com/Azure/open-service-broker-azure/pkg/log"
+	"github.com/Azure/open-service-broker-azure/pkg/policy"
 	"github.com/Azure/open-service-broker-azure/pkg/service"
+	"github.com/Azure/open-service-broker-azure/pkg/tracing"
 	log "github.com/Sirupsen/logrus"
 	"github.com/deis/async"
 	"github.com/gorilla/mux"
@@ -15,9 +18,8 @@ import (
 func (s *server) update(w http.ResponseWriter, r *http.Request) {
 	instanceID := mux.Vars(r)["instance_id"]
 
-	logFields := log.Fields{
-		"instanceID": instanceID,
-	}
+	logFields := brokerLog.FieldsFromContext(r.Context())
+	logFields["instanceID"] = instanceID
 
 	log.WithFields(logFields).Debug("received updating request")
 
@@ -52,7 +54,19 @@ func (s *server) update(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	bodyBytes, err := ioutil.ReadAll(r.Body)
+	bodyBytes, err := s.readRequestBody(r)
+	if isRequestBodyTooLarge(err) {
+		logFields["error"] = err
+		log.WithFields(logFields).Debug(
+			"bad updating request: request body too large",
+		)
+		s.writeResponse(
+			w,
+			http.StatusRequestEntityTooLarge,
+			generateRequestBodyTooLargeResponse(),
+		)
+		return
+	}
 	if err != nil {
 		logFields["error"] = err
 		log.WithFields(logFields).Error(
@@ -128,6 +142,29 @@ func (s *server) update(w http.ResponseWriter, r *http.Request) {
 		s.writeResponse(w, http.StatusBadRequest, generateEmptyResponse())
 		return
 	}
+	logFields["serviceID"] = instance.ServiceID
+	logFields["planID"] = instance.PlanID
+	auditPlanID := instance.PlanID
+	if updatingRequest.PlanID != "" {
+		auditPlanID = updatingRequest.PlanID
+	}
+	annotateAuditEvent(r, instance.ServiceID, auditPlanID)
+	annotateAuditEventParameters(
+		r,
+		instance.Plan.GetSchemas().ServiceInstances.UpdatingParametersSchema,
+		updatingRequest.Parameters,
+	)
+
+	if !isEntitled(r, instance.ServiceID, instance.PlanID) ||
+		(updatingRequest.PlanID != "" &&
+			!isEntitled(r, instance.ServiceID, updatingRequest.PlanID)) {
+		logFields["requestPlanID"] = updatingRequest.PlanID
+		log.WithFields(logFields).Debug(
+			"bad updating request: not entitled to the service and plan",
+		)
+		s.writeResponse(w, http.StatusForbidden, generateForbiddenResponse())
+		return
+	}
 
 	// Our broker doesn't actually require the serviceID and previousValues that,
 	// per spec, are passed to us in the request body (since this broker is
@@ -153,8 +190,35 @@ func (s *server) update(w http.ResponseWriter, r *http.Request) {
 
 	serviceManager := svc.GetServiceManager()
 
+	// If no plan change was requested, the instance retains its current plan
+	if plan == nil {
+		plan, ok = svc.GetPlan(instance.PlanID)
+		if !ok {
+			logFields["serviceID"] = updatingRequest.ServiceID
+			logFields["planID"] = instance.PlanID
+			log.WithFields(logFields).Error(
+				"pre-updating error: no Plan found for planID in Service",
+			)
+			s.writeResponse(
+				w,
+				http.StatusInternalServerError,
+				generateInvalidPlanIDResponse(),
+			)
+			return
+		}
+	}
+
+	// Coerce values in the update parameters to the types called for by the
+	// schema. Defaults are deliberately not applied here because any parameter
+	// omitted from an update request should retain its current value.
+	ups := instance.Plan.GetSchemas().ServiceInstances.UpdatingParametersSchema
+	updatingRequest.Parameters = ups.Coerce(updatingRequest.Parameters)
+
 	// Merge update parameters with the instance's provisioning params to build
-	// a complete set of params
+	// a complete set of params, then normalize them against the provisioning
+	// schema of the plan being updated to. It is the normalized parameters that
+	// are persisted.
+	pps := plan.GetSchemas().ServiceInstances.ProvisioningParametersSchema
 	rawUpdatingParameters := updatingRequest.Parameters
 	if instance.ProvisioningParameters != nil {
 		rawUpdatingParameters = mergeUpdateParameters(
@@ -162,6 +226,7 @@ func (s *server) update(w http.ResponseWriter, r *http.Request) {
 			updatingRequest.Parameters,
 		)
 	}
+	rawUpdatingParameters = pps.Normalize(rawUpdatingParameters)
 
 	// This determines whether the parameters of the update request are already
 	// reflected in the provisioning parameters of a fully provisioned (or fully
@@ -185,7 +250,9 @@ func (s *server) update(w http.ResponseWriter, r *http.Request) {
 		s.writeResponse(w, http.StatusConflict, generateEmptyResponse())
 		return
 	}
-	if !reflect.DeepEqual(existingParams, rawUpdatingParameters) {
+	// The existing parameters are normalized in case they were persisted before
+	// parameters were normalized upon receipt.
+	if !reflect.DeepEqual(pps.Normalize(existingParams), rawUpdatingParameters) {
 		if instance.Status == service.InstanceStateUpdating {
 			// We cannot handle two updates at once. This is a conflict.
 			s.writeResponse(w, http.StatusConflict, generateEmptyResponse())
@@ -211,18 +278,17 @@ func (s *server) update(w http.ResponseWriter, r *http.Request) {
 		instance.Plan.GetSchemas().ServiceInstances.UpdatingParametersSchema.Validate( // nolint: lll
 			updatingRequest.Parameters,
 		); err != nil {
-		var validationErr *service.ValidationError
-		validationErr, ok = err.(*service.ValidationError)
+		var validationErrs service.ValidationErrors
+		validationErrs, ok = service.GetValidationErrors(err)
 		if ok {
-			logFields["field"] = validationErr.Field
-			logFields["issue"] = validationErr.Issue
+			logFields["validationErrors"] = validationErrs.Error()
 			log.WithFields(logFields).Debug(
 				"bad updating request: validation error",
 			)
 			s.writeResponse(
 				w,
 				http.StatusBadRequest,
-				generateValidationFailedResponse(validationErr),
+				generateValidationFailedResponse(validationErrs),
 			)
 			return
 		}
@@ -235,7 +301,6 @@ func (s *server) update(w http.ResponseWriter, r *http.Request) {
 	// updating schema so that when persisting, we will be able to persist the
 	// full combined provisioning + updating parameters instea of just the subset
 	// that are updating params.
-	pps := plan.GetSchemas().ServiceInstances.ProvisioningParametersSchema
 	updatingParameters := &service.ProvisioningParameters{
 		Parameters: service.Parameters{
 			Schema: &pps,
@@ -248,18 +313,17 @@ func (s *server) update(w http.ResponseWriter, r *http.Request) {
 	// might be reducing the amound of storage allocated to a database.
 	instance.UpdatingParameters = updatingParameters
 	if err := serviceManager.ValidateUpdatingParameters(instance); err != nil {
-		var validationErr *service.ValidationError
-		validationErr, ok = err.(*service.ValidationError)
+		var validationErrs service.ValidationErrors
+		validationErrs, ok = service.GetValidationErrors(err)
 		if ok {
-			logFields["field"] = validationErr.Field
-			logFields["issue"] = validationErr.Issue
+			logFields["validationErrors"] = validationErrs.Error()
 			log.WithFields(logFields).Debug(
 				"bad updating request: validation error",
 			)
 			s.writeResponse(
 				w,
 				http.StatusBadRequest,
-				generateValidationFailedResponse(validationErr),
+				generateValidationFailedResponse(validationErrs),
 			)
 			return
 		}
@@ -267,24 +331,72 @@ func (s *server) update(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// If we get to here, we need to update the instance.
+	// Evaluate the request against the operator's policy. It is the complete
+	// set of parameters the instance will have once updated that is evaluated.
+	if violations := s.evaluatePolicy(
+		r,
+		policy.Input{
+			Operation:   policy.OperationUpdate,
+			ServiceID:   instance.ServiceID,
+			ServiceName: svc.GetName(),
+			PlanID:      plan.GetID(),
+			PlanName:    plan.GetName(),
+			Parameters:  rawUpdatingParameters,
+			Context:     updatingRequest.Context,
+		},
+	); len(violations) > 0 {
+		logFields["policyViolations"] = violations
+		log.WithFields(logFields).Debug(
+			"bad updating request: policy violation",
+		)
+		s.writeResponse(
+			w,
+			http.StatusBadRequest,
+			generatePolicyViolationResponse(violations),
+		)
+		return
+	}
 
-	if plan == nil {
-		plan, ok = svc.GetPlan(instance.PlanID)
-		if !ok {
-			logFields["serviceID"] = updatingRequest.ServiceID
-			logFields["planID"] = instance.PlanID
+	// Enforce the operator's quotas if the plan is changing. Existing instances
+	// are only retrieved if there are quotas to enforce. As when provisioning,
+	// this is only best-effort when there are several replicas of the broker.
+	if updatingRequest.PlanID != "" &&
+		updatingRequest.PlanID != instance.PlanID &&
+		!s.quotas.IsEmpty() {
+		s.quotaMutex.Lock()
+		defer s.quotaMutex.Unlock()
+		var instances []service.Instance
+		instances, err = s.store.GetInstances()
+		if err != nil {
+			logFields["error"] = err
 			log.WithFields(logFields).Error(
-				"pre-updating error: no Plan found for planID in Service",
+				"pre-updating error: error retrieving instances to enforce quotas",
+			)
+			s.writeResponse(w, http.StatusInternalServerError, generateEmptyResponse())
+			return
+		}
+		exceeded, isExceeded := s.quotas.CheckPlanChange(
+			instances,
+			instance,
+			updatingRequest.PlanID,
+		)
+		if isExceeded {
+			logFields["quota"] = exceeded.Limit.Name
+			logFields["tenant"] = exceeded.Tenant
+			log.WithFields(logFields).Debug(
+				"bad updating request: quota exceeded",
 			)
 			s.writeResponse(
 				w,
-				http.StatusInternalServerError,
-				generateInvalidPlanIDResponse(),
+				http.StatusForbidden,
+				generateQuotaExceededResponse(exceeded),
 			)
 			return
 		}
 	}
+
+	// If we get to here, we need to update the instance.
+
 	updater, err := serviceManager.GetUpdater(plan)
 	if err != nil {
 		logFields["serviceID"] = updatingRequest.ServiceID
@@ -308,6 +420,7 @@ func (s *server) update(w http.ResponseWriter, r *http.Request) {
 	}
 
 	instance.Status = service.InstanceStateUpdating
+	instance.Step = firstStepName
 	instance.PlanID = updatingRequest.PlanID
 	if err := s.store.WriteInstance(instance); err != nil {
 		logFields["error"] = err
@@ -325,6 +438,9 @@ func (s *server) update(w http.ResponseWriter, r *http.Request) {
 			"instanceID": instanceID,
 		},
 	)
+	brokerLog.InjectIntoTaskArgs(r.Context(), task.GetArgs())
+	audit.InjectIntoTaskArgs(r.Context(), task.GetArgs())
+	tracing.InjectIntoTaskArgs(r.Context(), task.GetArgs())
 	if err := s.asyncEngine.SubmitTask(task); err != nil {
 		logFields["step"] = firstStepName
 		logFields["error"] = err
@@ -17,6 +17,8 @@ type UpdatingRequest struct {
 	PlanID         string                 `json:"plan_id"`
 	Parameters     map[string]interface{} `json:"parameters"`
 	PreviousValues UpdatingPreviousValues `json:"previous_values"`
+	// Context is contextual data about the request provided by the platform
+	Context map[string]interface{} `json:"context,omitempty"`
 }
 
 // NewUpdatingRequestFromJSON returns a new UpdatingRequest unmarshaled from the
@@ -0,0 +1,35 @@
+package redis
+
+import (
+	osbaRedis "github.com/Azure/open-service-broker-azure/pkg/redis"
+	"github.com/kelseyhightower/envconfig"
+)
+
+const envconfigPrefix = "ASYNC"
+
+// Config encapsulates all configuration options for the Redis-based
+// implementation of the async.Engine interface
+type Config struct {
+	osbaRedis.Config
+	PendingTaskWorkerCount  int `envconfig:"PENDING_TASK_WORKER_COUNT"`
+	DeferedTaskWatcherCount int `envconfig:"DEFERED_TASK_WATCHER_COUNT"`
+}
+
+// NewConfigWithDefaults returns a Config object with default values already
+// applied. Callers are then free to set custom values for the remaining fields
+// and/or override default values.
+func NewConfigWithDefaults() Config {
+	return Config{
+		Config:                  osbaRedis.NewConfigWithDefaults(),
+		PendingTaskWorkerCount:  5,
+		DeferedTaskWatcherCount: 100,
+	}
+}
+
+// GetConfigFromEnvironment returns configuration derived from environment
+// variables
+func GetConfigFromEnvironment() (Config, error) {
+	c := NewConfigWithDefaults()
+	err := envconfig.Process(envconfigPrefix, &c)
+	return c, err
+}
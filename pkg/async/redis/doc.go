@@ -0,0 +1,9 @@
+// Package redis is a Redis-based implementation of the async.Engine interface
+// from github.com/deis/async. It is derived from that library's own redis
+// package, which is distributed under the terms of the MIT License found in
+// this directory, and differs from it only in that it can use a Redis
+// Sentinel or Redis Cluster deployment in addition to a single Redis host.
+// The names of all queues and sets it uses in Redis are unchanged, so brokers
+// using either implementation can share the same Redis database during an
+// upgrade.
+package redis
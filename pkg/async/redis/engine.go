@@ -0,0 +1,278 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	osbaRedis "github.com/Azure/open-service-broker-azure/pkg/redis"
+	log "github.com/Sirupsen/logrus"
+	"github.com/deis/async"
+	"github.com/go-redis/redis"
+	uuid "github.com/satori/go.uuid"
+)
+
+// defaultClusterKeyTag is the hash tag applied to all queue and worker set
+// keys when using Redis Cluster and no prefix has been configured
+const defaultClusterKeyTag = "osba-async"
+
+// Engine is a Redis-based implementation of the async.Engine interface
+type Engine interface {
+	async.Engine
+	// TestConnection tests the engine's connection to Redis, respecting
+	// cancelation of the given context
+	TestConnection(ctx context.Context) error
+}
+
+// engine is a Redis-based implementation of the Engine interface.
+type engine struct {
+	workerID     string
+	jobsFns      map[string]async.JobFn
+	jobsFnsMutex sync.RWMutex
+	redisClient  redis.UniversalClient
+	config       Config
+	prefix       string
+
+	pendingTaskQueueName  string
+	deferredTaskQueueName string
+	workerSetName         string
+	activeTaskQueueName   string
+	watchedTaskQueueName  string
+
+	// This allows tests to inject an alternative implementation of this function
+	clean cleanFn
+	// This allows tests to inject an alternative implementation of this function
+	cleanActiveTaskQueue cleanWorkerQueueFn
+	// This allows tests to inject an alternative implementation of this function
+	cleanWatchedTaskQueue cleanWorkerQueueFn
+	// This allows tests to inject an alternative implementation of this function
+	runHeart runHeartFn
+	// This allows tests to inject an alternative implementation of this function
+	heartbeat heartbeatFn
+	// This allows tests to inject an alternative implementation of this function
+	receivePendingTasks receiveTasksFn
+	// This allows tests to inject an alternative implementation of this function
+	receiveDeferredTasks receiveTasksFn
+	// This allows tests to inject an alternative implementation of this function
+	executeTasks executeTasksFn
+	// This allows tests to inject an alternative implementation of this function
+	watchDeferredTasks watchDeferredTasksFn
+}
+
+// NewEngine returns a new Redis-based implementation of the async.Engine
+// interface
+func NewEngine(config Config) (Engine, error) {
+	redisClient, prefix, err := osbaRedis.NewClient(
+		config.Config,
+		defaultClusterKeyTag,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return newEngine(redisClient, prefix, config), nil
+}
+
+// newEngine returns a new Redis-based implementation of the async.Engine
+// interface that uses the given Redis client and applies the given prefix to
+// the keys of all queues and sets
+func newEngine(
+	redisClient redis.UniversalClient,
+	prefix string,
+	config Config,
+) *engine {
+	workerID := uuid.NewV4().String()
+	e := &engine{
+		workerID:    workerID,
+		jobsFns:     make(map[string]async.JobFn),
+		redisClient: redisClient,
+		config:      config,
+		prefix:      prefix,
+	}
+
+	e.pendingTaskQueueName = e.prefixRedisKey("pendingTasks")
+	e.deferredTaskQueueName = e.prefixRedisKey("deferredTasks")
+	e.workerSetName = e.prefixRedisKey("workers")
+	e.activeTaskQueueName = e.prefixRedisKey(
+		fmt.Sprintf("active-tasks:%s", workerID),
+	)
+	e.watchedTaskQueueName = e.prefixRedisKey(
+		fmt.Sprintf("watched-tasks:%s", workerID),
+	)
+
+	e.clean = e.defaultClean
+	e.cleanActiveTaskQueue = e.defaultCleanWorkerQueue
+	e.cleanWatchedTaskQueue = e.defaultCleanWorkerQueue
+	e.runHeart = e.defaultRunHeart
+	e.heartbeat = e.defaultHeartbeat
+	e.receivePendingTasks = e.defaultReceiveTasks
+	e.receiveDeferredTasks = e.defaultReceiveTasks
+	e.executeTasks = e.defaultExecuteTasks
+	e.watchDeferredTasks = e.defaultWatchDeferredTasks
+
+	return e
+}
+
+// RegisterJob registers a new async.JobFn with the async engine
+func (e *engine) RegisterJob(name string, fn async.JobFn) error {
+	e.jobsFnsMutex.Lock()
+	defer e.jobsFnsMutex.Unlock()
+	if _, ok := e.jobsFns[name]; ok {
+		return &errDuplicateJob{name: name}
+	}
+	e.jobsFns[name] = fn
+	return nil
+}
+
+// SubmitTask submits an idempotent task to the async engine for reliable,
+// asynchronous completion
+func (e *engine) SubmitTask(task async.Task) error {
+	taskJSON, err := task.ToJSON()
+	if err != nil {
+		return fmt.Errorf("error encoding task %#v: %s", task, err)
+	}
+
+	var queueName string
+	if task.GetExecuteTime() != nil {
+		queueName = e.deferredTaskQueueName
+	} else {
+		queueName = e.pendingTaskQueueName
+	}
+
+	err = e.redisClient.LPush(queueName, taskJSON).Err()
+	if err != nil {
+		return fmt.Errorf("error encoding task %#v: %s", task, err)
+	}
+	return nil
+}
+
+// TestConnection pings the Redis server(s) used by the engine
+func (e *engine) TestConnection(ctx context.Context) error {
+	return osbaRedis.Ping(ctx, e.redisClient)
+}
+
+// Run causes the async engine to carry out all of its functions. It blocks
+// until a fatal error is encountered or the context passed to it has been
+// canceled. Run always returns a non-nil error.
+func (e *engine) Run(ctx context.Context) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	errCh := make(chan error)
+	// Start the cleaner
+	go func() {
+		select {
+		case errCh <- &errCleanerStopped{
+			err: e.clean(
+				ctx,
+				e.workerSetName,
+				e.pendingTaskQueueName,
+				e.deferredTaskQueueName,
+				cleaningInterval,
+			),
+		}:
+		case <-ctx.Done():
+		}
+	}()
+	// As soon as we add the worker to the workers set, it's eligible for the
+	// cleaner to clean up after it, so it's important that we guarantee the
+	// cleaner will see this worker as alive. We can't trust that the heartbeat
+	// loop (which we'll shortly start in its own goroutine) will have sent the
+	// first heartbeat BEFORE the worker is added to the workers set. To account
+	// for this, we synchronously send the first heartbeat.
+	if err := e.heartbeat(cleaningInterval * 2); err != nil {
+		return err
+	}
+	// Heartbeat loop
+	go func() {
+		select {
+		case errCh <- &errHeartStopped{
+			workerID: e.workerID,
+			err:      e.runHeart(ctx, cleaningInterval),
+		}:
+		case <-ctx.Done():
+		}
+	}()
+	// Announce this worker's existence
+	err := e.redisClient.SAdd(e.workerSetName, e.workerID).Err()
+	if err != nil {
+		return fmt.Errorf(
+			`error adding worker "%s" to worker set: %s`,
+			e.workerID,
+			err,
+		)
+	}
+	// Assemble and execute a pipeline to receive and execute pending tasks...
+	go func() {
+		pendingReceiverRetCh := make(chan []byte)
+		pendingReceiverErrCh := make(chan error)
+		executorErrCh := make(chan error)
+		go e.receivePendingTasks(
+			ctx,
+			e.pendingTaskQueueName,
+			e.activeTaskQueueName,
+			pendingReceiverRetCh,
+			pendingReceiverErrCh,
+		)
+		// Fan out to desired number of workers
+		for i := 0; i < e.config.PendingTaskWorkerCount; i++ {
+			go e.executeTasks(
+				ctx,
+				pendingReceiverRetCh,
+				e.pendingTaskQueueName,
+				e.deferredTaskQueueName,
+				executorErrCh,
+			)
+		}
+		select {
+		case err := <-pendingReceiverErrCh:
+			errCh <- &errReceiverStopped{
+				workerID:  e.workerID,
+				queueName: e.pendingTaskQueueName,
+				err:       err,
+			}
+		case err := <-executorErrCh:
+			errCh <- &errTaskExecutorStopped{workerID: e.workerID, err: err}
+		case <-ctx.Done():
+		}
+	}()
+	// Assemble and execute a pipeline to receive and watch deferred tasks...
+	go func() {
+		deferredReceiverRetCh := make(chan []byte)
+		deferredReceiverErrCh := make(chan error)
+		watcherErrCh := make(chan error)
+		go e.receiveDeferredTasks(
+			ctx,
+			e.deferredTaskQueueName,
+			e.watchedTaskQueueName,
+			deferredReceiverRetCh,
+			deferredReceiverErrCh,
+		)
+		// Fan out to desired number of watchers
+		for i := 0; i < e.config.DeferedTaskWatcherCount; i++ {
+			go e.watchDeferredTasks(
+				ctx,
+				deferredReceiverRetCh,
+				e.pendingTaskQueueName,
+				watcherErrCh,
+			)
+		}
+		select {
+		case err := <-deferredReceiverErrCh:
+			errCh <- &errReceiverStopped{
+				workerID:  e.workerID,
+				queueName: e.deferredTaskQueueName,
+				err:       err,
+			}
+		case err := <-watcherErrCh:
+			errCh <- &errDeferredTaskWatcherStopped{workerID: e.workerID, err: err}
+		case <-ctx.Done():
+		}
+	}()
+	// Now wait...
+	select {
+	case <-ctx.Done():
+		log.Debug("context canceled; async engine shutting down")
+		return ctx.Err()
+	case err := <-errCh:
+		return err
+	}
+}
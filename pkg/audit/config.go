@@ -0,0 +1,97 @@
+package audit
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/kelseyhightower/envconfig"
+)
+
+const envconfigPrefix = "AUDIT"
+
+const (
+	// SinkNone represents audit events being discarded
+	SinkNone = "NONE"
+	// SinkStdout represents audit events being written to stdout, one JSON
+	// object per line
+	SinkStdout = "STDOUT"
+	// SinkFile represents audit events being appended to a file, one JSON object
+	// per line
+	SinkFile = "FILE"
+	// SinkWebhook represents audit events being POSTed to a webhook
+	SinkWebhook = "WEBHOOK"
+)
+
+// Config represents configuration options for the audit subsystem
+type Config struct {
+	SinkType string `envconfig:"SINK" default:"NONE"`
+	// FilePath is the file to which audit events are appended when the FILE
+	// sink is selected
+	FilePath string `envconfig:"FILE_PATH"`
+	// WebhookURL is the URL to which audit events are POSTed when the WEBHOOK
+	// sink is selected
+	WebhookURL string `envconfig:"WEBHOOK_URL"`
+	// WebhookTimeout bounds how long delivery of each event to the webhook may
+	// take
+	WebhookTimeout time.Duration `envconfig:"WEBHOOK_TIMEOUT"`
+	// HMACKey is the secret key with which each event's hash is computed.
+	// Without it, a party able to alter recorded events could recompute the
+	// hashes of the altered chain. It is required unless auditing is disabled.
+	HMACKey string `envconfig:"HMAC_KEY"`
+	// ChainStatePath is a file to which the head of the chain of events is
+	// persisted so that the chain continues, rather than starting anew, when
+	// the broker restarts. If it is unset and the FILE sink is selected, it
+	// defaults to FilePath with ".head" appended.
+	ChainStatePath string `envconfig:"CHAIN_STATE_PATH"`
+	// QueueSize is the number of events that may await recording. Events are
+	// recorded in the background and retried until the sink accepts them.
+	// Events logged while the queue is full are dropped.
+	QueueSize int `envconfig:"QUEUE_SIZE"`
+}
+
+// minHMACKeyLength is the minimum length, in bytes, of the HMAC key. It
+// matches the size of the SHA-256 hash.
+const minHMACKeyLength = 32
+
+// NewConfigWithDefaults returns a Config object with default values already
+// applied. Callers are then free to set custom values for the remaining fields
+// and/or override default values.
+func NewConfigWithDefaults() Config {
+	return Config{
+		WebhookTimeout: 10 * time.Second,
+		QueueSize:      1024,
+	}
+}
+
+// GetConfigFromEnvironment returns configuration derived from environment
+// variables
+func GetConfigFromEnvironment() (Config, error) {
+	c := NewConfigWithDefaults()
+	err := envconfig.Process(envconfigPrefix, &c)
+	if err != nil {
+		return c, err
+	}
+	c.SinkType = strings.ToUpper(c.SinkType)
+	if c.SinkType == SinkNone {
+		return c, nil
+	}
+	if len(c.HMACKey) < minHMACKeyLength {
+		return c, fmt.Errorf(
+			"environment variable %s_HMAC_KEY must be at least %d bytes when "+
+				"auditing is enabled",
+			envconfigPrefix,
+			minHMACKeyLength,
+		)
+	}
+	if c.QueueSize <= 0 {
+		return c, fmt.Errorf(
+			"environment variable %s_QUEUE_SIZE must be positive",
+			envconfigPrefix,
+		)
+	}
+	if c.ChainStatePath == "" && c.SinkType == SinkFile && c.FilePath != "" {
+		c.ChainStatePath = c.FilePath + ".head"
+	}
+	return c, nil
+}
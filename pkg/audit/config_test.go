@@ -0,0 +1,33 @@
+package audit
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetConfigRequiresHMACKey(t *testing.T) {
+	err := os.Setenv("AUDIT_SINK", "file")
+	assert.Nil(t, err)
+	defer os.Unsetenv("AUDIT_SINK") // nolint: errcheck
+	err = os.Setenv("AUDIT_FILE_PATH", "/var/log/osba/audit.log")
+	assert.Nil(t, err)
+	defer os.Unsetenv("AUDIT_FILE_PATH") // nolint: errcheck
+	_, err = GetConfigFromEnvironment()
+	assert.NotNil(t, err)
+
+	err = os.Setenv("AUDIT_HMAC_KEY", "AUDITHMACKey-1234567890123456789")
+	assert.Nil(t, err)
+	defer os.Unsetenv("AUDIT_HMAC_KEY") // nolint: errcheck
+	c, err := GetConfigFromEnvironment()
+	assert.Nil(t, err)
+	// The chain head is persisted alongside the audit log by default
+	assert.Equal(t, "/var/log/osba/audit.log.head", c.ChainStatePath)
+}
+
+func TestGetConfigWithAuditingDisabled(t *testing.T) {
+	c, err := GetConfigFromEnvironment()
+	assert.Nil(t, err)
+	assert.Equal(t, SinkNone, c.SinkType)
+}
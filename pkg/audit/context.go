@@ -0,0 +1,91 @@
+package audit
+
+import (
+	"context"
+
+	"github.com/Azure/open-service-broker-azure/pkg/identity"
+)
+
+const (
+	actorSchemeKey               = "auditActorScheme"
+	actorNameKey                 = "auditActorName"
+	originatingIdentityHeaderKey = "auditOriginatingIdentity"
+)
+
+type actorContextKey struct{}
+
+type actorContextValue struct {
+	actor  Actor
+	header string
+}
+
+// NewContext returns a copy of the given context that carries the actor
+// responsible for a request. The actor is derived from the identity carried
+// by the context, if any, and the given originating identity header value.
+func NewContext(
+	ctx context.Context,
+	originatingIdentityHeader string,
+) context.Context {
+	actor := Actor{
+		OriginatingIdentity: ParseOriginatingIdentity(originatingIdentityHeader),
+	}
+	if id, ok := identity.FromContext(ctx); ok {
+		actor.Scheme = id.Scheme
+		actor.Name = id.Name
+	}
+	return context.WithValue(
+		ctx,
+		actorContextKey{},
+		actorContextValue{
+			actor:  actor,
+			header: originatingIdentityHeader,
+		},
+	)
+}
+
+// ActorFromContext returns the actor carried by the given context. If the
+// context carries no actor, an empty Actor is returned.
+func ActorFromContext(ctx context.Context) Actor {
+	val, _ := ctx.Value(actorContextKey{}).(actorContextValue)
+	return val.actor
+}
+
+// InjectIntoTaskArgs stores the actor carried by the given context, if any, in
+// the given async task args so that the outcome of the task can be attributed
+// to the actor that requested it
+func InjectIntoTaskArgs(ctx context.Context, args map[string]string) {
+	val, ok := ctx.Value(actorContextKey{}).(actorContextValue)
+	if !ok {
+		return
+	}
+	args[actorSchemeKey] = val.actor.Scheme
+	args[actorNameKey] = val.actor.Name
+	if val.header != "" {
+		args[originatingIdentityHeaderKey] = val.header
+	}
+}
+
+// NewContextFromTaskArgs returns a copy of the given context that carries the
+// actor stored in the given async task args, if any
+func NewContextFromTaskArgs(
+	ctx context.Context,
+	args map[string]string,
+) context.Context {
+	scheme, ok := args[actorSchemeKey]
+	if !ok {
+		return ctx
+	}
+	header := args[originatingIdentityHeaderKey]
+	return context.WithValue(
+		ctx,
+		actorContextKey{},
+		actorContextValue{
+			actor: Actor{
+				Scheme:              scheme,
+				Name:                args[actorNameKey],
+				OriginatingIdentity: ParseOriginatingIdentity(header),
+			},
+			header: header,
+		},
+	)
+}
@@ -0,0 +1,55 @@
+package audit
+
+import (
+	"context"
+	"encoding/base64"
+	"testing"
+
+	"github.com/Azure/open-service-broker-azure/pkg/identity"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseOriginatingIdentity(t *testing.T) {
+	value := `{"user_id":"683ea748-3092-4ff4-b656-39cacc4d5360"}`
+	oi := ParseOriginatingIdentity(
+		"cloudfoundry " + base64.StdEncoding.EncodeToString([]byte(value)),
+	)
+	assert.NotNil(t, oi)
+	assert.Equal(t, "cloudfoundry", oi.Platform)
+	assert.JSONEq(t, value, string(oi.Value))
+}
+
+func TestParseMalformedOriginatingIdentity(t *testing.T) {
+	assert.Nil(t, ParseOriginatingIdentity(""))
+	assert.Nil(t, ParseOriginatingIdentity("cloudfoundry"))
+	oi := ParseOriginatingIdentity("cloudfoundry not-base64!")
+	assert.NotNil(t, oi)
+	assert.Equal(t, `"not-base64!"`, string(oi.Value))
+}
+
+func TestActorRoundTripsThroughTaskArgs(t *testing.T) {
+	ctx := identity.NewContext(
+		context.Background(),
+		identity.Identity{
+			Scheme: "Basic",
+			Name:   "platform",
+		},
+	)
+	header := "kubernetes " +
+		base64.StdEncoding.EncodeToString([]byte(`{"username":"jane"}`))
+	ctx = NewContext(ctx, header)
+	args := map[string]string{}
+	InjectIntoTaskArgs(ctx, args)
+	actor := ActorFromContext(NewContextFromTaskArgs(context.Background(), args))
+	assert.Equal(t, ActorFromContext(ctx), actor)
+	assert.Equal(t, "Basic", actor.Scheme)
+	assert.Equal(t, "platform", actor.Name)
+	assert.Equal(t, "kubernetes", actor.OriginatingIdentity.Platform)
+}
+
+func TestActorFromContextWithoutActor(t *testing.T) {
+	assert.Equal(t, Actor{}, ActorFromContext(context.Background()))
+	args := map[string]string{}
+	InjectIntoTaskArgs(context.Background(), args)
+	assert.Empty(t, args)
+}
@@ -0,0 +1,117 @@
+package audit
+
+import (
+	"net/http"
+	"time"
+)
+
+const (
+	// OperationProvision represents provisioning of a service instance
+	OperationProvision = "provision"
+	// OperationUpdate represents updating of a service instance
+	OperationUpdate = "update"
+	// OperationDeprovision represents deprovisioning of a service instance
+	OperationDeprovision = "deprovision"
+	// OperationBind represents binding to a service instance
+	OperationBind = "bind"
+	// OperationUnbind represents unbinding from a service instance
+	OperationUnbind = "unbind"
+	// OperationAdminSetStatus represents an operator forcing a service instance
+	// into a given status using the admin API
+	OperationAdminSetStatus = "admin-set-status"
+	// OperationAdminRetry represents an operator re-enqueuing a service
+	// instance's current step using the admin API
+	OperationAdminRetry = "admin-retry"
+	// OperationAdminDeleteInstance represents an operator deleting a service
+	// instance's record using the admin API
+	OperationAdminDeleteInstance = "admin-delete-instance"
+	// OperationAdminDeleteBinding represents an operator deleting a binding's
+	// record using the admin API
+	OperationAdminDeleteBinding = "admin-delete-binding"
+)
+
+const (
+	// PhaseRequest represents an event recorded when the broker responds to a
+	// request
+	PhaseRequest = "request"
+	// PhaseCompletion represents an event recorded when asynchronous execution
+	// of an operation finishes
+	PhaseCompletion = "completion"
+)
+
+const (
+	// OutcomeAccepted represents a request that was accepted for asynchronous
+	// execution
+	OutcomeAccepted = "accepted"
+	// OutcomeSucceeded represents an operation that succeeded
+	OutcomeSucceeded = "succeeded"
+	// OutcomeRejected represents a request that was rejected because it was
+	// invalid, unauthorized or conflicted with the state of the broker
+	OutcomeRejected = "rejected"
+	// OutcomeFailed represents an operation that failed
+	OutcomeFailed = "failed"
+)
+
+// Event represents a single auditable occurrence. Events are chained
+// together-- each includes the hash of the event before it-- so that any
+// alteration or removal of events can be detected.
+type Event struct {
+	// ChainID identifies the chain of events this event belongs to. A broker
+	// process begins a new chain unless the head of its previous chain was
+	// persisted.
+	ChainID string `json:"chainID"`
+	// Sequence is the position of this event in its chain, beginning with 1
+	Sequence   uint64    `json:"sequence"`
+	Time       time.Time `json:"time"`
+	Operation  string    `json:"operation"`
+	Phase      string    `json:"phase"`
+	Outcome    string    `json:"outcome"`
+	StatusCode int       `json:"statusCode,omitempty"`
+	Actor      Actor     `json:"actor"`
+	RequestID  string    `json:"requestID,omitempty"`
+	InstanceID string    `json:"instanceID,omitempty"`
+	BindingID  string    `json:"bindingID,omitempty"`
+	ServiceID  string    `json:"serviceID,omitempty"`
+	PlanID     string    `json:"planID,omitempty"`
+	// Parameters are the parameters of the request, with all secure parameters
+	// redacted
+	Parameters interface{} `json:"parameters,omitempty"`
+	// PreviousStatus and Status are the status of the service instance before
+	// and after an operation that changed it directly, e.g. using the admin
+	// API. Status is empty if the instance's record was deleted.
+	PreviousStatus string `json:"previousStatus,omitempty"`
+	Status         string `json:"status,omitempty"`
+	Error          string `json:"error,omitempty"`
+	// PreviousHash is the hash of the previous event in the chain. It is empty
+	// for the first event in a chain.
+	PreviousHash string `json:"previousHash"`
+	// Hash is the hex encoded HMAC-SHA256 of the event's JSON representation,
+	// computed with this field empty, using the operator's secret key
+	Hash string `json:"hash"`
+}
+
+// Actor describes who initiated an operation
+type Actor struct {
+	// Scheme is the authentication scheme the caller used
+	Scheme string `json:"scheme,omitempty"`
+	// Name is the authenticated name of the caller-- typically a platform
+	Name string `json:"name,omitempty"`
+	// OriginatingIdentity is the platform user on whose behalf the caller made
+	// the request, if the platform supplied one
+	OriginatingIdentity *OriginatingIdentity `json:"originatingIdentity,omitempty"`
+}
+
+// GetOutcome returns the outcome of a request to which the broker responded
+// with the given HTTP status code
+func GetOutcome(statusCode int) string {
+	switch {
+	case statusCode == http.StatusAccepted:
+		return OutcomeAccepted
+	case statusCode >= 200 && statusCode < 300:
+		return OutcomeSucceeded
+	case statusCode >= 400 && statusCode < 500:
+		return OutcomeRejected
+	default:
+		return OutcomeFailed
+	}
+}
@@ -0,0 +1,15 @@
+package audit
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetOutcome(t *testing.T) {
+	assert.Equal(t, OutcomeAccepted, GetOutcome(http.StatusAccepted))
+	assert.Equal(t, OutcomeSucceeded, GetOutcome(http.StatusOK))
+	assert.Equal(t, OutcomeRejected, GetOutcome(http.StatusConflict))
+	assert.Equal(t, OutcomeFailed, GetOutcome(http.StatusInternalServerError))
+}
@@ -0,0 +1,351 @@
+package audit
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	log "github.com/Sirupsen/logrus"
+	uuid "github.com/satori/go.uuid"
+)
+
+const (
+	defaultMinRetryInterval = time.Second
+	defaultMaxRetryInterval = time.Minute
+)
+
+// Logger is an interface to be implemented by types that record audit events
+type Logger interface {
+	// Log records the given event. Fields that tie the event into the logger's
+	// chain of events (and the event's time, if unset) are populated by the
+	// logger.
+	Log(Event)
+	// Close attempts to record any events still awaiting recording and stops
+	// the logger. Events logged after Close has been called are dropped.
+	Close()
+}
+
+// ChainHead identifies the most recently recorded event in a chain
+type ChainHead struct {
+	ChainID  string `json:"chainID"`
+	Sequence uint64 `json:"sequence"`
+	Hash     string `json:"hash"`
+}
+
+// queuedEvent is an event that has been added to the chain and awaits
+// recording
+type queuedEvent struct {
+	eventJSON []byte
+	head      ChainHead
+}
+
+type logger struct {
+	sink      Sink
+	key       []byte
+	statePath string
+	// mutex guards head and closed. It is held only while an event is added to
+	// the chain and queued, never while an event is recorded.
+	mutex  sync.Mutex
+	head   ChainHead
+	closed bool
+	queue  chan queuedEvent
+	stopCh chan struct{}
+	doneCh chan struct{}
+	// These may be shortened by tests
+	minRetryInterval time.Duration
+	maxRetryInterval time.Duration
+}
+
+// NewLogger returns a Logger that records chained audit events to the given
+// sink. Each event's hash is an HMAC computed with the given key. Events are
+// queued, up to the given number, and recorded in order by a background
+// goroutine that retries each until the sink accepts it.
+//
+// If statePath is not empty, the head of the chain is persisted to that file
+// each time an event is recorded, and a Logger created with the same file
+// continues the chain where it left off.
+func NewLogger(
+	sink Sink,
+	key []byte,
+	queueSize int,
+	statePath string,
+) (Logger, error) {
+	l, err := newLogger(sink, key, queueSize, statePath)
+	if err != nil {
+		return nil, err
+	}
+	go l.run()
+	return l, nil
+}
+
+// newLogger returns a logger that has not yet begun recording events
+func newLogger(
+	sink Sink,
+	key []byte,
+	queueSize int,
+	statePath string,
+) (*logger, error) {
+	l := &logger{
+		sink:      sink,
+		key:       key,
+		statePath: statePath,
+		head: ChainHead{
+			ChainID: uuid.NewV4().String(),
+		},
+		queue:            make(chan queuedEvent, queueSize),
+		stopCh:           make(chan struct{}),
+		doneCh:           make(chan struct{}),
+		minRetryInterval: defaultMinRetryInterval,
+		maxRetryInterval: defaultMaxRetryInterval,
+	}
+	if statePath != "" {
+		head, ok, err := loadChainHead(statePath)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			l.head = head
+		}
+	}
+	return l, nil
+}
+
+func (l *logger) Log(event Event) {
+	if event.Time.IsZero() {
+		event.Time = time.Now().UTC()
+	}
+	logFields := log.Fields{
+		"operation": event.Operation,
+		"requestID": event.RequestID,
+	}
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	if l.closed {
+		log.WithFields(logFields).Error(
+			"audit logger is closed; dropping audit event",
+		)
+		return
+	}
+	event.ChainID = l.head.ChainID
+	event.Sequence = l.head.Sequence + 1
+	event.PreviousHash = l.head.Hash
+	event.Hash = ""
+	eventJSON, err := json.Marshal(event)
+	if err != nil {
+		log.WithField("error", err).Error("error marshaling audit event")
+		return
+	}
+	event.Hash = computeHash(l.key, eventJSON)
+	if eventJSON, err = json.Marshal(event); err != nil {
+		log.WithField("error", err).Error("error marshaling audit event")
+		return
+	}
+	head := ChainHead{
+		ChainID:  event.ChainID,
+		Sequence: event.Sequence,
+		Hash:     event.Hash,
+	}
+	select {
+	case l.queue <- queuedEvent{eventJSON: eventJSON, head: head}:
+		l.head = head
+	default:
+		// The event isn't part of the chain if it can't be recorded
+		log.WithFields(logFields).Error(
+			"audit event queue is full; dropping audit event",
+		)
+	}
+}
+
+func (l *logger) Close() {
+	l.mutex.Lock()
+	if l.closed {
+		l.mutex.Unlock()
+		return
+	}
+	l.closed = true
+	l.mutex.Unlock()
+	close(l.stopCh)
+	<-l.doneCh
+}
+
+// run records queued events, in order, until the logger is closed. Each event
+// is retried, with exponential backoff, until the sink accepts it. Once the
+// logger is closed, each event that remains queued is attempted only once.
+func (l *logger) run() {
+	defer close(l.doneCh)
+	for {
+		select {
+		case qe := <-l.queue:
+			l.record(qe, true)
+		case <-l.stopCh:
+			for {
+				select {
+				case qe := <-l.queue:
+					l.record(qe, false)
+				default:
+					return
+				}
+			}
+		}
+	}
+}
+
+func (l *logger) record(qe queuedEvent, retry bool) {
+	logFields := log.Fields{
+		"chainID":  qe.head.ChainID,
+		"sequence": qe.head.Sequence,
+	}
+	retryInterval := l.minRetryInterval
+	for {
+		err := l.sink.Write(qe.eventJSON)
+		if err == nil {
+			break
+		}
+		logFields["error"] = err
+		if !retry {
+			log.WithFields(logFields).Error(
+				"error recording audit event; audit logger is closed, so the event " +
+					"is dropped",
+			)
+			return
+		}
+		log.WithFields(logFields).WithField("retryIn", retryInterval).Error(
+			"error recording audit event",
+		)
+		select {
+		case <-time.After(retryInterval):
+		case <-l.stopCh:
+			// Make one final attempt without further delay
+			retry = false
+		}
+		if retryInterval *= 2; retryInterval > l.maxRetryInterval {
+			retryInterval = l.maxRetryInterval
+		}
+	}
+	if l.statePath != "" {
+		if err := saveChainHead(l.statePath, qe.head); err != nil {
+			logFields["error"] = err
+			log.WithFields(logFields).Error("error persisting audit chain head")
+		}
+	}
+}
+
+// loadChainHead loads the chain head persisted to the given file. If the file
+// doesn't exist, false is returned.
+func loadChainHead(path string) (ChainHead, bool, error) {
+	head := ChainHead{}
+	data, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return head, false, nil
+	}
+	if err != nil {
+		return head, false, fmt.Errorf(
+			"error reading audit chain head: %s",
+			err,
+		)
+	}
+	if err = json.Unmarshal(data, &head); err != nil {
+		return head, false, fmt.Errorf(
+			"error unmarshaling audit chain head: %s",
+			err,
+		)
+	}
+	return head, true, nil
+}
+
+// saveChainHead atomically replaces the chain head persisted to the given
+// file
+func saveChainHead(path string, head ChainHead) error {
+	data, err := json.Marshal(head)
+	if err != nil {
+		return err
+	}
+	tmp, err := ioutil.TempFile(filepath.Dir(path), filepath.Base(path))
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name()) // nolint: errcheck
+	if _, err = tmp.Write(data); err != nil {
+		tmp.Close() // nolint: errcheck
+		return err
+	}
+	if err = tmp.Sync(); err != nil {
+		tmp.Close() // nolint: errcheck
+		return err
+	}
+	if err = tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// computeHash returns the hex encoded HMAC-SHA256 of the given event JSON
+func computeHash(key []byte, eventJSON []byte) string {
+	mac := hmac.New(sha256.New, key)
+	mac.Write(eventJSON) // nolint: errcheck
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+var (
+	globalLogger      = NewDiscardLogger()
+	globalLoggerMutex sync.RWMutex
+)
+
+// NewDiscardLogger returns a Logger that discards all events
+func NewDiscardLogger() Logger {
+	return discardLogger{}
+}
+
+type discardLogger struct{}
+
+func (discardLogger) Log(Event) {}
+
+func (discardLogger) Close() {}
+
+// InitializeGlobalLogger sets the Logger used by the package level Log
+// function. Until it is called, audit events are discarded.
+func InitializeGlobalLogger(l Logger) {
+	globalLoggerMutex.Lock()
+	defer globalLoggerMutex.Unlock()
+	globalLogger = l
+}
+
+// Log records the given event using the global Logger
+func Log(event Event) {
+	globalLoggerMutex.RLock()
+	l := globalLogger
+	globalLoggerMutex.RUnlock()
+	l.Log(event)
+}
+
+// VerifyChain checks that the given events, in order, form an unbroken and
+// unaltered chain whose hashes were computed with the given key. It returns
+// the sequence number of the first event that fails verification, or 0 if all
+// events pass.
+func VerifyChain(events []Event, key []byte) uint64 {
+	var previousHash string
+	for i, event := range events {
+		if i > 0 && event.PreviousHash != previousHash {
+			return event.Sequence
+		}
+		hash := event.Hash
+		event.Hash = ""
+		eventJSON, err := json.Marshal(event)
+		if err != nil {
+			return event.Sequence
+		}
+		expectedHash := computeHash(key, eventJSON)
+		if !hmac.Equal([]byte(expectedHash), []byte(hash)) {
+			return event.Sequence
+		}
+		previousHash = hash
+	}
+	return 0
+}
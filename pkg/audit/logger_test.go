@@ -0,0 +1,191 @@
+package audit
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoggerChainsEvents(t *testing.T) {
+	buf := &bytes.Buffer{}
+	l := getTestLogger(t, NewWriterSink(buf), "")
+	l.Log(Event{Operation: OperationProvision, InstanceID: "foo"})
+	l.Log(Event{Operation: OperationBind, InstanceID: "foo", BindingID: "bar"})
+	l.Log(Event{Operation: OperationDeprovision, InstanceID: "foo"})
+	l.Close()
+	events := getTestEvents(t, buf)
+	assert.Len(t, events, 3)
+	assert.Empty(t, events[0].PreviousHash)
+	for i, event := range events {
+		assert.Equal(t, uint64(i+1), event.Sequence)
+		assert.Equal(t, events[0].ChainID, event.ChainID)
+		assert.NotEmpty(t, event.Hash)
+		assert.False(t, event.Time.IsZero())
+		if i > 0 {
+			assert.Equal(t, events[i-1].Hash, event.PreviousHash)
+		}
+	}
+	assert.Equal(t, uint64(0), VerifyChain(events, testHMACKey))
+	// Hashes can't be verified, or forged, without the key
+	assert.Equal(t, uint64(1), VerifyChain(events, []byte("wrong key")))
+}
+
+func TestVerifyChainDetectsAlteredEvent(t *testing.T) {
+	buf := &bytes.Buffer{}
+	l := getTestLogger(t, NewWriterSink(buf), "")
+	l.Log(Event{Operation: OperationProvision, InstanceID: "foo"})
+	l.Log(Event{Operation: OperationUpdate, InstanceID: "foo"})
+	l.Close()
+	events := getTestEvents(t, buf)
+	events[1].InstanceID = "bar"
+	assert.Equal(t, uint64(2), VerifyChain(events, testHMACKey))
+}
+
+func TestVerifyChainDetectsRemovedEvent(t *testing.T) {
+	buf := &bytes.Buffer{}
+	l := getTestLogger(t, NewWriterSink(buf), "")
+	l.Log(Event{Operation: OperationProvision, InstanceID: "foo"})
+	l.Log(Event{Operation: OperationUpdate, InstanceID: "foo"})
+	l.Log(Event{Operation: OperationDeprovision, InstanceID: "foo"})
+	l.Close()
+	events := getTestEvents(t, buf)
+	assert.Equal(
+		t,
+		uint64(3),
+		VerifyChain([]Event{events[0], events[2]}, testHMACKey),
+	)
+}
+
+func TestLoggerContinuesPersistedChain(t *testing.T) {
+	dir, err := ioutil.TempDir("", "audit")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir) // nolint: errcheck
+	statePath := filepath.Join(dir, "audit.head")
+	buf := &bytes.Buffer{}
+	l := getTestLogger(t, NewWriterSink(buf), statePath)
+	l.Log(Event{Operation: OperationProvision, InstanceID: "foo"})
+	l.Close()
+	// A new logger, as if the broker had restarted
+	l = getTestLogger(t, NewWriterSink(buf), statePath)
+	l.Log(Event{Operation: OperationDeprovision, InstanceID: "foo"})
+	l.Close()
+	events := getTestEvents(t, buf)
+	assert.Len(t, events, 2)
+	assert.Equal(t, events[0].ChainID, events[1].ChainID)
+	assert.Equal(t, uint64(2), events[1].Sequence)
+	assert.Equal(t, uint64(0), VerifyChain(events, testHMACKey))
+}
+
+func TestLoggerRetriesFailedWrites(t *testing.T) {
+	buf := &bytes.Buffer{}
+	sink := &flakySink{
+		Sink:     NewWriterSink(buf),
+		failures: 2,
+	}
+	l := getTestLogger(t, sink, "")
+	l.Log(Event{Operation: OperationProvision, InstanceID: "foo"})
+	l.Log(Event{Operation: OperationUpdate, InstanceID: "foo"})
+	// Wait for both events to be recorded before closing the logger, which
+	// would otherwise stop retrying
+	for i := 0; i < 100 && sink.getWritten() < 2; i++ {
+		time.Sleep(10 * time.Millisecond)
+	}
+	l.Close()
+	events := getTestEvents(t, buf)
+	assert.Len(t, events, 2)
+	assert.Equal(t, uint64(0), VerifyChain(events, testHMACKey))
+}
+
+func TestLoggerDropsEventsWhenQueueIsFull(t *testing.T) {
+	block := make(chan struct{})
+	buf := &bytes.Buffer{}
+	l, err := NewLogger(
+		&blockingSink{Sink: NewWriterSink(buf), block: block},
+		testHMACKey,
+		1,
+		"",
+	)
+	assert.Nil(t, err)
+	// The first event is taken from the queue and blocks in the sink...
+	l.Log(Event{Operation: OperationProvision, InstanceID: "foo"})
+	for i := 0; i < 100 && len(l.(*logger).queue) > 0; i++ {
+		time.Sleep(10 * time.Millisecond)
+	}
+	// ...the second fills the queue and the third is dropped
+	l.Log(Event{Operation: OperationUpdate, InstanceID: "foo"})
+	l.Log(Event{Operation: OperationDeprovision, InstanceID: "foo"})
+	close(block)
+	l.Close()
+	events := getTestEvents(t, buf)
+	assert.Len(t, events, 2)
+	// The dropped event never joined the chain, so the chain is unbroken
+	assert.Equal(t, uint64(0), VerifyChain(events, testHMACKey))
+}
+
+var testHMACKey = []byte("AUDITHMACKey-1234567890123456789")
+
+// flakySink is a Sink that fails a given number of times before delegating to
+// another Sink
+type flakySink struct {
+	Sink
+	mutex    sync.Mutex
+	failures int
+	written  int
+}
+
+func (f *flakySink) Write(event []byte) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	if f.failures > 0 {
+		f.failures--
+		return errors.New("sink unavailable")
+	}
+	f.written++
+	return f.Sink.Write(event)
+}
+
+func (f *flakySink) getWritten() int {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	return f.written
+}
+
+// blockingSink is a Sink that blocks until its channel is closed before
+// delegating to another Sink
+type blockingSink struct {
+	Sink
+	block chan struct{}
+}
+
+func (b *blockingSink) Write(event []byte) error {
+	<-b.block
+	return b.Sink.Write(event)
+}
+
+func getTestLogger(t *testing.T, sink Sink, statePath string) Logger {
+	l, err := newLogger(sink, testHMACKey, 10, statePath)
+	assert.Nil(t, err)
+	l.minRetryInterval = time.Millisecond
+	l.maxRetryInterval = time.Millisecond
+	go l.run()
+	return l
+}
+
+func getTestEvents(t *testing.T, buf *bytes.Buffer) []Event {
+	events := []Event{}
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		event := Event{}
+		assert.Nil(t, json.Unmarshal([]byte(line), &event))
+		events = append(events, event)
+	}
+	return events
+}
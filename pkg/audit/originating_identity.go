@@ -0,0 +1,40 @@
+package audit
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"strings"
+)
+
+// OriginatingIdentityHeader is the header in which platforms convey the
+// identity of the user on whose behalf a request is made
+const OriginatingIdentityHeader = "X-Broker-API-Originating-Identity"
+
+// OriginatingIdentity represents the identity of the platform user on whose
+// behalf a request is made
+type OriginatingIdentity struct {
+	Platform string `json:"platform"`
+	// Value is the platform-specific JSON object identifying the user. If it
+	// cannot be decoded, the undecoded value is retained instead.
+	Value json.RawMessage `json:"value"`
+}
+
+// ParseOriginatingIdentity parses the value of an originating identity
+// header. It returns nil if the value is empty or malformed.
+func ParseOriginatingIdentity(header string) *OriginatingIdentity {
+	tokens := strings.Fields(header)
+	if len(tokens) != 2 {
+		return nil
+	}
+	oi := &OriginatingIdentity{
+		Platform: tokens[0],
+	}
+	if value, err := base64.StdEncoding.DecodeString(tokens[1]); err == nil &&
+		json.Valid(value) {
+		oi.Value = value
+		return oi
+	}
+	// Retain the undecoded value as a JSON string
+	oi.Value, _ = json.Marshal(tokens[1])
+	return oi
+}
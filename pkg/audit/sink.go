@@ -0,0 +1,133 @@
+package audit
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+)
+
+// Sink is an interface to be implemented by types that durably record audit
+// events. Each event is passed to the sink as a single line of JSON.
+type Sink interface {
+	// Write records a single audit event
+	Write(event []byte) error
+}
+
+// NewSink returns the Sink selected by the given configuration
+func NewSink(config Config) (Sink, error) {
+	switch config.SinkType {
+	case SinkNone:
+		return NewWriterSink(ioutil.Discard), nil
+	case SinkStdout:
+		return NewWriterSink(os.Stdout), nil
+	case SinkFile:
+		if config.FilePath == "" {
+			return nil, fmt.Errorf(
+				"AUDIT_FILE_PATH must be specified when using the %s sink",
+				SinkFile,
+			)
+		}
+		return NewFileSink(config.FilePath)
+	case SinkWebhook:
+		if config.WebhookURL == "" {
+			return nil, fmt.Errorf(
+				"AUDIT_WEBHOOK_URL must be specified when using the %s sink",
+				SinkWebhook,
+			)
+		}
+		return NewWebhookSink(config.WebhookURL, config.WebhookTimeout), nil
+	default:
+		return nil, fmt.Errorf(`unrecognized audit sink "%s"`, config.SinkType)
+	}
+}
+
+type writerSink struct {
+	writer io.Writer
+	mutex  sync.Mutex
+}
+
+// NewWriterSink returns a Sink that writes audit events to the given writer,
+// one per line
+func NewWriterSink(writer io.Writer) Sink {
+	return &writerSink{
+		writer: writer,
+	}
+}
+
+func (w *writerSink) Write(event []byte) error {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	_, err := w.writer.Write(append(event, '\n'))
+	return err
+}
+
+type fileSink struct {
+	file  *os.File
+	mutex sync.Mutex
+}
+
+// NewFileSink returns a Sink that appends audit events to the file at the
+// given path, one per line. The file is created if it doesn't exist. Each
+// event is flushed to disk before Write returns.
+func NewFileSink(path string) (Sink, error) {
+	file, err := os.OpenFile(
+		path,
+		os.O_APPEND|os.O_CREATE|os.O_WRONLY,
+		0600,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("error opening audit log file: %s", err)
+	}
+	return &fileSink{
+		file: file,
+	}, nil
+}
+
+func (f *fileSink) Write(event []byte) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	if _, err := f.file.Write(append(event, '\n')); err != nil {
+		return err
+	}
+	return f.file.Sync()
+}
+
+type webhookSink struct {
+	url        string
+	httpClient *http.Client
+}
+
+// NewWebhookSink returns a Sink that POSTs each audit event, as JSON, to the
+// given URL. Any response status other than 2xx is treated as a failure.
+func NewWebhookSink(url string, timeout time.Duration) Sink {
+	return &webhookSink{
+		url: url,
+		httpClient: &http.Client{
+			Timeout: timeout,
+		},
+	}
+}
+
+func (w *webhookSink) Write(event []byte) error {
+	resp, err := w.httpClient.Post(
+		w.url,
+		"application/json",
+		bytes.NewReader(event),
+	)
+	if err != nil {
+		return fmt.Errorf("error delivering audit event to webhook: %s", err)
+	}
+	defer resp.Body.Close() // nolint: errcheck
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf(
+			"webhook responded to audit event with unexpected status %d",
+			resp.StatusCode,
+		)
+	}
+	return nil
+}
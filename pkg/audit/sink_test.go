@@ -0,0 +1,72 @@
+package audit
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFileSinkAppends(t *testing.T) {
+	dir, err := ioutil.TempDir("", "audit")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir) // nolint: errcheck
+	path := filepath.Join(dir, "audit.log")
+	sink, err := NewFileSink(path)
+	assert.Nil(t, err)
+	assert.Nil(t, sink.Write([]byte(`{"sequence":1}`)))
+	// A new sink for the same file must not truncate it
+	sink, err = NewFileSink(path)
+	assert.Nil(t, err)
+	assert.Nil(t, sink.Write([]byte(`{"sequence":2}`)))
+	data, err := ioutil.ReadFile(path)
+	assert.Nil(t, err)
+	assert.Equal(t, "{\"sequence\":1}\n{\"sequence\":2}\n", string(data))
+}
+
+func TestWebhookSink(t *testing.T) {
+	var received []byte
+	server := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			var err error
+			received, err = ioutil.ReadAll(r.Body)
+			assert.Nil(t, err)
+			assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+			w.WriteHeader(http.StatusNoContent)
+		}),
+	)
+	defer server.Close()
+	sink := NewWebhookSink(server.URL, time.Second)
+	assert.Nil(t, sink.Write([]byte(`{"sequence":1}`)))
+	assert.Equal(t, `{"sequence":1}`, string(received))
+}
+
+func TestWebhookSinkWithErrorResponse(t *testing.T) {
+	server := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusInternalServerError)
+		}),
+	)
+	defer server.Close()
+	sink := NewWebhookSink(server.URL, time.Second)
+	assert.NotNil(t, sink.Write([]byte(`{"sequence":1}`)))
+}
+
+func TestNewSinkRequiresFilePath(t *testing.T) {
+	config := NewConfigWithDefaults()
+	config.SinkType = SinkFile
+	_, err := NewSink(config)
+	assert.NotNil(t, err)
+}
+
+func TestNewSinkWithUnrecognizedType(t *testing.T) {
+	config := NewConfigWithDefaults()
+	config.SinkType = "BOGUS"
+	_, err := NewSink(config)
+	assert.NotNil(t, err)
+}
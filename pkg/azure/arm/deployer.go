@@ -10,6 +10,7 @@ import (
 
 	resourcesSDK "github.com/Azure/azure-sdk-for-go/services/resources/mgmt/2017-05-10/resources" // nolint: lll
 	"github.com/Azure/go-autorest/autorest"
+	brokerLog "github.com/Azure/open-service-broker-azure/pkg/log"
 	"github.com/Azure/open-service-broker-azure/pkg/template"
 	log "github.com/Sirupsen/logrus"
 )
@@ -25,9 +26,12 @@ const (
 )
 
 // Deployer is an interface to be implemented by any component capable of
-// deploying resource to Azure using an ARM template
+// deploying resource to Azure using an ARM template. The context passed to
+// each function carries tracing information only; canceling it does not
+// abandon an in-flight ARM operation.
 type Deployer interface {
 	Deploy(
+		ctx context.Context,
 		deploymentName string,
 		resourceGroupName string,
 		location string,
@@ -37,6 +41,7 @@ type Deployer interface {
 		tags map[string]string,
 	) (map[string]interface{}, error)
 	Update(
+		ctx context.Context,
 		deploymentName string,
 		resourceGroupName string,
 		location string,
@@ -45,7 +50,11 @@ type Deployer interface {
 		armParams map[string]interface{},
 		tags map[string]string,
 	) (map[string]interface{}, error)
-	Delete(deploymentName string, resourceGroupName string) error
+	Delete(
+		ctx context.Context,
+		deploymentName string,
+		resourceGroupName string,
+	) error
 }
 
 // deployer is an ARM-based implementation of the Deployer interface
@@ -59,9 +68,11 @@ func NewDeployer(
 	groupsClient resourcesSDK.GroupsClient,
 	deploymentsClient resourcesSDK.DeploymentsClient,
 ) Deployer {
-	return &deployer{
-		groupsClient:      groupsClient,
-		deploymentsClient: deploymentsClient,
+	return &instrumentedDeployer{
+		deployer: &deployer{
+			groupsClient:      groupsClient,
+			deploymentsClient: deploymentsClient,
+		},
 	}
 }
 
@@ -69,6 +80,7 @@ func NewDeployer(
 // existence and status of a deployment before choosing to create a new one,
 // poll until success or failure, or return an error.
 func (d *deployer) Deploy(
+	ctx context.Context,
 	deploymentName string,
 	resourceGroupName string,
 	location string,
@@ -77,10 +89,9 @@ func (d *deployer) Deploy(
 	armParams map[string]interface{},
 	tags map[string]string,
 ) (map[string]interface{}, error) {
-	logFields := log.Fields{
-		"resourceGroup": resourceGroupName,
-		"deployment":    deploymentName,
-	}
+	logFields := brokerLog.FieldsFromContext(ctx)
+	logFields["resourceGroup"] = resourceGroupName
+	logFields["deployment"] = deploymentName
 
 	// Get the deployment and its current status
 	deployment, ds, err := d.getDeploymentAndStatus(
@@ -173,6 +184,7 @@ func (d *deployer) Deploy(
 // existence and status of a deployment before choosing to update one,
 // poll until success or failure, or return an error.
 func (d *deployer) Update(
+	ctx context.Context,
 	deploymentName string,
 	resourceGroupName string,
 	location string,
@@ -181,10 +193,9 @@ func (d *deployer) Update(
 	armParams map[string]interface{},
 	tags map[string]string,
 ) (map[string]interface{}, error) {
-	logFields := log.Fields{
-		"resourceGroup": resourceGroupName,
-		"deployment":    deploymentName,
-	}
+	logFields := brokerLog.FieldsFromContext(ctx)
+	logFields["resourceGroup"] = resourceGroupName
+	logFields["deployment"] = deploymentName
 
 	// Get the deployment's current status
 	_, ds, err := d.getDeploymentAndStatus(
@@ -283,6 +294,7 @@ func (d *deployer) Update(
 }
 
 func (d *deployer) Delete(
+	_ context.Context,
 	deploymentName string,
 	resourceGroupName string,
 ) error {
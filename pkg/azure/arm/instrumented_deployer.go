@@ -0,0 +1,102 @@
+package arm
+
+import (
+	"context"
+	"time"
+
+	"github.com/Azure/open-service-broker-azure/pkg/metrics"
+	"github.com/Azure/open-service-broker-azure/pkg/tracing"
+	"go.opencensus.io/trace"
+)
+
+// instrumentedDeployer is an implementation of the Deployer interface that
+// traces every operation performed by the Deployer it wraps and records its
+// outcome and duration
+type instrumentedDeployer struct {
+	deployer Deployer
+}
+
+func (i *instrumentedDeployer) Deploy(
+	ctx context.Context,
+	deploymentName string,
+	resourceGroupName string,
+	location string,
+	template []byte,
+	goParams interface{},
+	armParams map[string]interface{},
+	tags map[string]string,
+) (map[string]interface{}, error) {
+	ctx, span := startSpan(ctx, "deploy", deploymentName, resourceGroupName)
+	start := time.Now()
+	outputs, err := i.deployer.Deploy(
+		ctx,
+		deploymentName,
+		resourceGroupName,
+		location,
+		template,
+		goParams,
+		armParams,
+		tags,
+	)
+	metrics.RecordARMDeployment("deploy", err, time.Since(start))
+	tracing.EndSpan(span, err)
+	return outputs, err
+}
+
+func (i *instrumentedDeployer) Update(
+	ctx context.Context,
+	deploymentName string,
+	resourceGroupName string,
+	location string,
+	template []byte,
+	goParams interface{},
+	armParams map[string]interface{},
+	tags map[string]string,
+) (map[string]interface{}, error) {
+	ctx, span := startSpan(ctx, "update", deploymentName, resourceGroupName)
+	start := time.Now()
+	outputs, err := i.deployer.Update(
+		ctx,
+		deploymentName,
+		resourceGroupName,
+		location,
+		template,
+		goParams,
+		armParams,
+		tags,
+	)
+	metrics.RecordARMDeployment("update", err, time.Since(start))
+	tracing.EndSpan(span, err)
+	return outputs, err
+}
+
+func (i *instrumentedDeployer) Delete(
+	ctx context.Context,
+	deploymentName string,
+	resourceGroupName string,
+) error {
+	ctx, span := startSpan(ctx, "delete", deploymentName, resourceGroupName)
+	start := time.Now()
+	err := i.deployer.Delete(ctx, deploymentName, resourceGroupName)
+	metrics.RecordARMDeployment("delete", err, time.Since(start))
+	tracing.EndSpan(span, err)
+	return err
+}
+
+func startSpan(
+	ctx context.Context,
+	operation string,
+	deploymentName string,
+	resourceGroupName string,
+) (context.Context, *trace.Span) {
+	ctx, span := trace.StartSpan(
+		ctx,
+		"arm "+operation,
+		trace.WithSpanKind(trace.SpanKindClient),
+	)
+	span.AddAttributes(
+		trace.StringAttribute("deployment", deploymentName),
+		trace.StringAttribute("resourceGroup", resourceGroupName),
+	)
+	return ctx, span
+}
@@ -0,0 +1,40 @@
+package azure
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/Azure/go-autorest/autorest"
+	"github.com/Azure/open-service-broker-azure/pkg/health"
+)
+
+// NewTokenHealthCheck returns a health.Check that verifies a bearer token for
+// the Azure APIs can be acquired using the configured service principal. The
+// token is cached between checks and only reacquired when it nears expiry,
+// so checks don't burden Azure Active Directory.
+func NewTokenHealthCheck(config Config) (health.Check, error) {
+	authorizer, err := GetBearerTokenAuthorizer(
+		config.Environment,
+		config.TenantID,
+		config.ClientID,
+		config.ClientSecret,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return health.NewCheck("azure", func(ctx context.Context) error {
+		req, err := http.NewRequest(
+			http.MethodGet,
+			config.Environment.ResourceManagerEndpoint,
+			nil,
+		)
+		if err != nil {
+			return err
+		}
+		_, err = autorest.Prepare(
+			req.WithContext(ctx),
+			authorizer.WithAuthorization(),
+		)
+		return err
+	}), nil
+}
@@ -3,24 +3,22 @@ package boot
 import (
 	"fmt"
 
-	"github.com/Azure/open-service-broker-azure/pkg/azure"
 	"github.com/Azure/open-service-broker-azure/pkg/service"
 )
 
-// GetCatalog returns a fully initialized catalog
+// GetCatalog returns a fully initialized catalog consolidated from the given
+// modules, with any catalog overrides from the given configuration applied
 func GetCatalog(
 	catalogConfig service.CatalogConfig,
-	azureConfig azure.Config,
+	modules []service.Module,
 ) (service.Catalog, error) {
-	modules, err := getModules(azureConfig)
-	if err != nil {
-		return nil, fmt.Errorf("error getting modules: %s", err)
-	}
-
 	// Consolidate the catalogs from all the individual modules into a single
 	// catalog. Check as we go along to make sure that no two modules provide
 	// services having the same ID.
 	services := []service.Service{}
+	// Catalog overrides are validated against all services, regardless of
+	// whether they're filtered out of the catalog
+	allServices := []service.Service{}
 	usedServiceIDs := map[string]string{}
 	for _, module := range modules {
 		moduleName := module.GetName()
@@ -46,6 +44,10 @@ func GetCatalog(
 				)
 			}
 
+			allServices = append(allServices, svc)
+			serviceOverrides, _ :=
+				catalogConfig.Overrides.GetServiceOverrides(serviceID)
+
 			serviceTags := svc.GetTags()
 			tagsMap := map[string]bool{}
 			for _, t := range serviceTags {
@@ -62,15 +64,33 @@ func GetCatalog(
 
 			filteredPlans := []service.Plan{}
 			for _, plan := range svc.GetPlans() {
+				pProp := plan.GetProperties()
+				// Overrides are applied even to plans that are filtered out so that
+				// any mistakes in them are caught regardless
+				planOverrides, ok := serviceOverrides.GetPlanOverrides(plan.GetID())
+				if ok {
+					if pProp, err = planOverrides.Apply(pProp); err != nil {
+						return nil, fmt.Errorf(
+							`error applying catalog overrides to service "%s": %s`,
+							serviceID,
+							err,
+						)
+					}
+				}
+				// Advertise the plan's costs in the environment the broker is
+				// deployed to
+				costs := pProp.EnvironmentCosts[catalogConfig.Environment]
+				if costs != nil {
+					pProp.Metadata.Costs = costs
+				}
 				if plan.GetStability() >= catalogConfig.MinStability {
-					pProp := plan.GetProperties()
 					pProp.Schemas.AddCommonSchema(svc.GetProperties())
 					filteredPlans = append(filteredPlans, service.NewPlan(pProp))
 				}
 			}
 			if len(filteredPlans) > 0 {
 				services = append(services, service.NewService(
-					svc.GetProperties(),
+					serviceOverrides.Apply(svc.GetProperties()),
 					svc.GetServiceManager(),
 					filteredPlans...,
 				))
@@ -78,6 +98,11 @@ func GetCatalog(
 			}
 		}
 	}
+	if err := catalogConfig.Overrides.Validate(
+		service.NewCatalog(allServices),
+	); err != nil {
+		return nil, err
+	}
 	catalog := service.NewCatalog(services)
 
 	return catalog, nil
@@ -0,0 +1,223 @@
+package boot
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io/ioutil"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Azure/open-service-broker-azure/pkg/quota"
+	"github.com/Azure/open-service-broker-azure/pkg/service"
+	"github.com/Azure/open-service-broker-azure/pkg/storage"
+	log "github.com/Sirupsen/logrus"
+)
+
+// CatalogReloader is an interface to be implemented by components that
+// rebuild the broker's catalog from the current catalog configuration and
+// put the rebuilt catalog into effect without restarting the broker
+type CatalogReloader interface {
+	// Reload rebuilds the catalog and puts it into effect. If the catalog
+	// cannot be rebuilt, if it would no longer include the service or plan of
+	// any existing or not yet purged deleted instance, or if it would no longer
+	// include a service or plan that a quota limit refers to, an error is
+	// returned and the catalog already in effect remains so.
+	Reload() error
+	// Run causes the reloader to reload the catalog whenever the catalog
+	// configuration or overrides files change, checking for changes on the
+	// given interval. It blocks until the context passed to it has been
+	// canceled. Run always returns a non-nil error.
+	Run(ctx context.Context, interval time.Duration) error
+}
+
+type catalogReloader struct {
+	catalog     service.ReloadableCatalog
+	modules     []service.Module
+	store       storage.Store
+	quotas      *quota.Quotas
+	environment string
+	// mutex serializes reloads
+	mutex sync.Mutex
+	// files maps the paths of the files the catalog was last built from to
+	// their contents at that time
+	files map[string][]byte
+	// This allows tests to inject an alternative source of catalog
+	// configuration
+	getConfig func() (service.CatalogConfig, error)
+}
+
+// NewCatalogReloader returns a CatalogReloader that rebuilds the catalog
+// offered by the given modules and puts it into effect in the given
+// ReloadableCatalog, which must be in effect with the catalog built from the
+// given catalog configuration. Catalog configuration is subsequently obtained
+// from the environment and from the files it refers to. The store is used to
+// verify that existing instances remain resolvable. The given quotas, if any,
+// are validated against each rebuilt catalog.
+func NewCatalogReloader(
+	catalogConfig service.CatalogConfig,
+	catalog service.ReloadableCatalog,
+	modules []service.Module,
+	store storage.Store,
+	quotas *quota.Quotas,
+) (CatalogReloader, error) {
+	files, err := readCatalogFiles(catalogConfig)
+	if err != nil {
+		return nil, err
+	}
+	return &catalogReloader{
+		catalog:     catalog,
+		modules:     modules,
+		store:       store,
+		quotas:      quotas,
+		environment: catalogConfig.Environment,
+		files:       files,
+		getConfig:   service.GetCatalogConfigFromEnvironment,
+	}, nil
+}
+
+func (c *catalogReloader) Reload() error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	catalogConfig, err := c.getConfig()
+	if err != nil {
+		return fmt.Errorf("error loading catalog configuration: %s", err)
+	}
+	catalogConfig.Environment = c.environment
+	// The files are read before the catalog is built from them so that a
+	// change made while the catalog is being built is not missed
+	files, err := readCatalogFiles(catalogConfig)
+	if err != nil {
+		return err
+	}
+	catalog, err := GetCatalog(catalogConfig, c.modules)
+	if err != nil {
+		return fmt.Errorf("error building catalog: %s", err)
+	}
+	if err = c.verifyInstancesResolvable(catalog); err != nil {
+		return err
+	}
+	if err = c.quotas.Validate(catalog); err != nil {
+		return fmt.Errorf("error validating quotas: %s", err)
+	}
+	c.catalog.SetCatalog(catalog)
+	c.files = files
+	return nil
+}
+
+// verifyInstancesResolvable returns an error if the given catalog does not
+// include the service and plan of every existing instance and of every deleted
+// instance that has not yet been purged, since the store cannot retrieve
+// instances whose service or plan is not in the catalog. Instances written
+// after this check but before the catalog is put into effect are not
+// verified; such instances are unlikely since a plan that is being removed
+// from the catalog is rarely being provisioned at the same time.
+func (c *catalogReloader) verifyInstancesResolvable(
+	catalog service.Catalog,
+) error {
+	instances, err := c.store.GetInstances()
+	if err != nil {
+		return fmt.Errorf("error retrieving existing instances: %s", err)
+	}
+	deletedInstances, err := c.store.GetDeletedInstances()
+	if err != nil {
+		return fmt.Errorf("error retrieving deleted instances: %s", err)
+	}
+	instances = append(instances, deletedInstances...)
+	missing := map[string]struct{}{}
+	for _, instance := range instances {
+		svc, ok := catalog.GetService(instance.ServiceID)
+		if !ok {
+			missing[fmt.Sprintf(`service "%s"`, instance.ServiceID)] = struct{}{}
+			continue
+		}
+		if _, ok = svc.GetPlan(instance.PlanID); !ok {
+			missing[fmt.Sprintf(
+				`plan "%s" of service "%s"`,
+				instance.PlanID,
+				instance.ServiceID,
+			)] = struct{}{}
+		}
+	}
+	if len(missing) == 0 {
+		return nil
+	}
+	descriptions := make([]string, 0, len(missing))
+	for description := range missing {
+		descriptions = append(descriptions, description)
+	}
+	sort.Strings(descriptions)
+	return fmt.Errorf(
+		"the reloaded catalog would not include the following, which existing "+
+			"instances or deleted instances that have not yet been purged refer "+
+			"to: %s",
+		strings.Join(descriptions, ", "),
+	)
+}
+
+func (c *catalogReloader) Run(
+	ctx context.Context,
+	interval time.Duration,
+) error {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			if c.filesChanged() {
+				if err := c.Reload(); err != nil {
+					log.WithField("error", err).Error(
+						"error reloading catalog; the catalog already in effect " +
+							"remains so",
+					)
+				} else {
+					log.Info("reloaded catalog")
+				}
+			}
+		case <-ctx.Done():
+			log.Debug("context canceled; catalog reloader shutting down")
+			return ctx.Err()
+		}
+	}
+}
+
+// filesChanged returns a bool indicating whether the catalog configuration
+// or overrides files have changed since the catalog was last built from them.
+// Files that cannot be read are treated as changed so that the resulting
+// error is reported by Reload.
+func (c *catalogReloader) filesChanged() bool {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	for path, contents := range c.files {
+		currentContents, err := ioutil.ReadFile(path)
+		if err != nil || !bytes.Equal(currentContents, contents) {
+			return true
+		}
+	}
+	return false
+}
+
+// readCatalogFiles returns a map of the paths of the catalog configuration
+// and overrides files referred to by the given catalog configuration to their
+// contents
+func readCatalogFiles(
+	catalogConfig service.CatalogConfig,
+) (map[string][]byte, error) {
+	files := map[string][]byte{}
+	for _, path := range []string{
+		catalogConfig.ConfigFile,
+		catalogConfig.OverridesFile,
+	} {
+		if path == "" {
+			continue
+		}
+		contents, err := ioutil.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf(`error reading "%s": %s`, path, err)
+		}
+		files[path] = contents
+	}
+	return files, nil
+}
@@ -0,0 +1,122 @@
+package boot
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/Azure/open-service-broker-azure/pkg/quota"
+	"github.com/Azure/open-service-broker-azure/pkg/service"
+	"github.com/Azure/open-service-broker-azure/pkg/services/fake"
+	"github.com/Azure/open-service-broker-azure/pkg/storage/memory"
+	"github.com/stretchr/testify/assert"
+)
+
+func getTestCatalogReloader(
+	t *testing.T,
+	catalogConfig service.CatalogConfig,
+) (*catalogReloader, service.ReloadableCatalog) {
+	fakeModule, err := fake.New()
+	assert.Nil(t, err)
+	modules := []service.Module{fakeModule}
+	initialCatalog, err := GetCatalog(catalogConfig, modules)
+	assert.Nil(t, err)
+	catalog := service.NewReloadableCatalog(initialCatalog)
+	reloader, err := NewCatalogReloader(
+		catalogConfig,
+		catalog,
+		modules,
+		memory.NewStore(catalog),
+		nil,
+	)
+	assert.Nil(t, err)
+	return reloader.(*catalogReloader), catalog
+}
+
+func TestReloadRefusesToRemovePlansInUse(t *testing.T) {
+	catalogConfig := service.NewCatalogConfigWithDefaults()
+	catalogConfig.MinStability = service.StabilityExperimental
+	reloader, catalog := getTestCatalogReloader(t, catalogConfig)
+	err := reloader.store.WriteInstance(
+		service.Instance{
+			InstanceID: "instance",
+			ServiceID:  fake.ServiceID,
+			PlanID:     fake.StandardPlanID,
+		},
+	)
+	assert.Nil(t, err)
+	initialCatalog := catalog.GetCatalog()
+
+	// The fake service's only plan is experimental, so requiring stable
+	// services would remove it from the catalog
+	reloader.getConfig = func() (service.CatalogConfig, error) {
+		stableConfig := service.NewCatalogConfigWithDefaults()
+		stableConfig.MinStability = service.StabilityStable
+		return stableConfig, nil
+	}
+	assert.NotNil(t, reloader.Reload())
+	assert.True(t, catalog.GetCatalog() == initialCatalog)
+	_, ok := catalog.GetService(fake.ServiceID)
+	assert.True(t, ok)
+
+	// Nor can it be removed while the deleted instance awaits purging
+	_, err = reloader.store.DeleteInstance("instance")
+	assert.Nil(t, err)
+	assert.NotNil(t, reloader.Reload())
+	assert.True(t, catalog.GetCatalog() == initialCatalog)
+
+	// Once the instance is gone, the plan can be removed
+	_, err = reloader.store.PurgeDeleted(time.Now().Add(time.Minute))
+	assert.Nil(t, err)
+	assert.Nil(t, reloader.Reload())
+	_, ok = catalog.GetService(fake.ServiceID)
+	assert.False(t, ok)
+}
+
+func TestReloadRefusesToRemovePlansInQuotas(t *testing.T) {
+	catalogConfig := service.NewCatalogConfigWithDefaults()
+	catalogConfig.MinStability = service.StabilityExperimental
+	reloader, catalog := getTestCatalogReloader(t, catalogConfig)
+	reloader.quotas = &quota.Quotas{
+		Limits: []quota.Limit{
+			{
+				Name:      "standard-per-namespace",
+				ServiceID: fake.ServiceID,
+				PlanID:    fake.StandardPlanID,
+				Key:       quota.KeyNamespace,
+				Max:       1,
+			},
+		},
+	}
+	initialCatalog := catalog.GetCatalog()
+	reloader.getConfig = func() (service.CatalogConfig, error) {
+		stableConfig := service.NewCatalogConfigWithDefaults()
+		stableConfig.MinStability = service.StabilityStable
+		return stableConfig, nil
+	}
+	assert.NotNil(t, reloader.Reload())
+	assert.True(t, catalog.GetCatalog() == initialCatalog)
+}
+
+func TestFilesChanged(t *testing.T) {
+	dir, err := ioutil.TempDir("", "catalog")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir) // nolint: errcheck
+	catalogConfig := service.NewCatalogConfigWithDefaults()
+	catalogConfig.ConfigFile = filepath.Join(dir, "catalog-config.json")
+	err = ioutil.WriteFile(catalogConfig.ConfigFile, []byte(`{}`), 0600)
+	assert.Nil(t, err)
+	reloader, _ := getTestCatalogReloader(t, catalogConfig)
+	assert.False(t, reloader.filesChanged())
+	err = ioutil.WriteFile(
+		catalogConfig.ConfigFile,
+		[]byte(`{"minStability":"STABLE"}`),
+		0600,
+	)
+	assert.Nil(t, err)
+	assert.True(t, reloader.filesChanged())
+	assert.Nil(t, os.Remove(catalogConfig.ConfigFile))
+	assert.True(t, reloader.filesChanged())
+}
@@ -0,0 +1,35 @@
+package boot
+
+import (
+	"fmt"
+
+	"github.com/Azure/open-service-broker-azure/pkg/azure"
+	"github.com/Azure/open-service-broker-azure/pkg/health"
+	"github.com/Azure/open-service-broker-azure/pkg/service"
+)
+
+// GetHealthChecks returns checks of Azure and of any components the given
+// modules depend upon. Module checks are named after the module that
+// contributed them.
+func GetHealthChecks(
+	azureConfig azure.Config,
+	modules []service.Module,
+) ([]health.Check, error) {
+	azureCheck, err := azure.NewTokenHealthCheck(azureConfig)
+	if err != nil {
+		return nil, fmt.Errorf("error getting azure health check: %s", err)
+	}
+	checks := []health.Check{azureCheck}
+	for _, module := range modules {
+		for _, check := range module.GetHealthChecks() {
+			checks = append(
+				checks,
+				health.NewCheck(
+					fmt.Sprintf("%s/%s", module.GetName(), check.GetName()),
+					check.Execute,
+				),
+			)
+		}
+	}
+	return checks, nil
+}
@@ -38,7 +38,8 @@ import (
 	"github.com/Azure/open-service-broker-azure/pkg/version"
 )
 
-func getModules(
+// GetModules returns all of the broker's modules, fully initialized
+func GetModules(
 	azureConfig azure.Config,
 ) ([]service.Module, error) {
 	azureSubscriptionID := azureConfig.SubscriptionID
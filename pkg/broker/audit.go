@@ -0,0 +1,36 @@
+package broker
+
+import (
+	"context"
+
+	"github.com/Azure/open-service-broker-azure/pkg/audit"
+	brokerLog "github.com/Azure/open-service-broker-azure/pkg/log"
+	"github.com/Azure/open-service-broker-azure/pkg/service"
+)
+
+// recordCompletion records an audit event for the completion of asynchronous
+// execution of the given operation on the given instance. A nil error
+// indicates the operation succeeded. The event is attributed to the actor
+// carried by the given context, which originates from the task args.
+func recordCompletion(
+	ctx context.Context,
+	operation string,
+	instance service.Instance,
+	err error,
+) {
+	event := audit.Event{
+		Operation:  operation,
+		Phase:      audit.PhaseCompletion,
+		Outcome:    audit.OutcomeSucceeded,
+		Actor:      audit.ActorFromContext(ctx),
+		InstanceID: instance.InstanceID,
+		ServiceID:  instance.ServiceID,
+		PlanID:     instance.PlanID,
+	}
+	event.RequestID, _ = brokerLog.RequestIDFromContext(ctx)
+	if err != nil {
+		event.Outcome = audit.OutcomeFailed
+		event.Error = err.Error()
+	}
+	audit.Log(event)
+}
@@ -59,14 +59,17 @@ func NewBroker(
 
 	err := b.asyncEngine.RegisterJob(
 		"executeProvisioningStep",
-		b.executeProvisioningStep,
+		instrumentJob("executeProvisioningStep", b.executeProvisioningStep),
 	)
 	if err != nil {
 		return nil, errors.New(
 			"error registering async job for executing provisioning steps",
 		)
 	}
-	err = b.asyncEngine.RegisterJob("executeUpdatingStep", b.executeUpdatingStep)
+	err = b.asyncEngine.RegisterJob(
+		"executeUpdatingStep",
+		instrumentJob("executeUpdatingStep", b.executeUpdatingStep),
+	)
 	if err != nil {
 		return nil, errors.New(
 			"error registering async job for executing updating steps",
@@ -74,7 +77,7 @@ func NewBroker(
 	}
 	err = b.asyncEngine.RegisterJob(
 		"executeDeprovisioningStep",
-		b.executeDeprovisioningStep,
+		instrumentJob("executeDeprovisioningStep", b.executeDeprovisioningStep),
 	)
 	if err != nil {
 		return nil, errors.New(
@@ -82,7 +85,10 @@ func NewBroker(
 		)
 	}
 
-	err = b.asyncEngine.RegisterJob("checkParentStatus", b.doCheckParentStatus)
+	err = b.asyncEngine.RegisterJob(
+		"checkParentStatus",
+		instrumentJob("checkParentStatus", b.doCheckParentStatus),
+	)
 	if err != nil {
 		return nil, errors.New(
 			"error registering async job for executing check of parent status",
@@ -91,7 +97,7 @@ func NewBroker(
 
 	err = b.asyncEngine.RegisterJob(
 		"checkChildrenStatuses",
-		b.doCheckChildrenStatuses,
+		instrumentJob("checkChildrenStatuses", b.doCheckChildrenStatuses),
 	)
 	if err != nil {
 		return nil, errors.New(
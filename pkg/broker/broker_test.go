@@ -149,6 +149,10 @@ func getTestBroker() (*broker, error) {
 		asyncEngine,
 		filter.NewChain(),
 		catalog,
+		nil,
+		nil,
+		nil,
+		nil,
 	)
 	if err != nil {
 		return nil, err
@@ -5,13 +5,14 @@ import (
 	"errors"
 	"time"
 
+	brokerLog "github.com/Azure/open-service-broker-azure/pkg/log"
 	"github.com/Azure/open-service-broker-azure/pkg/service"
 	log "github.com/Sirupsen/logrus"
 	"github.com/deis/async"
 )
 
 func (b *broker) doCheckChildrenStatuses(
-	_ context.Context,
+	ctx context.Context,
 	task async.Task,
 ) ([]async.Task, error) {
 	instanceID, ok := task.GetArgs()["instanceID"]
@@ -21,6 +22,7 @@ func (b *broker) doCheckChildrenStatuses(
 	instance, ok, err := b.store.GetInstance(instanceID)
 	if !ok {
 		return nil, b.handleDeprovisioningError(
+			ctx,
 			instanceID,
 			"checkChildrenStatuses",
 			nil,
@@ -29,6 +31,7 @@ func (b *broker) doCheckChildrenStatuses(
 	}
 	if err != nil {
 		return nil, b.handleDeprovisioningError(
+			ctx,
 			instanceID,
 			"checkChildrenStatuses",
 			err,
@@ -37,7 +40,7 @@ func (b *broker) doCheckChildrenStatuses(
 	}
 	childCount, err := b.store.GetInstanceChildCountByAlias(instance.Alias)
 	if err != nil {
-		log.WithFields(log.Fields{
+		brokerLog.FromContext(ctx).WithFields(log.Fields{
 			"step":       "checkChildrenStatuses",
 			"instanceID": instanceID,
 			"error":      err,
@@ -45,6 +48,7 @@ func (b *broker) doCheckChildrenStatuses(
 			"deprovisioning error: error determining child count",
 		)
 		return nil, b.handleDeprovisioningError(
+			ctx,
 			instance,
 			"checkChildrenStatuses",
 			err,
@@ -53,7 +57,7 @@ func (b *broker) doCheckChildrenStatuses(
 	}
 	if childCount > 0 {
 		//Put this task back into the queue
-		log.WithFields(log.Fields{
+		brokerLog.FromContext(ctx).WithFields(log.Fields{
 			"instanceID":          instanceID,
 			"provisionedChildren": childCount,
 		}).Debug("children not deprovisioned, will wait again")
@@ -71,7 +75,7 @@ func (b *broker) doCheckChildrenStatuses(
 	var deprovisioner service.Deprovisioner
 	deprovisioner, err = serviceManager.GetDeprovisioner(instance.Plan)
 	if err != nil {
-		log.WithFields(log.Fields{
+		brokerLog.FromContext(ctx).WithFields(log.Fields{
 			"instanceID": instanceID,
 			"serviceID":  instance.ServiceID,
 			"planID":     instance.PlanID,
@@ -81,6 +85,7 @@ func (b *broker) doCheckChildrenStatuses(
 				"service and plan",
 		)
 		return nil, b.handleDeprovisioningError(
+			ctx,
 			instance,
 			"checkChildrenStatuses",
 			err,
@@ -89,7 +94,7 @@ func (b *broker) doCheckChildrenStatuses(
 	}
 	deprovisionFirstStep, ok := deprovisioner.GetFirstStepName()
 	if !ok {
-		log.WithFields(log.Fields{
+		brokerLog.FromContext(ctx).WithFields(log.Fields{
 			"instanceID": instanceID,
 			"serviceID":  instance.ServiceID,
 			"planID":     instance.PlanID,
@@ -98,6 +103,7 @@ func (b *broker) doCheckChildrenStatuses(
 				"service and plan",
 		)
 		return nil, b.handleDeprovisioningError(
+			ctx,
 			instance,
 			"checkChildrenStatuses",
 			nil,
@@ -109,6 +115,7 @@ func (b *broker) doCheckChildrenStatuses(
 	instance.Status = service.InstanceStateDeprovisioning
 	if err = b.store.WriteInstance(instance); err != nil {
 		return nil, b.handleDeprovisioningError(
+			ctx,
 			instance,
 			"checkChildrenStatuses",
 			err,
@@ -117,7 +124,7 @@ func (b *broker) doCheckChildrenStatuses(
 	}
 
 	// Put the real deprovision task into the queue
-	log.WithFields(log.Fields{
+	brokerLog.FromContext(ctx).WithFields(log.Fields{
 		"step":       "checkChildrenStatuses",
 		"instanceID": instanceID,
 	}).Debug("children deprovisioned,  sending start deprovision task")
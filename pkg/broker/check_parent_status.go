@@ -6,13 +6,14 @@ import (
 	"fmt"
 	"time"
 
+	brokerLog "github.com/Azure/open-service-broker-azure/pkg/log"
 	"github.com/Azure/open-service-broker-azure/pkg/service"
 	log "github.com/Sirupsen/logrus"
 	"github.com/deis/async"
 )
 
 func (b *broker) doCheckParentStatus(
-	_ context.Context,
+	ctx context.Context,
 	task async.Task,
 ) ([]async.Task, error) {
 
@@ -24,6 +25,7 @@ func (b *broker) doCheckParentStatus(
 	instance, ok, err := b.store.GetInstance(instanceID)
 	if !ok {
 		return nil, b.handleProvisioningError(
+			ctx,
 			instanceID,
 			"checkParentStatus",
 			nil,
@@ -32,15 +34,17 @@ func (b *broker) doCheckParentStatus(
 	}
 	if err != nil {
 		return nil, b.handleProvisioningError(
+			ctx,
 			instanceID,
 			"checkParentStatus",
 			err,
 			"error loading persisted instance",
 		)
 	}
-	waitForParent, err := b.waitForParent(instance)
+	waitForParent, err := b.waitForParent(ctx, instance)
 	if err != nil {
 		return nil, b.handleProvisioningError(
+			ctx,
 			instance,
 			"checkParentStatus",
 			err,
@@ -48,7 +52,7 @@ func (b *broker) doCheckParentStatus(
 		)
 	}
 	if waitForParent {
-		log.WithFields(log.Fields{
+		brokerLog.FromContext(ctx).WithFields(log.Fields{
 			"instanceID": instanceID,
 		}).Debug("parent not done, will wait again")
 		return []async.Task{
@@ -65,7 +69,7 @@ func (b *broker) doCheckParentStatus(
 	var provisioner service.Provisioner
 	provisioner, err = serviceManager.GetProvisioner(instance.Plan)
 	if err != nil {
-		log.WithFields(log.Fields{
+		brokerLog.FromContext(ctx).WithFields(log.Fields{
 			"instanceID": instanceID,
 			"serviceID":  instance.ServiceID,
 			"planID":     instance.PlanID,
@@ -75,6 +79,7 @@ func (b *broker) doCheckParentStatus(
 				"service and plan",
 		)
 		return nil, b.handleProvisioningError(
+			ctx,
 			instanceID,
 			"checkParentStatus",
 			err,
@@ -83,7 +88,7 @@ func (b *broker) doCheckParentStatus(
 	}
 	provisionFirstStep, ok := provisioner.GetFirstStepName()
 	if !ok {
-		log.WithFields(log.Fields{
+		brokerLog.FromContext(ctx).WithFields(log.Fields{
 			"instanceID": instanceID,
 			"serviceID":  instance.ServiceID,
 			"planID":     instance.PlanID,
@@ -92,13 +97,14 @@ func (b *broker) doCheckParentStatus(
 				"service and plan",
 		)
 		return nil, b.handleProvisioningError(
+			ctx,
 			instanceID,
 			"checkParentStatus",
 			err,
 			"error: no steps found for provisioning service and plan",
 		)
 	}
-	log.WithFields(log.Fields{
+	brokerLog.FromContext(ctx).WithFields(log.Fields{
 		"step":       "checkParentStatus",
 		"instanceID": instanceID,
 	}).Debug("parent done, sending start provision task")
@@ -107,6 +113,7 @@ func (b *broker) doCheckParentStatus(
 	instance.Status = service.InstanceStateProvisioning
 	if err = b.store.WriteInstance(instance); err != nil {
 		return nil, b.handleProvisioningError(
+			ctx,
 			instance,
 			"checkParentStatus",
 			err,
@@ -126,7 +133,10 @@ func (b *broker) doCheckParentStatus(
 	}, nil
 }
 
-func (b *broker) waitForParent(instance service.Instance) (bool, error) {
+func (b *broker) waitForParent(
+	ctx context.Context,
+	instance service.Instance,
+) (bool, error) {
 	//Parent has not been submitted yet, so wait for that
 	if instance.Parent == nil {
 		return true, nil
@@ -134,7 +144,7 @@ func (b *broker) waitForParent(instance service.Instance) (bool, error) {
 
 	//If parent failed, we should not even attempt to provision this
 	if instance.Parent.Status == service.InstanceStateProvisioningFailed {
-		log.WithFields(log.Fields{
+		brokerLog.FromContext(ctx).WithFields(log.Fields{
 			"error":      "waitforParent",
 			"instanceID": instance.InstanceID,
 			"parentID":   instance.Parent.InstanceID,
@@ -145,7 +155,7 @@ func (b *broker) waitForParent(instance service.Instance) (bool, error) {
 	}
 	//If parent is deprovisioning, we should not even attempt to provision this
 	if instance.Parent.Status == service.InstanceStateDeprovisioning {
-		log.WithFields(log.Fields{
+		brokerLog.FromContext(ctx).WithFields(log.Fields{
 			"error":      "waitforParent",
 			"instanceID": instance.InstanceID,
 			"parentID":   instance.Parent.InstanceID,
@@ -0,0 +1,52 @@
+package broker
+
+import (
+	"context"
+	"time"
+
+	"github.com/Azure/open-service-broker-azure/pkg/audit"
+	brokerLog "github.com/Azure/open-service-broker-azure/pkg/log"
+	"github.com/Azure/open-service-broker-azure/pkg/metrics"
+	"github.com/Azure/open-service-broker-azure/pkg/tracing"
+	"github.com/deis/async"
+	"go.opencensus.io/trace"
+)
+
+// instrumentJob wraps the given async job function so that every step it
+// executes is traced, logged with fields correlating it to the originating
+// request and instance, and its outcome and duration are recorded. If the task
+// carries a span context, the step's span continues that trace. The span
+// context, request ID and audit actor are, in turn, passed along to any tasks
+// the step returns. Jobs that aren't composed of named steps (e.g. status
+// checks) are recorded with the job's name as the step name.
+func instrumentJob(jobName string, fn async.JobFn) async.JobFn {
+	return func(ctx context.Context, task async.Task) ([]async.Task, error) {
+		args := task.GetArgs()
+		stepName, ok := args["stepName"]
+		if !ok {
+			stepName = jobName
+		}
+		ctx = brokerLog.NewContext(ctx, brokerLog.ExtractFromTaskArgs(args))
+		ctx = audit.NewContextFromTaskArgs(ctx, args)
+		spanName := jobName + " " + stepName
+		var span *trace.Span
+		if sc, ok := tracing.ExtractFromTaskArgs(args); ok {
+			ctx, span = trace.StartSpanWithRemoteParent(ctx, spanName, sc)
+		} else {
+			ctx, span = trace.StartSpan(ctx, spanName)
+		}
+		span.AddAttributes(
+			trace.StringAttribute("instanceID", args["instanceID"]),
+		)
+		start := time.Now()
+		tasks, err := fn(ctx, task)
+		metrics.RecordAsyncStep(jobName, stepName, err, time.Since(start))
+		tracing.EndSpan(span, err)
+		for _, t := range tasks {
+			brokerLog.InjectIntoTaskArgs(ctx, t.GetArgs())
+			audit.InjectIntoTaskArgs(ctx, t.GetArgs())
+			tracing.InjectIntoTaskArgs(ctx, t.GetArgs())
+		}
+		return tasks, err
+	}
+}
@@ -5,6 +5,8 @@ import (
 	"errors"
 	"fmt"
 
+	"github.com/Azure/open-service-broker-azure/pkg/audit"
+	brokerLog "github.com/Azure/open-service-broker-azure/pkg/log"
 	"github.com/Azure/open-service-broker-azure/pkg/service"
 	log "github.com/Sirupsen/logrus"
 	"github.com/deis/async"
@@ -28,6 +30,7 @@ func (b *broker) executeProvisioningStep(
 	instance, ok, err := b.store.GetInstance(instanceID)
 	if err != nil {
 		return nil, b.handleProvisioningError(
+			ctx,
 			instanceID,
 			stepName,
 			err,
@@ -36,16 +39,24 @@ func (b *broker) executeProvisioningStep(
 	}
 	if !ok {
 		return nil, b.handleProvisioningError(
+			ctx,
 			instanceID,
 			stepName,
 			nil,
 			"instance does not exist in the data store",
 		)
 	}
-	log.WithFields(log.Fields{
-		"step":       stepName,
-		"instanceID": instance.InstanceID,
-	}).Debug("executing provisioning step")
+	ctx = brokerLog.NewContext(
+		ctx,
+		log.Fields{
+			"serviceID": instance.ServiceID,
+			"planID":    instance.PlanID,
+		},
+	)
+	brokerLog.FromContext(ctx).WithField(
+		"step",
+		stepName,
+	).Debug("executing provisioning step")
 	serviceManager := instance.Service.GetServiceManager()
 
 	// Retrieve a second copy of the instance from storage. Why? We're about to
@@ -60,6 +71,7 @@ func (b *broker) executeProvisioningStep(
 	instanceCopy, _, err := b.store.GetInstance(instanceID)
 	if err != nil {
 		return nil, b.handleProvisioningError(
+			ctx,
 			instanceID,
 			stepName,
 			err,
@@ -70,6 +82,7 @@ func (b *broker) executeProvisioningStep(
 	provisioner, err := serviceManager.GetProvisioner(instance.Plan)
 	if err != nil {
 		return nil, b.handleProvisioningError(
+			ctx,
 			instance,
 			stepName,
 			err,
@@ -82,6 +95,7 @@ func (b *broker) executeProvisioningStep(
 	step, ok := provisioner.GetStep(stepName)
 	if !ok {
 		return nil, b.handleProvisioningError(
+			ctx,
 			instance,
 			stepName,
 			nil,
@@ -91,6 +105,7 @@ func (b *broker) executeProvisioningStep(
 	updatedDetails, err := step.Execute(ctx, instance)
 	if err != nil {
 		return nil, b.handleProvisioningError(
+			ctx,
 			instance,
 			stepName,
 			err,
@@ -99,8 +114,10 @@ func (b *broker) executeProvisioningStep(
 	}
 	instanceCopy.Details = updatedDetails
 	if nextStepName, ok := provisioner.GetNextStepName(step.GetName()); ok {
+		instanceCopy.Step = nextStepName
 		if err = b.store.WriteInstance(instanceCopy); err != nil {
 			return nil, b.handleProvisioningError(
+				ctx,
 				instanceCopy,
 				stepName,
 				err,
@@ -119,14 +136,17 @@ func (b *broker) executeProvisioningStep(
 	}
 	// No next step-- we're done provisioning!
 	instanceCopy.Status = service.InstanceStateProvisioned
+	instanceCopy.Step = ""
 	if err = b.store.WriteInstance(instanceCopy); err != nil {
 		return nil, b.handleProvisioningError(
+			ctx,
 			instanceCopy,
 			stepName,
 			err,
 			"error persisting instance",
 		)
 	}
+	recordCompletion(ctx, audit.OperationProvision, instanceCopy, nil)
 	return nil, nil
 }
 
@@ -138,6 +158,7 @@ func (b *broker) executeProvisioningStep(
 // returned by the caller of this function. If an instanceID is passed in
 // (instead of an instance), only error formatting is handled.
 func (b *broker) handleProvisioningError(
+	ctx context.Context,
 	instanceOrInstanceID interface{},
 	stepName string,
 	e error,
@@ -182,13 +203,15 @@ func (b *broker) handleProvisioningError(
 		)
 	}
 	instance.StatusReason = ret.Error()
+	instance.Step = stepName
 	if err := b.store.WriteInstance(instance); err != nil {
-		log.WithFields(log.Fields{
+		brokerLog.FromContext(ctx).WithFields(log.Fields{
 			"instanceID":       instance.InstanceID,
 			"status":           instance.Status,
 			"originalError":    ret,
 			"persistenceError": err,
 		}).Fatal("error persisting instance with updated status")
 	}
+	recordCompletion(ctx, audit.OperationProvision, instance, ret)
 	return ret
 }
@@ -5,6 +5,8 @@ import (
 	"errors"
 	"fmt"
 
+	"github.com/Azure/open-service-broker-azure/pkg/audit"
+	brokerLog "github.com/Azure/open-service-broker-azure/pkg/log"
 	"github.com/Azure/open-service-broker-azure/pkg/service"
 	log "github.com/Sirupsen/logrus"
 	"github.com/deis/async"
@@ -28,6 +30,7 @@ func (b *broker) executeUpdatingStep(
 	instance, ok, err := b.store.GetInstance(instanceID)
 	if err != nil {
 		return nil, b.handleUpdatingError(
+			ctx,
 			instanceID,
 			stepName,
 			err,
@@ -36,16 +39,24 @@ func (b *broker) executeUpdatingStep(
 	}
 	if !ok {
 		return nil, b.handleUpdatingError(
+			ctx,
 			instanceID,
 			stepName,
 			nil,
 			"instance does not exist in the data store",
 		)
 	}
-	log.WithFields(log.Fields{
-		"step":       stepName,
-		"instanceID": instance.InstanceID,
-	}).Debug("executing updating step")
+	ctx = brokerLog.NewContext(
+		ctx,
+		log.Fields{
+			"serviceID": instance.ServiceID,
+			"planID":    instance.PlanID,
+		},
+	)
+	brokerLog.FromContext(ctx).WithField(
+		"step",
+		stepName,
+	).Debug("executing updating step")
 	serviceManager := instance.Service.GetServiceManager()
 
 	// Retrieve a second copy of the instance from storage. Why? We're about to
@@ -60,6 +71,7 @@ func (b *broker) executeUpdatingStep(
 	instanceCopy, _, err := b.store.GetInstance(instanceID)
 	if err != nil {
 		return nil, b.handleProvisioningError(
+			ctx,
 			instanceID,
 			stepName,
 			err,
@@ -70,6 +82,7 @@ func (b *broker) executeUpdatingStep(
 	updater, err := serviceManager.GetUpdater(instance.Plan)
 	if err != nil {
 		return nil, b.handleUpdatingError(
+			ctx,
 			instance,
 			stepName,
 			err,
@@ -82,6 +95,7 @@ func (b *broker) executeUpdatingStep(
 	step, ok := updater.GetStep(stepName)
 	if !ok {
 		return nil, b.handleUpdatingError(
+			ctx,
 			instance,
 			stepName,
 			nil,
@@ -91,6 +105,7 @@ func (b *broker) executeUpdatingStep(
 	updatedDetails, err := step.Execute(ctx, instance)
 	if err != nil {
 		return nil, b.handleUpdatingError(
+			ctx,
 			instance,
 			stepName,
 			err,
@@ -99,8 +114,10 @@ func (b *broker) executeUpdatingStep(
 	}
 	instanceCopy.Details = updatedDetails
 	if nextStepName, ok := updater.GetNextStepName(step.GetName()); ok {
+		instanceCopy.Step = nextStepName
 		if err = b.store.WriteInstance(instanceCopy); err != nil {
 			return nil, b.handleUpdatingError(
+				ctx,
 				instanceCopy,
 				stepName,
 				err,
@@ -119,6 +136,7 @@ func (b *broker) executeUpdatingStep(
 	}
 	// No next step-- we're done updating!
 	instanceCopy.Status = service.InstanceStateProvisioned
+	instanceCopy.Step = ""
 	// Set Provision Parameters to the values of Updating Parameters.
 	// No need to merge here, as it was done in the API surface before
 	// the update kicked off
@@ -127,12 +145,14 @@ func (b *broker) executeUpdatingStep(
 	instanceCopy.UpdatingParameters = nil
 	if err = b.store.WriteInstance(instanceCopy); err != nil {
 		return nil, b.handleUpdatingError(
+			ctx,
 			instanceCopy,
 			stepName,
 			err,
 			"error persisting instance",
 		)
 	}
+	recordCompletion(ctx, audit.OperationUpdate, instanceCopy, nil)
 	return nil, nil
 }
 
@@ -144,6 +164,7 @@ func (b *broker) executeUpdatingStep(
 // returned by the caller of this function. If an instanceID is passed in
 // (instead of an instance), only error formatting is handled.
 func (b *broker) handleUpdatingError(
+	ctx context.Context,
 	instanceOrInstanceID interface{},
 	stepName string,
 	e error,
@@ -188,13 +209,15 @@ func (b *broker) handleUpdatingError(
 		)
 	}
 	instance.StatusReason = ret.Error()
+	instance.Step = stepName
 	if err := b.store.WriteInstance(instance); err != nil {
-		log.WithFields(log.Fields{
+		brokerLog.FromContext(ctx).WithFields(log.Fields{
 			"instanceID":       instance.InstanceID,
 			"status":           instance.Status,
 			"originalError":    ret,
 			"persistenceError": err,
 		}).Fatal("error persisting instance with updated status")
 	}
+	recordCompletion(ctx, audit.OperationUpdate, instance, ret)
 	return ret
 }
@@ -0,0 +1,104 @@
+// Package certs provides TLS certificate handling shared by the broker's
+// HTTP servers
+package certs
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"fmt"
+	"io/ioutil"
+	"sync/atomic"
+	"time"
+
+	log "github.com/Sirupsen/logrus"
+)
+
+// Reloader serves a TLS certificate and key loaded from files, reloading them
+// whenever the files' contents change. This allows certificates to be rotated
+// without restarting the servers that use them.
+type Reloader struct {
+	certPath string
+	keyPath  string
+	// cert holds a *tls.Certificate
+	cert    atomic.Value
+	certPEM []byte
+	keyPEM  []byte
+}
+
+// NewReloader returns a Reloader for the certificate and key at the given
+// paths. An error is returned if the certificate and key cannot be
+// loaded initially.
+func NewReloader(certPath, keyPath string) (*Reloader, error) {
+	c := &Reloader{
+		certPath: certPath,
+		keyPath:  keyPath,
+	}
+	if _, err := c.reload(); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// GetCertificate returns the most recently loaded certificate. It is suitable
+// for use as a tls.Config's GetCertificate callback.
+func (c *Reloader) GetCertificate(
+	*tls.ClientHelloInfo,
+) (*tls.Certificate, error) {
+	return c.cert.Load().(*tls.Certificate), nil
+}
+
+// reload loads the certificate and key if the contents of either file have
+// changed since they were last loaded. It returns a bool indicating whether a
+// new certificate was loaded. If the files cannot be read or do not contain a
+// valid certificate and key, an error is returned and the previously loaded
+// certificate remains in use.
+func (c *Reloader) reload() (bool, error) {
+	certPEM, err := ioutil.ReadFile(c.certPath)
+	if err != nil {
+		return false, fmt.Errorf("error reading TLS certificate: %s", err)
+	}
+	keyPEM, err := ioutil.ReadFile(c.keyPath)
+	if err != nil {
+		return false, fmt.Errorf("error reading TLS key: %s", err)
+	}
+	if bytes.Equal(certPEM, c.certPEM) && bytes.Equal(keyPEM, c.keyPEM) {
+		return false, nil
+	}
+	cert, err := tls.X509KeyPair(certPEM, keyPEM)
+	if err != nil {
+		return false, fmt.Errorf("error parsing TLS certificate and key: %s", err)
+	}
+	c.cert.Store(&cert)
+	c.certPEM = certPEM
+	c.keyPEM = keyPEM
+	return true, nil
+}
+
+// Watch checks the certificate and key files for changes at the given
+// interval until the given context is canceled, logging the outcome of each
+// attempted reload
+func (c *Reloader) Watch(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	logFields := log.Fields{
+		"certPath": c.certPath,
+		"keyPath":  c.keyPath,
+	}
+	for {
+		select {
+		case <-ticker.C:
+			reloaded, err := c.reload()
+			if err != nil {
+				log.WithFields(logFields).WithField("error", err).Error(
+					"error reloading TLS certificate; continuing to serve the " +
+						"previously loaded certificate",
+				)
+			} else if reloaded {
+				log.WithFields(logFields).Info("reloaded TLS certificate")
+			}
+		case <-ctx.Done():
+			return
+		}
+	}
+}
@@ -0,0 +1,102 @@
+package certs
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReloader(t *testing.T) {
+	dir, err := ioutil.TempDir("", "certs")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+	certPath := filepath.Join(dir, "tls.crt")
+	keyPath := filepath.Join(dir, "tls.key")
+
+	writeTestCertAndKey(t, certPath, keyPath, "foo")
+	reloader, err := NewReloader(certPath, keyPath)
+	assert.Nil(t, err)
+	assert.Equal(t, "foo", getTestReloaderCommonName(t, reloader))
+
+	// Nothing has changed
+	reloaded, err := reloader.reload()
+	assert.Nil(t, err)
+	assert.False(t, reloaded)
+
+	// The certificate has been rotated
+	writeTestCertAndKey(t, certPath, keyPath, "bar")
+	reloaded, err = reloader.reload()
+	assert.Nil(t, err)
+	assert.True(t, reloaded)
+	assert.Equal(t, "bar", getTestReloaderCommonName(t, reloader))
+
+	// A bad certificate doesn't replace the good one
+	err = ioutil.WriteFile(certPath, []byte("garbage"), 0600)
+	assert.Nil(t, err)
+	reloaded, err = reloader.reload()
+	assert.NotNil(t, err)
+	assert.False(t, reloaded)
+	assert.Equal(t, "bar", getTestReloaderCommonName(t, reloader))
+}
+
+func TestNewReloaderWithMissingFiles(t *testing.T) {
+	_, err := NewReloader("/nonexistent/tls.crt", "/nonexistent/tls.key")
+	assert.NotNil(t, err)
+}
+
+func getTestReloaderCommonName(t *testing.T, reloader *Reloader) string {
+	cert, err := reloader.GetCertificate(nil)
+	assert.Nil(t, err)
+	x509Cert, err := x509.ParseCertificate(cert.Certificate[0])
+	assert.Nil(t, err)
+	return x509Cert.Subject.CommonName
+}
+
+func writeTestCertAndKey(
+	t *testing.T,
+	certPath string,
+	keyPath string,
+	commonName string,
+) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.Nil(t, err)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: commonName},
+		NotBefore:    time.Now(),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	certDER, err := x509.CreateCertificate(
+		rand.Reader,
+		template,
+		template,
+		&key.PublicKey,
+		key,
+	)
+	assert.Nil(t, err)
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	assert.Nil(t, err)
+	err = ioutil.WriteFile(
+		certPath,
+		pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: certDER}),
+		0600,
+	)
+	assert.Nil(t, err)
+	err = ioutil.WriteFile(
+		keyPath,
+		pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}),
+		0600,
+	)
+	assert.Nil(t, err)
+}
@@ -1,31 +1,92 @@
 package aes256
 
 import (
+	"bytes"
 	"crypto/aes"
 	"crypto/cipher"
 	"crypto/rand"
 	"errors"
 	"fmt"
 	"io"
+	"strings"
 
 	"github.com/Azure/open-service-broker-azure/pkg/crypto"
 )
 
 const nonceLength = 12
 
+// keyIDPrefix marks ciphertexts that are prefixed with the identifier of the
+// key used to produce them. Such ciphertexts look like:
+//
+//	aes256:<key id>:<nonce><ciphertext>
+//
+// Ciphertexts produced by earlier versions of this codec carry no prefix at all
+// and consist only of the nonce and ciphertext.
+var keyIDPrefix = []byte("aes256:")
+
 type codec struct {
-	aesgcm cipher.AEAD
+	// legacyAESGCM is used for ciphertexts that carry no key identifier
+	legacyAESGCM cipher.AEAD
+	// aesgcms maps key identifiers to ciphers
+	aesgcms     map[string]cipher.AEAD
+	activeKeyID string
+	// activeKeyIDPrefix is the prefix for ciphertexts produced using the
+	// active key. It is empty if there is no active key ID.
+	activeKeyIDPrefix []byte
 }
 
 // NewCodec returns a new aes256-based implementation of crypto.Codec
 func NewCodec(config Config) (crypto.Codec, error) {
-	if config.Key == "" {
+	c := &codec{
+		aesgcms:     map[string]cipher.AEAD{},
+		activeKeyID: config.ActiveKeyID,
+	}
+	if config.Key == "" && len(config.Keys) == 0 {
 		return nil, errors.New("AES256 key was not specified")
 	}
-	if len(config.Key) != 32 {
+	if config.Key != "" {
+		var err error
+		if c.legacyAESGCM, err = newAESGCM(config.Key); err != nil {
+			return nil, err
+		}
+	}
+	for keyID, key := range config.Keys {
+		if keyID == "" || strings.Contains(keyID, ":") {
+			return nil, fmt.Errorf(`AES256 key ID "%s" is invalid`, keyID)
+		}
+		aesgcm, err := newAESGCM(key)
+		if err != nil {
+			return nil, fmt.Errorf(`error with AES256 key "%s": %s`, keyID, err)
+		}
+		c.aesgcms[keyID] = aesgcm
+	}
+	if len(c.aesgcms) > 0 {
+		if c.activeKeyID == "" {
+			return nil, errors.New("active AES256 key ID was not specified")
+		}
+		if _, ok := c.aesgcms[c.activeKeyID]; !ok {
+			return nil, fmt.Errorf(
+				`active AES256 key ID "%s" does not identify a configured key`,
+				c.activeKeyID,
+			)
+		}
+		c.activeKeyIDPrefix = []byte(
+			fmt.Sprintf("%s%s:", keyIDPrefix, c.activeKeyID),
+		)
+	} else if c.activeKeyID != "" {
+		return nil, fmt.Errorf(
+			`active AES256 key ID "%s" does not identify a configured key`,
+			c.activeKeyID,
+		)
+	}
+	return c, nil
+}
+
+func newAESGCM(key string) (cipher.AEAD, error) {
+	if len(key) != 32 {
 		return nil, errors.New("AES256 key is an invalid length")
 	}
-	block, err := aes.NewCipher([]byte(config.Key))
+	block, err := aes.NewCipher([]byte(key))
 	if err != nil {
 		return nil, fmt.Errorf("error creating cipher: %s", err)
 	}
@@ -33,9 +94,7 @@ func NewCodec(config Config) (crypto.Codec, error) {
 	if err != nil {
 		return nil, fmt.Errorf("error creating cipher: %s", err)
 	}
-	return &codec{
-		aesgcm: aesgcm,
-	}, nil
+	return aesgcm, nil
 }
 
 func (c *codec) Encrypt(plaintext []byte) ([]byte, error) {
@@ -43,20 +102,55 @@ func (c *codec) Encrypt(plaintext []byte) ([]byte, error) {
 	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
 		return nil, fmt.Errorf("error generating nonce: %s", err)
 	}
-	ciphertext := c.aesgcm.Seal(nil, nonce, plaintext, nil)
-	// Return the ciphertext prefixed with the nonce-- this consolidates both
-	// into a single value so that anyone who has encrypted using this scheme
-	// isn't burdened with schlepping / storing the nonce in addition to the
-	// ciphertext. The Decrypt() function simply possesses the intelligence to
-	// split the nonce from the rest of the ciphertext before proceeding with
-	// decryption.
-	return append(nonce, ciphertext...), nil
+	aesgcm := c.legacyAESGCM
+	if c.activeKeyID != "" {
+		aesgcm = c.aesgcms[c.activeKeyID]
+	}
+	// Return the ciphertext prefixed with the key ID (if any) and the nonce--
+	// this consolidates all of them into a single value so that anyone who has
+	// encrypted using this scheme isn't burdened with schlepping / storing the
+	// key ID and nonce in addition to the ciphertext. The Decrypt() function
+	// simply possesses the intelligence to split the key ID and nonce from the
+	// rest of the ciphertext before proceeding with decryption.
+	ciphertext := make(
+		[]byte,
+		0,
+		len(c.activeKeyIDPrefix)+nonceLength+len(plaintext)+aesgcm.Overhead(),
+	)
+	ciphertext = append(ciphertext, c.activeKeyIDPrefix...)
+	ciphertext = append(ciphertext, nonce...)
+	return aesgcm.Seal(ciphertext, nonce, plaintext, nil), nil
 }
 
 func (c *codec) Decrypt(ciphertext []byte) ([]byte, error) {
+	aesgcm := c.legacyAESGCM
+	if bytes.HasPrefix(ciphertext, keyIDPrefix) {
+		ciphertext = ciphertext[len(keyIDPrefix):]
+		i := bytes.IndexByte(ciphertext, ':')
+		if i < 0 {
+			return nil, errors.New("error decrypting ciphertext: malformed key ID")
+		}
+		keyID := string(ciphertext[:i])
+		ciphertext = ciphertext[i+1:]
+		var ok bool
+		if aesgcm, ok = c.aesgcms[keyID]; !ok {
+			return nil, fmt.Errorf(
+				`error decrypting ciphertext: unknown AES256 key ID "%s"`,
+				keyID,
+			)
+		}
+	} else if aesgcm == nil {
+		return nil, errors.New(
+			"error decrypting ciphertext: ciphertext does not carry a key ID and " +
+				"no AES256 key without an ID is configured",
+		)
+	}
+	if len(ciphertext) < nonceLength {
+		return nil, errors.New("error decrypting ciphertext: too short")
+	}
 	nonce := ciphertext[:nonceLength]
 	ciphertext = ciphertext[nonceLength:]
-	plaintext, err := c.aesgcm.Open(nil, nonce, ciphertext, nil)
+	plaintext, err := aesgcm.Open(nil, nonce, ciphertext, nil)
 	if err != nil {
 		return nil, fmt.Errorf("error decrypting ciphertext: %s", err)
 	}
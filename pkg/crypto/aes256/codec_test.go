@@ -1,6 +1,7 @@
 package aes256
 
 import (
+	"bytes"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -21,3 +22,104 @@ func TestCodecEncryptAndDecrypt(t *testing.T) {
 	assert.Nil(t, err)
 	assert.Equal(t, initialPlaintext, plaintext)
 }
+
+func TestCodecWithKeyIDsEncryptAndDecrypt(t *testing.T) {
+	c, err := NewCodec(
+		Config{
+			Keys: map[string]string{
+				"foo": "AES256Key-32Characters1234567890",
+			},
+			ActiveKeyID: "foo",
+		},
+	)
+	assert.Nil(t, err)
+	initialPlaintext := []byte("foo")
+	ciphertext, err := c.Encrypt(initialPlaintext)
+	assert.Nil(t, err)
+	assert.True(t, bytes.HasPrefix(ciphertext, []byte("aes256:foo:")))
+	plaintext, err := c.Decrypt(ciphertext)
+	assert.Nil(t, err)
+	assert.Equal(t, initialPlaintext, plaintext)
+}
+
+func TestCodecKeyRotation(t *testing.T) {
+	const oldKey = "AES256Key-32Characters1234567890"
+	const newKey = "AES256Key-32Characters0987654321"
+	// A codec with only a key that has no ID, as configured before rotation
+	legacyCodec, err := NewCodec(Config{Key: oldKey})
+	assert.Nil(t, err)
+	legacyCiphertext, err := legacyCodec.Encrypt([]byte("foo"))
+	assert.Nil(t, err)
+	// A codec mid-rotation
+	oldCodec, err := NewCodec(
+		Config{
+			Key: oldKey,
+			Keys: map[string]string{
+				"old": oldKey,
+			},
+			ActiveKeyID: "old",
+		},
+	)
+	assert.Nil(t, err)
+	oldCiphertext, err := oldCodec.Encrypt([]byte("bar"))
+	assert.Nil(t, err)
+	newCodec, err := NewCodec(
+		Config{
+			Key: oldKey,
+			Keys: map[string]string{
+				"old": oldKey,
+				"new": newKey,
+			},
+			ActiveKeyID: "new",
+		},
+	)
+	assert.Nil(t, err)
+	// The new codec can decrypt everything
+	plaintext, err := newCodec.Decrypt(legacyCiphertext)
+	assert.Nil(t, err)
+	assert.Equal(t, []byte("foo"), plaintext)
+	plaintext, err = newCodec.Decrypt(oldCiphertext)
+	assert.Nil(t, err)
+	assert.Equal(t, []byte("bar"), plaintext)
+	// But encrypts using only the active key
+	newCiphertext, err := newCodec.Encrypt([]byte("bat"))
+	assert.Nil(t, err)
+	assert.True(t, bytes.HasPrefix(newCiphertext, []byte("aes256:new:")))
+	_, err = oldCodec.Decrypt(newCiphertext)
+	assert.NotNil(t, err)
+	// Once the old key is retired, new ciphertexts can still be decrypted
+	retiredCodec, err := NewCodec(
+		Config{
+			Keys: map[string]string{
+				"new": newKey,
+			},
+			ActiveKeyID: "new",
+		},
+	)
+	assert.Nil(t, err)
+	plaintext, err = retiredCodec.Decrypt(newCiphertext)
+	assert.Nil(t, err)
+	assert.Equal(t, []byte("bat"), plaintext)
+	_, err = retiredCodec.Decrypt(legacyCiphertext)
+	assert.NotNil(t, err)
+}
+
+func TestNewCodecWithInvalidActiveKeyID(t *testing.T) {
+	_, err := NewCodec(
+		Config{
+			Keys: map[string]string{
+				"foo": "AES256Key-32Characters1234567890",
+			},
+		},
+	)
+	assert.NotNil(t, err)
+	_, err = NewCodec(
+		Config{
+			Keys: map[string]string{
+				"foo": "AES256Key-32Characters1234567890",
+			},
+			ActiveKeyID: "bar",
+		},
+	)
+	assert.NotNil(t, err)
+}
@@ -9,7 +9,16 @@ const envconfigPrefix = "CRYPTO"
 // Config represents configuration options for the AES256-based implementation
 // of the Crypto interface
 type Config struct {
+	// Key is a key without an identifier. If no identified keys are configured,
+	// it is used for encryption. Regardless, it is always used for decrypting
+	// ciphertexts that do not carry a key identifier.
 	Key string `envconfig:"AES256_KEY"`
+	// Keys maps key identifiers to keys. Ciphertexts produced using one of these
+	// keys are prefixed with the key's identifier so that any of these keys can
+	// later be selected for decryption.
+	Keys map[string]string `envconfig:"AES256_KEYS"`
+	// ActiveKeyID identifies which of the keys in Keys is used for encryption.
+	ActiveKeyID string `envconfig:"AES256_ACTIVE_KEY_ID"`
 }
 
 // NewConfigWithDefaults returns a Config object with default values already
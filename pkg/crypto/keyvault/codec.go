@@ -0,0 +1,272 @@
+package keyvault
+
+import (
+	"bytes"
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/Azure/open-service-broker-azure/pkg/azure"
+	"github.com/Azure/open-service-broker-azure/pkg/crypto"
+	log "github.com/Sirupsen/logrus"
+	"github.com/hashicorp/golang-lru/simplelru"
+)
+
+const (
+	dataKeyLength = 32
+	nonceLength   = 12
+	// keyVaultTimeout bounds each call to the Key Vault API
+	keyVaultTimeout = 30 * time.Second
+)
+
+// magic identifies ciphertexts produced by this codec. Such ciphertexts look
+// like:
+//
+//	"akv1"
+//	1 byte length of key encryption key version
+//	key encryption key version
+//	2 byte (big endian) length of wrapped data key
+//	wrapped data key
+//	nonce
+//	ciphertext
+var magic = []byte("akv1")
+
+// dataKey is a locally generated key that is used for encrypting values and
+// which is itself encrypted ("wrapped") by a key encryption key in Key Vault
+type dataKey struct {
+	aesgcm     cipher.AEAD
+	keyVersion string
+	wrappedKey []byte
+	created    time.Time
+}
+
+type codec struct {
+	keyWrapper      keyWrapper
+	dataKeyLifetime time.Duration
+	// activeDataKey is used for all encryption until it expires
+	activeDataKey      *dataKey
+	activeDataKeyMutex sync.Mutex
+	// dataKeys caches data keys by their wrapped form so that Key Vault need not
+	// be called upon to unwrap a data key every time a value is decrypted
+	dataKeys      *simplelru.LRU
+	dataKeysMutex sync.Mutex
+	// fallbackCodec, if not nil, is used to decrypt ciphertexts that were not
+	// produced by this codec
+	fallbackCodec crypto.Codec
+}
+
+// NewCodec returns a new implementation of crypto.Codec that uses envelope
+// encryption. Values are encrypted using AES256 with locally generated data
+// keys, which are, in turn, wrapped by a key encryption key in Azure Key Vault.
+// If a fallback codec is provided, it is used to decrypt ciphertexts that were
+// not produced by this codec, e.g. ones encrypted before the broker was
+// configured to use Key Vault. This permits such ciphertexts to be
+// re-encrypted.
+func NewCodec(
+	config Config,
+	azureConfig azure.Config,
+	fallbackCodec crypto.Codec,
+) (crypto.Codec, error) {
+	authorizer, err := azure.GetBearerTokenAuthorizerForResource(
+		azureConfig.Environment,
+		azureConfig.TenantID,
+		azureConfig.ClientID,
+		azureConfig.ClientSecret,
+		azureConfig.Environment.ResourceIdentifiers.KeyVault,
+	)
+	if err != nil {
+		return nil, err
+	}
+	c, err := newCodec(config, newKeyVaultKeyWrapper(config, authorizer))
+	if err != nil {
+		return nil, err
+	}
+	c.fallbackCodec = fallbackCodec
+	return c, nil
+}
+
+func newCodec(config Config, keyWrapper keyWrapper) (*codec, error) {
+	dataKeys, err := simplelru.NewLRU(config.DataKeyCacheSize, nil)
+	if err != nil {
+		return nil, fmt.Errorf("error creating data key cache: %s", err)
+	}
+	c := &codec{
+		keyWrapper:      keyWrapper,
+		dataKeyLifetime: config.DataKeyLifetime,
+		dataKeys:        dataKeys,
+	}
+	// Generate the first data key right away. This verifies that Key Vault is
+	// reachable and that the key encryption key can be used.
+	if _, err := c.getActiveDataKey(); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+func (c *codec) Encrypt(plaintext []byte) ([]byte, error) {
+	dk, err := c.getActiveDataKey()
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, nonceLength)
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, fmt.Errorf("error generating nonce: %s", err)
+	}
+	header := make([]byte, 0, len(magic)+1+len(dk.keyVersion)+2+
+		len(dk.wrappedKey)+nonceLength)
+	header = append(header, magic...)
+	header = append(header, byte(len(dk.keyVersion)))
+	header = append(header, dk.keyVersion...)
+	wrappedKeyLength := make([]byte, 2)
+	binary.BigEndian.PutUint16(wrappedKeyLength, uint16(len(dk.wrappedKey)))
+	header = append(header, wrappedKeyLength...)
+	header = append(header, dk.wrappedKey...)
+	header = append(header, nonce...)
+	return dk.aesgcm.Seal(header, nonce, plaintext, nil), nil
+}
+
+func (c *codec) Decrypt(ciphertext []byte) ([]byte, error) {
+	if !bytes.HasPrefix(ciphertext, magic) {
+		if c.fallbackCodec != nil {
+			return c.fallbackCodec.Decrypt(ciphertext)
+		}
+		return nil, errors.New(
+			"error decrypting ciphertext: not produced by the Key Vault codec",
+		)
+	}
+	ciphertext = ciphertext[len(magic):]
+	errMalformed := errors.New("error decrypting ciphertext: malformed")
+	if len(ciphertext) < 1 {
+		return nil, errMalformed
+	}
+	keyVersionLength := int(ciphertext[0])
+	ciphertext = ciphertext[1:]
+	if len(ciphertext) < keyVersionLength+2 {
+		return nil, errMalformed
+	}
+	keyVersion := string(ciphertext[:keyVersionLength])
+	ciphertext = ciphertext[keyVersionLength:]
+	wrappedKeyLength := int(binary.BigEndian.Uint16(ciphertext))
+	ciphertext = ciphertext[2:]
+	if len(ciphertext) < wrappedKeyLength+nonceLength {
+		return nil, errMalformed
+	}
+	wrappedKey := ciphertext[:wrappedKeyLength]
+	ciphertext = ciphertext[wrappedKeyLength:]
+	dk, err := c.getDataKey(keyVersion, wrappedKey)
+	if err != nil {
+		return nil, err
+	}
+	nonce := ciphertext[:nonceLength]
+	ciphertext = ciphertext[nonceLength:]
+	plaintext, err := dk.aesgcm.Open(nil, nonce, ciphertext, nil)
+	if err != nil {
+		return nil, fmt.Errorf("error decrypting ciphertext: %s", err)
+	}
+	return plaintext, nil
+}
+
+// getActiveDataKey returns the data key that should be used for encryption,
+// first generating and wrapping a new one if there is no active data key or if
+// the active data key has expired
+func (c *codec) getActiveDataKey() (*dataKey, error) {
+	c.activeDataKeyMutex.Lock()
+	defer c.activeDataKeyMutex.Unlock()
+	if c.activeDataKey != nil &&
+		time.Since(c.activeDataKey.created) < c.dataKeyLifetime {
+		return c.activeDataKey, nil
+	}
+	key := make([]byte, dataKeyLength)
+	if _, err := io.ReadFull(rand.Reader, key); err != nil {
+		return nil, fmt.Errorf("error generating data key: %s", err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), keyVaultTimeout)
+	defer cancel()
+	keyVersion, wrappedKey, err := c.keyWrapper.wrapKey(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	dk, err := newDataKey(key, keyVersion, wrappedKey)
+	if err != nil {
+		return nil, err
+	}
+	c.dataKeysMutex.Lock()
+	c.dataKeys.Add(getDataKeyCacheKey(keyVersion, wrappedKey), dk)
+	c.dataKeysMutex.Unlock()
+	c.activeDataKey = dk
+	log.WithField(
+		"keyVersion",
+		keyVersion,
+	).Debug("generated new data key wrapped by Key Vault key")
+	return dk, nil
+}
+
+// getDataKey returns the data key corresponding to the given wrapped data key,
+// calling upon Key Vault to unwrap it only if it isn't already cached
+func (c *codec) getDataKey(
+	keyVersion string,
+	wrappedKey []byte,
+) (*dataKey, error) {
+	cacheKey := getDataKeyCacheKey(keyVersion, wrappedKey)
+	c.dataKeysMutex.Lock()
+	cached, ok := c.dataKeys.Get(cacheKey)
+	c.dataKeysMutex.Unlock()
+	if ok {
+		return cached.(*dataKey), nil
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), keyVaultTimeout)
+	defer cancel()
+	key, err := c.keyWrapper.unwrapKey(ctx, keyVersion, wrappedKey)
+	if err != nil {
+		return nil, err
+	}
+	dk, err := newDataKey(key, keyVersion, wrappedKey)
+	if err != nil {
+		return nil, err
+	}
+	c.dataKeysMutex.Lock()
+	c.dataKeys.Add(cacheKey, dk)
+	c.dataKeysMutex.Unlock()
+	return dk, nil
+}
+
+func newDataKey(
+	key []byte,
+	keyVersion string,
+	wrappedKey []byte,
+) (*dataKey, error) {
+	if len(key) != dataKeyLength {
+		return nil, errors.New("data key is an invalid length")
+	}
+	if len(keyVersion) > 255 {
+		return nil, errors.New("key encryption key version is too long")
+	}
+	if len(wrappedKey) > 65535 {
+		return nil, errors.New("wrapped data key is too long")
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("error creating cipher: %s", err)
+	}
+	aesgcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("error creating cipher: %s", err)
+	}
+	return &dataKey{
+		aesgcm:     aesgcm,
+		keyVersion: keyVersion,
+		wrappedKey: append([]byte{}, wrappedKey...),
+		created:    time.Now(),
+	}, nil
+}
+
+func getDataKeyCacheKey(keyVersion string, wrappedKey []byte) string {
+	return fmt.Sprintf("%s:%s", keyVersion, wrappedKey)
+}
@@ -0,0 +1,215 @@
+package keyvault
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Azure/go-autorest/autorest"
+	"github.com/Azure/open-service-broker-azure/pkg/crypto/aes256"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	testKeyName    = "test-key"
+	testKeyVersion = "0123456789abcdef0123456789abcdef"
+)
+
+// fakeKeyVault is a local fake of the Key Vault wrapkey and unwrapkey
+// operations. It "wraps" keys by XORing them with a fixed byte and prefixing
+// them with the key version, which is enough to verify that wrapped keys make
+// the round trip intact.
+type fakeKeyVault struct {
+	server      *httptest.Server
+	mutex       sync.Mutex
+	wrapCount   int
+	unwrapCount int
+}
+
+func newFakeKeyVault() *fakeKeyVault {
+	f := &fakeKeyVault{}
+	f.server = httptest.NewServer(http.HandlerFunc(f.handle))
+	return f
+}
+
+func (f *fakeKeyVault) handle(w http.ResponseWriter, r *http.Request) {
+	// Paths look like /keys/<name>/<version>/<operation>
+	pathTokens := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	if r.Method != http.MethodPost ||
+		len(pathTokens) != 4 ||
+		pathTokens[0] != "keys" ||
+		pathTokens[1] != testKeyName {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	keyVersion := pathTokens[2]
+	params := struct {
+		Algorithm string `json:"alg"`
+		Value     string `json:"value"`
+	}{}
+	if err := json.NewDecoder(r.Body).Decode(&params); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	value, err := base64.RawURLEncoding.DecodeString(params.Value)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	var result []byte
+	switch pathTokens[3] {
+	case "wrapkey":
+		f.wrapCount++
+		if keyVersion == "" {
+			keyVersion = testKeyVersion
+		}
+		result = append([]byte(keyVersion), xor(value)...)
+	case "unwrapkey":
+		f.unwrapCount++
+		if !strings.HasPrefix(string(value), keyVersion) {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		result = xor(value[len(keyVersion):])
+	default:
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]string{ // nolint: errcheck
+		"kid": fmt.Sprintf(
+			"%s/keys/%s/%s",
+			f.server.URL,
+			testKeyName,
+			keyVersion,
+		),
+		"value": base64.RawURLEncoding.EncodeToString(result),
+	})
+}
+
+func xor(value []byte) []byte {
+	result := make([]byte, len(value))
+	for i, b := range value {
+		result[i] = b ^ 0x5a
+	}
+	return result
+}
+
+func getTestCodec(t *testing.T, f *fakeKeyVault) *codec {
+	config := NewConfigWithDefaults()
+	config.VaultURL = f.server.URL
+	config.KeyName = testKeyName
+	c, err := newCodec(
+		config,
+		newKeyVaultKeyWrapper(config, autorest.NullAuthorizer{}),
+	)
+	assert.Nil(t, err)
+	return c
+}
+
+func TestCodecEncryptAndDecrypt(t *testing.T) {
+	f := newFakeKeyVault()
+	defer f.server.Close()
+	c := getTestCodec(t, f)
+	initialPlaintext := []byte("foo")
+	ciphertext, err := c.Encrypt(initialPlaintext)
+	assert.Nil(t, err)
+	assert.NotEqual(t, initialPlaintext, ciphertext)
+	plaintext, err := c.Decrypt(ciphertext)
+	assert.Nil(t, err)
+	assert.Equal(t, initialPlaintext, plaintext)
+	// A single data key should have been wrapped and the cached data key should
+	// have been used for decryption
+	assert.Equal(t, 1, f.wrapCount)
+	assert.Equal(t, 0, f.unwrapCount)
+}
+
+func TestCodecDecryptWithUnwrap(t *testing.T) {
+	f := newFakeKeyVault()
+	defer f.server.Close()
+	ciphertext, err := getTestCodec(t, f).Encrypt([]byte("foo"))
+	assert.Nil(t, err)
+	// A new codec, as in a restarted broker, has to unwrap the data key, but
+	// only the first time
+	c := getTestCodec(t, f)
+	for i := 0; i < 3; i++ {
+		plaintext, err := c.Decrypt(ciphertext)
+		assert.Nil(t, err)
+		assert.Equal(t, []byte("foo"), plaintext)
+	}
+	assert.Equal(t, 1, f.unwrapCount)
+}
+
+func TestCodecDataKeyExpiry(t *testing.T) {
+	f := newFakeKeyVault()
+	defer f.server.Close()
+	c := getTestCodec(t, f)
+	c.dataKeyLifetime = time.Nanosecond
+	time.Sleep(time.Millisecond)
+	ciphertext, err := c.Encrypt([]byte("foo"))
+	assert.Nil(t, err)
+	// The first data key expired, so a second one was generated and wrapped
+	assert.Equal(t, 2, f.wrapCount)
+	plaintext, err := c.Decrypt(ciphertext)
+	assert.Nil(t, err)
+	assert.Equal(t, []byte("foo"), plaintext)
+}
+
+func TestCodecDecryptMalformed(t *testing.T) {
+	f := newFakeKeyVault()
+	defer f.server.Close()
+	c := getTestCodec(t, f)
+	_, err := c.Decrypt([]byte("foo"))
+	assert.NotNil(t, err)
+	_, err = c.Decrypt([]byte("akv1\x05ab"))
+	assert.NotNil(t, err)
+}
+
+func TestCodecDecryptWithFallbackCodec(t *testing.T) {
+	f := newFakeKeyVault()
+	defer f.server.Close()
+	fallbackCodec, err := aes256.NewCodec(aes256.Config{
+		Key: "AES256Key-32Characters1234567890",
+	})
+	assert.Nil(t, err)
+	legacyCiphertext, err := fallbackCodec.Encrypt([]byte("foo"))
+	assert.Nil(t, err)
+	c := getTestCodec(t, f)
+	// Without a fallback codec, ciphertexts not produced by this codec can't be
+	// decrypted
+	_, err = c.Decrypt(legacyCiphertext)
+	assert.NotNil(t, err)
+	c.fallbackCodec = fallbackCodec
+	plaintext, err := c.Decrypt(legacyCiphertext)
+	assert.Nil(t, err)
+	assert.Equal(t, []byte("foo"), plaintext)
+	// New values are still encrypted using Key Vault-wrapped data keys
+	ciphertext, err := c.Encrypt([]byte("foo"))
+	assert.Nil(t, err)
+	_, err = fallbackCodec.Decrypt(ciphertext)
+	assert.NotNil(t, err)
+	plaintext, err = c.Decrypt(ciphertext)
+	assert.Nil(t, err)
+	assert.Equal(t, []byte("foo"), plaintext)
+}
+
+func TestNewCodecWithUnreachableVault(t *testing.T) {
+	f := newFakeKeyVault()
+	config := NewConfigWithDefaults()
+	config.VaultURL = f.server.URL
+	config.KeyName = "nonexistent-key"
+	wrapper := newKeyVaultKeyWrapper(config, autorest.NullAuthorizer{})
+	// Don't retry
+	wrapper.(*keyVaultKeyWrapper).client.RetryAttempts = 0
+	f.server.Close()
+	_, err := newCodec(config, wrapper)
+	assert.NotNil(t, err)
+}
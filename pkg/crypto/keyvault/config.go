@@ -0,0 +1,67 @@
+package keyvault
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/kelseyhightower/envconfig"
+)
+
+const envconfigPrefix = "CRYPTO"
+
+// Config represents configuration options for the Key Vault-based
+// implementation of the Crypto interface
+type Config struct {
+	// VaultURL is the base URL of the vault containing the key encryption key--
+	// for instance, https://myvault.vault.azure.net
+	VaultURL string `envconfig:"KEYVAULT_URL" required:"true"`
+	// KeyName is the name of the key encryption key
+	KeyName string `envconfig:"KEYVAULT_KEY_NAME" required:"true"`
+	// KeyVersion is the version of the key encryption key used for wrapping new
+	// data keys. If unspecified, the latest version is used.
+	KeyVersion string `envconfig:"KEYVAULT_KEY_VERSION"`
+	// WrapAlgorithm is the algorithm used to wrap and unwrap data keys
+	WrapAlgorithm string `envconfig:"KEYVAULT_WRAP_ALGORITHM"`
+	// DataKeyLifetime is how long a locally generated data key is used for
+	// encryption before a new one is generated and wrapped
+	DataKeyLifetime time.Duration `envconfig:"KEYVAULT_DATA_KEY_LIFETIME"`
+	// DataKeyCacheSize is the maximum number of unwrapped data keys that are
+	// cached in memory
+	DataKeyCacheSize int `envconfig:"KEYVAULT_DATA_KEY_CACHE_SIZE"`
+}
+
+// NewConfigWithDefaults returns a Config object with default values already
+// applied. Callers are then free to set custom values for the remaining fields
+// and/or override default values.
+func NewConfigWithDefaults() Config {
+	return Config{
+		WrapAlgorithm:    "RSA-OAEP-256",
+		DataKeyLifetime:  24 * time.Hour,
+		DataKeyCacheSize: 1000,
+	}
+}
+
+// GetConfigFromEnvironment returns configuration derived from environment
+// variables
+func GetConfigFromEnvironment() (Config, error) {
+	c := NewConfigWithDefaults()
+	err := envconfig.Process(envconfigPrefix, &c)
+	if err != nil {
+		return c, err
+	}
+	if c.DataKeyLifetime <= 0 {
+		return c, fmt.Errorf(
+			"environment variable %s_KEYVAULT_DATA_KEY_LIFETIME must be a "+
+				"positive duration",
+			envconfigPrefix,
+		)
+	}
+	if c.DataKeyCacheSize <= 0 {
+		return c, fmt.Errorf(
+			"environment variable %s_KEYVAULT_DATA_KEY_CACHE_SIZE must be a "+
+				"positive integer",
+			envconfigPrefix,
+		)
+	}
+	return c, nil
+}
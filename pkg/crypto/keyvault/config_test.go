@@ -0,0 +1,60 @@
+package keyvault
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func setRequiredEnv(t *testing.T) {
+	err := os.Setenv("CRYPTO_KEYVAULT_URL", "https://test.vault.azure.net")
+	assert.Nil(t, err)
+	err = os.Setenv("CRYPTO_KEYVAULT_KEY_NAME", testKeyName)
+	assert.Nil(t, err)
+}
+
+func unsetRequiredEnv() {
+	os.Unsetenv("CRYPTO_KEYVAULT_URL")      // nolint: errcheck
+	os.Unsetenv("CRYPTO_KEYVAULT_KEY_NAME") // nolint: errcheck
+}
+
+func TestGetConfigWithDataKeySettings(t *testing.T) {
+	setRequiredEnv(t)
+	defer unsetRequiredEnv()
+	err := os.Setenv("CRYPTO_KEYVAULT_DATA_KEY_LIFETIME", "1h")
+	assert.Nil(t, err)
+	defer os.Unsetenv("CRYPTO_KEYVAULT_DATA_KEY_LIFETIME") // nolint: errcheck
+	err = os.Setenv("CRYPTO_KEYVAULT_DATA_KEY_CACHE_SIZE", "10")
+	assert.Nil(t, err)
+	defer os.Unsetenv("CRYPTO_KEYVAULT_DATA_KEY_CACHE_SIZE") // nolint: errcheck
+	c, err := GetConfigFromEnvironment()
+	assert.Nil(t, err)
+	assert.Equal(t, time.Hour, c.DataKeyLifetime)
+	assert.Equal(t, 10, c.DataKeyCacheSize)
+}
+
+func TestGetConfigWithNonPositiveDataKeyLifetime(t *testing.T) {
+	setRequiredEnv(t)
+	defer unsetRequiredEnv()
+	for _, lifetime := range []string{"0", "-1h"} {
+		err := os.Setenv("CRYPTO_KEYVAULT_DATA_KEY_LIFETIME", lifetime)
+		assert.Nil(t, err)
+		_, err = GetConfigFromEnvironment()
+		assert.NotNil(t, err, lifetime)
+	}
+	os.Unsetenv("CRYPTO_KEYVAULT_DATA_KEY_LIFETIME") // nolint: errcheck
+}
+
+func TestGetConfigWithNonPositiveDataKeyCacheSize(t *testing.T) {
+	setRequiredEnv(t)
+	defer unsetRequiredEnv()
+	for _, size := range []string{"0", "-1"} {
+		err := os.Setenv("CRYPTO_KEYVAULT_DATA_KEY_CACHE_SIZE", size)
+		assert.Nil(t, err)
+		_, err = GetConfigFromEnvironment()
+		assert.NotNil(t, err, size)
+	}
+	os.Unsetenv("CRYPTO_KEYVAULT_DATA_KEY_CACHE_SIZE") // nolint: errcheck
+}
@@ -0,0 +1,120 @@
+package keyvault
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+
+	keyVaultSDK "github.com/Azure/azure-sdk-for-go/services/keyvault/v7.0/keyvault" // nolint: lll
+	"github.com/Azure/go-autorest/autorest"
+)
+
+// keyWrapper is an interface to be implemented by types that can wrap and
+// unwrap data keys using a key encryption key that never leaves some external
+// key management system
+type keyWrapper interface {
+	// wrapKey wraps the given data key and returns the version of the key
+	// encryption key that was used along with the wrapped data key
+	wrapKey(ctx context.Context, key []byte) (string, []byte, error)
+	// unwrapKey unwraps the given data key using the specified version of the
+	// key encryption key
+	unwrapKey(
+		ctx context.Context,
+		keyVersion string,
+		wrappedKey []byte,
+	) ([]byte, error)
+}
+
+// keyVaultKeyWrapper is an implementation of keyWrapper that uses the wrapkey
+// and unwrapkey operations of the Azure Key Vault API
+type keyVaultKeyWrapper struct {
+	client     keyVaultSDK.BaseClient
+	vaultURL   string
+	keyName    string
+	keyVersion string
+	algorithm  keyVaultSDK.JSONWebKeyEncryptionAlgorithm
+}
+
+func newKeyVaultKeyWrapper(
+	config Config,
+	authorizer autorest.Authorizer,
+) keyWrapper {
+	client := keyVaultSDK.New()
+	client.Authorizer = authorizer
+	return &keyVaultKeyWrapper{
+		client:     client,
+		vaultURL:   strings.TrimSuffix(config.VaultURL, "/"),
+		keyName:    config.KeyName,
+		keyVersion: config.KeyVersion,
+		algorithm:  keyVaultSDK.JSONWebKeyEncryptionAlgorithm(config.WrapAlgorithm),
+	}
+}
+
+func (k *keyVaultKeyWrapper) wrapKey(
+	ctx context.Context,
+	key []byte,
+) (string, []byte, error) {
+	value := base64.RawURLEncoding.EncodeToString(key)
+	result, err := k.client.WrapKey(
+		ctx,
+		k.vaultURL,
+		k.keyName,
+		k.keyVersion,
+		keyVaultSDK.KeyOperationsParameters{
+			Algorithm: k.algorithm,
+			Value:     &value,
+		},
+	)
+	if err != nil {
+		return "", nil, fmt.Errorf("error wrapping data key: %s", err)
+	}
+	if result.Kid == nil || result.Result == nil {
+		return "", nil, errors.New("error wrapping data key: incomplete response")
+	}
+	// The key ID is a URL ending in the key's name and version-- e.g.
+	// https://myvault.vault.azure.net/keys/mykey/<version>
+	kid := strings.TrimSuffix(*result.Kid, "/")
+	keyVersion := kid[strings.LastIndex(kid, "/")+1:]
+	wrappedKey, err := decodeBase64URL(*result.Result)
+	if err != nil {
+		return "", nil, fmt.Errorf("error decoding wrapped data key: %s", err)
+	}
+	return keyVersion, wrappedKey, nil
+}
+
+func (k *keyVaultKeyWrapper) unwrapKey(
+	ctx context.Context,
+	keyVersion string,
+	wrappedKey []byte,
+) ([]byte, error) {
+	value := base64.RawURLEncoding.EncodeToString(wrappedKey)
+	result, err := k.client.UnwrapKey(
+		ctx,
+		k.vaultURL,
+		k.keyName,
+		keyVersion,
+		keyVaultSDK.KeyOperationsParameters{
+			Algorithm: k.algorithm,
+			Value:     &value,
+		},
+	)
+	if err != nil {
+		return nil, fmt.Errorf("error unwrapping data key: %s", err)
+	}
+	if result.Result == nil {
+		return nil, errors.New("error unwrapping data key: incomplete response")
+	}
+	key, err := decodeBase64URL(*result.Result)
+	if err != nil {
+		return nil, fmt.Errorf("error decoding unwrapped data key: %s", err)
+	}
+	return key, nil
+}
+
+// decodeBase64URL decodes base64url-encoded values with or without padding.
+// Key Vault omits padding.
+func decodeBase64URL(value string) ([]byte, error) {
+	return base64.RawURLEncoding.DecodeString(strings.TrimRight(value, "="))
+}
@@ -5,4 +5,7 @@ const (
 	NOOP = "NOOP"
 	// AES256 represents AES256 encryption
 	AES256 = "AES256"
+	// KEYVAULT represents envelope encryption using data keys that are wrapped
+	// by a key in Azure Key Vault
+	KEYVAULT = "KEYVAULT"
 )
@@ -0,0 +1,40 @@
+package health
+
+import "context"
+
+// CheckFunction is the signature for functions that determine whether a
+// component the broker depends upon is healthy. Implementations should return
+// an error describing the problem if the component is unhealthy and should
+// respect cancelation of the context they are passed.
+type CheckFunction func(ctx context.Context) error
+
+// Check is an interface to be implemented by types that can determine whether
+// a single component the broker depends upon is healthy
+type Check interface {
+	// GetName returns the name of the component that is checked
+	GetName() string
+	// Execute checks the component, returning an error if it is unhealthy
+	Execute(ctx context.Context) error
+}
+
+type check struct {
+	name string
+	fn   CheckFunction
+}
+
+// NewCheck returns a new Check for the named component that uses the given
+// function to determine the component's health
+func NewCheck(name string, fn CheckFunction) Check {
+	return &check{
+		name: name,
+		fn:   fn,
+	}
+}
+
+func (c *check) GetName() string {
+	return c.name
+}
+
+func (c *check) Execute(ctx context.Context) error {
+	return c.fn(ctx)
+}
@@ -0,0 +1,78 @@
+package health
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// ComponentReport describes the outcome of checking a single component
+type ComponentReport struct {
+	Name    string `json:"name"`
+	Healthy bool   `json:"healthy"`
+	// Latency is how long the check took to complete, in milliseconds
+	Latency float64 `json:"latency"`
+	Error   string  `json:"error,omitempty"`
+}
+
+// Report describes the outcome of checking all of the components the broker
+// depends upon. The broker is only healthy if all components are.
+type Report struct {
+	Healthy    bool              `json:"healthy"`
+	Components []ComponentReport `json:"components"`
+}
+
+// Run executes all the given checks concurrently and reports their outcomes
+// in the order the checks were given. Any check that has not completed within
+// the given timeout is reported as unhealthy.
+func Run(ctx context.Context, checks []Check, timeout time.Duration) Report {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	reportChs := make([]chan ComponentReport, len(checks))
+	for i, c := range checks {
+		reportChs[i] = make(chan ComponentReport, 1)
+		go func(c Check, reportCh chan<- ComponentReport) {
+			reportCh <- runCheck(ctx, c)
+		}(c, reportChs[i])
+	}
+	report := Report{
+		Healthy:    true,
+		Components: make([]ComponentReport, len(checks)),
+	}
+	start := time.Now()
+	for i, c := range checks {
+		var componentReport ComponentReport
+		select {
+		case componentReport = <-reportChs[i]:
+		case <-ctx.Done():
+			componentReport = ComponentReport{
+				Name:    c.GetName(),
+				Latency: getMilliseconds(time.Since(start)),
+				Error:   fmt.Sprintf("check did not complete within %s", timeout),
+			}
+		}
+		if !componentReport.Healthy {
+			report.Healthy = false
+		}
+		report.Components[i] = componentReport
+	}
+	return report
+}
+
+func runCheck(ctx context.Context, c Check) ComponentReport {
+	start := time.Now()
+	err := c.Execute(ctx)
+	componentReport := ComponentReport{
+		Name:    c.GetName(),
+		Healthy: err == nil,
+		Latency: getMilliseconds(time.Since(start)),
+	}
+	if err != nil {
+		componentReport.Error = err.Error()
+	}
+	return componentReport
+}
+
+func getMilliseconds(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
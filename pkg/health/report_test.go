@@ -0,0 +1,64 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRunAllHealthy(t *testing.T) {
+	report := Run(
+		context.Background(),
+		[]Check{
+			NewCheck("foo", func(context.Context) error { return nil }),
+			NewCheck("bar", func(context.Context) error { return nil }),
+		},
+		time.Second,
+	)
+	assert.True(t, report.Healthy)
+	assert.Len(t, report.Components, 2)
+	assert.Equal(t, "foo", report.Components[0].Name)
+	assert.True(t, report.Components[0].Healthy)
+	assert.Equal(t, "bar", report.Components[1].Name)
+	assert.True(t, report.Components[1].Healthy)
+}
+
+func TestRunWithUnhealthyComponent(t *testing.T) {
+	report := Run(
+		context.Background(),
+		[]Check{
+			NewCheck("foo", func(context.Context) error { return nil }),
+			NewCheck("bar", func(context.Context) error {
+				return errors.New("bar is broken")
+			}),
+		},
+		time.Second,
+	)
+	assert.False(t, report.Healthy)
+	assert.True(t, report.Components[0].Healthy)
+	assert.Empty(t, report.Components[0].Error)
+	assert.False(t, report.Components[1].Healthy)
+	assert.Equal(t, "bar is broken", report.Components[1].Error)
+}
+
+func TestRunWithSlowComponent(t *testing.T) {
+	done := make(chan struct{})
+	defer close(done)
+	report := Run(
+		context.Background(),
+		[]Check{
+			NewCheck("foo", func(context.Context) error {
+				<-done
+				return nil
+			}),
+		},
+		10*time.Millisecond,
+	)
+	assert.False(t, report.Healthy)
+	assert.False(t, report.Components[0].Healthy)
+	assert.Contains(t, report.Components[0].Error, "did not complete")
+	assert.True(t, report.Components[0].Latency >= 10)
+}
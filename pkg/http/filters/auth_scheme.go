@@ -0,0 +1,46 @@
+package filters
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/Azure/open-service-broker-azure/pkg/http/filter"
+	"github.com/Azure/open-service-broker-azure/pkg/identity"
+)
+
+// NewAuthSchemeFilter returns an implementation of the filter.Filter interface
+// that delegates authentication of each HTTP request to the filter registered
+// for the authentication scheme (e.g. "Basic" or "Bearer") named in the
+// request's Authorization header. This permits a broker to accept more than
+// one form of credentials. Requests naming an unregistered scheme are rejected.
+// Requests without an Authorization header are admitted only if an identity
+// has already been established for them by an earlier filter, for instance
+// from a client certificate.
+func NewAuthSchemeFilter(
+	filtersByScheme map[string]filter.Filter,
+) filter.Filter {
+	return filter.NewGenericFilter(
+		func(handle http.HandlerFunc) http.HandlerFunc {
+			handlersByScheme := map[string]http.HandlerFunc{}
+			for scheme, f := range filtersByScheme {
+				handlersByScheme[strings.ToLower(scheme)] = f.GetHandler(handle)
+			}
+			return func(w http.ResponseWriter, r *http.Request) {
+				headerValue := r.Header.Get("Authorization")
+				if headerValue == "" {
+					if _, ok := identity.FromContext(r.Context()); ok {
+						handle(w, r)
+						return
+					}
+				}
+				scheme := strings.SplitN(headerValue, " ", 2)[0]
+				schemeHandle, ok := handlersByScheme[strings.ToLower(scheme)]
+				if !ok {
+					http.Error(w, "{}", http.StatusUnauthorized)
+					return
+				}
+				schemeHandle(w, r)
+			}
+		},
+	)
+}
@@ -0,0 +1,66 @@
+package filters
+
+import (
+	"encoding/base64"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Azure/open-service-broker-azure/pkg/http/filter"
+	"github.com/Azure/open-service-broker-azure/pkg/identity"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAuthSchemeFilter(t *testing.T) {
+	f := NewAuthSchemeFilter(
+		map[string]filter.Filter{
+			"Basic":  getTestBasicAuthFilter(),
+			"Bearer": getTestJWTFilter(),
+		},
+	)
+	testCases := map[string]int{
+		"":                       http.StatusUnauthorized,
+		"Digest foo":             http.StatusUnauthorized,
+		"Basic Zm9vOmJhcg==":     http.StatusUnauthorized,
+		getTestBasicAuthHeader(): http.StatusOK,
+		"Bearer " + getTestToken(t, testKeyID, getTestClaims()): http.StatusOK,
+	}
+	for header, expectedCode := range testCases {
+		req, err := http.NewRequest(http.MethodGet, "/", nil)
+		assert.Nil(t, err)
+		req.Header.Set("Authorization", header)
+		rr := httptest.NewRecorder()
+		f.GetHandler(func(http.ResponseWriter, *http.Request) {})(rr, req)
+		assert.Equal(t, expectedCode, rr.Code, header)
+	}
+}
+
+func TestAuthSchemeFilterWithEstablishedIdentity(t *testing.T) {
+	f := NewAuthSchemeFilter(
+		map[string]filter.Filter{
+			"Basic": getTestBasicAuthFilter(),
+		},
+	)
+	req, err := http.NewRequest(http.MethodGet, "/", nil)
+	assert.Nil(t, err)
+	req = req.WithContext(
+		identity.NewContext(
+			req.Context(),
+			identity.Identity{Scheme: ClientCertScheme, Name: "foo"},
+		),
+	)
+	rr := httptest.NewRecorder()
+	f.GetHandler(func(http.ResponseWriter, *http.Request) {})(rr, req)
+	assert.Equal(t, http.StatusOK, rr.Code)
+	// An identity doesn't excuse invalid credentials
+	req.Header.Set("Authorization", "Basic Zm9vOmJhcg==")
+	rr = httptest.NewRecorder()
+	f.GetHandler(func(http.ResponseWriter, *http.Request) {})(rr, req)
+	assert.Equal(t, http.StatusUnauthorized, rr.Code)
+}
+
+func getTestBasicAuthHeader() string {
+	return "Basic " + base64.StdEncoding.EncodeToString(
+		[]byte(testUsername+":"+testPassword),
+	)
+}
@@ -1,16 +1,43 @@
 package filters
 
 import (
+	"crypto/subtle"
 	"encoding/base64"
 	"net/http"
 	"strings"
 
 	"github.com/Azure/open-service-broker-azure/pkg/http/filter"
+	"github.com/Azure/open-service-broker-azure/pkg/identity"
 )
 
+// BasicAuthScheme is the scheme recorded in the identity of principals
+// authenticated using Basic Auth
+const BasicAuthScheme = "Basic"
+
+// BasicAuthUser represents the password and entitlements of a single user
+// that may authenticate using Basic Auth
+type BasicAuthUser struct {
+	Password string
+	// Entitlements optionally restricts the services and plans the user may see
+	// and use. If nil, the user is not restricted.
+	Entitlements identity.Entitlements
+}
+
 // NewBasicAuthFilter returns an implementation of the filter.Filter interface
 // that authenticates HTTP requests using Basic Auth
 func NewBasicAuthFilter(username, password string) filter.Filter {
+	return NewMultiUserBasicAuthFilter(
+		map[string]BasicAuthUser{
+			username: {Password: password},
+		},
+	)
+}
+
+// NewMultiUserBasicAuthFilter returns an implementation of the filter.Filter
+// interface that authenticates HTTP requests using Basic Auth, accepting the
+// credentials of any of the given users, which are keyed by username. The
+// authenticated user is added to the request's context as an identity.
+func NewMultiUserBasicAuthFilter(users map[string]BasicAuthUser) filter.Filter {
 	return filter.NewGenericFilter(
 		func(handle http.HandlerFunc) http.HandlerFunc {
 			return func(w http.ResponseWriter, r *http.Request) {
@@ -41,13 +68,28 @@ func NewBasicAuthFilter(username, password string) filter.Filter {
 					":",
 					2,
 				)
-				if len(usernameAndPasswordTokens) != 2 ||
-					usernameAndPasswordTokens[0] != username ||
-					usernameAndPasswordTokens[1] != password {
+				if len(usernameAndPasswordTokens) != 2 {
 					http.Error(w, "{}", http.StatusUnauthorized)
 					return
 				}
-				handle(w, r)
+				username := usernameAndPasswordTokens[0]
+				user, ok := users[username]
+				if !ok || subtle.ConstantTimeCompare(
+					[]byte(usernameAndPasswordTokens[1]),
+					[]byte(user.Password),
+				) != 1 {
+					http.Error(w, "{}", http.StatusUnauthorized)
+					return
+				}
+				ctx := identity.NewContext(
+					r.Context(),
+					identity.Identity{
+						Scheme:       BasicAuthScheme,
+						Name:         username,
+						Entitlements: user.Entitlements,
+					},
+				)
+				handle(w, r.WithContext(ctx))
 			}
 		},
 	)
@@ -8,6 +8,7 @@ import (
 	"testing"
 
 	"github.com/Azure/open-service-broker-azure/pkg/http/filter"
+	"github.com/Azure/open-service-broker-azure/pkg/identity"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -102,3 +103,47 @@ func TestBasicAuthFilterWithUsernamePasswordValid(t *testing.T) {
 func getTestBasicAuthFilter() filter.Filter {
 	return NewBasicAuthFilter(testUsername, testPassword)
 }
+
+func TestMultiUserBasicAuthFilter(t *testing.T) {
+	entitlements := identity.Entitlements{"svc-a": nil}
+	a := NewMultiUserBasicAuthFilter(
+		map[string]BasicAuthUser{
+			"alice": {Password: "foo", Entitlements: entitlements},
+			"bob":   {Password: "bar"},
+		},
+	)
+	testCases := []struct {
+		username     string
+		password     string
+		expectedCode int
+	}{
+		{"alice", "foo", http.StatusOK},
+		{"bob", "bar", http.StatusOK},
+		{"alice", "bar", http.StatusUnauthorized},
+		{"carol", "foo", http.StatusUnauthorized},
+	}
+	for _, testCase := range testCases {
+		req, err := http.NewRequest(http.MethodGet, "/", nil)
+		assert.Nil(t, err)
+		req.SetBasicAuth(testCase.username, testCase.password)
+		rr := httptest.NewRecorder()
+		var id identity.Identity
+		var ok bool
+		a.GetHandler(func(_ http.ResponseWriter, r *http.Request) {
+			id, ok = identity.FromContext(r.Context())
+		})(rr, req)
+		assert.Equal(t, testCase.expectedCode, rr.Code, testCase.username)
+		if testCase.expectedCode == http.StatusOK {
+			assert.True(t, ok)
+			assert.Equal(t, BasicAuthScheme, id.Scheme)
+			assert.Equal(t, testCase.username, id.Name)
+		}
+	}
+	req, err := http.NewRequest(http.MethodGet, "/", nil)
+	assert.Nil(t, err)
+	req.SetBasicAuth("alice", "foo")
+	a.GetHandler(func(_ http.ResponseWriter, r *http.Request) {
+		id, _ := identity.FromContext(r.Context())
+		assert.Equal(t, entitlements, id.Entitlements)
+	})(httptest.NewRecorder(), req)
+}
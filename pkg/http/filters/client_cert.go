@@ -0,0 +1,95 @@
+package filters
+
+import (
+	"crypto/x509"
+	"net/http"
+
+	"github.com/Azure/open-service-broker-azure/pkg/http/filter"
+	"github.com/Azure/open-service-broker-azure/pkg/identity"
+	log "github.com/Sirupsen/logrus"
+)
+
+// ClientCertScheme is the scheme recorded in the identity of principals
+// authenticated using a client certificate
+const ClientCertScheme = "ClientCert"
+
+// NewClientCertFilter returns an implementation of the filter.Filter interface
+// that identifies the principal behind each HTTP request using the client
+// certificate presented during the TLS handshake. Requests without a
+// certificate are rejected. Verification of the certificate itself is left to
+// the TLS server. If any allowed subjects are specified, the certificate's
+// subject, common name or one of its subject alternative names must match one
+// of them. The name that was matched (or, in the absence of an allow-list, the
+// certificate's common name) is added to the request's context as an identity.
+func NewClientCertFilter(allowedSubjects []string) filter.Filter {
+	allowed := map[string]bool{}
+	for _, subject := range allowedSubjects {
+		allowed[subject] = true
+	}
+	return filter.NewGenericFilter(
+		func(handle http.HandlerFunc) http.HandlerFunc {
+			return func(w http.ResponseWriter, r *http.Request) {
+				if r.TLS == nil || len(r.TLS.PeerCertificates) == 0 {
+					http.Error(w, "{}", http.StatusUnauthorized)
+					return
+				}
+				cert := r.TLS.PeerCertificates[0]
+				name, ok := getClientCertIdentityName(cert, allowed)
+				if !ok {
+					log.WithField(
+						"subject",
+						cert.Subject.String(),
+					).Debug("authentication error: client certificate not allowed")
+					http.Error(w, "{}", http.StatusForbidden)
+					return
+				}
+				ctx := identity.NewContext(
+					r.Context(),
+					identity.Identity{
+						Scheme: ClientCertScheme,
+						Name:   name,
+					},
+				)
+				handle(w, r.WithContext(ctx))
+			}
+		},
+	)
+}
+
+// getClientCertIdentityName returns the first of the given certificate's names
+// that appears in the given allow-list. If the allow-list is empty, the
+// certificate's common name is returned, falling back to its first subject
+// alternative name.
+func getClientCertIdentityName(
+	cert *x509.Certificate,
+	allowed map[string]bool,
+) (string, bool) {
+	names := getClientCertNames(cert)
+	if len(allowed) == 0 {
+		for _, name := range names[1:] {
+			if name != "" {
+				return name, true
+			}
+		}
+		return names[0], true
+	}
+	for _, name := range names {
+		if name != "" && allowed[name] {
+			return name, true
+		}
+	}
+	return "", false
+}
+
+// getClientCertNames returns all names by which the given certificate may be
+// identified-- its full subject, its common name and its subject alternative
+// names, in that order
+func getClientCertNames(cert *x509.Certificate) []string {
+	names := []string{cert.Subject.String(), cert.Subject.CommonName}
+	names = append(names, cert.DNSNames...)
+	names = append(names, cert.EmailAddresses...)
+	for _, uri := range cert.URIs {
+		names = append(names, uri.String())
+	}
+	return names
+}
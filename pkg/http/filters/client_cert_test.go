@@ -0,0 +1,90 @@
+package filters
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/Azure/open-service-broker-azure/pkg/http/filter"
+	"github.com/Azure/open-service-broker-azure/pkg/identity"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClientCertFilterWithNoCert(t *testing.T) {
+	f := NewClientCertFilter(nil)
+	req, err := http.NewRequest(http.MethodGet, "/", nil)
+	assert.Nil(t, err)
+	rr := httptest.NewRecorder()
+	f.GetHandler(func(http.ResponseWriter, *http.Request) {})(rr, req)
+	assert.Equal(t, http.StatusUnauthorized, rr.Code)
+}
+
+func TestClientCertFilterWithoutAllowList(t *testing.T) {
+	f := NewClientCertFilter(nil)
+	code, id := doClientCertRequest(t, f, getTestClientCert())
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(
+		t,
+		identity.Identity{Scheme: ClientCertScheme, Name: "service-catalog"},
+		id,
+	)
+}
+
+func TestClientCertFilterWithAllowedSAN(t *testing.T) {
+	f := NewClientCertFilter(
+		[]string{"spiffe://cluster.local/ns/catalog/sa/controller"},
+	)
+	code, id := doClientCertRequest(t, f, getTestClientCert())
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(
+		t,
+		"spiffe://cluster.local/ns/catalog/sa/controller",
+		id.Name,
+	)
+}
+
+func TestClientCertFilterWithAllowedSubject(t *testing.T) {
+	f := NewClientCertFilter([]string{"CN=service-catalog,O=Kubernetes"})
+	code, id := doClientCertRequest(t, f, getTestClientCert())
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, "CN=service-catalog,O=Kubernetes", id.Name)
+}
+
+func TestClientCertFilterWithDisallowedCert(t *testing.T) {
+	f := NewClientCertFilter([]string{"someone-else"})
+	code, _ := doClientCertRequest(t, f, getTestClientCert())
+	assert.Equal(t, http.StatusForbidden, code)
+}
+
+func getTestClientCert() *x509.Certificate {
+	uri, _ := url.Parse("spiffe://cluster.local/ns/catalog/sa/controller")
+	return &x509.Certificate{
+		Subject: pkix.Name{
+			CommonName:   "service-catalog",
+			Organization: []string{"Kubernetes"},
+		},
+		URIs: []*url.URL{uri},
+	}
+}
+
+func doClientCertRequest(
+	t *testing.T,
+	f filter.Filter,
+	cert *x509.Certificate,
+) (int, identity.Identity) {
+	req, err := http.NewRequest(http.MethodGet, "/", nil)
+	assert.Nil(t, err)
+	req.TLS = &tls.ConnectionState{
+		PeerCertificates: []*x509.Certificate{cert},
+	}
+	var id identity.Identity
+	rr := httptest.NewRecorder()
+	f.GetHandler(func(_ http.ResponseWriter, r *http.Request) {
+		id, _ = identity.FromContext(r.Context())
+	})(rr, req)
+	return rr.Code, id
+}
@@ -0,0 +1,148 @@
+package filters
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/Azure/open-service-broker-azure/pkg/http/filter"
+	"github.com/Azure/open-service-broker-azure/pkg/identity"
+	"github.com/Azure/open-service-broker-azure/pkg/jwks"
+	log "github.com/Sirupsen/logrus"
+	jwt "github.com/dgrijalva/jwt-go"
+)
+
+// BearerScheme is the scheme recorded in the identity of principals
+// authenticated using a bearer token
+const BearerScheme = "Bearer"
+
+// jwtSigningMethods are the signing methods accepted by the JWT filter. Only
+// asymmetric methods are accepted, since tokens are verified using public keys
+// published by the issuer.
+var jwtSigningMethods = []string{
+	jwt.SigningMethodRS256.Alg(),
+	jwt.SigningMethodRS384.Alg(),
+	jwt.SigningMethodRS512.Alg(),
+	jwt.SigningMethodPS256.Alg(),
+	jwt.SigningMethodPS384.Alg(),
+	jwt.SigningMethodPS512.Alg(),
+	jwt.SigningMethodES256.Alg(),
+	jwt.SigningMethodES384.Alg(),
+	jwt.SigningMethodES512.Alg(),
+}
+
+// NewJWTFilter returns an implementation of the filter.Filter interface that
+// authenticates HTTP requests using bearer tokens. Tokens must be JWTs signed
+// using a key from the given key set, issued by the given issuer for the given
+// audience, and must not have expired. Up to the given clock skew is tolerated
+// when checking a token's expiry, not before and issued at times. The
+// principal is identified by the token's subject or, if it has none, by its
+// issuer.
+func NewJWTFilter(
+	keySet jwks.KeySet,
+	issuer string,
+	audience string,
+	clockSkew time.Duration,
+) filter.Filter {
+	parser := &jwt.Parser{
+		ValidMethods: jwtSigningMethods,
+		// Claims are validated below, allowing for clock skew
+		SkipClaimsValidation: true,
+	}
+	keyFunc := func(token *jwt.Token) (interface{}, error) {
+		kid, ok := token.Header["kid"].(string)
+		if !ok {
+			return nil, fmt.Errorf("token header does not include a key ID")
+		}
+		return keySet.GetKey(kid)
+	}
+	return filter.NewGenericFilter(
+		func(handle http.HandlerFunc) http.HandlerFunc {
+			return func(w http.ResponseWriter, r *http.Request) {
+				headerValueTokens := strings.SplitN(
+					r.Header.Get("Authorization"),
+					" ",
+					2,
+				)
+				if len(headerValueTokens) != 2 ||
+					headerValueTokens[0] != BearerScheme {
+					http.Error(w, "{}", http.StatusUnauthorized)
+					return
+				}
+				claims := jwt.MapClaims{}
+				_, err := parser.ParseWithClaims(headerValueTokens[1], claims, keyFunc)
+				if err == nil {
+					err = validateJWTClaims(claims, issuer, audience, clockSkew)
+				}
+				if err != nil {
+					log.WithField("error", err).Debug(
+						"authentication error: invalid bearer token",
+					)
+					http.Error(w, "{}", http.StatusUnauthorized)
+					return
+				}
+				ctx := identity.NewContext(
+					r.Context(),
+					identity.Identity{
+						Scheme: BearerScheme,
+						Name:   getJWTIdentityName(claims),
+					},
+				)
+				handle(w, r.WithContext(ctx))
+			}
+		},
+	)
+}
+
+func validateJWTClaims(
+	claims jwt.MapClaims,
+	issuer string,
+	audience string,
+	clockSkew time.Duration,
+) error {
+	now := time.Now()
+	if !claims.VerifyExpiresAt(now.Add(-clockSkew).Unix(), true) {
+		return fmt.Errorf("token is expired or does not specify an expiry")
+	}
+	if !claims.VerifyNotBefore(now.Add(clockSkew).Unix(), false) {
+		return fmt.Errorf("token is not valid yet")
+	}
+	if !claims.VerifyIssuedAt(now.Add(clockSkew).Unix(), false) {
+		return fmt.Errorf("token was issued in the future")
+	}
+	if !claims.VerifyIssuer(issuer, true) {
+		return fmt.Errorf(`token was not issued by "%s"`, issuer)
+	}
+	if !hasAudience(claims, audience) {
+		return fmt.Errorf(`token was not issued for audience "%s"`, audience)
+	}
+	return nil
+}
+
+// hasAudience returns a bool indicating whether the given claims include the
+// given audience. Per RFC 7519, the aud claim may be either a single string or
+// an array of strings.
+func hasAudience(claims jwt.MapClaims, audience string) bool {
+	switch aud := claims["aud"].(type) {
+	case string:
+		return aud == audience
+	case []interface{}:
+		for _, a := range aud {
+			if a == audience {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// getJWTIdentityName returns the subject of a token with the given claims or,
+// if the token has no subject, its issuer
+func getJWTIdentityName(claims jwt.MapClaims) string {
+	if sub, ok := claims["sub"].(string); ok && sub != "" {
+		return sub
+	}
+	iss, _ := claims["iss"].(string)
+	return iss
+}
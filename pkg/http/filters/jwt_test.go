@@ -0,0 +1,205 @@
+package filters
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/Azure/open-service-broker-azure/pkg/http/filter"
+	"github.com/Azure/open-service-broker-azure/pkg/identity"
+	jwt "github.com/dgrijalva/jwt-go"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	testIssuer   = "https://issuer.example.com"
+	testAudience = "broker"
+	testKeyID    = "foo"
+)
+
+var testSigningKey *rsa.PrivateKey
+
+func init() {
+	var err error
+	testSigningKey, err = rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		panic(err)
+	}
+}
+
+type fakeKeySet struct{}
+
+func (fakeKeySet) GetKey(kid string) (interface{}, error) {
+	if kid != testKeyID {
+		return nil, fmt.Errorf(`key "%s" not found`, kid)
+	}
+	return &testSigningKey.PublicKey, nil
+}
+
+func TestJWTFilterWithHeaderMissing(t *testing.T) {
+	rr, handlerCalled := executeJWTFilter(t, "")
+	assert.Equal(t, http.StatusUnauthorized, rr.Code)
+	assert.False(t, handlerCalled)
+}
+
+func TestJWTFilterWithHeaderNotBearer(t *testing.T) {
+	rr, handlerCalled := executeJWTFilter(t, "Basic Zm9vOmJhcg==")
+	assert.Equal(t, http.StatusUnauthorized, rr.Code)
+	assert.False(t, handlerCalled)
+}
+
+func TestJWTFilterWithValidToken(t *testing.T) {
+	rr, handlerCalled := executeJWTFilter(
+		t,
+		"Bearer "+getTestToken(t, testKeyID, getTestClaims()),
+	)
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.True(t, handlerCalled)
+}
+
+func TestJWTFilterIdentifiesSubject(t *testing.T) {
+	code, id := doJWTRequest(
+		t,
+		"Bearer "+getTestToken(t, testKeyID, getTestClaims()),
+	)
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, identity.Identity{Scheme: BearerScheme, Name: "platform"}, id)
+}
+
+func TestJWTFilterIdentifiesIssuerWithoutSubject(t *testing.T) {
+	claims := getTestClaims()
+	delete(claims, "sub")
+	code, id := doJWTRequest(t, "Bearer "+getTestToken(t, testKeyID, claims))
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, identity.Identity{Scheme: BearerScheme, Name: testIssuer}, id)
+}
+
+func TestJWTFilterWithAudienceArray(t *testing.T) {
+	claims := getTestClaims()
+	claims["aud"] = []string{"foo", testAudience}
+	rr, handlerCalled := executeJWTFilter(
+		t,
+		"Bearer "+getTestToken(t, testKeyID, claims),
+	)
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.True(t, handlerCalled)
+}
+
+func TestJWTFilterWithExpiryWithinClockSkew(t *testing.T) {
+	claims := getTestClaims()
+	claims["exp"] = time.Now().Add(-30 * time.Second).Unix()
+	rr, handlerCalled := executeJWTFilter(
+		t,
+		"Bearer "+getTestToken(t, testKeyID, claims),
+	)
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.True(t, handlerCalled)
+}
+
+func TestJWTFilterWithInvalidTokens(t *testing.T) {
+	testCases := map[string]func(jwt.MapClaims){
+		"expired": func(c jwt.MapClaims) {
+			c["exp"] = time.Now().Add(-5 * time.Minute).Unix()
+		},
+		"no expiry": func(c jwt.MapClaims) {
+			delete(c, "exp")
+		},
+		"not yet valid": func(c jwt.MapClaims) {
+			c["nbf"] = time.Now().Add(5 * time.Minute).Unix()
+		},
+		"wrong issuer": func(c jwt.MapClaims) {
+			c["iss"] = "https://evil.example.com"
+		},
+		"wrong audience": func(c jwt.MapClaims) {
+			c["aud"] = "foo"
+		},
+	}
+	for name, mutate := range testCases {
+		t.Run(name, func(t *testing.T) {
+			claims := getTestClaims()
+			mutate(claims)
+			rr, handlerCalled := executeJWTFilter(
+				t,
+				"Bearer "+getTestToken(t, testKeyID, claims),
+			)
+			assert.Equal(t, http.StatusUnauthorized, rr.Code)
+			assert.False(t, handlerCalled)
+		})
+	}
+}
+
+func TestJWTFilterWithUnknownKey(t *testing.T) {
+	rr, handlerCalled := executeJWTFilter(
+		t,
+		"Bearer "+getTestToken(t, "bar", getTestClaims()),
+	)
+	assert.Equal(t, http.StatusUnauthorized, rr.Code)
+	assert.False(t, handlerCalled)
+}
+
+func TestJWTFilterWithSymmetricallySignedToken(t *testing.T) {
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, getTestClaims())
+	token.Header["kid"] = testKeyID
+	tokenStr, err := token.SignedString([]byte("secret"))
+	assert.Nil(t, err)
+	rr, handlerCalled := executeJWTFilter(t, "Bearer "+tokenStr)
+	assert.Equal(t, http.StatusUnauthorized, rr.Code)
+	assert.False(t, handlerCalled)
+}
+
+func getTestJWTFilter() filter.Filter {
+	return NewJWTFilter(fakeKeySet{}, testIssuer, testAudience, time.Minute)
+}
+
+func executeJWTFilter(
+	t *testing.T,
+	authHeader string,
+) (*httptest.ResponseRecorder, bool) {
+	req, err := http.NewRequest(http.MethodGet, "/", nil)
+	assert.Nil(t, err)
+	if authHeader != "" {
+		req.Header.Set("Authorization", authHeader)
+	}
+	rr := httptest.NewRecorder()
+	handlerCalled := false
+	getTestJWTFilter().GetHandler(func(http.ResponseWriter, *http.Request) {
+		handlerCalled = true
+	})(rr, req)
+	return rr, handlerCalled
+}
+
+func doJWTRequest(t *testing.T, authHeader string) (int, identity.Identity) {
+	req, err := http.NewRequest(http.MethodGet, "/", nil)
+	assert.Nil(t, err)
+	req.Header.Set("Authorization", authHeader)
+	var id identity.Identity
+	rr := httptest.NewRecorder()
+	getTestJWTFilter().GetHandler(func(_ http.ResponseWriter, r *http.Request) {
+		id, _ = identity.FromContext(r.Context())
+	})(rr, req)
+	return rr.Code, id
+}
+
+func getTestClaims() jwt.MapClaims {
+	now := time.Now()
+	return jwt.MapClaims{
+		"iss": testIssuer,
+		"aud": testAudience,
+		"sub": "platform",
+		"iat": now.Unix(),
+		"nbf": now.Unix(),
+		"exp": now.Add(time.Hour).Unix(),
+	}
+}
+
+func getTestToken(t *testing.T, kid string, claims jwt.MapClaims) string {
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	token.Header["kid"] = kid
+	tokenStr, err := token.SignedString(testSigningKey)
+	assert.Nil(t, err)
+	return tokenStr
+}
@@ -0,0 +1,155 @@
+package filters
+
+import (
+	"fmt"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/Azure/open-service-broker-azure/pkg/http/filter"
+	"github.com/Azure/open-service-broker-azure/pkg/identity"
+	log "github.com/Sirupsen/logrus"
+	"github.com/gorilla/mux"
+)
+
+// rateLimiterSweepInterval is how often idle token buckets are discarded
+const rateLimiterSweepInterval = time.Minute
+
+// tokenBucket tracks the number of requests a single client may make on a
+// single route
+type tokenBucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// rateLimiter maintains a token bucket for each combination of client and
+// route
+type rateLimiter struct {
+	rate      float64
+	burst     float64
+	now       func() time.Time
+	mutex     sync.Mutex
+	buckets   map[string]*tokenBucket
+	lastSweep time.Time
+}
+
+// NewRateLimitFilter returns an implementation of the filter.Filter interface
+// that limits the rate at which each client may make requests on each route.
+// Each client may make requests on each route at the given sustained rate (in
+// requests per second) and may exceed that rate by up to the given burst.
+// Clients are distinguished by the identity established by earlier filters
+// or, failing that, by remote address, so this filter should follow any
+// authentication filters in the chain. Requests in excess of the limit are
+// rejected with a 429 and a Retry-After header.
+func NewRateLimitFilter(rate float64, burst int) filter.Filter {
+	return newRateLimitFilter(rate, burst, time.Now)
+}
+
+func newRateLimitFilter(
+	rate float64,
+	burst int,
+	now func() time.Time,
+) filter.Filter {
+	limiter := &rateLimiter{
+		rate:      rate,
+		burst:     float64(burst),
+		now:       now,
+		buckets:   map[string]*tokenBucket{},
+		lastSweep: now(),
+	}
+	return filter.NewGenericFilter(
+		func(handle http.HandlerFunc) http.HandlerFunc {
+			return func(w http.ResponseWriter, r *http.Request) {
+				client := getRateLimitClient(r)
+				route := getRateLimitRoute(r)
+				ok, retryAfter := limiter.take(client + " " + route)
+				if !ok {
+					log.WithFields(log.Fields{
+						"client": client,
+						"route":  route,
+					}).Debug("request rejected: rate limit exceeded")
+					w.Header().Set(
+						"Retry-After",
+						strconv.Itoa(int(math.Ceil(retryAfter.Seconds()))),
+					)
+					http.Error(w, "{}", http.StatusTooManyRequests)
+					return
+				}
+				handle(w, r)
+			}
+		},
+	)
+}
+
+// take attempts to take a token from the bucket with the given key. If no
+// token is available, it returns false, along with how long it will be until
+// one is.
+func (l *rateLimiter) take(key string) (bool, time.Duration) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	now := l.now()
+	l.sweep(now)
+	bucket, ok := l.buckets[key]
+	if !ok {
+		bucket = &tokenBucket{
+			tokens: l.burst,
+			last:   now,
+		}
+		l.buckets[key] = bucket
+	}
+	bucket.tokens = math.Min(
+		l.burst,
+		bucket.tokens+now.Sub(bucket.last).Seconds()*l.rate,
+	)
+	bucket.last = now
+	if bucket.tokens < 1 {
+		wait := (1 - bucket.tokens) / l.rate
+		return false, time.Duration(wait * float64(time.Second))
+	}
+	bucket.tokens--
+	return true, 0
+}
+
+// sweep discards buckets that would have refilled completely by now, since
+// they are indistinguishable from new buckets
+func (l *rateLimiter) sweep(now time.Time) {
+	if now.Sub(l.lastSweep) < rateLimiterSweepInterval {
+		return
+	}
+	l.lastSweep = now
+	refillTime := time.Duration(l.burst / l.rate * float64(time.Second))
+	for key, bucket := range l.buckets {
+		if now.Sub(bucket.last) >= refillTime {
+			delete(l.buckets, key)
+		}
+	}
+}
+
+// getRateLimitClient returns a string identifying the client making the given
+// request
+func getRateLimitClient(r *http.Request) string {
+	if id, ok := identity.FromContext(r.Context()); ok {
+		return fmt.Sprintf("%s:%s", id.Scheme, id.Name)
+	}
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+// getRateLimitRoute returns a string identifying the route of the given
+// request. Where possible, the route's path template is used so that, for
+// instance, requests to provision different instances share a limit.
+func getRateLimitRoute(r *http.Request) string {
+	route := r.URL.Path
+	if currentRoute := mux.CurrentRoute(r); currentRoute != nil {
+		if tpl, err := currentRoute.GetPathTemplate(); err == nil {
+			route = tpl
+		}
+	}
+	return r.Method + " " + route
+}
@@ -0,0 +1,64 @@
+package filters
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/Azure/open-service-broker-azure/pkg/identity"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRateLimitFilter(t *testing.T) {
+	now := time.Now()
+	f := newRateLimitFilter(1, 2, func() time.Time { return now })
+	handle := f.GetHandler(func(http.ResponseWriter, *http.Request) {})
+	doRequest := func(path string, name string) *httptest.ResponseRecorder {
+		req, err := http.NewRequest(http.MethodPut, path, nil)
+		assert.Nil(t, err)
+		req = req.WithContext(
+			identity.NewContext(
+				req.Context(),
+				identity.Identity{Scheme: BasicAuthScheme, Name: name},
+			),
+		)
+		rr := httptest.NewRecorder()
+		handle(rr, req)
+		return rr
+	}
+	// The burst is permitted
+	assert.Equal(t, http.StatusOK, doRequest("/foo", "alice").Code)
+	assert.Equal(t, http.StatusOK, doRequest("/foo", "alice").Code)
+	// But nothing more
+	rr := doRequest("/foo", "alice")
+	assert.Equal(t, http.StatusTooManyRequests, rr.Code)
+	assert.Equal(t, "1", rr.Header().Get("Retry-After"))
+	// Other clients and other routes are limited separately
+	assert.Equal(t, http.StatusOK, doRequest("/foo", "bob").Code)
+	assert.Equal(t, http.StatusOK, doRequest("/bar", "alice").Code)
+	// Tokens are replenished over time
+	now = now.Add(time.Second)
+	assert.Equal(t, http.StatusOK, doRequest("/foo", "alice").Code)
+	assert.Equal(t, http.StatusTooManyRequests, doRequest("/foo", "alice").Code)
+}
+
+func TestRateLimitFilterSweepsIdleBuckets(t *testing.T) {
+	now := time.Now()
+	limiter := &rateLimiter{
+		rate:      1,
+		burst:     2,
+		now:       func() time.Time { return now },
+		buckets:   map[string]*tokenBucket{},
+		lastSweep: now,
+	}
+	ok, _ := limiter.take("foo")
+	assert.True(t, ok)
+	assert.Len(t, limiter.buckets, 1)
+	now = now.Add(rateLimiterSweepInterval)
+	ok, _ = limiter.take("bar")
+	assert.True(t, ok)
+	assert.Len(t, limiter.buckets, 1)
+	_, ok = limiter.buckets["bar"]
+	assert.True(t, ok)
+}
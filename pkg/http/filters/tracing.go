@@ -0,0 +1,79 @@
+package filters
+
+import (
+	"net/http"
+
+	"github.com/Azure/open-service-broker-azure/pkg/http/filter"
+	"github.com/gorilla/mux"
+	"go.opencensus.io/plugin/ochttp"
+	"go.opencensus.io/plugin/ochttp/propagation/tracecontext"
+	"go.opencensus.io/trace"
+)
+
+// statusRecorder is an http.ResponseWriter that remembers the status code
+// written to it
+type statusRecorder struct {
+	http.ResponseWriter
+	statusCode int
+}
+
+func (s *statusRecorder) WriteHeader(statusCode int) {
+	s.statusCode = statusCode
+	s.ResponseWriter.WriteHeader(statusCode)
+}
+
+// NewTracingFilter returns an implementation of the filter.Filter interface
+// that starts a span for each HTTP request. If the request carries a W3C
+// traceparent header, the span continues the caller's trace. The span is
+// stored in the request's context so that downstream handlers may start child
+// spans. This filter should be first in the chain so that work done by all
+// other filters is included in the span.
+func NewTracingFilter() filter.Filter {
+	format := &tracecontext.HTTPFormat{}
+	return filter.NewGenericFilter(
+		func(handle http.HandlerFunc) http.HandlerFunc {
+			return func(w http.ResponseWriter, r *http.Request) {
+				name := r.URL.Path
+				if route := mux.CurrentRoute(r); route != nil {
+					if tpl, err := route.GetPathTemplate(); err == nil {
+						name = tpl
+					}
+				}
+				name = r.Method + " " + name
+				ctx := r.Context()
+				var span *trace.Span
+				if sc, ok := format.SpanContextFromRequest(r); ok {
+					ctx, span = trace.StartSpanWithRemoteParent(
+						ctx,
+						name,
+						sc,
+						trace.WithSpanKind(trace.SpanKindServer),
+					)
+				} else {
+					ctx, span = trace.StartSpan(
+						ctx,
+						name,
+						trace.WithSpanKind(trace.SpanKindServer),
+					)
+				}
+				defer span.End()
+				span.AddAttributes(
+					trace.StringAttribute(ochttp.MethodAttribute, r.Method),
+					trace.StringAttribute(ochttp.PathAttribute, r.URL.Path),
+				)
+				rec := &statusRecorder{
+					ResponseWriter: w,
+					statusCode:     http.StatusOK,
+				}
+				handle(rec, r.WithContext(ctx))
+				span.AddAttributes(
+					trace.Int64Attribute(
+						ochttp.StatusCodeAttribute,
+						int64(rec.statusCode),
+					),
+				)
+				span.SetStatus(ochttp.TraceStatus(rec.statusCode, ""))
+			}
+		},
+	)
+}
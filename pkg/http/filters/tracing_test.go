@@ -0,0 +1,43 @@
+package filters
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.opencensus.io/trace"
+)
+
+func TestTracingFilterStartsSpan(t *testing.T) {
+	req, err := http.NewRequest(http.MethodGet, "/", nil)
+	assert.Nil(t, err)
+	rr := httptest.NewRecorder()
+	var span *trace.Span
+	NewTracingFilter().GetHandler(func(w http.ResponseWriter, r *http.Request) {
+		span = trace.FromContext(r.Context())
+		w.WriteHeader(http.StatusAccepted)
+	})(rr, req)
+	assert.NotNil(t, span)
+	assert.Equal(t, http.StatusAccepted, rr.Code)
+}
+
+func TestTracingFilterContinuesRemoteTrace(t *testing.T) {
+	req, err := http.NewRequest(http.MethodGet, "/", nil)
+	assert.Nil(t, err)
+	req.Header.Set(
+		"traceparent",
+		"00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01",
+	)
+	rr := httptest.NewRecorder()
+	var span *trace.Span
+	NewTracingFilter().GetHandler(func(w http.ResponseWriter, r *http.Request) {
+		span = trace.FromContext(r.Context())
+	})(rr, req)
+	assert.NotNil(t, span)
+	assert.Equal(
+		t,
+		"0af7651916cd43dd8448eb211c80319c",
+		span.SpanContext().TraceID.String(),
+	)
+}
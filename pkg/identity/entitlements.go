@@ -0,0 +1,38 @@
+package identity
+
+// Entitlements restricts the services and plans that a principal may see and
+// use. Keys are the IDs of permitted services. Values are the IDs of permitted
+// plans for the corresponding service; an empty list of plan IDs permits all
+// of a service's plans. A nil Entitlements permits all services and plans.
+type Entitlements map[string][]string
+
+// AllowsService returns a bool indicating whether the entitlements permit use
+// of at least some plans of the service with the given ID
+func (e Entitlements) AllowsService(serviceID string) bool {
+	if e == nil {
+		return true
+	}
+	_, ok := e[serviceID]
+	return ok
+}
+
+// AllowsPlan returns a bool indicating whether the entitlements permit use of
+// the given plan of the given service
+func (e Entitlements) AllowsPlan(serviceID, planID string) bool {
+	if e == nil {
+		return true
+	}
+	planIDs, ok := e[serviceID]
+	if !ok {
+		return false
+	}
+	if len(planIDs) == 0 {
+		return true
+	}
+	for _, id := range planIDs {
+		if id == planID {
+			return true
+		}
+	}
+	return false
+}
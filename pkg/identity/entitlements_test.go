@@ -0,0 +1,28 @@
+package identity
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNilEntitlementsAllowEverything(t *testing.T) {
+	var e Entitlements
+	assert.True(t, e.AllowsService("foo"))
+	assert.True(t, e.AllowsPlan("foo", "bar"))
+}
+
+func TestEntitlements(t *testing.T) {
+	e := Entitlements{
+		"svc-a": nil,
+		"svc-b": {"plan-1"},
+	}
+	assert.True(t, e.AllowsService("svc-a"))
+	assert.True(t, e.AllowsPlan("svc-a", "plan-1"))
+	assert.True(t, e.AllowsPlan("svc-a", "plan-2"))
+	assert.True(t, e.AllowsService("svc-b"))
+	assert.True(t, e.AllowsPlan("svc-b", "plan-1"))
+	assert.False(t, e.AllowsPlan("svc-b", "plan-2"))
+	assert.False(t, e.AllowsService("svc-c"))
+	assert.False(t, e.AllowsPlan("svc-c", "plan-1"))
+}
@@ -0,0 +1,32 @@
+package identity
+
+import (
+	"context"
+)
+
+// Identity describes the authenticated principal on whose behalf a request is
+// being made
+type Identity struct {
+	// Scheme is the means by which the principal was authenticated, e.g.
+	// "ClientCert"
+	Scheme string
+	// Name uniquely identifies the principal within the given scheme
+	Name string
+	// Entitlements optionally restricts which services and plans the principal
+	// may see and use. If nil, the principal is not restricted.
+	Entitlements Entitlements
+}
+
+type identityContextKey struct{}
+
+// NewContext returns a copy of the given context that carries the given
+// identity
+func NewContext(ctx context.Context, id Identity) context.Context {
+	return context.WithValue(ctx, identityContextKey{}, id)
+}
+
+// FromContext returns the identity carried by the given context, if any
+func FromContext(ctx context.Context) (Identity, bool) {
+	id, ok := ctx.Value(identityContextKey{}).(Identity)
+	return id, ok
+}
@@ -0,0 +1,34 @@
+package jwks
+
+import (
+	"fmt"
+	"io/ioutil"
+)
+
+type staticKeySet struct {
+	keys map[string]interface{}
+}
+
+// NewKeySetFromFile returns a KeySet containing the keys in the JWKS document
+// at the given path. The document is read only once.
+func NewKeySetFromFile(path string) (KeySet, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf(`error reading JWKS document "%s": %s`, path, err)
+	}
+	keys, err := parse(data)
+	if err != nil {
+		return nil, err
+	}
+	return &staticKeySet{
+		keys: keys,
+	}, nil
+}
+
+func (s *staticKeySet) GetKey(kid string) (interface{}, error) {
+	key, ok := s.keys[kid]
+	if !ok {
+		return nil, &errKeyNotFound{kid: kid}
+	}
+	return key, nil
+}
@@ -0,0 +1,136 @@
+// Package jwks provides access to the public keys published in JSON Web Key
+// Set (JWKS) documents, such as those published by OpenID Connect providers
+// for the purpose of verifying the signatures of the tokens they issue.
+package jwks
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math/big"
+)
+
+// KeySet is an interface to be implemented by components that provide public
+// keys by key ID
+type KeySet interface {
+	// GetKey returns the public key having the given key ID. The returned key
+	// is either an *rsa.PublicKey or an *ecdsa.PublicKey.
+	GetKey(kid string) (interface{}, error)
+}
+
+type document struct {
+	Keys []jsonWebKey `json:"keys"`
+}
+
+type jsonWebKey struct {
+	KeyType string `json:"kty"`
+	KeyID   string `json:"kid"`
+	Use     string `json:"use"`
+	N       string `json:"n"`
+	E       string `json:"e"`
+	Curve   string `json:"crv"`
+	X       string `json:"x"`
+	Y       string `json:"y"`
+}
+
+// parse parses a JWKS document and returns the signature verification keys it
+// contains, indexed by key ID. Keys of unsupported types and keys intended for
+// uses other than signature verification are ignored.
+func parse(data []byte) (map[string]interface{}, error) {
+	doc := document{}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("error unmarshaling JWKS document: %s", err)
+	}
+	keys := map[string]interface{}{}
+	for _, jwk := range doc.Keys {
+		if jwk.Use != "" && jwk.Use != "sig" {
+			continue
+		}
+		var key interface{}
+		var err error
+		switch jwk.KeyType {
+		case "RSA":
+			key, err = jwk.rsaPublicKey()
+		case "EC":
+			key, err = jwk.ecdsaPublicKey()
+		default:
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf(`error parsing key "%s": %s`, jwk.KeyID, err)
+		}
+		keys[jwk.KeyID] = key
+	}
+	return keys, nil
+}
+
+func (j jsonWebKey) rsaPublicKey() (*rsa.PublicKey, error) {
+	n, err := decodeBigInt(j.N)
+	if err != nil {
+		return nil, fmt.Errorf("error decoding modulus: %s", err)
+	}
+	e, err := decodeBigInt(j.E)
+	if err != nil {
+		return nil, fmt.Errorf("error decoding exponent: %s", err)
+	}
+	if !e.IsInt64() {
+		return nil, fmt.Errorf("exponent is too large")
+	}
+	return &rsa.PublicKey{
+		N: n,
+		E: int(e.Int64()),
+	}, nil
+}
+
+func (j jsonWebKey) ecdsaPublicKey() (*ecdsa.PublicKey, error) {
+	var curve elliptic.Curve
+	switch j.Curve {
+	case "P-256":
+		curve = elliptic.P256()
+	case "P-384":
+		curve = elliptic.P384()
+	case "P-521":
+		curve = elliptic.P521()
+	default:
+		return nil, fmt.Errorf(`unsupported curve "%s"`, j.Curve)
+	}
+	x, err := decodeBigInt(j.X)
+	if err != nil {
+		return nil, fmt.Errorf("error decoding x coordinate: %s", err)
+	}
+	y, err := decodeBigInt(j.Y)
+	if err != nil {
+		return nil, fmt.Errorf("error decoding y coordinate: %s", err)
+	}
+	if !curve.IsOnCurve(x, y) {
+		return nil, fmt.Errorf("point is not on curve %s", j.Curve)
+	}
+	return &ecdsa.PublicKey{
+		Curve: curve,
+		X:     x,
+		Y:     y,
+	}, nil
+}
+
+func decodeBigInt(s string) (*big.Int, error) {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, err
+	}
+	if len(b) == 0 {
+		return nil, fmt.Errorf("value is empty")
+	}
+	return new(big.Int).SetBytes(b), nil
+}
+
+// errKeyNotFound is returned when a key set does not contain a requested key
+type errKeyNotFound struct {
+	kid string
+}
+
+func (e *errKeyNotFound) Error() string {
+	return fmt.Sprintf(`key "%s" not found`, e.kid)
+}
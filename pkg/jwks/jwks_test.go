@@ -0,0 +1,228 @@
+package jwks
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"io/ioutil"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParse(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.Nil(t, err)
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.Nil(t, err)
+	keys, err := parse(
+		getTestDocument(
+			t,
+			rsaJWK("rsa", &rsaKey.PublicKey),
+			ecJWK("ec", &ecKey.PublicKey),
+			map[string]string{"kty": "oct", "kid": "symmetric", "k": "Zm9v"},
+			map[string]string{"kty": "RSA", "kid": "encryption", "use": "enc"},
+		),
+	)
+	assert.Nil(t, err)
+	assert.Len(t, keys, 2)
+	assert.Equal(t, &rsaKey.PublicKey, keys["rsa"])
+	assert.Equal(t, &ecKey.PublicKey, keys["ec"])
+}
+
+func TestParseWithInvalidKey(t *testing.T) {
+	_, err := parse(
+		getTestDocument(
+			t,
+			map[string]string{"kty": "EC", "kid": "foo", "crv": "P-256"},
+		),
+	)
+	assert.NotNil(t, err)
+}
+
+func TestNewKeySetFromFile(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.Nil(t, err)
+	file, err := ioutil.TempFile("", "jwks")
+	assert.Nil(t, err)
+	defer os.Remove(file.Name()) // nolint: errcheck
+	_, err = file.Write(getTestDocument(t, rsaJWK("foo", &rsaKey.PublicKey)))
+	assert.Nil(t, err)
+	assert.Nil(t, file.Close())
+	keySet, err := NewKeySetFromFile(file.Name())
+	assert.Nil(t, err)
+	key, err := keySet.GetKey("foo")
+	assert.Nil(t, err)
+	assert.Equal(t, &rsaKey.PublicKey, key)
+	_, err = keySet.GetKey("bar")
+	assert.NotNil(t, err)
+}
+
+func TestRemoteKeySetCachesKeys(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.Nil(t, err)
+	doc := getTestDocument(t, rsaJWK("foo", &rsaKey.PublicKey))
+	var fetches int32
+	server := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			atomic.AddInt32(&fetches, 1)
+			w.Write(doc) // nolint: errcheck
+		}),
+	)
+	defer server.Close()
+	keySet := NewRemoteKeySet(server.URL, time.Hour)
+	for i := 0; i < 3; i++ {
+		key, err := keySet.GetKey("foo")
+		assert.Nil(t, err)
+		assert.Equal(t, &rsaKey.PublicKey, key)
+	}
+	// An unknown key shouldn't cause a refetch so soon after the last fetch
+	_, err = keySet.GetKey("bar")
+	assert.NotNil(t, err)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&fetches))
+}
+
+func TestRemoteKeySetRefreshesStaleKeys(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.Nil(t, err)
+	doc := getTestDocument(t, rsaJWK("foo", &rsaKey.PublicKey))
+	var fetches int32
+	server := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			if atomic.AddInt32(&fetches, 1) > 1 {
+				w.WriteHeader(http.StatusInternalServerError)
+				return
+			}
+			w.Write(doc) // nolint: errcheck
+		}),
+	)
+	defer server.Close()
+	keySet := NewRemoteKeySet(server.URL, 0)
+	_, err = keySet.GetKey("foo")
+	assert.Nil(t, err)
+	// The refresh fails, but the cached key is still served
+	key, err := keySet.GetKey("foo")
+	assert.Nil(t, err)
+	assert.Equal(t, &rsaKey.PublicKey, key)
+	assert.Equal(t, int32(2), atomic.LoadInt32(&fetches))
+}
+
+func TestRemoteKeySetFetchesOnceForConcurrentRequests(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.Nil(t, err)
+	doc := getTestDocument(t, rsaJWK("foo", &rsaKey.PublicKey))
+	var fetches int32
+	fetching := make(chan struct{})
+	release := make(chan struct{})
+	server := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			if atomic.AddInt32(&fetches, 1) == 1 {
+				close(fetching)
+			}
+			<-release
+			w.Write(doc) // nolint: errcheck
+		}),
+	)
+	defer server.Close()
+	keySet := NewRemoteKeySet(server.URL, time.Hour)
+	const requests = 10
+	errCh := make(chan error, requests)
+	for i := 0; i < requests; i++ {
+		go func() {
+			_, getErr := keySet.GetKey("foo")
+			errCh <- getErr
+		}()
+	}
+	<-fetching
+	close(release)
+	for i := 0; i < requests; i++ {
+		assert.Nil(t, <-errCh)
+	}
+	assert.Equal(t, int32(1), atomic.LoadInt32(&fetches))
+}
+
+func TestRemoteKeySetServesCachedKeysDuringRefresh(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.Nil(t, err)
+	doc := getTestDocument(t, rsaJWK("foo", &rsaKey.PublicKey))
+	fetching := make(chan struct{})
+	release := make(chan struct{})
+	server := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			close(fetching)
+			<-release
+			w.Write(doc) // nolint: errcheck
+		}),
+	)
+	defer server.Close()
+	keySet := NewRemoteKeySet(server.URL, time.Hour).(*remoteKeySet)
+	keySet.keys = map[string]interface{}{"foo": &rsaKey.PublicKey}
+	keySet.fetched = time.Now().Add(-2 * minRefreshInterval)
+	// A request for an unknown key prompts a refresh...
+	errCh := make(chan error, 1)
+	go func() {
+		_, getErr := keySet.GetKey("bar")
+		errCh <- getErr
+	}()
+	<-fetching
+	// ...during which cached keys are still served without waiting
+	key, err := keySet.GetKey("foo")
+	assert.Nil(t, err)
+	assert.Equal(t, &rsaKey.PublicKey, key)
+	close(release)
+	assert.NotNil(t, <-errCh)
+}
+
+func TestDiscoverURL(t *testing.T) {
+	server := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "/.well-known/openid-configuration", r.URL.Path)
+			w.Write( // nolint: errcheck
+				[]byte(`{"jwks_uri":"https://example.com/keys"}`),
+			)
+		}),
+	)
+	defer server.Close()
+	url, err := DiscoverURL(server.URL + "/")
+	assert.Nil(t, err)
+	assert.Equal(t, "https://example.com/keys", url)
+}
+
+func getTestDocument(t *testing.T, keys ...map[string]string) []byte {
+	data, err := json.Marshal(map[string]interface{}{"keys": keys})
+	assert.Nil(t, err)
+	return data
+}
+
+func rsaJWK(kid string, key *rsa.PublicKey) map[string]string {
+	return map[string]string{
+		"kty": "RSA",
+		"kid": kid,
+		"use": "sig",
+		"n":   encodeBigInt(key.N),
+		"e":   encodeBigInt(big.NewInt(int64(key.E))),
+	}
+}
+
+func ecJWK(kid string, key *ecdsa.PublicKey) map[string]string {
+	return map[string]string{
+		"kty": "EC",
+		"kid": kid,
+		"crv": key.Curve.Params().Name,
+		"x":   encodeBigInt(key.X),
+		"y":   encodeBigInt(key.Y),
+	}
+}
+
+func encodeBigInt(i *big.Int) string {
+	return base64.RawURLEncoding.EncodeToString(i.Bytes())
+}
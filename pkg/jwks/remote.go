@@ -0,0 +1,181 @@
+package jwks
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	log "github.com/Sirupsen/logrus"
+)
+
+// minRefreshInterval is the minimum time that must elapse between fetches
+// prompted by requests for unknown keys. It prevents requests bearing bogus
+// key IDs from causing the JWKS document to be fetched on every request.
+const minRefreshInterval = time.Minute
+
+type remoteKeySet struct {
+	url             string
+	refreshInterval time.Duration
+	client          *http.Client
+	// mutex guards all of the fields below. It is never held while the JWKS
+	// document is being fetched.
+	mutex   sync.RWMutex
+	keys    map[string]interface{}
+	fetched time.Time
+	// refreshing is non-nil while the JWKS document is being fetched and is
+	// closed when the fetch completes
+	refreshing chan struct{}
+	// refreshErr is the error, if any, from the most recent fetch
+	refreshErr error
+}
+
+// NewRemoteKeySet returns a KeySet containing the keys in the JWKS document
+// published at the given URL. The document is fetched on first use and cached
+// keys are refreshed once the given interval has elapsed, or sooner if a key
+// that isn't cached is requested. This accommodates key rotation by the
+// publisher. If a refresh fails, previously cached keys continue to be used.
+func NewRemoteKeySet(url string, refreshInterval time.Duration) KeySet {
+	return &remoteKeySet{
+		url:             url,
+		refreshInterval: refreshInterval,
+		client: &http.Client{
+			Timeout: 30 * time.Second,
+		},
+	}
+}
+
+func (r *remoteKeySet) GetKey(kid string) (interface{}, error) {
+	r.mutex.RLock()
+	key, ok := r.keys[kid]
+	needsRefresh := r.needsRefresh(kid)
+	r.mutex.RUnlock()
+	if needsRefresh {
+		err := r.refresh(kid)
+		r.mutex.RLock()
+		key, ok = r.keys[kid]
+		haveKeys := r.keys != nil
+		r.mutex.RUnlock()
+		if err != nil {
+			if !haveKeys {
+				return nil, err
+			}
+			log.WithFields(log.Fields{
+				"url":   r.url,
+				"error": err,
+			}).Warn("error refreshing JWKS document; using cached keys")
+		}
+	}
+	if !ok {
+		return nil, &errKeyNotFound{kid: kid}
+	}
+	return key, nil
+}
+
+// needsRefresh returns true if the JWKS document should be fetched before the
+// given key is looked up. The caller must hold the mutex.
+func (r *remoteKeySet) needsRefresh(kid string) bool {
+	_, ok := r.keys[kid]
+	sinceFetched := time.Since(r.fetched)
+	return r.keys == nil ||
+		sinceFetched > r.refreshInterval ||
+		(!ok && sinceFetched > minRefreshInterval)
+}
+
+// refresh fetches the JWKS document and replaces the cached keys with the keys
+// it contains. If a fetch is already in progress, refresh waits for it to
+// complete instead of starting another. If the fetch that was waited upon, or
+// one that completed while refresh was waiting to acquire the mutex, made the
+// given key current, no further fetch is made.
+func (r *remoteKeySet) refresh(kid string) error {
+	r.mutex.Lock()
+	if done := r.refreshing; done != nil {
+		r.mutex.Unlock()
+		<-done
+		r.mutex.RLock()
+		defer r.mutex.RUnlock()
+		return r.refreshErr
+	}
+	if !r.needsRefresh(kid) {
+		r.mutex.Unlock()
+		return nil
+	}
+	done := make(chan struct{})
+	r.refreshing = done
+	// Record the attempt even if it fails so that a publisher that is down
+	// isn't hammered
+	r.fetched = time.Now()
+	r.mutex.Unlock()
+
+	keys, err := r.fetch()
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	if err == nil {
+		r.keys = keys
+	}
+	r.refreshErr = err
+	r.refreshing = nil
+	close(done)
+	return err
+}
+
+func (r *remoteKeySet) fetch() (map[string]interface{}, error) {
+	data, err := get(r.client, r.url)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching JWKS document: %s", err)
+	}
+	return parse(data)
+}
+
+// DiscoverURL uses OpenID Connect discovery to determine the URL of the JWKS
+// document published by the given issuer
+func DiscoverURL(issuer string) (string, error) {
+	url := strings.TrimSuffix(issuer, "/") + "/.well-known/openid-configuration"
+	data, err := get(
+		&http.Client{
+			Timeout: 30 * time.Second,
+		},
+		url,
+	)
+	if err != nil {
+		return "", fmt.Errorf(
+			"error fetching OpenID Connect provider configuration: %s",
+			err,
+		)
+	}
+	config := struct {
+		JWKSURI string `json:"jwks_uri"`
+	}{}
+	if err = json.Unmarshal(data, &config); err != nil {
+		return "", fmt.Errorf(
+			"error unmarshaling OpenID Connect provider configuration: %s",
+			err,
+		)
+	}
+	if config.JWKSURI == "" {
+		return "", fmt.Errorf(
+			"OpenID Connect provider configuration does not include jwks_uri",
+		)
+	}
+	return config.JWKSURI, nil
+}
+
+func get(client *http.Client, url string) ([]byte, error) {
+	resp, err := client.Get(url)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close() // nolint: errcheck
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf(
+			`unexpected status code %d from "%s"`,
+			resp.StatusCode,
+			url,
+		)
+	}
+	return ioutil.ReadAll(resp.Body)
+}
@@ -1,18 +1,31 @@
 package log
 
 import (
+	"fmt"
+	"strings"
+
 	log "github.com/Sirupsen/logrus"
 	"github.com/kelseyhightower/envconfig"
 )
 
+const (
+	// TEXT represents human-readable log output
+	TEXT = "TEXT"
+	// JSON represents log output with one JSON object per line
+	JSON = "JSON"
+)
+
 // Config represents configuration options for the broker's leveled logging
 type Config interface {
 	GetLevel() log.Level
+	GetFormatter() log.Formatter
 }
 
 type config struct {
-	LevelStr string `envconfig:"LOG_LEVEL" default:"INFO"`
-	Level    log.Level
+	LevelStr  string `envconfig:"LOG_LEVEL" default:"INFO"`
+	Level     log.Level
+	FormatStr string `envconfig:"LOG_FORMAT" default:"TEXT"`
+	Formatter log.Formatter
 }
 
 // GetConfig returns log configuration
@@ -23,9 +36,26 @@ func GetConfig() (Config, error) {
 		return lc, err
 	}
 	lc.Level, err = log.ParseLevel(lc.LevelStr)
-	return lc, err
+	if err != nil {
+		return lc, err
+	}
+	switch strings.ToUpper(lc.FormatStr) {
+	case TEXT:
+		lc.Formatter = &log.TextFormatter{
+			FullTimestamp: true,
+		}
+	case JSON:
+		lc.Formatter = &log.JSONFormatter{}
+	default:
+		return lc, fmt.Errorf(`unrecognized log format "%s"`, lc.FormatStr)
+	}
+	return lc, nil
 }
 
 func (c config) GetLevel() log.Level {
 	return c.Level
 }
+
+func (c config) GetFormatter() log.Formatter {
+	return c.Formatter
+}
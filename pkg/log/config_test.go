@@ -0,0 +1,26 @@
+package log
+
+import (
+	"os"
+	"testing"
+
+	log "github.com/Sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetConfigWithJSONFormat(t *testing.T) {
+	err := os.Setenv("LOG_FORMAT", "json")
+	assert.Nil(t, err)
+	defer os.Unsetenv("LOG_FORMAT") // nolint: errcheck
+	c, err := GetConfig()
+	assert.Nil(t, err)
+	assert.IsType(t, &log.JSONFormatter{}, c.GetFormatter())
+}
+
+func TestGetConfigWithInvalidFormat(t *testing.T) {
+	err := os.Setenv("LOG_FORMAT", "xml")
+	assert.Nil(t, err)
+	defer os.Unsetenv("LOG_FORMAT") // nolint: errcheck
+	_, err = GetConfig()
+	assert.NotNil(t, err)
+}
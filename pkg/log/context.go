@@ -0,0 +1,78 @@
+package log
+
+import (
+	"context"
+
+	log "github.com/Sirupsen/logrus"
+)
+
+// requestIDKey is the key under which a request ID is stored, both as a log
+// field and in the args of an async task
+const requestIDKey = "requestID"
+
+type fieldsContextKey struct{}
+
+// NewContext returns a copy of the given context that carries the given log
+// fields in addition to any fields already carried by the parent context.
+// Where field names collide, the given fields win.
+func NewContext(ctx context.Context, fields log.Fields) context.Context {
+	merged := FieldsFromContext(ctx)
+	for k, v := range fields {
+		merged[k] = v
+	}
+	return context.WithValue(ctx, fieldsContextKey{}, merged)
+}
+
+// FieldsFromContext returns a copy of the log fields carried by the given
+// context. Callers are free to add to or otherwise modify the copy.
+func FieldsFromContext(ctx context.Context) log.Fields {
+	fields := log.Fields{}
+	if ctxFields, ok := ctx.Value(fieldsContextKey{}).(log.Fields); ok {
+		for k, v := range ctxFields {
+			fields[k] = v
+		}
+	}
+	return fields
+}
+
+// NewRequestContext returns a copy of the given context that carries the given
+// request ID as a log field
+func NewRequestContext(ctx context.Context, requestID string) context.Context {
+	return NewContext(ctx, log.Fields{requestIDKey: requestID})
+}
+
+// RequestIDFromContext returns the request ID carried by the given context, if
+// any
+func RequestIDFromContext(ctx context.Context) (string, bool) {
+	requestID, ok := FieldsFromContext(ctx)[requestIDKey].(string)
+	return requestID, ok
+}
+
+// FromContext returns a log entry populated with the log fields carried by
+// the given context
+func FromContext(ctx context.Context) *log.Entry {
+	return log.WithFields(FieldsFromContext(ctx))
+}
+
+// InjectIntoTaskArgs stores the request ID carried by the given context, if
+// any, in the given async task args so that log entries written while
+// executing the task can be correlated with the originating request
+func InjectIntoTaskArgs(ctx context.Context, args map[string]string) {
+	if requestID, ok := RequestIDFromContext(ctx); ok {
+		args[requestIDKey] = requestID
+	}
+}
+
+// ExtractFromTaskArgs returns log fields that correlate log entries written
+// while executing a task having the given args with the originating request
+// and instance
+func ExtractFromTaskArgs(args map[string]string) log.Fields {
+	fields := log.Fields{}
+	if requestID, ok := args[requestIDKey]; ok {
+		fields[requestIDKey] = requestID
+	}
+	if instanceID, ok := args["instanceID"]; ok {
+		fields["instanceID"] = instanceID
+	}
+	return fields
+}
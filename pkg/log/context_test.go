@@ -0,0 +1,64 @@
+package log
+
+import (
+	"context"
+	"testing"
+
+	log "github.com/Sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewContextMergesFields(t *testing.T) {
+	ctx := NewContext(
+		context.Background(),
+		log.Fields{
+			"instanceID": "foo",
+			"serviceID":  "bar",
+		},
+	)
+	ctx = NewContext(
+		ctx,
+		log.Fields{
+			"serviceID": "bat",
+			"planID":    "baz",
+		},
+	)
+	assert.Equal(
+		t,
+		log.Fields{
+			"instanceID": "foo",
+			"serviceID":  "bat",
+			"planID":     "baz",
+		},
+		FieldsFromContext(ctx),
+	)
+}
+
+func TestFieldsFromContextReturnsCopy(t *testing.T) {
+	ctx := NewContext(context.Background(), log.Fields{"instanceID": "foo"})
+	fields := FieldsFromContext(ctx)
+	fields["error"] = "bar"
+	assert.Equal(t, log.Fields{"instanceID": "foo"}, FieldsFromContext(ctx))
+}
+
+func TestTaskArgsRoundTrip(t *testing.T) {
+	ctx := NewRequestContext(context.Background(), "foo")
+	args := map[string]string{
+		"instanceID": "bar",
+	}
+	InjectIntoTaskArgs(ctx, args)
+	assert.Equal(
+		t,
+		log.Fields{
+			"requestID":  "foo",
+			"instanceID": "bar",
+		},
+		ExtractFromTaskArgs(args),
+	)
+}
+
+func TestInjectIntoTaskArgsWithoutRequestID(t *testing.T) {
+	args := map[string]string{}
+	InjectIntoTaskArgs(context.Background(), args)
+	assert.Empty(t, args)
+}
@@ -0,0 +1,46 @@
+package metrics
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/kelseyhightower/envconfig"
+)
+
+const envconfigPrefix = "METRICS"
+
+// Config represents configuration options for exposing broker metrics
+type Config struct {
+	// Port is the port on which metrics are served. If it is 0, metrics are
+	// served by the API server instead.
+	Port int `envconfig:"PORT"`
+	// InstancesInterval is how often instance gauges are refreshed from the
+	// Store
+	InstancesInterval time.Duration `envconfig:"INSTANCES_INTERVAL"`
+}
+
+// NewConfigWithDefaults returns a Config object with default values already
+// applied. Callers are then free to set custom values for the remaining fields
+// and/or override default values.
+func NewConfigWithDefaults() Config {
+	return Config{
+		InstancesInterval: time.Minute,
+	}
+}
+
+// GetConfigFromEnvironment returns configuration derived from environment
+// variables
+func GetConfigFromEnvironment() (Config, error) {
+	c := NewConfigWithDefaults()
+	if err := envconfig.Process(envconfigPrefix, &c); err != nil {
+		return c, err
+	}
+	if c.InstancesInterval <= 0 {
+		return c, fmt.Errorf(
+			"environment variable %s_INSTANCES_INTERVAL must be a positive "+
+				"duration",
+			envconfigPrefix,
+		)
+	}
+	return c, nil
+}
@@ -0,0 +1,28 @@
+package metrics
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetConfigWithInstancesInterval(t *testing.T) {
+	err := os.Setenv("METRICS_INSTANCES_INTERVAL", "5m")
+	assert.Nil(t, err)
+	defer os.Unsetenv("METRICS_INSTANCES_INTERVAL") // nolint: errcheck
+	c, err := GetConfigFromEnvironment()
+	assert.Nil(t, err)
+	assert.Equal(t, 5*time.Minute, c.InstancesInterval)
+}
+
+func TestGetConfigWithNonPositiveInstancesInterval(t *testing.T) {
+	for _, interval := range []string{"0", "-1m"} {
+		err := os.Setenv("METRICS_INSTANCES_INTERVAL", interval)
+		assert.Nil(t, err)
+		_, err = GetConfigFromEnvironment()
+		assert.NotNil(t, err, interval)
+	}
+	os.Unsetenv("METRICS_INSTANCES_INTERVAL") // nolint: errcheck
+}
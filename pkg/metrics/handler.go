@@ -0,0 +1,25 @@
+package metrics
+
+import (
+	"net/http"
+
+	"go.opencensus.io/exporter/prometheus"
+	"go.opencensus.io/stats/view"
+)
+
+const namespace = "osba"
+
+// NewHandler returns an http.Handler that serves all broker metrics in the
+// Prometheus exposition format. It should be called only once.
+func NewHandler() (http.Handler, error) {
+	exporter, err := prometheus.NewExporter(
+		prometheus.Options{
+			Namespace: namespace,
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+	view.RegisterExporter(exporter)
+	return exporter, nil
+}
@@ -0,0 +1,102 @@
+package metrics
+
+import (
+	"context"
+	"time"
+
+	"github.com/Azure/open-service-broker-azure/pkg/service"
+	"github.com/Azure/open-service-broker-azure/pkg/storage"
+	log "github.com/Sirupsen/logrus"
+	"go.opencensus.io/tag"
+)
+
+// InstanceRecorder is an interface to be implemented by components that
+// periodically record the number of instances in a Store by service, plan, and
+// status
+type InstanceRecorder interface {
+	// Run causes the recorder to record instance counts on an interval. It
+	// blocks until the context passed to it has been canceled. Run always
+	// returns a non-nil error.
+	Run(context.Context) error
+}
+
+type instanceKey struct {
+	service string
+	plan    string
+	status  string
+}
+
+type instanceRecorder struct {
+	store    storage.Store
+	interval time.Duration
+	// recorded tracks every combination of service, plan, and status ever
+	// recorded so that combinations that no longer have any instances are
+	// reset to zero instead of reporting a stale count
+	recorded map[instanceKey]struct{}
+}
+
+// NewInstanceRecorder returns a new InstanceRecorder for the given Store
+func NewInstanceRecorder(store storage.Store, config Config) InstanceRecorder {
+	return &instanceRecorder{
+		store:    store,
+		interval: config.InstancesInterval,
+		recorded: map[instanceKey]struct{}{},
+	}
+}
+
+func (i *instanceRecorder) Run(ctx context.Context) error {
+	ticker := time.NewTicker(i.interval)
+	defer ticker.Stop()
+	for {
+		i.record()
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			log.Debug("context canceled; instance recorder shutting down")
+			return ctx.Err()
+		}
+	}
+}
+
+func (i *instanceRecorder) record() {
+	instances, err := i.store.GetInstances()
+	if err != nil {
+		// Log the error, but don't return it. We'll try again on the next tick.
+		log.WithField("error", err).Error("error retrieving instances")
+		return
+	}
+	counts := map[instanceKey]int64{}
+	for key := range i.recorded {
+		counts[key] = 0
+	}
+	for _, instance := range instances {
+		key := getInstanceKey(instance)
+		counts[key]++
+		i.recorded[key] = struct{}{}
+	}
+	for key, count := range counts {
+		record(
+			[]tag.Mutator{
+				tag.Upsert(keyService, key.service),
+				tag.Upsert(keyPlan, key.plan),
+				tag.Upsert(keyStatus, key.status),
+			},
+			instanceCount.M(count),
+		)
+	}
+}
+
+func getInstanceKey(instance service.Instance) instanceKey {
+	key := instanceKey{
+		service: instance.ServiceID,
+		plan:    instance.PlanID,
+		status:  instance.Status,
+	}
+	if instance.Service != nil {
+		key.service = instance.Service.GetName()
+	}
+	if instance.Plan != nil {
+		key.plan = instance.Plan.GetName()
+	}
+	return key
+}
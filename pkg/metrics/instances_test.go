@@ -0,0 +1,64 @@
+package metrics
+
+import (
+	"testing"
+
+	"github.com/Azure/open-service-broker-azure/pkg/service"
+	"github.com/Azure/open-service-broker-azure/pkg/services/fake"
+	memoryStorage "github.com/Azure/open-service-broker-azure/pkg/storage/memory"
+	uuid "github.com/satori/go.uuid"
+	"github.com/stretchr/testify/assert"
+	"go.opencensus.io/stats/view"
+)
+
+func TestInstanceRecorderResetsStaleCounts(t *testing.T) {
+	fakeModule, err := fake.New()
+	assert.Nil(t, err)
+	fakeCatalog, err := fakeModule.GetCatalog()
+	assert.Nil(t, err)
+	store := memoryStorage.NewStore(fakeCatalog)
+	instance := service.Instance{
+		InstanceID: uuid.NewV4().String(),
+		ServiceID:  fake.ServiceID,
+		PlanID:     fake.StandardPlanID,
+		Status:     service.InstanceStateProvisioning,
+	}
+	assert.Nil(t, store.WriteInstance(instance))
+	svc, ok := fakeCatalog.GetService(fake.ServiceID)
+	assert.True(t, ok)
+	plan, ok := svc.GetPlan(fake.StandardPlanID)
+	assert.True(t, ok)
+	provisioningTags := map[string]string{
+		"service": svc.GetName(),
+		"plan":    plan.GetName(),
+		"status":  service.InstanceStateProvisioning,
+	}
+	provisionedTags := map[string]string{
+		"service": svc.GetName(),
+		"plan":    plan.GetName(),
+		"status":  service.InstanceStateProvisioned,
+	}
+
+	recorder := NewInstanceRecorder(
+		store,
+		NewConfigWithDefaults(),
+	).(*instanceRecorder)
+	recorder.record()
+	assertLastValue(t, provisioningTags, 1)
+
+	instance.Status = service.InstanceStateProvisioned
+	assert.Nil(t, store.WriteInstance(instance))
+	recorder.record()
+	assertLastValue(t, provisioningTags, 0)
+	assertLastValue(t, provisionedTags, 1)
+}
+
+func assertLastValue(t *testing.T, tags map[string]string, expected float64) {
+	rows, err := view.RetrieveData("instances")
+	assert.Nil(t, err)
+	row, ok := findRow(rows, tags)
+	assert.True(t, ok)
+	if ok {
+		assert.Equal(t, expected, row.Data.(*view.LastValueData).Value)
+	}
+}
@@ -0,0 +1,194 @@
+package metrics
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	log "github.com/Sirupsen/logrus"
+	"go.opencensus.io/stats"
+	"go.opencensus.io/stats/view"
+	"go.opencensus.io/tag"
+)
+
+const (
+	outcomeSuccess = "success"
+	outcomeFailure = "failure"
+)
+
+var (
+	keyRoute     = mustNewKey("route")
+	keyMethod    = mustNewKey("method")
+	keyStatus    = mustNewKey("status")
+	keyJob       = mustNewKey("job")
+	keyStep      = mustNewKey("step")
+	keyOutcome   = mustNewKey("outcome")
+	keyOperation = mustNewKey("operation")
+	keyService   = mustNewKey("service")
+	keyPlan      = mustNewKey("plan")
+
+	httpRequestLatency = stats.Float64(
+		"http_request_duration_seconds",
+		"Latency of HTTP requests handled by the API server",
+		"s",
+	)
+	asyncStepLatency = stats.Float64(
+		"async_step_duration_seconds",
+		"Duration of asynchronously executed steps",
+		"s",
+	)
+	armDeploymentLatency = stats.Float64(
+		"arm_deployment_duration_seconds",
+		"Duration of ARM deployment operations",
+		"s",
+	)
+	instanceCount = stats.Int64(
+		"instances",
+		"Number of instances",
+		stats.UnitDimensionless,
+	)
+
+	// latencyBuckets are histogram bucket boundaries, in seconds, that span
+	// everything from quick API requests to lengthy ARM deployments
+	latencyBuckets = []float64{
+		0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30, 60, 120, 300,
+		600, 1200, 2700,
+	}
+
+	views = []*view.View{
+		{
+			Name:        "http_requests_total",
+			Description: "Number of HTTP requests handled by the API server",
+			Measure:     httpRequestLatency,
+			TagKeys:     []tag.Key{keyRoute, keyMethod, keyStatus},
+			Aggregation: view.Count(),
+		},
+		{
+			Name:        "http_request_duration_seconds",
+			Description: httpRequestLatency.Description(),
+			Measure:     httpRequestLatency,
+			TagKeys:     []tag.Key{keyRoute, keyMethod, keyStatus},
+			Aggregation: view.Distribution(latencyBuckets...),
+		},
+		{
+			Name:        "async_steps_total",
+			Description: "Number of asynchronously executed steps",
+			Measure:     asyncStepLatency,
+			TagKeys:     []tag.Key{keyJob, keyStep, keyOutcome},
+			Aggregation: view.Count(),
+		},
+		{
+			Name:        "async_step_duration_seconds",
+			Description: asyncStepLatency.Description(),
+			Measure:     asyncStepLatency,
+			TagKeys:     []tag.Key{keyJob, keyStep, keyOutcome},
+			Aggregation: view.Distribution(latencyBuckets...),
+		},
+		{
+			Name:        "arm_deployments_total",
+			Description: "Number of ARM deployment operations",
+			Measure:     armDeploymentLatency,
+			TagKeys:     []tag.Key{keyOperation, keyOutcome},
+			Aggregation: view.Count(),
+		},
+		{
+			Name:        "arm_deployment_duration_seconds",
+			Description: armDeploymentLatency.Description(),
+			Measure:     armDeploymentLatency,
+			TagKeys:     []tag.Key{keyOperation, keyOutcome},
+			Aggregation: view.Distribution(latencyBuckets...),
+		},
+		{
+			Name:        "instances",
+			Description: "Number of instances by service, plan, and status",
+			Measure:     instanceCount,
+			TagKeys:     []tag.Key{keyService, keyPlan, keyStatus},
+			Aggregation: view.LastValue(),
+		},
+	}
+)
+
+func init() {
+	// Views are registered unconditionally so that measurements are aggregated
+	// whether or not an exporter is ever attached
+	if err := view.Register(views...); err != nil {
+		panic(err)
+	}
+}
+
+func mustNewKey(name string) tag.Key {
+	key, err := tag.NewKey(name)
+	if err != nil {
+		panic(err)
+	}
+	return key
+}
+
+// RecordHTTPRequest records the handling of an HTTP request by the API server.
+// The route should be the route's path template (e.g.
+// /v2/service_instances/{instance_id}) rather than the request's path so as to
+// bound the number of distinct values.
+func RecordHTTPRequest(
+	route string,
+	method string,
+	statusCode int,
+	latency time.Duration,
+) {
+	record(
+		[]tag.Mutator{
+			tag.Upsert(keyRoute, route),
+			tag.Upsert(keyMethod, method),
+			tag.Upsert(keyStatus, strconv.Itoa(statusCode)),
+		},
+		httpRequestLatency.M(latency.Seconds()),
+	)
+}
+
+// RecordAsyncStep records the execution of an asynchronous step of the named
+// job
+func RecordAsyncStep(
+	job string,
+	step string,
+	err error,
+	latency time.Duration,
+) {
+	record(
+		[]tag.Mutator{
+			tag.Upsert(keyJob, job),
+			tag.Upsert(keyStep, step),
+			tag.Upsert(keyOutcome, getOutcome(err)),
+		},
+		asyncStepLatency.M(latency.Seconds()),
+	)
+}
+
+// RecordARMDeployment records an ARM deployment operation (e.g. deploy,
+// update, or delete)
+func RecordARMDeployment(operation string, err error, latency time.Duration) {
+	record(
+		[]tag.Mutator{
+			tag.Upsert(keyOperation, operation),
+			tag.Upsert(keyOutcome, getOutcome(err)),
+		},
+		armDeploymentLatency.M(latency.Seconds()),
+	)
+}
+
+func getOutcome(err error) string {
+	if err != nil {
+		return outcomeFailure
+	}
+	return outcomeSuccess
+}
+
+func record(mutators []tag.Mutator, measurement stats.Measurement) {
+	if err := stats.RecordWithTags(
+		context.Background(),
+		mutators,
+		measurement,
+	); err != nil {
+		// Failing to record a metric should never fail the operation being
+		// measured
+		log.WithField("error", err).Error("error recording metric")
+	}
+}
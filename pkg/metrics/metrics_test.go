@@ -0,0 +1,100 @@
+package metrics
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.opencensus.io/stats/view"
+	"go.opencensus.io/tag"
+)
+
+func TestRecordHTTPRequest(t *testing.T) {
+	RecordHTTPRequest("/foo/{id}", "GET", 200, time.Second)
+	assertCount(
+		t,
+		"http_requests_total",
+		map[string]string{
+			"route":  "/foo/{id}",
+			"method": "GET",
+			"status": "200",
+		},
+		1,
+	)
+}
+
+func TestRecordAsyncStep(t *testing.T) {
+	RecordAsyncStep("fooJob", "fooStep", nil, time.Second)
+	RecordAsyncStep("fooJob", "fooStep", errors.New("an error"), time.Second)
+	RecordAsyncStep("fooJob", "fooStep", errors.New("an error"), time.Second)
+	assertCount(
+		t,
+		"async_steps_total",
+		map[string]string{
+			"job":     "fooJob",
+			"step":    "fooStep",
+			"outcome": outcomeSuccess,
+		},
+		1,
+	)
+	assertCount(
+		t,
+		"async_steps_total",
+		map[string]string{
+			"job":     "fooJob",
+			"step":    "fooStep",
+			"outcome": outcomeFailure,
+		},
+		2,
+	)
+}
+
+func TestRecordARMDeployment(t *testing.T) {
+	RecordARMDeployment("barOperation", errors.New("an error"), time.Minute)
+	assertCount(
+		t,
+		"arm_deployments_total",
+		map[string]string{
+			"operation": "barOperation",
+			"outcome":   outcomeFailure,
+		},
+		1,
+	)
+}
+
+func assertCount(
+	t *testing.T,
+	viewName string,
+	tags map[string]string,
+	expected int64,
+) {
+	rows, err := view.RetrieveData(viewName)
+	assert.Nil(t, err)
+	row, ok := findRow(rows, tags)
+	assert.True(t, ok)
+	if ok {
+		assert.Equal(t, expected, row.Data.(*view.CountData).Value)
+	}
+}
+
+func findRow(rows []*view.Row, tags map[string]string) (*view.Row, bool) {
+	for _, row := range rows {
+		if rowHasTags(row.Tags, tags) {
+			return row, true
+		}
+	}
+	return nil, false
+}
+
+func rowHasTags(rowTags []tag.Tag, tags map[string]string) bool {
+	if len(rowTags) != len(tags) {
+		return false
+	}
+	for _, rowTag := range rowTags {
+		if tags[rowTag.Key.Name()] != rowTag.Value {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,66 @@
+package metrics
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	log "github.com/Sirupsen/logrus"
+)
+
+// Server is an interface for components that serve broker metrics on a port
+// of their own
+type Server interface {
+	// Run causes the metrics server to start serving HTTP requests. It will
+	// block until an error occurs or the context passed to it has been canceled
+	// and will return that error.
+	Run(context.Context) error
+}
+
+type server struct {
+	port    int
+	handler http.Handler
+}
+
+// NewServer returns a new Server that serves the given metrics handler at
+// /metrics on the configured port
+func NewServer(config Config, handler http.Handler) Server {
+	return &server{
+		port:    config.Port,
+		handler: handler,
+	}
+}
+
+func (s *server) Run(ctx context.Context) error {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", s.handler)
+	svr := http.Server{
+		Addr:    fmt.Sprintf(":%d", s.port),
+		Handler: mux,
+	}
+	errChan := make(chan error)
+	go func() {
+		select {
+		case errChan <- svr.ListenAndServe():
+		case <-ctx.Done():
+		}
+	}()
+	log.WithField(
+		"address",
+		fmt.Sprintf("http://0.0.0.0:%d/metrics", s.port),
+	).Info("Metrics server is listening")
+	select {
+	case err := <-errChan:
+		return err
+	case <-ctx.Done():
+		log.Debug("context canceled; metrics server shutting down")
+		shutdownCtx, cancel := context.WithTimeout(
+			context.Background(),
+			time.Second*5,
+		)
+		defer cancel()
+		svr.Shutdown(shutdownCtx) // nolint: errcheck
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,229 @@
+package policy
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Condition is a declarative predicate over the document a rule is evaluated
+// against. A condition either combines other conditions using exactly one of
+// All, Any, or Not, or it tests the value found at Field using exactly one of
+// the remaining operators. Except for Exists, every operator evaluates to
+// false if Field is not present in the document.
+type Condition struct {
+	// All is satisfied if every one of the given conditions is satisfied
+	All []*Condition `json:"all,omitempty"`
+	// Any is satisfied if at least one of the given conditions is satisfied
+	Any []*Condition `json:"any,omitempty"`
+	// Not is satisfied if the given condition is not satisfied
+	Not *Condition `json:"not,omitempty"`
+	// Field is a JSON pointer, e.g. "/parameters/location", identifying the
+	// value in the document to be tested
+	Field string `json:"field,omitempty"`
+	// Exists tests whether Field is (true) or is not (false) present
+	Exists *bool `json:"exists,omitempty"`
+	// Equals tests whether the value of Field is equal to the given value
+	Equals interface{} `json:"equals,omitempty"`
+	// In tests whether the value of Field is equal to any of the given values
+	In []interface{} `json:"in,omitempty"`
+	// Contains tests whether the value of Field is an array containing the
+	// given value or is a string containing the given string
+	Contains interface{} `json:"contains,omitempty"`
+	// HasPrefix tests whether the value of Field is a string beginning with the
+	// given string
+	HasPrefix *string `json:"hasPrefix,omitempty"`
+	// Matches tests whether the value of Field is a string matching the given
+	// regular expression
+	Matches string `json:"matches,omitempty"`
+
+	path    []string
+	matches *regexp.Regexp
+}
+
+// compile verifies that the condition is well-formed and prepares it for
+// evaluation
+func (c *Condition) compile(context string) error {
+	if c == nil {
+		return fmt.Errorf("%s: condition must not be empty", context)
+	}
+	var combinators int
+	if c.All != nil {
+		combinators++
+	}
+	if c.Any != nil {
+		combinators++
+	}
+	if c.Not != nil {
+		combinators++
+	}
+	var operators int
+	if c.Exists != nil {
+		operators++
+	}
+	if c.Equals != nil {
+		operators++
+	}
+	if c.In != nil {
+		operators++
+	}
+	if c.Contains != nil {
+		operators++
+	}
+	if c.HasPrefix != nil {
+		operators++
+	}
+	if c.Matches != "" {
+		operators++
+	}
+	switch {
+	case combinators == 1 && operators == 0 && c.Field == "":
+		for i, condition := range c.All {
+			if err := condition.compile(
+				fmt.Sprintf("%s/all/%d", context, i),
+			); err != nil {
+				return err
+			}
+		}
+		for i, condition := range c.Any {
+			if err := condition.compile(
+				fmt.Sprintf("%s/any/%d", context, i),
+			); err != nil {
+				return err
+			}
+		}
+		if c.Not != nil {
+			return c.Not.compile(context + "/not")
+		}
+		return nil
+	case combinators == 0 && operators == 1:
+		if !strings.HasPrefix(c.Field, "/") {
+			return fmt.Errorf(
+				`%s: field "%s" is not a JSON pointer`,
+				context,
+				c.Field,
+			)
+		}
+		c.path = parseJSONPointer(c.Field)
+		if c.Matches != "" {
+			var err error
+			if c.matches, err = regexp.Compile(c.Matches); err != nil {
+				return fmt.Errorf(
+					`%s: invalid regular expression "%s": %s`,
+					context,
+					c.Matches,
+					err,
+				)
+			}
+		}
+		return nil
+	default:
+		return fmt.Errorf(
+			"%s: condition must specify exactly one of all, any, or not, or a "+
+				"field and exactly one operator",
+			context,
+		)
+	}
+}
+
+// evaluate returns a bool indicating whether the given document satisfies
+// the condition. The condition must have been compiled.
+func (c *Condition) evaluate(doc interface{}) bool {
+	switch {
+	case c.All != nil:
+		for _, condition := range c.All {
+			if !condition.evaluate(doc) {
+				return false
+			}
+		}
+		return true
+	case c.Any != nil:
+		for _, condition := range c.Any {
+			if condition.evaluate(doc) {
+				return true
+			}
+		}
+		return false
+	case c.Not != nil:
+		return !c.Not.evaluate(doc)
+	}
+	value, ok := resolve(doc, c.path)
+	if c.Exists != nil {
+		return ok == *c.Exists
+	}
+	if !ok {
+		return false
+	}
+	switch {
+	case c.Equals != nil:
+		return reflect.DeepEqual(value, c.Equals)
+	case c.In != nil:
+		for _, allowed := range c.In {
+			if reflect.DeepEqual(value, allowed) {
+				return true
+			}
+		}
+		return false
+	case c.Contains != nil:
+		switch v := value.(type) {
+		case []interface{}:
+			for _, element := range v {
+				if reflect.DeepEqual(element, c.Contains) {
+					return true
+				}
+			}
+			return false
+		case string:
+			substr, isString := c.Contains.(string)
+			return isString && strings.Contains(v, substr)
+		}
+		return false
+	case c.HasPrefix != nil:
+		str, isString := value.(string)
+		return isString && strings.HasPrefix(str, *c.HasPrefix)
+	case c.matches != nil:
+		str, isString := value.(string)
+		return isString && c.matches.MatchString(str)
+	}
+	return false
+}
+
+// parseJSONPointer splits the given JSON pointer into its unescaped reference
+// tokens
+func parseJSONPointer(pointer string) []string {
+	tokens := strings.Split(pointer, "/")[1:]
+	for i, token := range tokens {
+		token = strings.Replace(token, "~1", "/", -1)
+		tokens[i] = strings.Replace(token, "~0", "~", -1)
+	}
+	return tokens
+}
+
+// resolve returns the value found by following the given reference tokens
+// through the given document, if any
+func resolve(doc interface{}, path []string) (interface{}, bool) {
+	value := doc
+	for _, token := range path {
+		switch v := value.(type) {
+		case map[string]interface{}:
+			var ok bool
+			if value, ok = v[token]; !ok {
+				return nil, false
+			}
+		case []interface{}:
+			i, err := strconv.Atoi(token)
+			if err != nil || i < 0 || i >= len(v) {
+				return nil, false
+			}
+			value = v[i]
+		default:
+			return nil, false
+		}
+	}
+	if value == nil {
+		return nil, false
+	}
+	return value, true
+}
@@ -0,0 +1,29 @@
+package policy
+
+import (
+	"github.com/kelseyhightower/envconfig"
+)
+
+const envconfigPrefix = "POLICY"
+
+// Config represents configuration options for the policy engine
+type Config struct {
+	// Files are the JSON files from which policy rules are loaded. If none are
+	// specified, requests are not subject to any policy.
+	Files []string `envconfig:"FILES"`
+}
+
+// NewConfigWithDefaults returns a Config object with default values already
+// applied. Callers are then free to set custom values for the remaining fields
+// and/or override default values.
+func NewConfigWithDefaults() Config {
+	return Config{}
+}
+
+// GetConfigFromEnvironment returns configuration derived from environment
+// variables
+func GetConfigFromEnvironment() (Config, error) {
+	c := NewConfigWithDefaults()
+	err := envconfig.Process(envconfigPrefix, &c)
+	return c, err
+}
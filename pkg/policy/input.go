@@ -0,0 +1,80 @@
+package policy
+
+import (
+	"encoding/json"
+)
+
+// Input represents a request to be evaluated against a policy
+type Input struct {
+	// Operation is one of OperationProvision, OperationUpdate, or OperationBind
+	Operation   string
+	ServiceID   string
+	ServiceName string
+	PlanID      string
+	PlanName    string
+	// Parameters are the normalized parameters of the request. For updates,
+	// these are the complete set of parameters the instance will have once
+	// updated.
+	Parameters map[string]interface{}
+	// Context is the contextual data provided by the platform in the request
+	Context map[string]interface{}
+	// IdentityScheme and IdentityName identify the authenticated principal
+	// making the request, if any
+	IdentityScheme string
+	IdentityName   string
+	// OriginatingIdentityPlatform and OriginatingIdentityValue identify the
+	// platform user on whose behalf the request was made, if any
+	OriginatingIdentityPlatform string
+	OriginatingIdentityValue    json.RawMessage
+}
+
+// document returns the JSON document that rule conditions are evaluated
+// against. It has the following form:
+//
+//	{
+//	  "operation": "provision",
+//	  "service": { "id": "...", "name": "..." },
+//	  "plan": { "id": "...", "name": "..." },
+//	  "parameters": { ... },
+//	  "context": { ... },
+//	  "identity": { "scheme": "...", "name": "..." },
+//	  "originatingIdentity": { "platform": "...", "value": { ... } }
+//	}
+//
+// Fields that are empty are omitted.
+func (i Input) document() map[string]interface{} {
+	doc := map[string]interface{}{
+		"operation": i.Operation,
+		"service": map[string]interface{}{
+			"id":   i.ServiceID,
+			"name": i.ServiceName,
+		},
+		"plan": map[string]interface{}{
+			"id":   i.PlanID,
+			"name": i.PlanName,
+		},
+	}
+	if i.Parameters != nil {
+		doc["parameters"] = i.Parameters
+	}
+	if i.Context != nil {
+		doc["context"] = i.Context
+	}
+	if i.IdentityScheme != "" {
+		doc["identity"] = map[string]interface{}{
+			"scheme": i.IdentityScheme,
+			"name":   i.IdentityName,
+		}
+	}
+	if i.OriginatingIdentityPlatform != "" {
+		originatingIdentity := map[string]interface{}{
+			"platform": i.OriginatingIdentityPlatform,
+		}
+		var value interface{}
+		if err := json.Unmarshal(i.OriginatingIdentityValue, &value); err == nil {
+			originatingIdentity["value"] = value
+		}
+		doc["originatingIdentity"] = originatingIdentity
+	}
+	return doc
+}
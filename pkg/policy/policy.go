@@ -0,0 +1,166 @@
+package policy
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+)
+
+// Operations that rules may apply to
+const (
+	// OperationProvision represents the provisioning of a service instance
+	OperationProvision = "provision"
+	// OperationUpdate represents the updating of a service instance
+	OperationUpdate = "update"
+	// OperationBind represents the binding to a service instance
+	OperationBind = "bind"
+)
+
+// Rule is a single declarative guardrail. A request that a rule applies to
+// violates the rule if it does not satisfy the rule's Require condition.
+type Rule struct {
+	// Name uniquely identifies the rule
+	Name string `json:"name"`
+	// Description optionally explains the purpose of the rule
+	Description string `json:"description,omitempty"`
+	// Operations restricts the rule to the given operations. If empty, the rule
+	// applies to all operations.
+	Operations []string `json:"operations,omitempty"`
+	// When optionally restricts the rule to requests that satisfy the given
+	// condition, e.g. to those for a particular service or plan
+	When *Condition `json:"when,omitempty"`
+	// Require is the condition that every request the rule applies to must
+	// satisfy
+	Require *Condition `json:"require"`
+	// Message is returned to the client when the rule is violated
+	Message string `json:"message"`
+}
+
+// Policy is a set of rules that provisioning, updating, and binding requests
+// are evaluated against
+type Policy struct {
+	Rules []*Rule `json:"rules"`
+}
+
+// Violation describes a rule that a request has violated
+type Violation struct {
+	Rule    string `json:"rule"`
+	Message string `json:"message"`
+}
+
+// New returns a policy consisting of the given rules. An error is returned if
+// any of the rules are malformed.
+func New(rules ...*Rule) (*Policy, error) {
+	p := &Policy{
+		Rules: rules,
+	}
+	if err := p.compile(); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// Load loads a policy from the JSON files at the given paths. The rules from
+// all files are combined. Fields that are not recognized and malformed rules
+// are treated as errors so that mistakes in the files cannot go unnoticed.
+func Load(paths ...string) (*Policy, error) {
+	var rules []*Rule
+	for _, path := range paths {
+		policyBytes, err := ioutil.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf(
+				`error reading policy file "%s": %s`,
+				path,
+				err,
+			)
+		}
+		decoder := json.NewDecoder(bytes.NewReader(policyBytes))
+		decoder.DisallowUnknownFields()
+		filePolicy := &Policy{}
+		if err = decoder.Decode(filePolicy); err != nil {
+			return nil, fmt.Errorf(
+				`error parsing policy file "%s": %s`,
+				path,
+				err,
+			)
+		}
+		rules = append(rules, filePolicy.Rules...)
+	}
+	return New(rules...)
+}
+
+func (p *Policy) compile() error {
+	names := map[string]struct{}{}
+	for i, rule := range p.Rules {
+		if rule == nil || rule.Name == "" {
+			return fmt.Errorf("policy rule %d has no name", i)
+		}
+		if _, ok := names[rule.Name]; ok {
+			return fmt.Errorf(`policy rule name "%s" is not unique`, rule.Name)
+		}
+		names[rule.Name] = struct{}{}
+		if rule.Message == "" {
+			return fmt.Errorf(`policy rule "%s" has no message`, rule.Name)
+		}
+		for _, operation := range rule.Operations {
+			switch operation {
+			case OperationProvision, OperationUpdate, OperationBind:
+			default:
+				return fmt.Errorf(
+					`policy rule "%s" refers to unknown operation "%s"`,
+					rule.Name,
+					operation,
+				)
+			}
+		}
+		if rule.When != nil {
+			if err := rule.When.compile("/when"); err != nil {
+				return fmt.Errorf(`policy rule "%s": %s`, rule.Name, err)
+			}
+		}
+		if err := rule.Require.compile("/require"); err != nil {
+			return fmt.Errorf(`policy rule "%s": %s`, rule.Name, err)
+		}
+	}
+	return nil
+}
+
+// Evaluate evaluates the given input against every applicable rule and
+// returns all violations, in the order the violated rules were defined. A nil
+// policy is never violated.
+func (p *Policy) Evaluate(input Input) []Violation {
+	if p == nil || len(p.Rules) == 0 {
+		return nil
+	}
+	doc := input.document()
+	var violations []Violation
+	for _, rule := range p.Rules {
+		if !rule.appliesTo(input.Operation) ||
+			(rule.When != nil && !rule.When.evaluate(doc)) {
+			continue
+		}
+		if !rule.Require.evaluate(doc) {
+			violations = append(
+				violations,
+				Violation{
+					Rule:    rule.Name,
+					Message: rule.Message,
+				},
+			)
+		}
+	}
+	return violations
+}
+
+func (r *Rule) appliesTo(operation string) bool {
+	if len(r.Operations) == 0 {
+		return true
+	}
+	for _, op := range r.Operations {
+		if op == operation {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,244 @@
+package policy
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const testPolicyJSON = `{
+	"rules": [
+		{
+			"name": "allowed-locations",
+			"operations": ["provision", "update"],
+			"require": {
+				"field": "/parameters/location",
+				"in": ["eastus", "westus"]
+			},
+			"message": "instances may only be created in eastus or westus"
+		},
+		{
+			"name": "team-resource-groups",
+			"operations": ["provision"],
+			"require": {
+				"field": "/parameters/resourceGroup",
+				"hasPrefix": "team-"
+			},
+			"message": "resource group names must start with team-"
+		},
+		{
+			"name": "premium-redis",
+			"when": {
+				"all": [
+					{ "field": "/service/name", "equals": "azure-rediscache" },
+					{ "field": "/plan/name", "equals": "premium" }
+				]
+			},
+			"require": {
+				"field": "/originatingIdentity/value/groups",
+				"contains": "platform-admins"
+			},
+			"message": "premium redis requires membership in platform-admins"
+		},
+		{
+			"name": "cost-center",
+			"operations": ["provision", "update"],
+			"require": {
+				"field": "/parameters/tags/cost-center",
+				"exists": true
+			},
+			"message": "every instance must carry a cost-center tag"
+		}
+	]
+}`
+
+func loadTestPolicy(t *testing.T) *Policy {
+	dir, err := ioutil.TempDir("", "policy")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir) // nolint: errcheck
+	path := filepath.Join(dir, "policy.json")
+	err = ioutil.WriteFile(path, []byte(testPolicyJSON), 0600)
+	assert.Nil(t, err)
+	p, err := Load(path)
+	assert.Nil(t, err)
+	return p
+}
+
+func TestEvaluateCompliantInput(t *testing.T) {
+	p := loadTestPolicy(t)
+	violations := p.Evaluate(
+		Input{
+			Operation:   OperationProvision,
+			ServiceName: "azure-rediscache",
+			PlanName:    "premium",
+			Parameters: map[string]interface{}{
+				"location":      "eastus",
+				"resourceGroup": "team-foo",
+				"tags": map[string]interface{}{
+					"cost-center": "1234",
+				},
+			},
+			OriginatingIdentityPlatform: "kubernetes",
+			OriginatingIdentityValue: json.RawMessage(
+				`{"username":"alice","groups":["platform-admins"]}`,
+			),
+		},
+	)
+	assert.Empty(t, violations)
+}
+
+func TestEvaluateReportsAllViolations(t *testing.T) {
+	p := loadTestPolicy(t)
+	violations := p.Evaluate(
+		Input{
+			Operation:   OperationProvision,
+			ServiceName: "azure-rediscache",
+			PlanName:    "premium",
+			Parameters: map[string]interface{}{
+				"location": "southcentralus",
+			},
+		},
+	)
+	assert.Equal(
+		t,
+		[]Violation{
+			{
+				Rule:    "allowed-locations",
+				Message: "instances may only be created in eastus or westus",
+			},
+			{
+				Rule:    "team-resource-groups",
+				Message: "resource group names must start with team-",
+			},
+			{
+				Rule:    "premium-redis",
+				Message: "premium redis requires membership in platform-admins",
+			},
+			{
+				Rule:    "cost-center",
+				Message: "every instance must carry a cost-center tag",
+			},
+		},
+		violations,
+	)
+}
+
+func TestEvaluateRespectsOperationsAndWhen(t *testing.T) {
+	p := loadTestPolicy(t)
+	violations := p.Evaluate(
+		Input{
+			Operation:   OperationBind,
+			ServiceName: "azure-rediscache",
+			PlanName:    "basic",
+		},
+	)
+	assert.Empty(t, violations)
+}
+
+func TestNilPolicyIsNeverViolated(t *testing.T) {
+	var p *Policy
+	assert.Empty(t, p.Evaluate(Input{Operation: OperationProvision}))
+}
+
+func TestConditionOperators(t *testing.T) {
+	doc := map[string]interface{}{
+		"a/b": "foo",
+		"list": []interface{}{
+			"x",
+			map[string]interface{}{"n": float64(1)},
+		},
+	}
+	testCases := []struct {
+		name      string
+		condition string
+		expected  bool
+	}{
+		{"escaped pointer", `{"field":"/a~1b","equals":"foo"}`, true},
+		{"array index", `{"field":"/list/1/n","equals":1}`, true},
+		{"out of range", `{"field":"/list/2","exists":true}`, false},
+		{"absent", `{"field":"/missing","exists":false}`, true},
+		{"absent equals", `{"field":"/missing","equals":"foo"}`, false},
+		{"absent not", `{"not":{"field":"/missing","equals":"foo"}}`, true},
+		{"substring", `{"field":"/a~1b","contains":"oo"}`, true},
+		{"element", `{"field":"/list","contains":"x"}`, true},
+		{"matches", `{"field":"/a~1b","matches":"^f.o$"}`, true},
+		{"prefix", `{"field":"/a~1b","hasPrefix":"bar"}`, false},
+		{
+			"any",
+			`{"any":[{"field":"/a~1b","equals":"bar"},` +
+				`{"field":"/a~1b","equals":"foo"}]}`,
+			true,
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			c := &Condition{}
+			err := json.Unmarshal([]byte(testCase.condition), c)
+			assert.Nil(t, err)
+			assert.Nil(t, c.compile(""))
+			assert.Equal(t, testCase.expected, c.evaluate(doc))
+		})
+	}
+}
+
+func TestNewRejectsMalformedRules(t *testing.T) {
+	testCases := []struct {
+		name string
+		rule *Rule
+	}{
+		{"no name", &Rule{Message: "foo", Require: &Condition{}}},
+		{"no message", &Rule{Name: "foo", Require: &Condition{}}},
+		{"no require", &Rule{Name: "foo", Message: "foo"}},
+		{
+			"unknown operation",
+			&Rule{
+				Name:       "foo",
+				Message:    "foo",
+				Operations: []string{"deprovision"},
+				Require:    &Condition{Field: "/foo", Equals: "bar"},
+			},
+		},
+		{
+			"no operator",
+			&Rule{
+				Name:    "foo",
+				Message: "foo",
+				Require: &Condition{Field: "/foo"},
+			},
+		},
+		{
+			"two operators",
+			&Rule{
+				Name:    "foo",
+				Message: "foo",
+				Require: &Condition{Field: "/foo", Equals: "bar", Matches: "bar"},
+			},
+		},
+		{
+			"not a pointer",
+			&Rule{
+				Name:    "foo",
+				Message: "foo",
+				Require: &Condition{Field: "foo", Equals: "bar"},
+			},
+		},
+		{
+			"bad regex",
+			&Rule{
+				Name:    "foo",
+				Message: "foo",
+				Require: &Condition{Field: "/foo", Matches: "("},
+			},
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			_, err := New(testCase.rule)
+			assert.NotNil(t, err)
+		})
+	}
+}
@@ -0,0 +1,29 @@
+package quota
+
+import (
+	"github.com/kelseyhightower/envconfig"
+)
+
+const envconfigPrefix = "QUOTA"
+
+// Config represents configuration options for the quota subsystem
+type Config struct {
+	// File is the JSON file from which quotas are loaded. If it is not
+	// specified, the number of instances tenants may provision is unlimited.
+	File string `envconfig:"FILE"`
+}
+
+// NewConfigWithDefaults returns a Config object with default values already
+// applied. Callers are then free to set custom values for the remaining fields
+// and/or override default values.
+func NewConfigWithDefaults() Config {
+	return Config{}
+}
+
+// GetConfigFromEnvironment returns configuration derived from environment
+// variables
+func GetConfigFromEnvironment() (Config, error) {
+	c := NewConfigWithDefaults()
+	err := envconfig.Process(envconfigPrefix, &c)
+	return c, err
+}
@@ -0,0 +1,345 @@
+package quota
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"sort"
+
+	"github.com/Azure/open-service-broker-azure/pkg/service"
+)
+
+// Keys by which instances may be attributed to a tenant
+const (
+	// KeyOrganization attributes instances to the Cloud Foundry organization
+	// identified by the organization_guid field of their OSB context
+	KeyOrganization = "organization"
+	// KeySpace attributes instances to the Cloud Foundry space identified by
+	// the space_guid field of their OSB context
+	KeySpace = "space"
+	// KeyNamespace attributes instances to the Kubernetes namespace identified
+	// by the namespace field of their OSB context
+	KeyNamespace = "namespace"
+	// KeyPrincipal attributes instances to the broker credential used to
+	// provision them
+	KeyPrincipal = "principal"
+)
+
+// contextFields maps keys that are derived from OSB context to the
+// corresponding context field
+var contextFields = map[string]string{
+	KeyOrganization: "organization_guid",
+	KeySpace:        "space_guid",
+	KeyNamespace:    "namespace",
+}
+
+// Limit caps the number of instances each tenant may have of a service or
+// plan
+type Limit struct {
+	// Name uniquely identifies the limit
+	Name string `json:"name"`
+	// ServiceID optionally restricts the limit to instances of a service. If
+	// empty, instances of all services are counted.
+	ServiceID string `json:"serviceId,omitempty"`
+	// PlanID optionally restricts the limit to instances of a plan of the
+	// service identified by ServiceID
+	PlanID string `json:"planId,omitempty"`
+	// Key is one of KeyOrganization, KeySpace, KeyNamespace, or KeyPrincipal
+	// and determines the tenant each instance is attributed to. Instances that
+	// cannot be attributed to a tenant by this key are not subject to the limit.
+	Key string `json:"key"`
+	// Max is the number of instances each tenant may have
+	Max int `json:"max"`
+}
+
+// Quotas is a set of limits on the number of instances tenants may provision
+type Quotas struct {
+	Limits []Limit `json:"limits"`
+}
+
+// Tenant identifies the tenant on whose behalf an instance is provisioned
+type Tenant struct {
+	// Context is the OSB context of the provisioning request
+	Context map[string]interface{}
+	// Principal is the name of the authenticated principal that made the
+	// provisioning request, if any
+	Principal string
+}
+
+// Exceeded describes a limit that provisioning an instance would exceed
+type Exceeded struct {
+	Limit Limit
+	// Tenant is the value of the limit's key for the tenant that has reached
+	// the limit
+	Tenant string
+	// Used is the number of instances the tenant already has
+	Used int
+}
+
+// Usage describes the number of instances a tenant has that count toward a
+// limit. If Unattributed is true, it instead describes the number of instances
+// the limit applies to that cannot be attributed to any tenant using the
+// limit's key, e.g. instances provisioned before OSBA recorded the OSB
+// context. These do not count toward the limit.
+type Usage struct {
+	Limit        string `json:"limit"`
+	ServiceID    string `json:"serviceId,omitempty"`
+	PlanID       string `json:"planId,omitempty"`
+	Key          string `json:"key"`
+	Tenant       string `json:"tenant"`
+	Unattributed bool   `json:"unattributed,omitempty"`
+	Used         int    `json:"used"`
+	Max          int    `json:"max"`
+}
+
+// Load loads quotas from the JSON file at the given path. Fields that are not
+// recognized and malformed limits are treated as errors so that mistakes in
+// the file cannot go unnoticed.
+func Load(path string) (*Quotas, error) {
+	quotasBytes, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf(`error reading quotas file "%s": %s`, path, err)
+	}
+	decoder := json.NewDecoder(bytes.NewReader(quotasBytes))
+	decoder.DisallowUnknownFields()
+	q := &Quotas{}
+	if err = decoder.Decode(q); err != nil {
+		return nil, fmt.Errorf(`error parsing quotas file "%s": %s`, path, err)
+	}
+	names := map[string]struct{}{}
+	for i, limit := range q.Limits {
+		if limit.Name == "" {
+			return nil, fmt.Errorf("quota limit %d has no name", i)
+		}
+		if _, ok := names[limit.Name]; ok {
+			return nil, fmt.Errorf(
+				`quota limit name "%s" is not unique`,
+				limit.Name,
+			)
+		}
+		names[limit.Name] = struct{}{}
+		if _, ok := contextFields[limit.Key]; !ok && limit.Key != KeyPrincipal {
+			return nil, fmt.Errorf(
+				`quota limit "%s" has invalid key "%s"`,
+				limit.Name,
+				limit.Key,
+			)
+		}
+		if limit.PlanID != "" && limit.ServiceID == "" {
+			return nil, fmt.Errorf(
+				`quota limit "%s" specifies a plan but no service`,
+				limit.Name,
+			)
+		}
+		if limit.Max < 0 {
+			return nil, fmt.Errorf(
+				`quota limit "%s" has a negative max`,
+				limit.Name,
+			)
+		}
+	}
+	return q, nil
+}
+
+// Validate verifies that the quotas refer only to services and plans that
+// exist in the given catalog
+func (q *Quotas) Validate(catalog service.Catalog) error {
+	if q == nil {
+		return nil
+	}
+	for _, limit := range q.Limits {
+		if limit.ServiceID == "" {
+			continue
+		}
+		svc, ok := catalog.GetService(limit.ServiceID)
+		if !ok {
+			return fmt.Errorf(
+				`quota limit "%s" refers to unknown service "%s"`,
+				limit.Name,
+				limit.ServiceID,
+			)
+		}
+		if limit.PlanID == "" {
+			continue
+		}
+		if _, ok = svc.GetPlan(limit.PlanID); !ok {
+			return fmt.Errorf(
+				`quota limit "%s" refers to unknown plan "%s" of service "%s"`,
+				limit.Name,
+				limit.PlanID,
+				limit.ServiceID,
+			)
+		}
+	}
+	return nil
+}
+
+// IsEmpty returns a bool indicating whether there are no limits. Callers can
+// use this to avoid retrieving existing instances needlessly.
+func (q *Quotas) IsEmpty() bool {
+	return q == nil || len(q.Limits) == 0
+}
+
+// Check returns the first limit, if any, that provisioning a new instance of
+// the given service and plan on behalf of the given tenant would exceed,
+// given the existing instances
+func (q *Quotas) Check(
+	instances []service.Instance,
+	serviceID string,
+	planID string,
+	tenant Tenant,
+) (Exceeded, bool) {
+	if q.IsEmpty() {
+		return Exceeded{}, false
+	}
+	for _, limit := range q.Limits {
+		if !limit.appliesTo(serviceID, planID) {
+			continue
+		}
+		if exceeded, ok := limit.check(instances, tenant); ok {
+			return exceeded, true
+		}
+	}
+	return Exceeded{}, false
+}
+
+// CheckPlanChange returns the first limit, if any, that changing the plan of
+// the given existing instance to the given plan would exceed, given the
+// existing instances. The instance remains attributed to the tenant that
+// provisioned it. Limits that the instance already counts toward are
+// unaffected by the change and are not checked.
+func (q *Quotas) CheckPlanChange(
+	instances []service.Instance,
+	instance service.Instance,
+	planID string,
+) (Exceeded, bool) {
+	if q.IsEmpty() {
+		return Exceeded{}, false
+	}
+	tenant := getInstanceTenant(instance)
+	for _, limit := range q.Limits {
+		if !limit.appliesTo(instance.ServiceID, planID) ||
+			limit.appliesTo(instance.ServiceID, instance.PlanID) {
+			continue
+		}
+		if exceeded, ok := limit.check(instances, tenant); ok {
+			return exceeded, true
+		}
+	}
+	return Exceeded{}, false
+}
+
+// GetUsage returns, for each limit, the usage of every tenant that has at
+// least one instance counting toward the limit, ordered by limit and then by
+// tenant. Each limit's tenants are followed by the number of unattributed
+// instances the limit applies to, if there are any.
+func (q *Quotas) GetUsage(instances []service.Instance) []Usage {
+	usages := []Usage{}
+	if q.IsEmpty() {
+		return usages
+	}
+	for _, limit := range q.Limits {
+		used := map[string]int{}
+		var unattributed int
+		for _, instance := range instances {
+			if !limit.appliesTo(instance.ServiceID, instance.PlanID) {
+				continue
+			}
+			if tenantValue, ok := limit.getTenant(
+				getInstanceTenant(instance),
+			); ok {
+				used[tenantValue]++
+			} else {
+				unattributed++
+			}
+		}
+		tenantValues := make([]string, 0, len(used))
+		for tenantValue := range used {
+			tenantValues = append(tenantValues, tenantValue)
+		}
+		sort.Strings(tenantValues)
+		for _, tenantValue := range tenantValues {
+			usages = append(
+				usages,
+				Usage{
+					Limit:     limit.Name,
+					ServiceID: limit.ServiceID,
+					PlanID:    limit.PlanID,
+					Key:       limit.Key,
+					Tenant:    tenantValue,
+					Used:      used[tenantValue],
+					Max:       limit.Max,
+				},
+			)
+		}
+		if unattributed > 0 {
+			usages = append(
+				usages,
+				Usage{
+					Limit:        limit.Name,
+					ServiceID:    limit.ServiceID,
+					PlanID:       limit.PlanID,
+					Key:          limit.Key,
+					Unattributed: true,
+					Used:         unattributed,
+					Max:          limit.Max,
+				},
+			)
+		}
+	}
+	return usages
+}
+
+// check returns a bool indicating whether the given tenant already has as
+// many of the given instances counting toward the limit as the limit allows.
+// Tenants that cannot be identified by the limit's key are not subject to it.
+func (l Limit) check(
+	instances []service.Instance,
+	tenant Tenant,
+) (Exceeded, bool) {
+	tenantValue, ok := l.getTenant(tenant)
+	if !ok {
+		return Exceeded{}, false
+	}
+	var used int
+	for _, instance := range instances {
+		if !l.appliesTo(instance.ServiceID, instance.PlanID) {
+			continue
+		}
+		if t, counted := l.getTenant(getInstanceTenant(instance)); counted &&
+			t == tenantValue {
+			used++
+		}
+	}
+	if used >= l.Max {
+		return Exceeded{
+			Limit:  l,
+			Tenant: tenantValue,
+			Used:   used,
+		}, true
+	}
+	return Exceeded{}, false
+}
+
+func (l Limit) appliesTo(serviceID, planID string) bool {
+	return (l.ServiceID == "" || l.ServiceID == serviceID) &&
+		(l.PlanID == "" || l.PlanID == planID)
+}
+
+// getTenant returns the value of the limit's key for the given tenant, if
+// the tenant can be identified by that key
+func (l Limit) getTenant(tenant Tenant) (string, bool) {
+	if l.Key == KeyPrincipal {
+		return tenant.Principal, tenant.Principal != ""
+	}
+	value, ok := tenant.Context[contextFields[l.Key]].(string)
+	return value, ok && value != ""
+}
+
+func getInstanceTenant(instance service.Instance) Tenant {
+	return Tenant{
+		Context:   instance.Context,
+		Principal: instance.Principal,
+	}
+}
@@ -0,0 +1,266 @@
+package quota
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Azure/open-service-broker-azure/pkg/service"
+	"github.com/stretchr/testify/assert"
+)
+
+var testQuotas = &Quotas{
+	Limits: []Limit{
+		{
+			Name:      "premium-per-namespace",
+			ServiceID: "svc",
+			PlanID:    "premium",
+			Key:       KeyNamespace,
+			Max:       1,
+		},
+		{
+			Name: "instances-per-principal",
+			Key:  KeyPrincipal,
+			Max:  3,
+		},
+	},
+}
+
+func getTestInstance(
+	serviceID string,
+	planID string,
+	namespace string,
+	principal string,
+) service.Instance {
+	return service.Instance{
+		ServiceID: serviceID,
+		PlanID:    planID,
+		Context: map[string]interface{}{
+			"platform":  "kubernetes",
+			"namespace": namespace,
+		},
+		Principal: principal,
+	}
+}
+
+func TestCheck(t *testing.T) {
+	instances := []service.Instance{
+		getTestInstance("svc", "premium", "team-a", "k8s"),
+		getTestInstance("svc", "basic", "team-a", "k8s"),
+		getTestInstance("svc", "basic", "team-b", "cf"),
+	}
+	testCases := []struct {
+		name     string
+		planID   string
+		tenant   Tenant
+		exceeded bool
+		limit    string
+		used     int
+	}{
+		{
+			name:   "another plan",
+			planID: "basic",
+			tenant: Tenant{
+				Context:   map[string]interface{}{"namespace": "team-a"},
+				Principal: "k8s",
+			},
+		},
+		{
+			name:   "another namespace",
+			planID: "premium",
+			tenant: Tenant{
+				Context:   map[string]interface{}{"namespace": "team-b"},
+				Principal: "cf",
+			},
+		},
+		{
+			name:   "no namespace",
+			planID: "premium",
+			tenant: Tenant{
+				Principal: "cf",
+			},
+		},
+		{
+			name:   "namespace at limit",
+			planID: "premium",
+			tenant: Tenant{
+				Context:   map[string]interface{}{"namespace": "team-a"},
+				Principal: "cf",
+			},
+			exceeded: true,
+			limit:    "premium-per-namespace",
+			used:     1,
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			exceeded, ok := testQuotas.Check(
+				instances,
+				"svc",
+				testCase.planID,
+				testCase.tenant,
+			)
+			assert.Equal(t, testCase.exceeded, ok)
+			if ok {
+				assert.Equal(t, testCase.limit, exceeded.Limit.Name)
+				assert.Equal(t, testCase.used, exceeded.Used)
+			}
+		})
+	}
+	// A third instance brings the principal to its limit
+	instances = append(
+		instances,
+		getTestInstance("other", "plan", "team-c", "k8s"),
+	)
+	exceeded, ok := testQuotas.Check(
+		instances,
+		"svc",
+		"basic",
+		Tenant{Principal: "k8s"},
+	)
+	assert.True(t, ok)
+	assert.Equal(t, "instances-per-principal", exceeded.Limit.Name)
+	assert.Equal(t, "k8s", exceeded.Tenant)
+	assert.Equal(t, 3, exceeded.Used)
+}
+
+func TestCheckPlanChange(t *testing.T) {
+	instances := []service.Instance{
+		getTestInstance("svc", "premium", "team-a", "k8s"),
+		getTestInstance("svc", "basic", "team-a", "k8s"),
+		getTestInstance("svc", "basic", "team-b", "k8s"),
+	}
+	// team-a already has a premium instance
+	exceeded, ok := testQuotas.CheckPlanChange(instances, instances[1], "premium")
+	assert.True(t, ok)
+	assert.Equal(t, "premium-per-namespace", exceeded.Limit.Name)
+	assert.Equal(t, "team-a", exceeded.Tenant)
+	assert.Equal(t, 1, exceeded.Used)
+	// team-b doesn't. The k8s principal is at its limit, but the instance
+	// already counts toward that limit, so the change doesn't affect it.
+	_, ok = testQuotas.CheckPlanChange(instances, instances[2], "premium")
+	assert.False(t, ok)
+	// Changing to a plan no limit specifically restricts is always permitted
+	_, ok = testQuotas.CheckPlanChange(instances, instances[0], "basic")
+	assert.False(t, ok)
+}
+
+func TestGetUsage(t *testing.T) {
+	instances := []service.Instance{
+		getTestInstance("svc", "premium", "team-b", "k8s"),
+		getTestInstance("svc", "premium", "team-a", "k8s"),
+		getTestInstance("svc", "basic", "team-a", "cf"),
+		// Provisioned before the OSB context and principal were recorded
+		{ServiceID: "svc", PlanID: "premium"},
+	}
+	assert.Equal(
+		t,
+		[]Usage{
+			{
+				Limit:     "premium-per-namespace",
+				ServiceID: "svc",
+				PlanID:    "premium",
+				Key:       KeyNamespace,
+				Tenant:    "team-a",
+				Used:      1,
+				Max:       1,
+			},
+			{
+				Limit:     "premium-per-namespace",
+				ServiceID: "svc",
+				PlanID:    "premium",
+				Key:       KeyNamespace,
+				Tenant:    "team-b",
+				Used:      1,
+				Max:       1,
+			},
+			{
+				Limit:        "premium-per-namespace",
+				ServiceID:    "svc",
+				PlanID:       "premium",
+				Key:          KeyNamespace,
+				Unattributed: true,
+				Used:         1,
+				Max:          1,
+			},
+			{
+				Limit:  "instances-per-principal",
+				Key:    KeyPrincipal,
+				Tenant: "cf",
+				Used:   1,
+				Max:    3,
+			},
+			{
+				Limit:  "instances-per-principal",
+				Key:    KeyPrincipal,
+				Tenant: "k8s",
+				Used:   2,
+				Max:    3,
+			},
+			{
+				Limit:        "instances-per-principal",
+				Key:          KeyPrincipal,
+				Unattributed: true,
+				Used:         1,
+				Max:          3,
+			},
+		},
+		testQuotas.GetUsage(instances),
+	)
+}
+
+func TestNilQuotas(t *testing.T) {
+	var q *Quotas
+	assert.True(t, q.IsEmpty())
+	_, ok := q.Check(nil, "svc", "premium", Tenant{Principal: "k8s"})
+	assert.False(t, ok)
+	assert.Empty(t, q.GetUsage(nil))
+}
+
+func TestLoad(t *testing.T) {
+	testCases := []struct {
+		name    string
+		json    string
+		isValid bool
+	}{
+		{
+			name: "valid",
+			json: `{"limits":[{"name":"a","serviceId":"svc","planId":"plan",` +
+				`"key":"space","max":5}]}`,
+			isValid: true,
+		},
+		{
+			name: "unknown field",
+			json: `{"limits":[{"name":"a","key":"space","max":5,"foo":1}]}`,
+		},
+		{
+			name: "invalid key",
+			json: `{"limits":[{"name":"a","key":"cluster","max":5}]}`,
+		},
+		{
+			name: "plan without service",
+			json: `{"limits":[{"name":"a","planId":"plan","key":"space","max":5}]}`,
+		},
+		{
+			name: "duplicate name",
+			json: `{"limits":[{"name":"a","key":"space","max":5},` +
+				`{"name":"a","key":"namespace","max":5}]}`,
+		},
+	}
+	dir, err := ioutil.TempDir("", "quota")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir) // nolint: errcheck
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			path := filepath.Join(dir, "quotas.json")
+			assert.Nil(t, ioutil.WriteFile(path, []byte(testCase.json), 0600))
+			_, err = Load(path)
+			if testCase.isValid {
+				assert.Nil(t, err)
+			} else {
+				assert.NotNil(t, err)
+			}
+		})
+	}
+}
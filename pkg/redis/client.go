@@ -0,0 +1,116 @@
+package redis
+
+import (
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+
+	"github.com/go-redis/redis"
+)
+
+// NewClient returns a Redis client for the standalone, Sentinel, or Cluster
+// deployment described by the given config, along with the prefix that should
+// be applied to all keys.
+//
+// Components that use Redis update related keys together, either within a
+// single transaction or using commands such as BRPOPLPUSH that span two keys.
+// Redis Cluster only permits that when all keys involved map to the same hash
+// slot, so when using Redis Cluster, the prefix is wrapped in braces to make
+// it a hash tag. Only the hash tag is hashed to determine a key's slot, so all
+// of a component's keys land in the same slot. If no prefix has been
+// configured, the given default is used as the hash tag.
+func NewClient(
+	config Config,
+	defaultClusterKeyTag string,
+) (redis.UniversalClient, string, error) {
+	modes := 0
+	if config.RedisHost != "" {
+		modes++
+	}
+	if config.RedisSentinelMasterName != "" ||
+		len(config.RedisSentinelAddresses) > 0 {
+		modes++
+	}
+	if len(config.RedisClusterAddresses) > 0 {
+		modes++
+	}
+	if modes != 1 {
+		return nil, "", errors.New(
+			"exactly one of a Redis host, a Redis Sentinel master name and " +
+				"addresses, or Redis Cluster addresses must be specified",
+		)
+	}
+	var tlsConfig *tls.Config
+	if config.RedisEnableTLS {
+		tlsConfig = &tls.Config{
+			ServerName: getTLSServerName(config),
+		}
+	}
+	switch {
+	case config.RedisHost != "":
+		return redis.NewClient(&redis.Options{
+			Addr:       fmt.Sprintf("%s:%d", config.RedisHost, config.RedisPort),
+			Password:   config.RedisPassword,
+			DB:         config.RedisDB,
+			MaxRetries: 5,
+			TLSConfig:  tlsConfig,
+		}), config.RedisPrefix, nil
+	case len(config.RedisClusterAddresses) > 0:
+		if config.RedisDB != 0 {
+			return nil, "", errors.New(
+				"Redis Cluster does not support selecting a database",
+			)
+		}
+		prefix := config.RedisPrefix
+		if prefix == "" {
+			prefix = defaultClusterKeyTag
+		}
+		return redis.NewClusterClient(&redis.ClusterOptions{
+			Addrs:      config.RedisClusterAddresses,
+			Password:   config.RedisPassword,
+			MaxRetries: 5,
+			TLSConfig:  tlsConfig,
+		}), fmt.Sprintf("{%s}", prefix), nil
+	default:
+		if config.RedisSentinelMasterName == "" {
+			return nil, "", errors.New("Redis Sentinel master name was not specified")
+		}
+		if len(config.RedisSentinelAddresses) == 0 {
+			return nil, "", errors.New("Redis Sentinel addresses were not specified")
+		}
+		return redis.NewFailoverClient(&redis.FailoverOptions{
+			MasterName:    config.RedisSentinelMasterName,
+			SentinelAddrs: config.RedisSentinelAddresses,
+			Password:      config.RedisPassword,
+			DB:            config.RedisDB,
+			MaxRetries:    5,
+			TLSConfig:     tlsConfig,
+		}), config.RedisPrefix, nil
+	}
+}
+
+// getTLSServerName returns the server name that certificates presented by
+// Redis should be verified against. Unless explicitly overridden, this is the
+// configured Redis host or, failing that, the host portion of the first
+// Sentinel or Cluster address.
+func getTLSServerName(config Config) string {
+	if config.RedisTLSServerName != "" {
+		return config.RedisTLSServerName
+	}
+	if config.RedisHost != "" {
+		return config.RedisHost
+	}
+	addrs := config.RedisClusterAddresses
+	if len(addrs) == 0 {
+		addrs = config.RedisSentinelAddresses
+	}
+	if len(addrs) == 0 {
+		return ""
+	}
+	host, _, err := net.SplitHostPort(addrs[0])
+	if err != nil {
+		return addrs[0]
+	}
+	return host
+}
@@ -0,0 +1,67 @@
+package redis
+
+import (
+	"testing"
+
+	"github.com/go-redis/redis"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewClientRequiresExactlyOneMode(t *testing.T) {
+	_, _, err := NewClient(NewConfigWithDefaults(), "osba")
+	assert.NotNil(t, err)
+	config := NewConfigWithDefaults()
+	config.RedisHost = "localhost"
+	config.RedisClusterAddresses = []string{"localhost:7000"}
+	_, _, err = NewClient(config, "osba")
+	assert.NotNil(t, err)
+}
+
+func TestNewClientWithHost(t *testing.T) {
+	config := NewConfigWithDefaults()
+	config.RedisHost = "localhost"
+	config.RedisPrefix = "foo"
+	client, prefix, err := NewClient(config, "osba")
+	assert.Nil(t, err)
+	assert.IsType(t, &redis.Client{}, client)
+	assert.Equal(t, "foo", prefix)
+}
+
+func TestNewClientWithSentinel(t *testing.T) {
+	config := NewConfigWithDefaults()
+	config.RedisSentinelAddresses = []string{"localhost:26379"}
+	_, _, err := NewClient(config, "osba")
+	// The master name is required
+	assert.NotNil(t, err)
+	config.RedisSentinelMasterName = "mymaster"
+	config.RedisPrefix = "foo"
+	client, prefix, err := NewClient(config, "osba")
+	assert.Nil(t, err)
+	assert.IsType(t, &redis.Client{}, client)
+	assert.Equal(t, "foo", prefix)
+}
+
+func TestNewClientWithCluster(t *testing.T) {
+	config := NewConfigWithDefaults()
+	config.RedisClusterAddresses = []string{"localhost:7000", "localhost:7001"}
+	client, prefix, err := NewClient(config, "osba")
+	assert.Nil(t, err)
+	assert.IsType(t, &redis.ClusterClient{}, client)
+	assert.Equal(t, "{osba}", prefix)
+	config.RedisPrefix = "foo"
+	_, prefix, err = NewClient(config, "osba")
+	assert.Nil(t, err)
+	assert.Equal(t, "{foo}", prefix)
+	// Databases can't be selected in a cluster
+	config.RedisDB = 1
+	_, _, err = NewClient(config, "osba")
+	assert.NotNil(t, err)
+}
+
+func TestGetTLSServerName(t *testing.T) {
+	config := NewConfigWithDefaults()
+	config.RedisClusterAddresses = []string{"redis.example.com:7000"}
+	assert.Equal(t, "redis.example.com", getTLSServerName(config))
+	config.RedisTLSServerName = "foo.example.com"
+	assert.Equal(t, "foo.example.com", getTLSServerName(config))
+}
@@ -0,0 +1,29 @@
+package redis
+
+// Config represents configuration options for connecting to Redis. Exactly
+// one of a single Redis host, a Redis Sentinel master name plus Sentinel
+// addresses, or a list of Redis Cluster addresses must be specified. Config
+// carries no envconfig prefix of its own; it is meant to be embedded in the
+// configuration of each component that uses Redis.
+type Config struct {
+	RedisHost      string `envconfig:"REDIS_HOST"`
+	RedisPort      int    `envconfig:"REDIS_PORT"`
+	RedisPassword  string `envconfig:"REDIS_PASSWORD"`
+	RedisDB        int    `envconfig:"REDIS_DB"`
+	RedisEnableTLS bool   `envconfig:"REDIS_ENABLE_TLS"`
+	// RedisTLSServerName overrides the server name used to verify the
+	// certificates presented by Redis. This is useful when connecting via
+	// Sentinel or to a Cluster, where nodes are discovered by IP address.
+	RedisTLSServerName      string   `envconfig:"REDIS_TLS_SERVER_NAME"`
+	RedisSentinelMasterName string   `envconfig:"REDIS_SENTINEL_MASTER_NAME"`
+	RedisSentinelAddresses  []string `envconfig:"REDIS_SENTINEL_ADDRESSES"`
+	RedisClusterAddresses   []string `envconfig:"REDIS_CLUSTER_ADDRESSES"`
+	RedisPrefix             string   `envconfig:"REDIS_PREFIX"`
+}
+
+// NewConfigWithDefaults returns a Config object with default values already
+// applied. Callers are then free to set custom values for the remaining fields
+// and/or override default values.
+func NewConfigWithDefaults() Config {
+	return Config{RedisPort: 6379}
+}
@@ -0,0 +1,25 @@
+package redis
+
+import (
+	"context"
+
+	"github.com/go-redis/redis"
+)
+
+// Ping pings the Redis server(s) used by the given client. The vendored Redis
+// client does not accept a context, so the ping is abandoned, and the
+// context's error returned, if the context is canceled or its deadline is
+// exceeded before a reply is received. The client's own timeouts eventually
+// end the abandoned ping.
+func Ping(ctx context.Context, redisClient redis.UniversalClient) error {
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- redisClient.Ping().Err()
+	}()
+	select {
+	case err := <-errCh:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,35 @@
+package redis
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/go-redis/redis"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPingRespectsContext(t *testing.T) {
+	// This listener accepts connections, but never replies
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.Nil(t, err)
+	defer listener.Close() // nolint: errcheck
+	go func() {
+		for {
+			if _, acceptErr := listener.Accept(); acceptErr != nil {
+				return
+			}
+		}
+	}()
+	client := redis.NewClient(&redis.Options{
+		Addr: listener.Addr().String(),
+	})
+	defer client.Close() // nolint: errcheck
+	ctx, cancel := context.WithTimeout(
+		context.Background(),
+		100*time.Millisecond,
+	)
+	defer cancel()
+	assert.Equal(t, context.DeadlineExceeded, Ping(ctx, client))
+}
@@ -15,6 +15,9 @@ type Binding struct {
 	StatusReason      string             `json:"statusReason"`
 	Details           BindingDetails     `json:"details"`
 	Created           time.Time          `json:"created"`
+	// Deleted is set when a binding is deleted from storage. Deleted bindings
+	// are retained for a period of time before they are purged.
+	Deleted *time.Time `json:"deleted,omitempty"`
 }
 
 // NewBindingFromJSON returns a new Binding unmarshalled from the provided JSON
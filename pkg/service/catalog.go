@@ -80,12 +80,28 @@ type PlanProperties struct {
 	EndOfLife   bool                   `json:"-"`
 	Schemas     PlanSchemas            `json:"schemas,omitempty"`
 	Stability   Stability              `json:"-"`
+	// EnvironmentCosts maps the names of cloud environments, e.g.
+	// "AzurePublicCloud", to the costs of the plan in that environment. The
+	// costs for the environment the broker is deployed to are advertised in the
+	// plan's metadata. This is left unset for plans whose cost depends on
+	// provisioning parameters or on consumption.
+	EnvironmentCosts map[string][]PlanCost `json:"-"`
 }
 
 // ServicePlanMetadata contains metadata about the service plans
 type ServicePlanMetadata struct { // nolint: golint
-	DisplayName string   `json:"displayName,omitempty"`
-	Bullets     []string `json:"bullets,omitempty"`
+	DisplayName string     `json:"displayName,omitempty"`
+	Bullets     []string   `json:"bullets,omitempty"`
+	Costs       []PlanCost `json:"costs,omitempty"`
+}
+
+// PlanCost represents one cost of a service plan, e.g. a monthly charge
+type PlanCost struct {
+	// Amount maps lowercase currency codes, e.g. "usd", to the amount charged
+	// in that currency
+	Amount map[string]float64 `json:"amount"`
+	// Unit is the unit the amount is charged per, e.g. "MONTHLY"
+	Unit string `json:"unit"`
 }
 
 // Plan is an interface to be implemented by types that represent a single
@@ -1,9 +1,13 @@
 package service
 
 import (
+	"bytes"
+	"encoding/json"
 	"fmt"
+	"io/ioutil"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/kelseyhightower/envconfig"
 )
@@ -14,6 +18,24 @@ type CatalogConfig struct {
 	MinStability            Stability
 	EnableMigrationServices bool
 	EnableDRServices        bool
+	// Environment is the name of the cloud environment the broker is deployed
+	// to, e.g. "AzureChinaCloud". It determines which costs are advertised for
+	// each plan.
+	Environment string `ignored:"true"`
+	// Overrides, if not nil, are operator-specified modifications to be applied
+	// to the code-defined catalogs of all modules
+	Overrides *CatalogOverrides `ignored:"true"`
+	// ConfigFile, if not empty, is the path to a JSON file whose settings take
+	// precedence over those specified by environment variables. Unlike
+	// environment variables, the file can be changed while the broker is
+	// running.
+	ConfigFile string `envconfig:"CATALOG_CONFIG_FILE"`
+	// OverridesFile, if not empty, is the path to the JSON file that Overrides
+	// were loaded from
+	OverridesFile string `envconfig:"CATALOG_OVERRIDES_FILE"`
+	// ReloadInterval is how often ConfigFile and OverridesFile are checked for
+	// changes that require the catalog to be reloaded. Zero disables checking.
+	ReloadInterval time.Duration `envconfig:"CATALOG_RELOAD_INTERVAL"`
 }
 
 type tempCatalogConfig struct {
@@ -23,6 +45,15 @@ type tempCatalogConfig struct {
 	EnableDRServicesStr        string `envconfig:"ENABLE_DISASTER_RECOVERY_SERVICES" default:"false"` // nolint: lll
 }
 
+// catalogConfigFile represents the settings that may be specified in a
+// catalog configuration file. Settings that are omitted from the file are
+// left as specified by environment variables.
+type catalogConfigFile struct {
+	MinStability            *string `json:"minStability"`
+	EnableMigrationServices *bool   `json:"enableMigrationServices"`
+	EnableDRServices        *bool   `json:"enableDisasterRecoveryServices"`
+}
+
 // NewCatalogConfigWithDefaults returns a CatalogConfig object with default
 // values already applied. Callers are then free to set custom values for the
 // remaining fields and/or override default values.
@@ -30,6 +61,8 @@ func NewCatalogConfigWithDefaults() CatalogConfig {
 	return CatalogConfig{
 		MinStability:            StabilityPreview,
 		EnableMigrationServices: false,
+		Environment:             "AzurePublicCloud",
+		ReloadInterval:          30 * time.Second,
 	}
 }
 
@@ -42,6 +75,11 @@ func GetCatalogConfigFromEnvironment() (CatalogConfig, error) {
 	if err != nil {
 		return c.CatalogConfig, err
 	}
+	if c.ConfigFile != "" {
+		if err = c.loadConfigFile(); err != nil {
+			return c.CatalogConfig, err
+		}
+	}
 	minStabilityStr := strings.ToUpper(c.MinStabilityStr)
 	switch minStabilityStr {
 	case "EXPERIMENTAL":
@@ -73,5 +111,44 @@ func GetCatalogConfigFromEnvironment() (CatalogConfig, error) {
 			err,
 		)
 	}
+	if c.OverridesFile != "" {
+		c.Overrides, err = LoadCatalogOverrides(c.OverridesFile)
+		if err != nil {
+			return c.CatalogConfig, err
+		}
+	}
 	return c.CatalogConfig, nil
 }
+
+// loadConfigFile applies the settings found in the catalog configuration file
+func (c *tempCatalogConfig) loadConfigFile() error {
+	configBytes, err := ioutil.ReadFile(c.ConfigFile)
+	if err != nil {
+		return fmt.Errorf(
+			`error reading catalog configuration file "%s": %s`,
+			c.ConfigFile,
+			err,
+		)
+	}
+	decoder := json.NewDecoder(bytes.NewReader(configBytes))
+	decoder.DisallowUnknownFields()
+	configFile := catalogConfigFile{}
+	if err = decoder.Decode(&configFile); err != nil {
+		return fmt.Errorf(
+			`error parsing catalog configuration file "%s": %s`,
+			c.ConfigFile,
+			err,
+		)
+	}
+	if configFile.MinStability != nil {
+		c.MinStabilityStr = *configFile.MinStability
+	}
+	if configFile.EnableMigrationServices != nil {
+		c.EnableMigrationServicesStr =
+			strconv.FormatBool(*configFile.EnableMigrationServices)
+	}
+	if configFile.EnableDRServices != nil {
+		c.EnableDRServicesStr = strconv.FormatBool(*configFile.EnableDRServices)
+	}
+	return nil
+}
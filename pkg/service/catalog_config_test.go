@@ -0,0 +1,39 @@
+package service
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoadCatalogConfigFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "catalog")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir) // nolint: errcheck
+	c := tempCatalogConfig{
+		CatalogConfig: CatalogConfig{
+			ConfigFile: filepath.Join(dir, "catalog-config.json"),
+		},
+		MinStabilityStr:            "PREVIEW",
+		EnableMigrationServicesStr: "true",
+		EnableDRServicesStr:        "false",
+	}
+	err = ioutil.WriteFile(
+		c.ConfigFile,
+		[]byte(`{"minStability":"STABLE","enableDisasterRecoveryServices":true}`),
+		0600,
+	)
+	assert.Nil(t, err)
+	assert.Nil(t, c.loadConfigFile())
+	assert.Equal(t, "STABLE", c.MinStabilityStr)
+	// Settings omitted from the file are left unchanged
+	assert.Equal(t, "true", c.EnableMigrationServicesStr)
+	assert.Equal(t, "true", c.EnableDRServicesStr)
+
+	err = ioutil.WriteFile(c.ConfigFile, []byte(`{"foo":"bar"}`), 0600)
+	assert.Nil(t, err)
+	assert.NotNil(t, c.loadConfigFile())
+}
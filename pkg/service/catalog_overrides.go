@@ -0,0 +1,395 @@
+package service
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"math"
+	"sort"
+
+	"github.com/Azure/open-service-broker-azure/pkg/ptr"
+)
+
+// CatalogOverrides represents operator-specified modifications to the
+// code-defined catalogs of all modules
+type CatalogOverrides struct {
+	// Services maps service IDs to overrides for the corresponding service
+	Services map[string]ServiceOverrides `json:"services"`
+}
+
+// ServiceOverrides represents operator-specified modifications to a single
+// service. Empty fields leave the corresponding attribute of the service
+// unchanged.
+type ServiceOverrides struct {
+	// Hidden services are omitted from the catalog and cannot be provisioned,
+	// but existing instances of them can still be updated, bound, and
+	// deprovisioned
+	Hidden          bool   `json:"hidden"`
+	Description     string `json:"description"`
+	DisplayName     string `json:"displayName"`
+	LongDescription string `json:"longDescription"`
+	// Plans maps plan IDs to overrides for the corresponding plan
+	Plans map[string]PlanOverrides `json:"plans"`
+}
+
+// PlanOverrides represents operator-specified modifications to a single plan.
+// Empty fields leave the corresponding attribute of the plan unchanged.
+type PlanOverrides struct {
+	// Hidden plans are omitted from the catalog and cannot be used to provision
+	// new instances, but existing instances of them can still be updated,
+	// bound, and deprovisioned
+	Hidden      bool     `json:"hidden"`
+	Description string   `json:"description"`
+	DisplayName string   `json:"displayName"`
+	Bullets     []string `json:"bullets"`
+	// Costs maps the names of cloud environments, e.g. "AzureChinaCloud", to
+	// costs that replace the plan's costs in that environment
+	Costs map[string][]PlanCost `json:"costs"`
+	// Parameters maps the names of top-level provisioning and updating
+	// parameters to overrides for the schema of the corresponding parameter
+	Parameters map[string]ParameterOverrides `json:"parameters"`
+}
+
+// ParameterOverrides represents operator-specified modifications to the schema
+// of a single string, integer, or number parameter
+type ParameterOverrides struct {
+	// Default replaces the parameter's default value
+	Default interface{} `json:"default"`
+	// AllowedValues narrows the values permitted for the parameter. Each must
+	// already be permitted by the parameter's schema.
+	AllowedValues []interface{} `json:"allowedValues"`
+}
+
+// LoadCatalogOverrides loads catalog overrides from the JSON file at the
+// given path. Fields that are not recognized are treated as errors so that
+// mistakes in the file cannot go unnoticed.
+func LoadCatalogOverrides(path string) (*CatalogOverrides, error) {
+	overridesBytes, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf(
+			`error reading catalog overrides file "%s": %s`,
+			path,
+			err,
+		)
+	}
+	decoder := json.NewDecoder(bytes.NewReader(overridesBytes))
+	decoder.DisallowUnknownFields()
+	overrides := &CatalogOverrides{}
+	if err = decoder.Decode(overrides); err != nil {
+		return nil, fmt.Errorf(
+			`error parsing catalog overrides file "%s": %s`,
+			path,
+			err,
+		)
+	}
+	return overrides, nil
+}
+
+// GetServiceOverrides returns the overrides for the service with the given ID,
+// if any
+func (c *CatalogOverrides) GetServiceOverrides(
+	serviceID string,
+) (ServiceOverrides, bool) {
+	if c == nil {
+		return ServiceOverrides{}, false
+	}
+	serviceOverrides, ok := c.Services[serviceID]
+	return serviceOverrides, ok
+}
+
+// Validate verifies that the overrides refer only to services and plans that
+// exist in the given catalog. The catalog should be unfiltered, as it is not
+// an error to override a service or plan that is not currently offered.
+func (c *CatalogOverrides) Validate(catalog Catalog) error {
+	if c == nil {
+		return nil
+	}
+	serviceIDs := make([]string, 0, len(c.Services))
+	for serviceID := range c.Services {
+		serviceIDs = append(serviceIDs, serviceID)
+	}
+	sort.Strings(serviceIDs)
+	for _, serviceID := range serviceIDs {
+		svc, ok := catalog.GetService(serviceID)
+		if !ok {
+			return fmt.Errorf(
+				`catalog overrides refer to unknown service "%s"`,
+				serviceID,
+			)
+		}
+		for planID := range c.Services[serviceID].Plans {
+			if _, ok := svc.GetPlan(planID); !ok {
+				return fmt.Errorf(
+					`catalog overrides refer to unknown plan "%s" of service "%s"`,
+					planID,
+					serviceID,
+				)
+			}
+		}
+	}
+	return nil
+}
+
+// Apply returns a copy of the given service properties with the overrides
+// applied
+func (s ServiceOverrides) Apply(props ServiceProperties) ServiceProperties {
+	if s.Hidden {
+		props.EndOfLife = true
+	}
+	if s.Description != "" {
+		props.Description = s.Description
+	}
+	if s.DisplayName != "" {
+		props.Metadata.DisplayName = s.DisplayName
+	}
+	if s.LongDescription != "" {
+		props.Metadata.LongDescription = s.LongDescription
+	}
+	return props
+}
+
+// GetPlanOverrides returns the overrides for the plan with the given ID, if
+// any
+func (s ServiceOverrides) GetPlanOverrides(
+	planID string,
+) (PlanOverrides, bool) {
+	planOverrides, ok := s.Plans[planID]
+	return planOverrides, ok
+}
+
+// Apply returns a copy of the given plan properties with the overrides
+// applied. An error is returned if any parameter overrides are invalid. Plan
+// schemas are copied as needed and never modified in place.
+func (p PlanOverrides) Apply(props PlanProperties) (PlanProperties, error) {
+	if p.Hidden {
+		props.EndOfLife = true
+	}
+	if p.Description != "" {
+		props.Description = p.Description
+	}
+	if p.DisplayName != "" {
+		props.Metadata.DisplayName = p.DisplayName
+	}
+	if p.Bullets != nil {
+		props.Metadata.Bullets = p.Bullets
+	}
+	if len(p.Costs) > 0 {
+		environmentCosts := make(
+			map[string][]PlanCost,
+			len(props.EnvironmentCosts)+len(p.Costs),
+		)
+		for environment, costs := range props.EnvironmentCosts {
+			environmentCosts[environment] = costs
+		}
+		for environment, costs := range p.Costs {
+			for _, cost := range costs {
+				if len(cost.Amount) == 0 || cost.Unit == "" {
+					return props, fmt.Errorf(
+						`costs of plan "%s" in environment "%s" must each specify an `+
+							"amount and a unit",
+						props.ID,
+						environment,
+					)
+				}
+			}
+			environmentCosts[environment] = costs
+		}
+		props.EnvironmentCosts = environmentCosts
+	}
+	if len(p.Parameters) == 0 {
+		return props, nil
+	}
+	instanceSchemas := &props.Schemas.ServiceInstances
+	provisioningSchemas := copyPropertySchemas(
+		instanceSchemas.ProvisioningParametersSchema.PropertySchemas,
+	)
+	updatingSchemas := copyPropertySchemas(
+		instanceSchemas.UpdatingParametersSchema.PropertySchemas,
+	)
+	names := make([]string, 0, len(p.Parameters))
+	for name := range p.Parameters {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		var found bool
+		for _, propertySchemas := range []map[string]PropertySchema{
+			provisioningSchemas,
+			updatingSchemas,
+		} {
+			propertySchema, ok := propertySchemas[name]
+			if !ok {
+				continue
+			}
+			found = true
+			var err error
+			propertySchemas[name], err = p.Parameters[name].apply(propertySchema)
+			if err != nil {
+				return props, fmt.Errorf(
+					`error overriding parameter "%s" of plan "%s": %s`,
+					name,
+					props.ID,
+					err,
+				)
+			}
+		}
+		if !found {
+			return props, fmt.Errorf(
+				`catalog overrides refer to unknown parameter "%s" of plan "%s"`,
+				name,
+				props.ID,
+			)
+		}
+	}
+	instanceSchemas.ProvisioningParametersSchema.PropertySchemas =
+		provisioningSchemas
+	instanceSchemas.UpdatingParametersSchema.PropertySchemas = updatingSchemas
+	return props, nil
+}
+
+func copyPropertySchemas(
+	propertySchemas map[string]PropertySchema,
+) map[string]PropertySchema {
+	if propertySchemas == nil {
+		return nil
+	}
+	propertySchemasCopy := make(map[string]PropertySchema, len(propertySchemas))
+	for k, v := range propertySchemas {
+		propertySchemasCopy[k] = v
+	}
+	return propertySchemasCopy
+}
+
+// apply returns a modified copy of the given property schema. Allowed values
+// are validated against the original schema so that overrides can only ever
+// narrow what the module permits. The default is validated against the
+// resulting schema.
+func (p ParameterOverrides) apply(
+	propertySchema PropertySchema,
+) (PropertySchema, error) {
+	if p.AllowedValues != nil && len(p.AllowedValues) == 0 {
+		return nil, fmt.Errorf("allowed values must not be empty")
+	}
+	for _, allowedValue := range p.AllowedValues {
+		if err := propertySchema.validate("", allowedValue); err != nil {
+			return nil, fmt.Errorf(
+				"allowed value %v is invalid: %s",
+				allowedValue,
+				err,
+			)
+		}
+	}
+	var overridden PropertySchema
+	switch ps := propertySchema.(type) {
+	case *StringPropertySchema:
+		sps := *ps
+		if err := p.applyToString(&sps); err != nil {
+			return nil, err
+		}
+		if sps.DefaultValue != "" {
+			if err := sps.validate("", sps.DefaultValue); err != nil {
+				return nil, fmt.Errorf("default value is invalid: %s", err)
+			}
+		}
+		overridden = &sps
+	case *IntPropertySchema:
+		ips := *ps
+		if err := p.applyToInt(&ips); err != nil {
+			return nil, err
+		}
+		if ips.DefaultValue != nil {
+			if err := ips.validate("", *ips.DefaultValue); err != nil {
+				return nil, fmt.Errorf("default value is invalid: %s", err)
+			}
+		}
+		overridden = &ips
+	case *FloatPropertySchema:
+		fps := *ps
+		if err := p.applyToFloat(&fps); err != nil {
+			return nil, err
+		}
+		if fps.DefaultValue != nil {
+			if err := fps.validate("", *fps.DefaultValue); err != nil {
+				return nil, fmt.Errorf("default value is invalid: %s", err)
+			}
+		}
+		overridden = &fps
+	default:
+		return nil, fmt.Errorf(
+			"only string, integer, and number parameters can be overridden",
+		)
+	}
+	return overridden, nil
+}
+
+func (p ParameterOverrides) applyToString(sps *StringPropertySchema) error {
+	if p.AllowedValues != nil {
+		allowedValues := make([]string, len(p.AllowedValues))
+		for i, allowedValue := range p.AllowedValues {
+			// Validation against the original schema guarantees this is a string
+			allowedValues[i] = allowedValue.(string)
+		}
+		if len(sps.OneOf) > 0 {
+			oneOf := []EnumValue{}
+			for _, enumValue := range sps.OneOf {
+				for _, allowedValue := range allowedValues {
+					if enumValue.Value == allowedValue {
+						oneOf = append(oneOf, enumValue)
+						break
+					}
+				}
+			}
+			sps.OneOf = oneOf
+			if len(sps.AllowedValues) > 0 {
+				sps.AllowedValues = allowedValues
+			}
+		} else {
+			sps.AllowedValues = allowedValues
+		}
+	}
+	if p.Default != nil {
+		defaultValue, ok := p.Default.(string)
+		if !ok {
+			return fmt.Errorf("default value %v is not a string", p.Default)
+		}
+		sps.DefaultValue = defaultValue
+	}
+	return nil
+}
+
+func (p ParameterOverrides) applyToInt(ips *IntPropertySchema) error {
+	if p.AllowedValues != nil {
+		ips.AllowedValues = make([]int64, len(p.AllowedValues))
+		for i, allowedValue := range p.AllowedValues {
+			// Validation against the original schema guarantees this is integral
+			ips.AllowedValues[i] = int64(allowedValue.(float64))
+		}
+	}
+	if p.Default != nil {
+		defaultValue, ok := p.Default.(float64)
+		if !ok || defaultValue != math.Trunc(defaultValue) {
+			return fmt.Errorf("default value %v is not an integer", p.Default)
+		}
+		ips.DefaultValue = ptr.ToInt64(int64(defaultValue))
+	}
+	return nil
+}
+
+func (p ParameterOverrides) applyToFloat(fps *FloatPropertySchema) error {
+	if p.AllowedValues != nil {
+		fps.AllowedValues = make([]float64, len(p.AllowedValues))
+		for i, allowedValue := range p.AllowedValues {
+			// Validation against the original schema guarantees this is a number
+			fps.AllowedValues[i] = allowedValue.(float64)
+		}
+	}
+	if p.Default != nil {
+		defaultValue, ok := p.Default.(float64)
+		if !ok {
+			return fmt.Errorf("default value %v is not a number", p.Default)
+		}
+		fps.DefaultValue = &defaultValue
+	}
+	return nil
+}
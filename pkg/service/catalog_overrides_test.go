@@ -0,0 +1,292 @@
+package service
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/Azure/open-service-broker-azure/pkg/ptr"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	testOverridesServiceID = "a7454e0e-be2c-46ac-b55f-8c4278117525"
+	testOverridesPlanID    = "8e5cdb29-8b91-4aba-8e3e-e4a7c9b2cac1"
+)
+
+var testOverridesLocationSchema = &StringPropertySchema{
+	AllowedValues: []string{"eastus", "westus", "westeurope"},
+	DefaultValue:  "eastus",
+}
+
+func getTestOverridesPlanProperties() PlanProperties {
+	return PlanProperties{
+		ID:          testOverridesPlanID,
+		Name:        "basic",
+		Description: "Basic",
+		Metadata: ServicePlanMetadata{
+			DisplayName: "Basic Tier",
+			Bullets:     []string{"Up to 2 cores"},
+		},
+		Schemas: PlanSchemas{
+			ServiceInstances: InstanceSchemas{
+				ProvisioningParametersSchema: InputParametersSchema{
+					PropertySchemas: map[string]PropertySchema{
+						"location": testOverridesLocationSchema,
+						"sslEnforcement": &StringPropertySchema{
+							OneOf: []EnumValue{
+								{Value: "enabled", Title: "Enabled"},
+								{Value: "disabled", Title: "Disabled"},
+							},
+							DefaultValue: "enabled",
+						},
+						"cores": &IntPropertySchema{
+							AllowedValues: []int64{1, 2},
+							DefaultValue:  ptr.ToInt64(1),
+						},
+						"tags": &ObjectPropertySchema{},
+					},
+				},
+				UpdatingParametersSchema: InputParametersSchema{
+					PropertySchemas: map[string]PropertySchema{
+						"cores": &IntPropertySchema{
+							AllowedValues: []int64{1, 2},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func TestLoadCatalogOverrides(t *testing.T) {
+	file, err := ioutil.TempFile("", "catalog-overrides")
+	assert.Nil(t, err)
+	defer os.Remove(file.Name()) // nolint: errcheck
+	_, err = file.WriteString(`{
+		"services": {
+			"` + testOverridesServiceID + `": {
+				"displayName": "Database",
+				"plans": {
+					"` + testOverridesPlanID + `": {
+						"hidden": true,
+						"parameters": {
+							"location": {
+								"default": "westus",
+								"allowedValues": ["westus"]
+							}
+						}
+					}
+				}
+			}
+		}
+	}`)
+	assert.Nil(t, err)
+	assert.Nil(t, file.Close())
+	overrides, err := LoadCatalogOverrides(file.Name())
+	assert.Nil(t, err)
+	serviceOverrides, ok := overrides.GetServiceOverrides(testOverridesServiceID)
+	assert.True(t, ok)
+	assert.Equal(t, "Database", serviceOverrides.DisplayName)
+	planOverrides, ok := serviceOverrides.GetPlanOverrides(testOverridesPlanID)
+	assert.True(t, ok)
+	assert.True(t, planOverrides.Hidden)
+	assert.Equal(
+		t,
+		ParameterOverrides{
+			Default:       "westus",
+			AllowedValues: []interface{}{"westus"},
+		},
+		planOverrides.Parameters["location"],
+	)
+}
+
+func TestLoadCatalogOverridesWithUnknownField(t *testing.T) {
+	file, err := ioutil.TempFile("", "catalog-overrides")
+	assert.Nil(t, err)
+	defer os.Remove(file.Name()) // nolint: errcheck
+	_, err = file.WriteString(`{ "services": {}, "bogus": true }`)
+	assert.Nil(t, err)
+	assert.Nil(t, file.Close())
+	_, err = LoadCatalogOverrides(file.Name())
+	assert.NotNil(t, err)
+}
+
+func TestValidateCatalogOverrides(t *testing.T) {
+	catalog := NewCatalog([]Service{
+		NewService(
+			ServiceProperties{
+				ID: testOverridesServiceID,
+			},
+			nil,
+			NewPlan(getTestOverridesPlanProperties()),
+		),
+	})
+	overrides := &CatalogOverrides{
+		Services: map[string]ServiceOverrides{
+			testOverridesServiceID: {
+				Plans: map[string]PlanOverrides{
+					testOverridesPlanID: {},
+				},
+			},
+		},
+	}
+	assert.Nil(t, overrides.Validate(catalog))
+	// Nil overrides are always valid
+	assert.Nil(t, (*CatalogOverrides)(nil).Validate(catalog))
+	// This should fail because the plan doesn't exist
+	overrides.Services[testOverridesServiceID].Plans["bogus"] = PlanOverrides{}
+	assert.NotNil(t, overrides.Validate(catalog))
+	// This should fail because the service doesn't exist
+	overrides = &CatalogOverrides{
+		Services: map[string]ServiceOverrides{
+			"bogus": {},
+		},
+	}
+	assert.NotNil(t, overrides.Validate(catalog))
+}
+
+func TestApplyServiceOverrides(t *testing.T) {
+	props := ServiceOverrides{
+		Hidden:      true,
+		DisplayName: "Database",
+	}.Apply(
+		ServiceProperties{
+			ID:          testOverridesServiceID,
+			Description: "Original description",
+			Metadata: ServiceMetadata{
+				DisplayName: "Original display name",
+			},
+		},
+	)
+	assert.Equal(t, testOverridesServiceID, props.ID)
+	assert.True(t, props.EndOfLife)
+	assert.Equal(t, "Original description", props.Description)
+	assert.Equal(t, "Database", props.Metadata.DisplayName)
+}
+
+func TestApplyPlanOverrides(t *testing.T) {
+	originalProps := getTestOverridesPlanProperties()
+	props, err := PlanOverrides{
+		Description: "Overridden description",
+		Bullets:     []string{"Overridden bullet"},
+		Parameters: map[string]ParameterOverrides{
+			"location": {
+				Default:       "westeurope",
+				AllowedValues: []interface{}{"westus", "westeurope"},
+			},
+			"sslEnforcement": {
+				AllowedValues: []interface{}{"enabled"},
+			},
+			"cores": {
+				Default:       float64(2),
+				AllowedValues: []interface{}{float64(2)},
+			},
+		},
+	}.Apply(originalProps)
+	assert.Nil(t, err)
+	assert.Equal(t, testOverridesPlanID, props.ID)
+	assert.Equal(t, "Overridden description", props.Description)
+	assert.Equal(t, "Basic Tier", props.Metadata.DisplayName)
+	assert.Equal(t, []string{"Overridden bullet"}, props.Metadata.Bullets)
+
+	pps := props.Schemas.ServiceInstances.ProvisioningParametersSchema
+	locationSchema, ok := pps.PropertySchemas["location"].(*StringPropertySchema)
+	assert.True(t, ok)
+	assert.Equal(t, "westeurope", locationSchema.DefaultValue)
+	assert.Equal(t, []string{"westus", "westeurope"}, locationSchema.AllowedValues)
+	sslSchema, ok := pps.PropertySchemas["sslEnforcement"].(*StringPropertySchema)
+	assert.True(t, ok)
+	assert.Equal(
+		t,
+		[]EnumValue{{Value: "enabled", Title: "Enabled"}},
+		sslSchema.OneOf,
+	)
+	assert.Empty(t, sslSchema.AllowedValues)
+	coresSchema, ok := pps.PropertySchemas["cores"].(*IntPropertySchema)
+	assert.True(t, ok)
+	assert.Equal(t, int64(2), *coresSchema.DefaultValue)
+	assert.Equal(t, []int64{2}, coresSchema.AllowedValues)
+	// Allowed values are also narrowed for updates
+	ups := props.Schemas.ServiceInstances.UpdatingParametersSchema
+	coresSchema, ok = ups.PropertySchemas["cores"].(*IntPropertySchema)
+	assert.True(t, ok)
+	assert.Equal(t, []int64{2}, coresSchema.AllowedValues)
+	assert.Equal(t, int64(2), *coresSchema.DefaultValue)
+
+	// The original schemas must not have been modified
+	assert.Equal(t, getTestOverridesPlanProperties(), originalProps)
+	assert.Equal(t, "eastus", testOverridesLocationSchema.DefaultValue)
+}
+
+func TestApplyInvalidPlanOverrides(t *testing.T) {
+	testCases := map[string]ParameterOverrides{
+		// Not already allowed by the module
+		"location": {AllowedValues: []interface{}{"northeurope"}},
+		// Not of the right type
+		"cores": {Default: "two"},
+		// Not allowed by the module
+		"sslEnforcement": {Default: "required"},
+		// Not a type whose schema can be overridden
+		"tags": {Default: "foo"},
+		// Not a parameter at all
+		"bogus": {Default: "foo"},
+	}
+	for name, parameterOverrides := range testCases {
+		_, err := PlanOverrides{
+			Parameters: map[string]ParameterOverrides{
+				name: parameterOverrides,
+			},
+		}.Apply(getTestOverridesPlanProperties())
+		assert.NotNil(t, err, name)
+	}
+	// Narrowing allowed values such that the existing default is no longer
+	// allowed requires a new default
+	_, err := PlanOverrides{
+		Parameters: map[string]ParameterOverrides{
+			"location": {AllowedValues: []interface{}{"westus"}},
+		},
+	}.Apply(getTestOverridesPlanProperties())
+	assert.NotNil(t, err)
+	// Allowed values cannot be narrowed to nothing
+	_, err = PlanOverrides{
+		Parameters: map[string]ParameterOverrides{
+			"location": {AllowedValues: []interface{}{}},
+		},
+	}.Apply(getTestOverridesPlanProperties())
+	assert.NotNil(t, err)
+}
+
+func TestApplyPlanCostOverrides(t *testing.T) {
+	originalProps := getTestOverridesPlanProperties()
+	publicCosts := []PlanCost{
+		{Amount: map[string]float64{"usd": 25}, Unit: "MONTHLY"},
+	}
+	originalProps.EnvironmentCosts = map[string][]PlanCost{
+		"AzurePublicCloud": publicCosts,
+	}
+	chinaCosts := []PlanCost{
+		{Amount: map[string]float64{"cny": 160}, Unit: "MONTHLY"},
+	}
+	props, err := PlanOverrides{
+		Costs: map[string][]PlanCost{"AzureChinaCloud": chinaCosts},
+	}.Apply(originalProps)
+	assert.Nil(t, err)
+	assert.Equal(
+		t,
+		map[string][]PlanCost{
+			"AzurePublicCloud": publicCosts,
+			"AzureChinaCloud":  chinaCosts,
+		},
+		props.EnvironmentCosts,
+	)
+	// The original costs must not have been modified
+	assert.Len(t, originalProps.EnvironmentCosts, 1)
+	// Every cost must specify an amount and a unit
+	_, err = PlanOverrides{
+		Costs: map[string][]PlanCost{
+			"AzureChinaCloud": {{Amount: map[string]float64{"cny": 160}}},
+		},
+	}.Apply(originalProps)
+	assert.NotNil(t, err)
+}
@@ -39,6 +39,17 @@ func init() {
 				Name:        name,
 				Description: description,
 				Free:        free,
+				Metadata: ServicePlanMetadata{
+					Costs: []PlanCost{
+						{Amount: map[string]float64{"usd": 25}, Unit: "MONTHLY"},
+					},
+				},
+				// Costs for other environments must not be advertised
+				EnvironmentCosts: map[string][]PlanCost{
+					"AzureChinaCloud": {
+						{Amount: map[string]float64{"cny": 160}, Unit: "MONTHLY"},
+					},
+				},
 				Schemas: PlanSchemas{
 					ServiceInstances: InstanceSchemas{
 						ProvisioningParametersSchema: InputParametersSchema{
@@ -117,7 +128,9 @@ func init() {
 						"name":"%s",
 						"description":"%s",
 						"free":%t,
-						"metadata":{},
+						"metadata":{
+							"costs":[{"amount":{"usd":25},"unit":"MONTHLY"}]
+						},
 						"schemas": {
 							"service_instance": {
 								"create": {
@@ -1,24 +1,123 @@
 package service
 
-import "fmt"
+import (
+	"fmt"
+	"strings"
+)
+
+// Machine-readable reasons for which validation of a field may fail
+const (
+	// ValidationReasonRequired indicates a required field is missing
+	ValidationReasonRequired = "required"
+	// ValidationReasonUnrecognized indicates a field isn't defined by the schema
+	ValidationReasonUnrecognized = "unrecognized"
+	// ValidationReasonType indicates a field's value is of the wrong type
+	ValidationReasonType = "type"
+	// ValidationReasonMinLength indicates a string is too short
+	ValidationReasonMinLength = "minLength"
+	// ValidationReasonMaxLength indicates a string is too long
+	ValidationReasonMaxLength = "maxLength"
+	// ValidationReasonEnum indicates a value isn't one of those allowed
+	ValidationReasonEnum = "enum"
+	// ValidationReasonPattern indicates a string doesn't match the required
+	// pattern
+	ValidationReasonPattern = "pattern"
+	// ValidationReasonMinimum indicates a number is too small
+	ValidationReasonMinimum = "minimum"
+	// ValidationReasonMaximum indicates a number is too large
+	ValidationReasonMaximum = "maximum"
+	// ValidationReasonMultipleOf indicates a number isn't a multiple of the
+	// required increment
+	ValidationReasonMultipleOf = "multipleOf"
+	// ValidationReasonMinItems indicates an array has too few elements
+	ValidationReasonMinItems = "minItems"
+	// ValidationReasonMaxItems indicates an array has too many elements
+	ValidationReasonMaxItems = "maxItems"
+	// ValidationReasonFormat indicates a string doesn't conform to the required
+	// format
+	ValidationReasonFormat = "format"
+	// ValidationReasonDependencies indicates a field required by the presence
+	// of another field is missing
+	ValidationReasonDependencies = "dependencies"
+	// ValidationReasonAnyOf indicates a value matches none of the schemas it
+	// must match at least one of
+	ValidationReasonAnyOf = "anyOf"
+	// ValidationReasonOneOf indicates a value doesn't match exactly one of the
+	// schemas it must match exactly one of
+	ValidationReasonOneOf = "oneOf"
+	// ValidationReasonInvalid indicates a value is invalid for some other
+	// reason-- typically one determined by custom validation logic
+	ValidationReasonInvalid = "invalid"
+)
 
 // ValidationError represents an error validating requestParameters. This
 // specific error type should be used to allow the broker's framework to
 // differentiate between validation errors and other common, unexpected errors.
 type ValidationError struct {
-	Field string
-	Issue string
+	// Field is a JSON pointer (e.g. /firewallRules/2/startIPAddress) to the
+	// field that failed validation, relative to the parameters object
+	Field string `json:"field"`
+	// Reason is a machine-readable reason for the failure
+	Reason string `json:"reason"`
+	// Issue is a human-readable description of the failure
+	Issue string `json:"issue"`
 }
 
 // NewValidationError returns a new ValidationError for the given field and
 // issue
 func NewValidationError(field, issue string) *ValidationError {
+	return newValidationError(field, ValidationReasonInvalid, issue)
+}
+
+func newValidationError(field, reason, issue string) *ValidationError {
 	return &ValidationError{
-		Field: field,
-		Issue: issue,
+		Field:  field,
+		Reason: reason,
+		Issue:  issue,
 	}
 }
 
 func (e *ValidationError) Error() string {
 	return fmt.Sprintf("Error validating field '%s': %s", e.Field, e.Issue)
 }
+
+// ValidationErrors represents every error found while validating request
+// parameters
+type ValidationErrors []*ValidationError
+
+func (e ValidationErrors) Error() string {
+	msgs := make([]string, len(e))
+	for i, validationErr := range e {
+		msgs[i] = validationErr.Error()
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// GetValidationErrors returns all the validation errors represented by the
+// given error, which may be either a *ValidationError or ValidationErrors. If
+// the error is of neither type, false is returned.
+func GetValidationErrors(err error) (ValidationErrors, bool) {
+	switch e := err.(type) {
+	case *ValidationError:
+		return ValidationErrors{e}, true
+	case ValidationErrors:
+		return e, true
+	}
+	return nil, false
+}
+
+// collectValidationErrors appends any validation errors represented by the
+// given error to the given ValidationErrors. Any other kind of error is
+// returned so that validation can be aborted.
+func collectValidationErrors(
+	errs ValidationErrors,
+	err error,
+) (ValidationErrors, error) {
+	if err == nil {
+		return errs, nil
+	}
+	if validationErrs, ok := GetValidationErrors(err); ok {
+		return append(errs, validationErrs...), nil
+	}
+	return errs, err
+}
@@ -21,6 +21,20 @@ type Instance struct {
 	ParentAlias            string                  `json:"parentAlias"`
 	Details                InstanceDetails         `json:"details"`
 	Created                time.Time               `json:"created"`
+	// Step is the name of the provisioning, updating or deprovisioning step
+	// that is next to execute, is executing, or most recently failed. It is
+	// empty when no such operation is underway.
+	Step string `json:"step,omitempty"`
+	// Deleted is set when an instance is deleted from storage. Deleted instances
+	// are retained for a period of time before they are purged.
+	Deleted *time.Time `json:"deleted,omitempty"`
+	// Context is the contextual data provided by the platform when the instance
+	// was provisioned, e.g. the Cloud Foundry organization and space or the
+	// Kubernetes namespace the instance belongs to
+	Context map[string]interface{} `json:"context,omitempty"`
+	// Principal is the name of the authenticated principal that provisioned the
+	// instance, if any
+	Principal string `json:"principal,omitempty"`
 }
 
 // NewInstanceFromJSON returns a new Instance unmarshalled from the provided
@@ -1,9 +1,15 @@
 package service
 
+import "github.com/Azure/open-service-broker-azure/pkg/health"
+
 // Module is an interface to be implemented by the broker's modules
 type Module interface {
 	// GetName returns a module's name
 	GetName() string
 	// GetCatalog returns a Catalog of service/plans offered by a module
 	GetCatalog() (Catalog, error)
+	// GetHealthChecks returns checks of any components, beyond those common to
+	// all modules, that a module depends upon. These contribute to the broker's
+	// readiness. Modules with no such dependencies may return nil.
+	GetHealthChecks() []health.Check
 }
@@ -0,0 +1,181 @@
+package service
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Normalize returns a copy of the given parameters in which values of
+// compatible types (e.g. the string "5" for an integer property) have been
+// coerced to the types called for by this schema and in which missing
+// properties have been set to their default values. Both are applied
+// recursively to nested objects and arrays. Values that cannot be coerced are
+// left as they are for validation to reject.
+func (i InputParametersSchema) Normalize(
+	valMap map[string]interface{},
+) map[string]interface{} {
+	return i.normalize(valMap, true)
+}
+
+// Coerce returns a copy of the given parameters in which values of compatible
+// types have been coerced to the types called for by this schema. Unlike
+// Normalize, it does not apply default values. This is appropriate for
+// parameters that are to be merged with others, such as those of an update.
+func (i InputParametersSchema) Coerce(
+	valMap map[string]interface{},
+) map[string]interface{} {
+	return i.normalize(valMap, false)
+}
+
+func (i InputParametersSchema) normalize(
+	valMap map[string]interface{},
+	applyDefaults bool,
+) map[string]interface{} {
+	if valMap == nil {
+		valMap = map[string]interface{}{}
+	}
+	ops := ObjectPropertySchema{
+		PropertySchemas: i.PropertySchemas,
+	}
+	return ops.normalize(valMap, applyDefaults).(map[string]interface{})
+}
+
+func (s StringPropertySchema) normalize(
+	value interface{},
+	applyDefaults bool,
+) interface{} {
+	// An empty string is indistinguishable from no default at all
+	if value == nil && applyDefaults && s.DefaultValue != "" {
+		return s.DefaultValue
+	}
+	return value
+}
+
+func (i IntPropertySchema) normalize(
+	value interface{},
+	applyDefaults bool,
+) interface{} {
+	if value == nil && applyDefaults && i.DefaultValue != nil {
+		value = *i.DefaultValue
+	}
+	// Numbers are normalized to float64 because that is what they'll be once
+	// they have been round-tripped through JSON
+	switch val := value.(type) {
+	case string:
+		intVal, err := strconv.ParseInt(strings.TrimSpace(val), 10, 64)
+		if err == nil {
+			return float64(intVal)
+		}
+	case int:
+		return float64(val)
+	case int32:
+		return float64(val)
+	case int64:
+		return float64(val)
+	}
+	return value
+}
+
+func (f FloatPropertySchema) normalize(
+	value interface{},
+	applyDefaults bool,
+) interface{} {
+	if value == nil && applyDefaults && f.DefaultValue != nil {
+		value = *f.DefaultValue
+	}
+	switch val := value.(type) {
+	case string:
+		floatVal, err := strconv.ParseFloat(strings.TrimSpace(val), 64)
+		if err == nil {
+			return floatVal
+		}
+	case int:
+		return float64(val)
+	case int32:
+		return float64(val)
+	case int64:
+		return float64(val)
+	case float32:
+		return float64(val)
+	}
+	return value
+}
+
+func (o ObjectPropertySchema) normalize(
+	value interface{},
+	applyDefaults bool,
+) interface{} {
+	if value == nil && applyDefaults && o.DefaultValue != nil {
+		value = o.DefaultValue
+	}
+	valMap, ok := value.(map[string]interface{})
+	if !ok {
+		return value
+	}
+	normalizedMap := make(map[string]interface{}, len(valMap))
+	for k, v := range valMap {
+		propertySchema, ok := o.PropertySchemas[k]
+		if !ok {
+			propertySchema = o.Additional
+		}
+		if propertySchema == nil {
+			normalizedMap[k] = v
+			continue
+		}
+		normalizedMap[k] = propertySchema.normalize(v, applyDefaults)
+	}
+	if applyDefaults {
+		for k, propertySchema := range o.PropertySchemas {
+			if _, ok := normalizedMap[k]; ok {
+				continue
+			}
+			if defaultVal := propertySchema.normalize(nil, true); defaultVal != nil {
+				normalizedMap[k] = defaultVal
+			}
+		}
+	}
+	return normalizedMap
+}
+
+func (a ArrayPropertySchema) normalize(
+	value interface{},
+	applyDefaults bool,
+) interface{} {
+	if value == nil && applyDefaults && a.DefaultValue != nil {
+		value = a.DefaultValue
+	}
+	valArray, ok := value.([]interface{})
+	if !ok {
+		return value
+	}
+	normalizedArray := make([]interface{}, len(valArray))
+	for i, v := range valArray {
+		if a.ItemsSchema == nil {
+			normalizedArray[i] = v
+			continue
+		}
+		normalizedArray[i] = a.ItemsSchema.normalize(v, applyDefaults)
+	}
+	return normalizedArray
+}
+
+func (ObjectConstraintSchema) normalize(
+	value interface{},
+	applyDefaults bool,
+) interface{} {
+	return value
+}
+
+func (PropertyDependency) normalize(
+	value interface{},
+	applyDefaults bool,
+) interface{} {
+	return value
+}
+
+func (falsePropertySchema) normalize(
+	value interface{},
+	applyDefaults bool,
+) interface{} {
+	return value
+}
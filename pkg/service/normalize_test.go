@@ -0,0 +1,138 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/Azure/open-service-broker-azure/pkg/ptr"
+	"github.com/stretchr/testify/assert"
+)
+
+var testNormalizationSchema = InputParametersSchema{
+	PropertySchemas: map[string]PropertySchema{
+		"location": &StringPropertySchema{
+			DefaultValue: "eastus",
+		},
+		"cores": &IntPropertySchema{
+			DefaultValue: ptr.ToInt64(2),
+		},
+		"ratio": &FloatPropertySchema{},
+		"settings": &ObjectPropertySchema{
+			PropertySchemas: map[string]PropertySchema{
+				"tier": &StringPropertySchema{
+					DefaultValue: "basic",
+				},
+				"replicas": &IntPropertySchema{
+					DefaultValue: ptr.ToInt64(1),
+				},
+			},
+			DefaultValue: map[string]interface{}{},
+		},
+		"firewallRules": &ArrayPropertySchema{
+			ItemsSchema: &ObjectPropertySchema{
+				PropertySchemas: map[string]PropertySchema{
+					"name": &StringPropertySchema{},
+					"port": &IntPropertySchema{
+						DefaultValue: ptr.ToInt64(443),
+					},
+				},
+			},
+		},
+		"tags": &ObjectPropertySchema{
+			Additional: &StringPropertySchema{},
+		},
+	},
+}
+
+func TestNormalizeAppliesDefaultsRecursively(t *testing.T) {
+	normalized := testNormalizationSchema.Normalize(
+		map[string]interface{}{
+			"firewallRules": []interface{}{
+				map[string]interface{}{
+					"name": "foo",
+				},
+			},
+		},
+	)
+	assert.Equal(
+		t,
+		map[string]interface{}{
+			"location": "eastus",
+			"cores":    float64(2),
+			"settings": map[string]interface{}{
+				"tier":     "basic",
+				"replicas": float64(1),
+			},
+			"firewallRules": []interface{}{
+				map[string]interface{}{
+					"name": "foo",
+					"port": float64(443),
+				},
+			},
+		},
+		normalized,
+	)
+}
+
+func TestNormalizeCoercesCompatibleTypes(t *testing.T) {
+	normalized := testNormalizationSchema.Normalize(
+		map[string]interface{}{
+			"cores": "8",
+			"ratio": " 0.5 ",
+			"settings": map[string]interface{}{
+				"replicas": 3,
+			},
+			"tags": map[string]interface{}{
+				"foo": "bar",
+			},
+		},
+	)
+	assert.Equal(t, float64(8), normalized["cores"])
+	assert.Equal(t, 0.5, normalized["ratio"])
+	settings, ok := normalized["settings"].(map[string]interface{})
+	assert.True(t, ok)
+	assert.Equal(t, float64(3), settings["replicas"])
+	assert.Equal(t, map[string]interface{}{"foo": "bar"}, normalized["tags"])
+	assert.Nil(t, testNormalizationSchema.Validate(normalized))
+}
+
+func TestNormalizeLeavesIncompatibleTypes(t *testing.T) {
+	normalized := testNormalizationSchema.Normalize(
+		map[string]interface{}{
+			"cores": "eight",
+			"tags":  "bogus",
+		},
+	)
+	assert.Equal(t, "eight", normalized["cores"])
+	assert.Equal(t, "bogus", normalized["tags"])
+	err := testNormalizationSchema.Validate(normalized)
+	validationErrs, ok := GetValidationErrors(err)
+	assert.True(t, ok)
+	assert.Equal(t, 2, len(validationErrs))
+}
+
+func TestCoerceDoesNotApplyDefaults(t *testing.T) {
+	coerced := testNormalizationSchema.Coerce(
+		map[string]interface{}{
+			"cores": "8",
+		},
+	)
+	assert.Equal(
+		t,
+		map[string]interface{}{
+			"cores": float64(8),
+		},
+		coerced,
+	)
+}
+
+func TestNormalizeDoesNotModifyInput(t *testing.T) {
+	settings := map[string]interface{}{}
+	params := map[string]interface{}{
+		"settings": settings,
+	}
+	testNormalizationSchema.Normalize(params)
+	assert.Empty(t, settings)
+	settingsSchema :=
+		testNormalizationSchema.PropertySchemas["settings"].(*ObjectPropertySchema)
+	assert.Empty(t, settingsSchema.DefaultValue)
+}
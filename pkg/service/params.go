@@ -1,12 +1,10 @@
 package service
 
 import (
-	"encoding/base64"
 	"encoding/json"
 	"errors"
 	"fmt"
 
-	"github.com/Azure/open-service-broker-azure/pkg/crypto"
 	"github.com/Azure/open-service-broker-azure/pkg/slice"
 )
 
@@ -52,13 +50,11 @@ func (p Parameters) MarshalJSON() ([]byte, error) {
 						k,
 					)
 				}
-				encryptedBytes, err := crypto.Encrypt([]byte(vStr))
+				encryptedStr, err := encrypt(vStr)
 				if err != nil {
 					return nil, err
 				}
-				// When the whole map gets marshaled, this []byte will automatically be
-				// base64 encoded and wrapped in quotes.
-				v = encryptedBytes
+				v = encryptedStr
 			}
 			data[k] = v
 		}
@@ -106,16 +102,19 @@ func (p *Parameters) UnmarshalJSON(bytes []byte) error {
 							k,
 						)
 					}
-					encryptedBytes, err := base64.StdEncoding.DecodeString(vStr)
-					if err != nil {
-						return err
-					}
-					decryptedBytes, err := crypto.Decrypt(encryptedBytes)
+					decryptedStr, err := decrypt(
+						vStr,
+						slice.ContainsString(ips.MigratedSecureProperties, k),
+					)
 					if err != nil {
-						return err
+						return fmt.Errorf(
+							`error unmarshaling parameters: cannot decrypt field "%s": %s`,
+							k,
+							err,
+						)
 					}
 					// The map we're building should have a string in it
-					v = string(decryptedBytes)
+					v = decryptedStr
 				}
 				p.Data[k] = v
 			}
@@ -3,9 +3,11 @@ package service
 import (
 	"encoding/base64"
 	"encoding/json"
+	"strings"
 	"testing"
 
 	"github.com/Azure/open-service-broker-azure/pkg/crypto"
+	"github.com/Azure/open-service-broker-azure/pkg/crypto/aes256"
 	"github.com/Azure/open-service-broker-azure/pkg/ptr"
 
 	"github.com/stretchr/testify/assert"
@@ -103,7 +105,10 @@ func TestMarshalParameters(t *testing.T) {
 	assert.True(t, ok)
 	encodedFooStr, ok := encodedFooIface.(string)
 	assert.True(t, ok)
-	encryptedFooBytes, err := base64.StdEncoding.DecodeString(encodedFooStr)
+	assert.True(t, strings.HasPrefix(encodedFooStr, encryptedValuePrefix))
+	encryptedFooBytes, err := base64.StdEncoding.DecodeString(
+		strings.TrimPrefix(encodedFooStr, encryptedValuePrefix),
+	)
 	assert.Nil(t, err)
 	decryptedFooBytes, err := crypto.Decrypt(encryptedFooBytes)
 	assert.Nil(t, err)
@@ -220,6 +225,69 @@ func TestUnmarshalParameters(t *testing.T) {
 	assert.Equal(t, fooVal, p.Data["foo"])
 }
 
+func TestUnmarshalParametersWithPlaintextSecureFieldValue(t *testing.T) {
+	// Values persisted before a field was marked secure are plaintext
+	const fooVal = "bar"
+	data := map[string]interface{}{
+		"foo": fooVal,
+	}
+	// Turn the raw map into JSON
+	jsonBytes, err := json.Marshal(data)
+	assert.Nil(t, err)
+	p := Parameters{
+		Schema: &InputParametersSchema{
+			SecureProperties:         []string{"foo"},
+			MigratedSecureProperties: []string{"foo"},
+			PropertySchemas: map[string]PropertySchema{
+				"foo": &StringPropertySchema{},
+			},
+		},
+	}
+	err = json.Unmarshal(jsonBytes, &p)
+	assert.Nil(t, err)
+	assert.Equal(t, fooVal, p.Data["foo"])
+	// Fields that have always been secure never hold plaintext
+	p = Parameters{
+		Schema: &InputParametersSchema{
+			SecureProperties: []string{"foo"},
+			PropertySchemas: map[string]PropertySchema{
+				"foo": &StringPropertySchema{},
+			},
+		},
+	}
+	err = json.Unmarshal(jsonBytes, &p)
+	assert.NotNil(t, err)
+}
+
+func TestUnmarshalParametersEncryptedWithWrongKey(t *testing.T) {
+	codec, err := aes256.NewCodec(
+		aes256.Config{
+			Key: "AES256Key-WrongKey-1234567890123",
+		},
+	)
+	assert.Nil(t, err)
+	encryptedFooBytes, err := codec.Encrypt([]byte("bar"))
+	assert.Nil(t, err)
+	data := map[string]interface{}{
+		"foo": encryptedValuePrefix +
+			base64.StdEncoding.EncodeToString(encryptedFooBytes),
+	}
+	// Turn the raw map into JSON
+	jsonBytes, err := json.Marshal(data)
+	assert.Nil(t, err)
+	p := Parameters{
+		Schema: &InputParametersSchema{
+			SecureProperties:         []string{"foo"},
+			MigratedSecureProperties: []string{"foo"},
+			PropertySchemas: map[string]PropertySchema{
+				"foo": &StringPropertySchema{},
+			},
+		},
+	}
+	err = json.Unmarshal(jsonBytes, &p)
+	assert.NotNil(t, err)
+}
+
 func TestGetStringWithNoSchema(t *testing.T) {
 	p := Parameters{
 		Data: map[string]interface{}{
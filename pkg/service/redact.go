@@ -0,0 +1,161 @@
+package service
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+
+	"github.com/Azure/open-service-broker-azure/pkg/slice"
+)
+
+// Redacted is the value substituted for sensitive values by Redact()
+const Redacted = "REDACTED"
+
+var (
+	secureStringType         = reflect.TypeOf(SecureString(""))
+	migratedSecureStringType = reflect.TypeOf(MigratedSecureString(""))
+	jsonMarshalerType        = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+)
+
+// Redact returns a representation of the given value that is suitable for
+// marshaling to JSON for display. Unlike the value itself, the representation
+// marshals to JSON without encrypting anything. Instead, all SecureStrings,
+// MigratedSecureStrings, and secure parameters are replaced with a
+// placeholder. This permits
+// (decrypted) records such as instances and bindings to be inspected without
+// exposing any secrets.
+func Redact(v interface{}) interface{} {
+	return redactValue(reflect.ValueOf(v))
+}
+
+func redactValue(v reflect.Value) interface{} {
+	if !v.IsValid() {
+		return nil
+	}
+	if v.Type() == secureStringType || v.Type() == migratedSecureStringType {
+		if v.String() == "" {
+			return ""
+		}
+		return Redacted
+	}
+	switch p := v.Interface().(type) {
+	case Parameters:
+		return redactParameters(p)
+	case ProvisioningParameters:
+		return redactParameters(p.Parameters)
+	case BindingParameters:
+		return redactParameters(p.Parameters)
+	}
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if v.IsNil() {
+			return nil
+		}
+		return redactValue(v.Elem())
+	case reflect.Struct:
+		// Structs that know how to marshal themselves (e.g. time.Time) are left
+		// as is
+		if v.Type().Implements(jsonMarshalerType) {
+			return v.Interface()
+		}
+		return redactStruct(v)
+	case reflect.Map:
+		if v.IsNil() {
+			return nil
+		}
+		m := map[string]interface{}{}
+		for _, key := range v.MapKeys() {
+			m[key.String()] = redactValue(v.MapIndex(key))
+		}
+		return m
+	case reflect.Slice, reflect.Array:
+		if v.Kind() == reflect.Slice && v.IsNil() {
+			return nil
+		}
+		// Byte slices are left as is so they'll be base64 encoded, as usual
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			return v.Interface()
+		}
+		s := make([]interface{}, v.Len())
+		for i := 0; i < v.Len(); i++ {
+			s[i] = redactValue(v.Index(i))
+		}
+		return s
+	default:
+		return v.Interface()
+	}
+}
+
+// redactStruct returns a map representation of the given struct that honors
+// the struct's JSON field tags
+func redactStruct(v reflect.Value) map[string]interface{} {
+	m := map[string]interface{}{}
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.PkgPath != "" { // Unexported
+			continue
+		}
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		tagTokens := strings.Split(tag, ",")
+		name := tagTokens[0]
+		fieldValue := v.Field(i)
+		if name == "" && field.Anonymous {
+			if embedded, ok := redactValue(fieldValue).(map[string]interface{}); ok {
+				for k, ev := range embedded {
+					m[k] = ev
+				}
+			}
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		if slice.ContainsString(tagTokens[1:], "omitempty") &&
+			isEmptyValue(fieldValue) {
+			continue
+		}
+		m[name] = redactValue(fieldValue)
+	}
+	return m
+}
+
+func redactParameters(p Parameters) map[string]interface{} {
+	var secureProperties []string
+	if ips, ok := p.Schema.(*InputParametersSchema); ok && ips != nil {
+		secureProperties = ips.SecureProperties
+	}
+	m := map[string]interface{}{}
+	for k, v := range p.Data {
+		if slice.ContainsString(secureProperties, k) {
+			m[k] = Redacted
+			continue
+		}
+		m[k] = v
+	}
+	return m
+}
+
+// isEmptyValue mirrors the encoding/json package's notion of an empty value
+// for the purposes of honoring omitempty
+func isEmptyValue(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Array, reflect.Map, reflect.Slice, reflect.String:
+		return v.Len() == 0
+	case reflect.Bool:
+		return !v.Bool()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return v.Int() == 0
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32,
+		reflect.Uint64, reflect.Uintptr:
+		return v.Uint() == 0
+	case reflect.Float32, reflect.Float64:
+		return v.Float() == 0
+	case reflect.Interface, reflect.Ptr:
+		return v.IsNil()
+	}
+	return false
+}
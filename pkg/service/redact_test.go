@@ -0,0 +1,77 @@
+package service
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testRedactableDetails struct {
+	Name      string               `json:"name"`
+	Password  SecureString         `json:"password"`
+	AccessKey MigratedSecureString `json:"accessKey"`
+	Empty     string               `json:"empty,omitempty"`
+	Ignored   string               `json:"-"`
+	internal  string
+}
+
+func TestRedact(t *testing.T) {
+	created := time.Date(2018, time.January, 1, 0, 0, 0, 0, time.UTC)
+	instance := Instance{
+		InstanceID: "foo",
+		Status:     InstanceStateProvisioned,
+		ProvisioningParameters: &ProvisioningParameters{
+			Parameters: Parameters{
+				Schema: &InputParametersSchema{
+					PropertySchemas: map[string]PropertySchema{
+						"location": &StringPropertySchema{},
+						"secret":   &StringPropertySchema{},
+					},
+					SecureProperties: []string{"secret"},
+				},
+				Data: map[string]interface{}{
+					"location": "eastus",
+					"secret":   "s3cr3t",
+				},
+			},
+		},
+		Details: &testRedactableDetails{
+			Name:      "bar",
+			Password:  "p@ssw0rd",
+			AccessKey: "t0ps3cr3t",
+			Ignored:   "baz",
+			internal:  "bat",
+		},
+		Created: created,
+	}
+	jsonBytes, err := json.Marshal(Redact(instance))
+	assert.Nil(t, err)
+	redacted := map[string]interface{}{}
+	err = json.Unmarshal(jsonBytes, &redacted)
+	assert.Nil(t, err)
+	assert.Equal(t, "foo", redacted["instanceId"])
+	assert.Equal(t, InstanceStateProvisioned, redacted["status"])
+	assert.Equal(t, created.Format(time.RFC3339), redacted["created"])
+	assert.Equal(
+		t,
+		map[string]interface{}{
+			"location": "eastus",
+			"secret":   Redacted,
+		},
+		redacted["provisioningParameters"],
+	)
+	assert.Equal(
+		t,
+		map[string]interface{}{
+			"name":      "bar",
+			"password":  Redacted,
+			"accessKey": Redacted,
+		},
+		redacted["details"],
+	)
+	assert.NotContains(t, redacted, "Service")
+	assert.NotContains(t, redacted, "deleted")
+	assert.Nil(t, redacted["updatingParameters"])
+}
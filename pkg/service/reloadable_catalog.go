@@ -0,0 +1,58 @@
+package service
+
+import (
+	"encoding/json"
+	"sync/atomic"
+)
+
+// ReloadableCatalog is a Catalog whose services and plans can be atomically
+// replaced while it is in use. Components that resolve services and plans
+// through a ReloadableCatalog observe a replacement as soon as it is made.
+type ReloadableCatalog interface {
+	Catalog
+	// GetCatalog returns the catalog currently in effect. Callers that must
+	// observe a consistent set of services and plans across several lookups
+	// should use the returned catalog instead of the ReloadableCatalog itself.
+	GetCatalog() Catalog
+	// SetCatalog atomically replaces the catalog currently in effect
+	SetCatalog(catalog Catalog)
+}
+
+type reloadableCatalog struct {
+	// catalog holds a catalogHolder
+	catalog atomic.Value
+}
+
+// catalogHolder wraps a Catalog so that catalogs of differing concrete types
+// can be stored in the same atomic.Value
+type catalogHolder struct {
+	catalog Catalog
+}
+
+// NewReloadableCatalog returns a ReloadableCatalog initially in effect with
+// the given catalog
+func NewReloadableCatalog(catalog Catalog) ReloadableCatalog {
+	r := &reloadableCatalog{}
+	r.SetCatalog(catalog)
+	return r
+}
+
+func (r *reloadableCatalog) MarshalJSON() ([]byte, error) {
+	return json.Marshal(r.GetCatalog())
+}
+
+func (r *reloadableCatalog) GetServices() []Service {
+	return r.GetCatalog().GetServices()
+}
+
+func (r *reloadableCatalog) GetService(serviceID string) (Service, bool) {
+	return r.GetCatalog().GetService(serviceID)
+}
+
+func (r *reloadableCatalog) GetCatalog() Catalog {
+	return r.catalog.Load().(catalogHolder).catalog
+}
+
+func (r *reloadableCatalog) SetCatalog(catalog Catalog) {
+	r.catalog.Store(catalogHolder{catalog: catalog})
+}
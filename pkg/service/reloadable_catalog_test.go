@@ -0,0 +1,25 @@
+package service
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReloadableCatalog(t *testing.T) {
+	catalog := NewReloadableCatalog(testCatalog)
+	_, ok := catalog.GetService("test-id")
+	assert.True(t, ok)
+	catalogJSON, err := json.Marshal(catalog)
+	assert.Nil(t, err)
+	assert.Equal(t, testCatalogJSON, catalogJSON)
+
+	catalog.SetCatalog(NewCatalog(nil))
+	_, ok = catalog.GetService("test-id")
+	assert.False(t, ok)
+	assert.Empty(t, catalog.GetServices())
+	catalogJSON, err = json.Marshal(catalog)
+	assert.Nil(t, err)
+	assert.Equal(t, `{"services":[]}`, string(catalogJSON))
+}
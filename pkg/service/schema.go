@@ -4,6 +4,9 @@ import (
 	"encoding/json"
 	"fmt"
 	"regexp"
+	"sort"
+	"strconv"
+	"strings"
 )
 
 const jsonSchemaVersion = "http://json-schema.org/draft-04/schema#"
@@ -35,6 +38,18 @@ type InputParametersSchema struct {
 	RequiredProperties []string                  `json:"required,omitempty"`
 	SecureProperties   []string                  `json:"-"`
 	PropertySchemas    map[string]PropertySchema `json:"properties,omitempty"`
+	// Dependencies maps the names of properties to either a PropertyDependency
+	// or a schema that the parameters must additionally satisfy whenever that
+	// property is present
+	Dependencies map[string]PropertySchema `json:"dependencies,omitempty"`
+	AllOf        []PropertySchema          `json:"allOf,omitempty"`
+	AnyOf        []PropertySchema          `json:"anyOf,omitempty"`
+	OneOf        []PropertySchema          `json:"oneOf,omitempty"`
+	// MigratedSecureProperties lists those of the SecureProperties that were
+	// persisted as plaintext before they were marked secure. Unencrypted values
+	// of these properties are accepted as legacy plaintext when parameters are
+	// unmarshaled.
+	MigratedSecureProperties []string `json:"-"`
 }
 
 // GetPropertySchemas returns a map of subordinate property schemas
@@ -73,29 +88,100 @@ func (i InputParametersSchema) MarshalJSON() ([]byte, error) {
 	)
 }
 
-// Validate validates the given map[string]interface{} again this schema
+// Validate validates the given map[string]interface{} again this schema. If
+// validation fails, the returned error is a ValidationErrors that describes
+// every failure, not just the first.
 func (i InputParametersSchema) Validate(valMap map[string]interface{}) error {
-	for _, requiredProperty := range i.RequiredProperties {
-		_, ok := valMap[requiredProperty]
-		if !ok {
-			return NewValidationError(requiredProperty, "field is required")
+	return ObjectPropertySchema{
+		RequiredProperties: i.RequiredProperties,
+		PropertySchemas:    i.PropertySchemas,
+		Dependencies:       i.Dependencies,
+		AllOf:              i.AllOf,
+		AnyOf:              i.AnyOf,
+		OneOf:              i.OneOf,
+	}.validate("", valMap)
+}
+
+// PropertySchema is an interface for the schema of any kind of property.
+type PropertySchema interface {
+	// validate validates the given value, which is found at the location
+	// indicated by the given JSON pointer. Validation failures are returned as
+	// a *ValidationError or ValidationErrors.
+	validate(context string, value interface{}) error
+	// normalize returns a copy of the given value in which values of compatible
+	// types are coerced to the type called for by the schema and, if
+	// applyDefaults is true, missing values are replaced with their defaults.
+	// A nil value is treated as missing.
+	normalize(value interface{}, applyDefaults bool) interface{}
+}
+
+// validateProperties validates each of the properties of an object against
+// the corresponding property schema (or, for properties without one, the
+// given additional property schema), collecting all validation failures
+func validateProperties(
+	context string,
+	requiredProperties []string,
+	propertySchemas map[string]PropertySchema,
+	additional PropertySchema,
+	valMap map[string]interface{},
+) error {
+	errs := ValidationErrors{}
+	for _, requiredProperty := range requiredProperties {
+		if _, ok := valMap[requiredProperty]; !ok {
+			errs = append(
+				errs,
+				newValidationError(
+					getJSONPointer(context, requiredProperty),
+					ValidationReasonRequired,
+					"field is required",
+				),
+			)
 		}
 	}
-	for k, v := range valMap {
-		propertySchema, ok := i.PropertySchemas[k]
+	// Properties are visited in a predictable order so that errors are, too
+	keys := make([]string, 0, len(valMap))
+	for k := range valMap {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	var err error
+	for _, k := range keys {
+		propertyContext := getJSONPointer(context, k)
+		propertySchema, ok := propertySchemas[k]
 		if !ok {
-			return NewValidationError(k, "unrecognized field")
+			propertySchema = additional
+		}
+		if propertySchema == nil {
+			errs = append(
+				errs,
+				newValidationError(
+					propertyContext,
+					ValidationReasonUnrecognized,
+					"unrecognized field",
+				),
+			)
+			continue
 		}
-		if err := propertySchema.validate(k, v); err != nil {
+		errs, err = collectValidationErrors(
+			errs,
+			propertySchema.validate(propertyContext, valMap[k]),
+		)
+		if err != nil {
 			return err
 		}
 	}
+	if len(errs) > 0 {
+		return errs
+	}
 	return nil
 }
 
-// PropertySchema is an interface for the schema of any kind of property.
-type PropertySchema interface {
-	validate(context string, value interface{}) error
+// getJSONPointer returns a JSON pointer to the named child of the location
+// indicated by the given JSON pointer
+func getJSONPointer(parent string, child string) string {
+	child = strings.Replace(child, "~", "~0", -1)
+	child = strings.Replace(child, "/", "~1", -1)
+	return parent + "/" + child
 }
 
 // CustomStringPropertyValidator is a function type that describes the signature
@@ -112,7 +198,14 @@ type StringPropertySchema struct {
 	AllowedPattern          string                        `json:"pattern,omitempty"` // nolint: lll
 	CustomPropertyValidator CustomStringPropertyValidator `json:"-"`
 	DefaultValue            string                        `json:"default,omitempty"` // nolint: lll
-	OneOf                   []EnumValue                   `json:"oneOf,omitempty"`   // nolint: lll
+	// Format is one of the Format* constants. Other formats are permitted for
+	// the schema consumer's benefit, but are not validated.
+	Format string           `json:"format,omitempty"`
+	AllOf  []PropertySchema `json:"allOf,omitempty"`
+	AnyOf  []PropertySchema `json:"anyOf,omitempty"`
+	// OneOf enumerates allowed values, each with a title. Unlike the OneOf field
+	// of other property schemas, it does not admit arbitrary schemas.
+	OneOf []EnumValue `json:"oneOf,omitempty"`
 }
 
 // MarshalJSON provides functionality to marshal a StringPropertySchema to JSON
@@ -138,18 +231,31 @@ func (s StringPropertySchema) validate(
 	}
 	val, ok := value.(string)
 	if !ok {
-		return NewValidationError(context, "field value is not of type string")
+		return newValidationError(
+			context,
+			ValidationReasonType,
+			"field value is not of type string",
+		)
 	}
+	errs := ValidationErrors{}
 	if s.MinLength != nil && len(val) < *s.MinLength {
-		return NewValidationError(
-			context,
-			fmt.Sprintf("field length is less than minimum %d", *s.MinLength),
+		errs = append(
+			errs,
+			newValidationError(
+				context,
+				ValidationReasonMinLength,
+				fmt.Sprintf("field length is less than minimum %d", *s.MinLength),
+			),
 		)
 	}
 	if s.MaxLength != nil && len(val) > *s.MaxLength {
-		return NewValidationError(
-			context,
-			fmt.Sprintf("field length is greater than maximum %d", *s.MaxLength),
+		errs = append(
+			errs,
+			newValidationError(
+				context,
+				ValidationReasonMaxLength,
+				fmt.Sprintf("field length is greater than maximum %d", *s.MaxLength),
+			),
 		)
 	}
 	if len(s.AllowedValues) > 0 {
@@ -161,7 +267,14 @@ func (s StringPropertySchema) validate(
 			}
 		}
 		if !found {
-			return NewValidationError(context, "field value is invalid")
+			errs = append(
+				errs,
+				newValidationError(
+					context,
+					ValidationReasonEnum,
+					"field value is invalid",
+				),
+			)
 		}
 	}
 	if len(s.OneOf) > 0 {
@@ -173,15 +286,47 @@ func (s StringPropertySchema) validate(
 			}
 		}
 		if !found {
-			return NewValidationError(context, "field value is invalid")
+			errs = append(
+				errs,
+				newValidationError(
+					context,
+					ValidationReasonEnum,
+					"field value is invalid",
+				),
+			)
 		}
 	}
 	if s.AllowedPattern != "" {
 		pattern := regexp.MustCompile(s.AllowedPattern)
 		if !pattern.MatchString(val) {
-			return NewValidationError(context, "field value is invalid")
+			errs = append(
+				errs,
+				newValidationError(
+					context,
+					ValidationReasonPattern,
+					"field value is invalid",
+				),
+			)
+		}
+	}
+	if s.Format != "" {
+		if validationErr :=
+			validateFormat(context, s.Format, val); validationErr != nil {
+			errs = append(errs, validationErr)
 		}
 	}
+	var err error
+	errs, err = collectValidationErrors(
+		errs,
+		validateComposition(context, value, s.AllOf, s.AnyOf, nil),
+	)
+	if err != nil {
+		return err
+	}
+	// Custom validation is only applied to values that are otherwise valid
+	if len(errs) > 0 {
+		return errs
+	}
 	if s.CustomPropertyValidator != nil {
 		return s.CustomPropertyValidator(context, val)
 	}
@@ -222,6 +367,9 @@ type IntPropertySchema struct {
 	AllowedIncrement        *int64                     `json:"multipleOf,omitempty"` // nolint: lll
 	CustomPropertyValidator CustomIntPropertyValidator `json:"-"`
 	DefaultValue            *int64                     `json:"default,omitempty"`
+	AllOf                   []PropertySchema           `json:"allOf,omitempty"`
+	AnyOf                   []PropertySchema           `json:"anyOf,omitempty"`
+	OneOf                   []PropertySchema           `json:"oneOf,omitempty"`
 }
 
 // MarshalJSON provides functionality to marshal an IntPropertySchema to JSON
@@ -246,22 +394,38 @@ func (i IntPropertySchema) validate(context string, value interface{}) error {
 	if floatVal, ok := value.(float64); ok {
 		val = int64(floatVal)
 		if floatVal != float64(val) {
-			return NewValidationError(context, "field value is not of type integer")
+			return newValidationError(
+				context,
+				ValidationReasonType,
+				"field value is not of type integer",
+			)
 		}
 	} else if floatVal, ok := value.(*float64); ok {
 		val = int64(*floatVal)
 		if *floatVal != float64(val) {
-			return NewValidationError(context, "field value is not of type integer")
+			return newValidationError(
+				context,
+				ValidationReasonType,
+				"field value is not of type integer",
+			)
 		}
 	} else if floatVal, ok := value.(float32); ok {
 		val = int64(floatVal)
 		if floatVal != float32(val) {
-			return NewValidationError(context, "field value is not of type integer")
+			return newValidationError(
+				context,
+				ValidationReasonType,
+				"field value is not of type integer",
+			)
 		}
 	} else if floatVal, ok := value.(*float32); ok {
 		val = int64(*floatVal)
 		if *floatVal != float32(val) {
-			return NewValidationError(context, "field value is not of type integer")
+			return newValidationError(
+				context,
+				ValidationReasonType,
+				"field value is not of type integer",
+			)
 		}
 	} else if intVal, ok := value.(int64); ok {
 		val = intVal
@@ -276,18 +440,31 @@ func (i IntPropertySchema) validate(context string, value interface{}) error {
 	} else if intVal, ok := value.(*int); ok {
 		val = int64(*intVal)
 	} else {
-		return NewValidationError(context, "field value is not of type integer")
+		return newValidationError(
+			context,
+			ValidationReasonType,
+			"field value is not of type integer",
+		)
 	}
+	errs := ValidationErrors{}
 	if i.MinValue != nil && val < *i.MinValue {
-		return NewValidationError(
-			context,
-			fmt.Sprintf("field value is less than minimum %d", *i.MinValue),
+		errs = append(
+			errs,
+			newValidationError(
+				context,
+				ValidationReasonMinimum,
+				fmt.Sprintf("field value is less than minimum %d", *i.MinValue),
+			),
 		)
 	}
 	if i.MaxValue != nil && val > *i.MaxValue {
-		return NewValidationError(
-			context,
-			fmt.Sprintf("field value is greater than maximum %d", *i.MaxValue),
+		errs = append(
+			errs,
+			newValidationError(
+				context,
+				ValidationReasonMaximum,
+				fmt.Sprintf("field value is greater than maximum %d", *i.MaxValue),
+			),
 		)
 	}
 	if len(i.AllowedValues) > 0 {
@@ -299,15 +476,41 @@ func (i IntPropertySchema) validate(context string, value interface{}) error {
 			}
 		}
 		if !found {
-			return NewValidationError(context, "field value is invalid")
+			errs = append(
+				errs,
+				newValidationError(
+					context,
+					ValidationReasonEnum,
+					"field value is invalid",
+				),
+			)
 		}
 	}
 	if i.AllowedIncrement != nil && val%*i.AllowedIncrement != 0 {
-		return NewValidationError(
-			context,
-			fmt.Sprintf("field value is not a multiple of %d", *i.AllowedIncrement),
+		errs = append(
+			errs,
+			newValidationError(
+				context,
+				ValidationReasonMultipleOf,
+				fmt.Sprintf(
+					"field value is not a multiple of %d",
+					*i.AllowedIncrement,
+				),
+			),
 		)
 	}
+	var err error
+	errs, err = collectValidationErrors(
+		errs,
+		validateComposition(context, value, i.AllOf, i.AnyOf, i.OneOf),
+	)
+	if err != nil {
+		return err
+	}
+	// Custom validation is only applied to values that are otherwise valid
+	if len(errs) > 0 {
+		return errs
+	}
 	if i.CustomPropertyValidator != nil {
 		return i.CustomPropertyValidator(context, val)
 	}
@@ -333,6 +536,9 @@ type FloatPropertySchema struct {
 	AllowedIncrement        *float64                     `json:"multipleOf,omitempty"` // nolint: lll
 	CustomPropertyValidator CustomFloatPropertyValidator `json:"-"`
 	DefaultValue            *float64                     `json:"default,omitempty"` // nolint: lll
+	AllOf                   []PropertySchema             `json:"allOf,omitempty"`
+	AnyOf                   []PropertySchema             `json:"anyOf,omitempty"`
+	OneOf                   []PropertySchema             `json:"oneOf,omitempty"`
 }
 
 // MarshalJSON provides functionality to marshal a FloatPropertySchema to JSON
@@ -363,18 +569,31 @@ func (f FloatPropertySchema) validate(context string, value interface{}) error {
 	} else if floatVal, ok := value.(*float32); ok {
 		val = float64(*floatVal)
 	} else {
-		return NewValidationError(context, "field value is not of type float")
+		return newValidationError(
+			context,
+			ValidationReasonType,
+			"field value is not of type float",
+		)
 	}
+	errs := ValidationErrors{}
 	if f.MinValue != nil && val < *f.MinValue {
-		return NewValidationError(
-			context,
-			fmt.Sprintf("field value is less than minimum %f", *f.MinValue),
+		errs = append(
+			errs,
+			newValidationError(
+				context,
+				ValidationReasonMinimum,
+				fmt.Sprintf("field value is less than minimum %f", *f.MinValue),
+			),
 		)
 	}
 	if f.MaxValue != nil && val > *f.MaxValue {
-		return NewValidationError(
-			context,
-			fmt.Sprintf("field value is greater than maximum %f", *f.MaxValue),
+		errs = append(
+			errs,
+			newValidationError(
+				context,
+				ValidationReasonMaximum,
+				fmt.Sprintf("field value is greater than maximum %f", *f.MaxValue),
+			),
 		)
 	}
 	if len(f.AllowedValues) > 0 {
@@ -386,7 +605,14 @@ func (f FloatPropertySchema) validate(context string, value interface{}) error {
 			}
 		}
 		if !found {
-			return NewValidationError(context, "field value is invalid")
+			errs = append(
+				errs,
+				newValidationError(
+					context,
+					ValidationReasonEnum,
+					"field value is invalid",
+				),
+			)
 		}
 	}
 	// krancour: Currently not supported because of floating point division
@@ -398,6 +624,18 @@ func (f FloatPropertySchema) validate(context string, value interface{}) error {
 	// 		fmt.Sprintf("field value is not a multiple of %f", *f.AllowedIncrement),
 	// 	)
 	// }
+	var err error
+	errs, err = collectValidationErrors(
+		errs,
+		validateComposition(context, value, f.AllOf, f.AnyOf, f.OneOf),
+	)
+	if err != nil {
+		return err
+	}
+	// Custom validation is only applied to values that are otherwise valid
+	if len(errs) > 0 {
+		return errs
+	}
 	if f.CustomPropertyValidator != nil {
 		return f.CustomPropertyValidator(context, val)
 	}
@@ -431,6 +669,13 @@ type ObjectPropertySchema struct {
 	Additional              PropertySchema                `json:"additionalProperties,omitempty"` // nolint: lll
 	CustomPropertyValidator CustomObjectPropertyValidator `json:"-"`
 	DefaultValue            map[string]interface{}        `json:"-"`
+	// Dependencies maps the names of properties to either a PropertyDependency
+	// or a schema that the object must additionally satisfy whenever that
+	// property is present
+	Dependencies map[string]PropertySchema `json:"dependencies,omitempty"`
+	AllOf        []PropertySchema          `json:"allOf,omitempty"`
+	AnyOf        []PropertySchema          `json:"anyOf,omitempty"`
+	OneOf        []PropertySchema          `json:"oneOf,omitempty"`
 }
 
 // GetPropertySchemas returns a map of subordinate property schemas
@@ -469,29 +714,42 @@ func (o ObjectPropertySchema) validate(
 	}
 	valMap, ok := value.(map[string]interface{})
 	if !ok {
-		return NewValidationError(context, "field value is not of type object")
+		return newValidationError(
+			context,
+			ValidationReasonType,
+			"field value is not of type object",
+		)
 	}
-	for _, requiredProperty := range o.RequiredProperties {
-		_, ok := valMap[requiredProperty]
-		if !ok {
-			propertyContext := fmt.Sprintf("%s.%s", context, requiredProperty)
-			return NewValidationError(propertyContext, "field is required")
-		}
+	errs, err := collectValidationErrors(
+		ValidationErrors{},
+		validateProperties(
+			context,
+			o.RequiredProperties,
+			o.PropertySchemas,
+			o.Additional,
+			valMap,
+		),
+	)
+	if err != nil {
+		return err
 	}
-	for k, v := range valMap {
-		propertySchema, ok := o.PropertySchemas[k]
-		propertyContext := fmt.Sprintf("%s.%s", context, k)
-		if ok {
-			if err := propertySchema.validate(propertyContext, v); err != nil {
-				return err
-			}
-		} else if o.Additional == nil {
-			return NewValidationError(propertyContext, "unrecognized field")
-		} else {
-			if err := o.Additional.validate(propertyContext, v); err != nil {
-				return err
-			}
-		}
+	errs, err = collectValidationErrors(
+		errs,
+		validateDependencies(context, o.Dependencies, valMap),
+	)
+	if err != nil {
+		return err
+	}
+	errs, err = collectValidationErrors(
+		errs,
+		validateComposition(context, value, o.AllOf, o.AnyOf, o.OneOf),
+	)
+	if err != nil {
+		return err
+	}
+	// Custom validation is only applied to objects that are otherwise valid
+	if len(errs) > 0 {
+		return errs
 	}
 	if o.CustomPropertyValidator != nil {
 		return o.CustomPropertyValidator(context, valMap)
@@ -515,6 +773,9 @@ type ArrayPropertySchema struct {
 	ItemsSchema             PropertySchema               `json:"items,omitempty"`
 	CustomPropertyValidator CustomArrayPropertyValidator `json:"-"`
 	DefaultValue            []interface{}                `json:"-"`
+	AllOf                   []PropertySchema             `json:"allOf,omitempty"`
+	AnyOf                   []PropertySchema             `json:"anyOf,omitempty"`
+	OneOf                   []PropertySchema             `json:"oneOf,omitempty"`
 }
 
 // MarshalJSON provides functionality to marshal an
@@ -536,31 +797,62 @@ func (a ArrayPropertySchema) validate(context string, value interface{}) error {
 	}
 	valArray, ok := value.([]interface{})
 	if !ok {
-		return NewValidationError(context, "field value is not of type array")
+		return newValidationError(
+			context,
+			ValidationReasonType,
+			"field value is not of type array",
+		)
 	}
+	errs := ValidationErrors{}
 	if a.MinItems != nil && len(valArray) < *a.MinItems {
-		return NewValidationError(
-			context,
-			fmt.Sprintf("field contains fewer than minimum elements %d", *a.MinItems),
+		errs = append(
+			errs,
+			newValidationError(
+				context,
+				ValidationReasonMinItems,
+				fmt.Sprintf(
+					"field contains fewer than minimum elements %d",
+					*a.MinItems,
+				),
+			),
 		)
 	}
 	if a.MaxItems != nil && len(valArray) > *a.MaxItems {
-		return NewValidationError(
-			context,
-			fmt.Sprintf(
-				"field contains greater than maximum elements %d",
-				*a.MaxItems,
+		errs = append(
+			errs,
+			newValidationError(
+				context,
+				ValidationReasonMaxItems,
+				fmt.Sprintf(
+					"field contains greater than maximum elements %d",
+					*a.MaxItems,
+				),
 			),
 		)
 	}
+	var err error
 	if a.ItemsSchema != nil {
 		for i, val := range valArray {
-			itemContext := fmt.Sprintf("%s[%d]", context, i)
-			if err := a.ItemsSchema.validate(itemContext, val); err != nil {
+			errs, err = collectValidationErrors(
+				errs,
+				a.ItemsSchema.validate(getJSONPointer(context, strconv.Itoa(i)), val),
+			)
+			if err != nil {
 				return err
 			}
 		}
 	}
+	errs, err = collectValidationErrors(
+		errs,
+		validateComposition(context, value, a.AllOf, a.AnyOf, a.OneOf),
+	)
+	if err != nil {
+		return err
+	}
+	// Custom validation is only applied to arrays whose elements are valid
+	if len(errs) > 0 {
+		return errs
+	}
 	if a.CustomPropertyValidator != nil {
 		return a.CustomPropertyValidator(context, valArray)
 	}
@@ -0,0 +1,202 @@
+package service
+
+import (
+	"fmt"
+	"sort"
+)
+
+// PropertyDependency is used in the dependencies of an object to enumerate
+// the names of properties that are required whenever the property it is
+// keyed by is present
+type PropertyDependency []string
+
+// validate is a no-op. Property dependencies are evaluated by the object that
+// contains them, which, unlike the dependency itself, knows which property
+// they are keyed by.
+func (PropertyDependency) validate(string, interface{}) error {
+	return nil
+}
+
+// ObjectConstraintSchema represents schema that further constrains an object
+// without (like an ObjectPropertySchema) specifying its type or disallowing
+// additional properties. It is intended for use in the dependencies of an
+// object or with allOf, anyOf, or oneOf to describe conditional requirements.
+type ObjectConstraintSchema struct {
+	Title              string                    `json:"title,omitempty"`
+	Description        string                    `json:"description,omitempty"` // nolint: lll
+	RequiredProperties []string                  `json:"required,omitempty"`
+	PropertySchemas    map[string]PropertySchema `json:"properties,omitempty"`
+}
+
+func (o ObjectConstraintSchema) validate(
+	context string,
+	value interface{},
+) error {
+	valMap, ok := value.(map[string]interface{})
+	if !ok {
+		// Without a type, this schema places no constraints on non-objects
+		return nil
+	}
+	errs := ValidationErrors{}
+	for _, requiredProperty := range o.RequiredProperties {
+		if _, ok := valMap[requiredProperty]; !ok {
+			errs = append(
+				errs,
+				newValidationError(
+					getJSONPointer(context, requiredProperty),
+					ValidationReasonRequired,
+					"field is required",
+				),
+			)
+		}
+	}
+	keys := make([]string, 0, len(o.PropertySchemas))
+	for k := range o.PropertySchemas {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	var err error
+	for _, k := range keys {
+		v, ok := valMap[k]
+		if !ok {
+			continue
+		}
+		errs, err = collectValidationErrors(
+			errs,
+			o.PropertySchemas[k].validate(getJSONPointer(context, k), v),
+		)
+		if err != nil {
+			return err
+		}
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+// validateDependencies validates an object against the dependencies of each
+// of its properties that is present
+func validateDependencies(
+	context string,
+	dependencies map[string]PropertySchema,
+	valMap map[string]interface{},
+) error {
+	keys := make([]string, 0, len(dependencies))
+	for k := range dependencies {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	errs := ValidationErrors{}
+	var err error
+	for _, k := range keys {
+		if _, ok := valMap[k]; !ok {
+			continue
+		}
+		propertyDependency, ok := dependencies[k].(PropertyDependency)
+		if !ok {
+			errs, err = collectValidationErrors(
+				errs,
+				dependencies[k].validate(context, valMap),
+			)
+			if err != nil {
+				return err
+			}
+			continue
+		}
+		for _, requiredProperty := range propertyDependency {
+			if _, ok := valMap[requiredProperty]; !ok {
+				errs = append(
+					errs,
+					newValidationError(
+						getJSONPointer(context, requiredProperty),
+						ValidationReasonDependencies,
+						fmt.Sprintf(`field is required when "%s" is specified`, k),
+					),
+				)
+			}
+		}
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+// validateComposition validates a value against the schemas it must match
+// all of, any of, and exactly one of
+func validateComposition(
+	context string,
+	value interface{},
+	allOf []PropertySchema,
+	anyOf []PropertySchema,
+	oneOf []PropertySchema,
+) error {
+	errs := ValidationErrors{}
+	var err error
+	for _, schema := range allOf {
+		errs, err = collectValidationErrors(errs, schema.validate(context, value))
+		if err != nil {
+			return err
+		}
+	}
+	if len(anyOf) > 0 {
+		matches, err := countMatches(context, value, anyOf)
+		if err != nil {
+			return err
+		}
+		if matches == 0 {
+			errs = append(
+				errs,
+				newValidationError(
+					context,
+					ValidationReasonAnyOf,
+					"field value does not match any of the allowed schemas",
+				),
+			)
+		}
+	}
+	if len(oneOf) > 0 {
+		matches, err := countMatches(context, value, oneOf)
+		if err != nil {
+			return err
+		}
+		if matches != 1 {
+			errs = append(
+				errs,
+				newValidationError(
+					context,
+					ValidationReasonOneOf,
+					fmt.Sprintf(
+						"field value must match exactly one of the allowed schemas, "+
+							"but matches %d",
+						matches,
+					),
+				),
+			)
+		}
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+// countMatches returns the number of the given schemas that the given value
+// is valid against
+func countMatches(
+	context string,
+	value interface{},
+	schemas []PropertySchema,
+) (int, error) {
+	var matches int
+	for _, schema := range schemas {
+		err := schema.validate(context, value)
+		if err == nil {
+			matches++
+		} else if _, ok := GetValidationErrors(err); !ok {
+			return 0, err
+		}
+	}
+	return matches, nil
+}
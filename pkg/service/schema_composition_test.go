@@ -0,0 +1,228 @@
+package service
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/Azure/open-service-broker-azure/pkg/ptr"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateObjectPropertyDependencies(t *testing.T) {
+	const fieldName = "/xyz"
+	ops := ObjectPropertySchema{
+		PropertySchemas: map[string]PropertySchema{
+			"username": &StringPropertySchema{},
+			"password": &StringPropertySchema{},
+			"tier":     &StringPropertySchema{},
+			"cores":    &IntPropertySchema{},
+		},
+		Dependencies: map[string]PropertySchema{
+			"username": PropertyDependency{"password"},
+			"tier": ObjectConstraintSchema{
+				PropertySchemas: map[string]PropertySchema{
+					"cores": &IntPropertySchema{
+						MaxValue: ptr.ToInt64(4),
+					},
+				},
+			},
+		},
+	}
+	// This should fail validation because username requires password
+	err := ops.validate(
+		fieldName,
+		map[string]interface{}{
+			"username": "foo",
+		},
+	)
+	assert.NotNil(t, err)
+	validationErrs, ok := GetValidationErrors(err)
+	assert.True(t, ok)
+	assert.Equal(t, 1, len(validationErrs))
+	assert.Equal(t, "/xyz/password", validationErrs[0].Field)
+	assert.Equal(t, ValidationReasonDependencies, validationErrs[0].Reason)
+	// This should fail validation because, when tier is specified, cores must
+	// not exceed 4
+	err = ops.validate(
+		fieldName,
+		map[string]interface{}{
+			"tier":  "basic",
+			"cores": 8,
+		},
+	)
+	assert.NotNil(t, err)
+	validationErrs, ok = GetValidationErrors(err)
+	assert.True(t, ok)
+	assert.Equal(t, 1, len(validationErrs))
+	assert.Equal(t, "/xyz/cores", validationErrs[0].Field)
+	assert.Equal(t, ValidationReasonMaximum, validationErrs[0].Reason)
+	// These should pass validation
+	err = ops.validate(
+		fieldName,
+		map[string]interface{}{
+			"username": "foo",
+			"password": "bar",
+			"tier":     "basic",
+			"cores":    2,
+		},
+	)
+	assert.Nil(t, err)
+	err = ops.validate(
+		fieldName,
+		map[string]interface{}{
+			"password": "bar",
+			"cores":    8,
+		},
+	)
+	assert.Nil(t, err)
+}
+
+func TestValidateObjectPropertyComposition(t *testing.T) {
+	const fieldName = "/xyz"
+	ops := ObjectPropertySchema{
+		PropertySchemas: map[string]PropertySchema{
+			"subnetId":  &StringPropertySchema{},
+			"vnetName":  &StringPropertySchema{},
+			"ipAddress": &StringPropertySchema{},
+		},
+		// Exactly one of subnetId or vnetName must be specified
+		OneOf: []PropertySchema{
+			ObjectConstraintSchema{
+				RequiredProperties: []string{"subnetId"},
+			},
+			ObjectConstraintSchema{
+				RequiredProperties: []string{"vnetName"},
+			},
+		},
+		AllOf: []PropertySchema{
+			ObjectConstraintSchema{
+				PropertySchemas: map[string]PropertySchema{
+					"ipAddress": &StringPropertySchema{
+						Format: FormatIPv4,
+					},
+				},
+			},
+		},
+	}
+	// This should fail validation because neither is specified
+	err := ops.validate(fieldName, map[string]interface{}{})
+	assert.NotNil(t, err)
+	validationErrs, ok := GetValidationErrors(err)
+	assert.True(t, ok)
+	assert.Equal(t, 1, len(validationErrs))
+	assert.Equal(t, fieldName, validationErrs[0].Field)
+	assert.Equal(t, ValidationReasonOneOf, validationErrs[0].Reason)
+	// This should fail validation because both are specified and because the
+	// IP address isn't valid
+	err = ops.validate(
+		fieldName,
+		map[string]interface{}{
+			"subnetId":  "foo",
+			"vnetName":  "bar",
+			"ipAddress": "bogus",
+		},
+	)
+	assert.NotNil(t, err)
+	validationErrs, ok = GetValidationErrors(err)
+	assert.True(t, ok)
+	assert.Equal(t, 2, len(validationErrs))
+	assert.Equal(t, "/xyz/ipAddress", validationErrs[0].Field)
+	assert.Equal(t, ValidationReasonFormat, validationErrs[0].Reason)
+	assert.Equal(t, fieldName, validationErrs[1].Field)
+	assert.Equal(t, ValidationReasonOneOf, validationErrs[1].Reason)
+	// This should pass validation
+	err = ops.validate(
+		fieldName,
+		map[string]interface{}{
+			"subnetId":  "foo",
+			"ipAddress": "10.0.0.1",
+		},
+	)
+	assert.Nil(t, err)
+}
+
+func TestValidateIntPropertyAnyOf(t *testing.T) {
+	const fieldName = "/xyz"
+	ips := IntPropertySchema{
+		AnyOf: []PropertySchema{
+			&IntPropertySchema{
+				MaxValue: ptr.ToInt64(4),
+			},
+			&IntPropertySchema{
+				MinValue: ptr.ToInt64(16),
+			},
+		},
+	}
+	// This should fail validation because the value matches neither schema
+	err := ips.validate(fieldName, 8)
+	assert.NotNil(t, err)
+	validationErrs, ok := GetValidationErrors(err)
+	assert.True(t, ok)
+	assert.Equal(t, 1, len(validationErrs))
+	assert.Equal(t, ValidationReasonAnyOf, validationErrs[0].Reason)
+	// These should pass validation
+	assert.Nil(t, ips.validate(fieldName, 2))
+	assert.Nil(t, ips.validate(fieldName, 32))
+}
+
+func TestInputParametersSchemaWithDependenciesToJSON(t *testing.T) {
+	ips := InputParametersSchema{
+		PropertySchemas: map[string]PropertySchema{
+			"username": &StringPropertySchema{},
+			"password": &StringPropertySchema{},
+			"endpoint": &StringPropertySchema{
+				Format: FormatURI,
+			},
+		},
+		Dependencies: map[string]PropertySchema{
+			"username": PropertyDependency{"password"},
+		},
+		AnyOf: []PropertySchema{
+			ObjectConstraintSchema{
+				RequiredProperties: []string{"username"},
+			},
+			ObjectConstraintSchema{
+				RequiredProperties: []string{"endpoint"},
+			},
+		},
+	}
+	jsonBytes, err := json.Marshal(ips)
+	assert.Nil(t, err)
+	// We'll unmarshal into a map (that should always work) and then we'll
+	// make assertions on the map to prove the JSON was what we'd expected it to
+	// be.
+	ipsMap := map[string]interface{}{}
+	err = json.Unmarshal(jsonBytes, &ipsMap)
+	assert.Nil(t, err)
+	params, ok := ipsMap["parameters"].(map[string]interface{})
+	assert.True(t, ok)
+	assert.Equal(
+		t,
+		map[string]interface{}{
+			"username": []interface{}{"password"},
+		},
+		params["dependencies"],
+	)
+	assert.Equal(
+		t,
+		[]interface{}{
+			map[string]interface{}{
+				"required": []interface{}{"username"},
+			},
+			map[string]interface{}{
+				"required": []interface{}{"endpoint"},
+			},
+		},
+		params["anyOf"],
+	)
+	properties, ok := params["properties"].(map[string]interface{})
+	assert.True(t, ok)
+	assert.Equal(
+		t,
+		map[string]interface{}{
+			"type":   "string",
+			"format": "uri",
+		},
+		properties["endpoint"],
+	)
+}
@@ -0,0 +1,58 @@
+package service
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// Formats that may be specified for string properties using the JSON schema
+// "format" keyword
+const (
+	// FormatIPv4 indicates a string must be an IPv4 address in dotted-quad
+	// notation
+	FormatIPv4 = "ipv4"
+	// FormatUUID indicates a string must be a UUID in its canonical,
+	// hyphenated form
+	FormatUUID = "uuid"
+	// FormatURI indicates a string must be an absolute URI
+	FormatURI = "uri"
+)
+
+var uuidRegex = regexp.MustCompile(
+	`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-` +
+		`[0-9a-fA-F]{12}$`,
+)
+
+// formatValidators maps each supported format to a function that determines
+// whether a string conforms to that format. Per the JSON schema spec, formats
+// that are not recognized are not validated.
+var formatValidators = map[string]func(string) bool{
+	FormatIPv4: func(value string) bool {
+		ip := net.ParseIP(value)
+		return ip != nil && ip.To4() != nil && !strings.Contains(value, ":")
+	},
+	FormatUUID: uuidRegex.MatchString,
+	FormatURI: func(value string) bool {
+		u, err := url.Parse(value)
+		return err == nil && u.IsAbs()
+	},
+}
+
+func validateFormat(
+	context string,
+	format string,
+	value string,
+) *ValidationError {
+	isValid, ok := formatValidators[format]
+	if !ok || isValid(value) {
+		return nil
+	}
+	return newValidationError(
+		context,
+		ValidationReasonFormat,
+		fmt.Sprintf("field value is not a valid %s", format),
+	)
+}
@@ -0,0 +1,54 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateStringPropertyFormats(t *testing.T) {
+	const fieldName = "/xyz"
+	testCases := []struct {
+		format  string
+		valid   []string
+		invalid []string
+	}{
+		{
+			format:  FormatIPv4,
+			valid:   []string{"10.0.0.1", "255.255.255.255"},
+			invalid: []string{"10.0.0", "10.0.0.256", "::1", "::ffff:10.0.0.1"},
+		},
+		{
+			format:  FormatUUID,
+			valid:   []string{"fe5f0b02-4b35-4e3e-9b9e-34a1f0e1d1c5"},
+			invalid: []string{"fe5f0b024b354e3e9b9e34a1f0e1d1c5", "foo"},
+		},
+		{
+			format:  FormatURI,
+			valid:   []string{"https://example.com/foo?bar=bat", "urn:foo:bar"},
+			invalid: []string{"/foo/bar", "example.com"},
+		},
+		{
+			// Unrecognized formats aren't validated
+			format: "bogus",
+			valid:  []string{"foo"},
+		},
+	}
+	for _, testCase := range testCases {
+		sps := StringPropertySchema{
+			Format: testCase.format,
+		}
+		for _, val := range testCase.valid {
+			assert.Nil(t, sps.validate(fieldName, val), val)
+		}
+		for _, val := range testCase.invalid {
+			err := sps.validate(fieldName, val)
+			assert.NotNil(t, err, val)
+			validationErrs, ok := GetValidationErrors(err)
+			assert.True(t, ok)
+			assert.Equal(t, 1, len(validationErrs))
+			assert.Equal(t, fieldName, validationErrs[0].Field)
+			assert.Equal(t, ValidationReasonFormat, validationErrs[0].Reason)
+		}
+	}
+}
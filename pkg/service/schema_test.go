@@ -24,9 +24,9 @@ func TestValidateInputParametersSchema(t *testing.T) {
 		},
 	)
 	assert.NotNil(t, err)
-	validationError, ok := err.(*ValidationError)
+	validationErrs, ok := GetValidationErrors(err)
 	assert.True(t, ok)
-	assert.Equal(t, "bat", validationError.Field)
+	assert.Equal(t, "/bat", validationErrs[0].Field)
 	// This should fail validation because a required property is missing
 	err = ips.Validate(
 		map[string]interface{}{
@@ -34,9 +34,9 @@ func TestValidateInputParametersSchema(t *testing.T) {
 		},
 	)
 	assert.NotNil(t, err)
-	validationError, ok = err.(*ValidationError)
+	validationErrs, ok = GetValidationErrors(err)
 	assert.True(t, ok)
-	assert.Equal(t, "foo", validationError.Field)
+	assert.Equal(t, "/foo", validationErrs[0].Field)
 	// This should fail validation because an unrecognized property is included
 	err = ips.Validate(
 		map[string]interface{}{
@@ -46,9 +46,9 @@ func TestValidateInputParametersSchema(t *testing.T) {
 		},
 	)
 	assert.NotNil(t, err)
-	validationError, ok = err.(*ValidationError)
+	validationErrs, ok = GetValidationErrors(err)
 	assert.True(t, ok)
-	assert.Equal(t, "bogus", validationError.Field)
+	assert.Equal(t, "/bogus", validationErrs[0].Field)
 	// This should pass validation
 	err = ips.Validate(
 		map[string]interface{}{
@@ -76,9 +76,9 @@ func TestValidateInputParametersSchema(t *testing.T) {
 		},
 	)
 	assert.NotNil(t, err)
-	validationError, ok = err.(*ValidationError)
+	validationErrs, ok = GetValidationErrors(err)
 	assert.True(t, ok)
-	assert.Equal(t, "foo", validationError.Field)
+	assert.Equal(t, "/foo", validationErrs[0].Field)
 	err = ips.Validate(
 		map[string]interface{}{
 			"foo": "bar",
@@ -87,9 +87,9 @@ func TestValidateInputParametersSchema(t *testing.T) {
 	)
 	assert.NotNil(t, err)
 	// This should fail validation because the value of bar is not a multiple of 2
-	validationError, ok = err.(*ValidationError)
+	validationErrs, ok = GetValidationErrors(err)
 	assert.True(t, ok)
-	assert.Equal(t, "bar", validationError.Field)
+	assert.Equal(t, "/bar", validationErrs[0].Field)
 	// This should pass validation
 	err = ips.Validate(
 		map[string]interface{}{
@@ -100,6 +100,89 @@ func TestValidateInputParametersSchema(t *testing.T) {
 	assert.Nil(t, err)
 }
 
+func TestValidateInputParametersSchemaReportsAllErrors(t *testing.T) {
+	ips := InputParametersSchema{
+		RequiredProperties: []string{"location"},
+		PropertySchemas: map[string]PropertySchema{
+			"location": &StringPropertySchema{},
+			"tiers": &IntPropertySchema{
+				MinValue:         ptr.ToInt64(2),
+				AllowedIncrement: ptr.ToInt64(2),
+			},
+			"firewallRules": &ArrayPropertySchema{
+				ItemsSchema: &ObjectPropertySchema{
+					RequiredProperties: []string{"name"},
+					PropertySchemas: map[string]PropertySchema{
+						"name": &StringPropertySchema{},
+						"startIPAddress": &StringPropertySchema{
+							AllowedPattern: `^[0-9.]+$`,
+						},
+					},
+				},
+			},
+			"tags": &ObjectPropertySchema{
+				Additional: &StringPropertySchema{},
+			},
+		},
+	}
+	err := ips.Validate(
+		map[string]interface{}{
+			"tiers": 1,
+			"firewallRules": []interface{}{
+				map[string]interface{}{
+					"name":           "good",
+					"startIPAddress": "10.0.0.1",
+				},
+				map[string]interface{}{
+					"startIPAddress": "bogus",
+				},
+			},
+			"tags": map[string]interface{}{
+				"a/b~c": 42,
+			},
+		},
+	)
+	assert.NotNil(t, err)
+	validationErrs, ok := err.(ValidationErrors)
+	assert.True(t, ok)
+	assert.Equal(
+		t,
+		ValidationErrors{
+			{
+				Field:  "/location",
+				Reason: ValidationReasonRequired,
+				Issue:  "field is required",
+			},
+			{
+				Field:  "/firewallRules/1/name",
+				Reason: ValidationReasonRequired,
+				Issue:  "field is required",
+			},
+			{
+				Field:  "/firewallRules/1/startIPAddress",
+				Reason: ValidationReasonPattern,
+				Issue:  "field value is invalid",
+			},
+			{
+				Field:  "/tags/a~1b~0c",
+				Reason: ValidationReasonType,
+				Issue:  "field value is not of type string",
+			},
+			{
+				Field:  "/tiers",
+				Reason: ValidationReasonMinimum,
+				Issue:  "field value is less than minimum 2",
+			},
+			{
+				Field:  "/tiers",
+				Reason: ValidationReasonMultipleOf,
+				Issue:  "field value is not a multiple of 2",
+			},
+		},
+		validationErrs,
+	)
+}
+
 func TestValidateInputParameterSchemaWithOneOfField(t *testing.T) {
 	ips := InputParametersSchema{
 		PropertySchemas: map[string]PropertySchema{
@@ -162,9 +245,9 @@ func TestValidateStringProperty(t *testing.T) {
 	// This should fail validation because the value is of the wrong type
 	err := sps.validate(fieldName, 5)
 	assert.NotNil(t, err)
-	validationError, ok := err.(*ValidationError)
+	validationErrs, ok := GetValidationErrors(err)
 	assert.True(t, ok)
-	assert.Equal(t, fieldName, validationError.Field)
+	assert.Equal(t, fieldName, validationErrs[0].Field)
 
 	sps = StringPropertySchema{
 		MinLength: ptr.ToInt(3),
@@ -172,9 +255,9 @@ func TestValidateStringProperty(t *testing.T) {
 	// This should fail validation because the value is too short
 	err = sps.validate(fieldName, "fo")
 	assert.NotNil(t, err)
-	validationError, ok = err.(*ValidationError)
+	validationErrs, ok = GetValidationErrors(err)
 	assert.True(t, ok)
-	assert.Equal(t, fieldName, validationError.Field)
+	assert.Equal(t, fieldName, validationErrs[0].Field)
 	// This should pass validation
 	err = sps.validate(fieldName, "foo")
 	assert.Nil(t, err)
@@ -188,9 +271,9 @@ func TestValidateStringProperty(t *testing.T) {
 	// This should fail validation because the value is too long
 	err = sps.validate(fieldName, "foobarr")
 	assert.NotNil(t, err)
-	validationError, ok = err.(*ValidationError)
+	validationErrs, ok = GetValidationErrors(err)
 	assert.True(t, ok)
-	assert.Equal(t, fieldName, validationError.Field)
+	assert.Equal(t, fieldName, validationErrs[0].Field)
 	// This should pass validation
 	err = sps.validate(fieldName, "foobar")
 	assert.Nil(t, err)
@@ -204,9 +287,9 @@ func TestValidateStringProperty(t *testing.T) {
 	// This should fail validation because the value isn't allowed
 	err = sps.validate(fieldName, "foobar")
 	assert.NotNil(t, err)
-	validationError, ok = err.(*ValidationError)
+	validationErrs, ok = GetValidationErrors(err)
 	assert.True(t, ok)
-	assert.Equal(t, fieldName, validationError.Field)
+	assert.Equal(t, fieldName, validationErrs[0].Field)
 	// This should pass validation
 	err = sps.validate(fieldName, "foo")
 	assert.Nil(t, err)
@@ -220,9 +303,9 @@ func TestValidateStringProperty(t *testing.T) {
 	// This should fail validation because the value does not match the regex
 	err = sps.validate(fieldName, "foobar")
 	assert.NotNil(t, err)
-	validationError, ok = err.(*ValidationError)
+	validationErrs, ok = GetValidationErrors(err)
 	assert.True(t, ok)
-	assert.Equal(t, fieldName, validationError.Field)
+	assert.Equal(t, fieldName, validationErrs[0].Field)
 	// This should pass validation
 	err = sps.validate(fieldName, "foo")
 	assert.Nil(t, err)
@@ -281,15 +364,15 @@ func TestValidateIntProperty(t *testing.T) {
 	// This should fail validation because the value is of the wrong type
 	err := ips.validate(fieldName, "foobar")
 	assert.NotNil(t, err)
-	validationError, ok := err.(*ValidationError)
+	validationErrs, ok := GetValidationErrors(err)
 	assert.True(t, ok)
-	assert.Equal(t, fieldName, validationError.Field)
+	assert.Equal(t, fieldName, validationErrs[0].Field)
 	// This should fail validation because the value is of the wrong type
 	err = ips.validate(fieldName, 3.14)
 	assert.NotNil(t, err)
-	validationError, ok = err.(*ValidationError)
+	validationErrs, ok = GetValidationErrors(err)
 	assert.True(t, ok)
-	assert.Equal(t, fieldName, validationError.Field)
+	assert.Equal(t, fieldName, validationErrs[0].Field)
 	// This should pass validation-- nil == no value == valid
 	err = ips.validate(fieldName, nil)
 	assert.Nil(t, err)
@@ -300,9 +383,9 @@ func TestValidateIntProperty(t *testing.T) {
 	// This should fail validation because the value is too small
 	err = ips.validate(fieldName, 2)
 	assert.NotNil(t, err)
-	validationError, ok = err.(*ValidationError)
+	validationErrs, ok = GetValidationErrors(err)
 	assert.True(t, ok)
-	assert.Equal(t, fieldName, validationError.Field)
+	assert.Equal(t, fieldName, validationErrs[0].Field)
 	// This should pass validation
 	err = ips.validate(fieldName, 3)
 	assert.Nil(t, err)
@@ -316,9 +399,9 @@ func TestValidateIntProperty(t *testing.T) {
 	// This should fail validation because the value is too large
 	err = ips.validate(fieldName, 7)
 	assert.NotNil(t, err)
-	validationError, ok = err.(*ValidationError)
+	validationErrs, ok = GetValidationErrors(err)
 	assert.True(t, ok)
-	assert.Equal(t, fieldName, validationError.Field)
+	assert.Equal(t, fieldName, validationErrs[0].Field)
 	// This should pass validation
 	err = ips.validate(fieldName, 6)
 	assert.Nil(t, err)
@@ -332,9 +415,9 @@ func TestValidateIntProperty(t *testing.T) {
 	// This should fail validation because the value isn't allowed
 	err = ips.validate(fieldName, 5)
 	assert.NotNil(t, err)
-	validationError, ok = err.(*ValidationError)
+	validationErrs, ok = GetValidationErrors(err)
 	assert.True(t, ok)
-	assert.Equal(t, fieldName, validationError.Field)
+	assert.Equal(t, fieldName, validationErrs[0].Field)
 	// This should pass validation
 	err = ips.validate(fieldName, 3)
 	assert.Nil(t, err)
@@ -348,9 +431,9 @@ func TestValidateIntProperty(t *testing.T) {
 	// This should fail validation because the value is not a multiple of 2
 	err = ips.validate(fieldName, 5)
 	assert.NotNil(t, err)
-	validationError, ok = err.(*ValidationError)
+	validationErrs, ok = GetValidationErrors(err)
 	assert.True(t, ok)
-	assert.Equal(t, fieldName, validationError.Field)
+	assert.Equal(t, fieldName, validationErrs[0].Field)
 	// This should pass validation
 	err = ips.validate(fieldName, 0)
 	assert.Nil(t, err)
@@ -403,9 +486,9 @@ func TestValidateFloatProperty(t *testing.T) {
 	// This should fail validation because the value is of the wrong type
 	err := fps.validate(fieldName, "foobar")
 	assert.NotNil(t, err)
-	validationError, ok := err.(*ValidationError)
+	validationErrs, ok := GetValidationErrors(err)
 	assert.True(t, ok)
-	assert.Equal(t, fieldName, validationError.Field)
+	assert.Equal(t, fieldName, validationErrs[0].Field)
 	// This should pass validation-- nil == no value == valid
 	err = fps.validate(fieldName, nil)
 	assert.Nil(t, err)
@@ -416,9 +499,9 @@ func TestValidateFloatProperty(t *testing.T) {
 	// This should fail validation because the value is too small
 	err = fps.validate(fieldName, 2.5)
 	assert.NotNil(t, err)
-	validationError, ok = err.(*ValidationError)
+	validationErrs, ok = GetValidationErrors(err)
 	assert.True(t, ok)
-	assert.Equal(t, fieldName, validationError.Field)
+	assert.Equal(t, fieldName, validationErrs[0].Field)
 	// This should pass validation
 	err = fps.validate(fieldName, 3.5)
 	assert.Nil(t, err)
@@ -432,9 +515,9 @@ func TestValidateFloatProperty(t *testing.T) {
 	// This should fail validation because the value is too large
 	err = fps.validate(fieldName, 3.5)
 	assert.NotNil(t, err)
-	validationError, ok = err.(*ValidationError)
+	validationErrs, ok = GetValidationErrors(err)
 	assert.True(t, ok)
-	assert.Equal(t, fieldName, validationError.Field)
+	assert.Equal(t, fieldName, validationErrs[0].Field)
 	// This should pass validation
 	err = fps.validate(fieldName, 3.0)
 	assert.Nil(t, err)
@@ -448,9 +531,9 @@ func TestValidateFloatProperty(t *testing.T) {
 	// This should fail validation because the value isn't allowed
 	err = fps.validate(fieldName, 5.0)
 	assert.NotNil(t, err)
-	validationError, ok = err.(*ValidationError)
+	validationErrs, ok = GetValidationErrors(err)
 	assert.True(t, ok)
-	assert.Equal(t, fieldName, validationError.Field)
+	assert.Equal(t, fieldName, validationErrs[0].Field)
 	// This should pass validation
 	err = fps.validate(fieldName, 3.14)
 	assert.Nil(t, err)
@@ -500,9 +583,9 @@ func TestValidateArrayProperty(t *testing.T) {
 	// This should fail validation because the value is of the wrong type
 	err := aps.validate(fieldName, "foobar")
 	assert.NotNil(t, err)
-	validationError, ok := err.(*ValidationError)
+	validationErrs, ok := GetValidationErrors(err)
 	assert.True(t, ok)
-	assert.Equal(t, fieldName, validationError.Field)
+	assert.Equal(t, fieldName, validationErrs[0].Field)
 	// This should pass validation-- nil == no value == valid
 	err = aps.validate(fieldName, nil)
 	assert.Nil(t, err)
@@ -513,9 +596,9 @@ func TestValidateArrayProperty(t *testing.T) {
 	// This should fail validation because the value contains too few elements
 	err = aps.validate(fieldName, []interface{}{1, 2})
 	assert.NotNil(t, err)
-	validationError, ok = err.(*ValidationError)
+	validationErrs, ok = GetValidationErrors(err)
 	assert.True(t, ok)
-	assert.Equal(t, fieldName, validationError.Field)
+	assert.Equal(t, fieldName, validationErrs[0].Field)
 	// This should pass validation
 	err = aps.validate(fieldName, []interface{}{1, 2, 3})
 	assert.Nil(t, err)
@@ -529,9 +612,9 @@ func TestValidateArrayProperty(t *testing.T) {
 	// This should fail validation because the value contains too many elements
 	err = aps.validate(fieldName, []interface{}{1, 2, 3, 4, 5, 6, 7})
 	assert.NotNil(t, err)
-	validationError, ok = err.(*ValidationError)
+	validationErrs, ok = GetValidationErrors(err)
 	assert.True(t, ok)
-	assert.Equal(t, fieldName, validationError.Field)
+	assert.Equal(t, fieldName, validationErrs[0].Field)
 	// This should pass validation
 	err = aps.validate(fieldName, []interface{}{1, 2, 3, 4, 5, 6})
 	assert.Nil(t, err)
@@ -547,9 +630,9 @@ func TestValidateArrayProperty(t *testing.T) {
 	// This should fail validation because the value contains elements < 3
 	err = aps.validate(fieldName, []interface{}{3.0, 2.0, 1.0})
 	assert.NotNil(t, err)
-	validationError, ok = err.(*ValidationError)
+	validationErrs, ok = GetValidationErrors(err)
 	assert.True(t, ok)
-	assert.Equal(t, fmt.Sprintf("%s[1]", fieldName), validationError.Field)
+	assert.Equal(t, fmt.Sprintf("%s/1", fieldName), validationErrs[0].Field)
 	// This should pass validation
 	err = aps.validate(fieldName, []interface{}{3.0, 4.0, 5.0})
 	assert.Nil(t, err)
@@ -609,9 +692,9 @@ func TestValidateObjectProperty(t *testing.T) {
 	// This should fail validation because the value is of the wrong type
 	err := ops.validate(fieldName, "foobar")
 	assert.NotNil(t, err)
-	validationError, ok := err.(*ValidationError)
+	validationErrs, ok := GetValidationErrors(err)
 	assert.True(t, ok)
-	assert.Equal(t, fieldName, validationError.Field)
+	assert.Equal(t, fieldName, validationErrs[0].Field)
 	// This should pass validation-- nil == no value == valid
 	err = ops.validate(fieldName, nil)
 	assert.Nil(t, err)
@@ -631,9 +714,9 @@ func TestValidateObjectProperty(t *testing.T) {
 		},
 	)
 	assert.NotNil(t, err)
-	validationError, ok = err.(*ValidationError)
+	validationErrs, ok = GetValidationErrors(err)
 	assert.True(t, ok)
-	assert.Equal(t, fmt.Sprintf("%s.bat", fieldName), validationError.Field)
+	assert.Equal(t, fmt.Sprintf("%s/bat", fieldName), validationErrs[0].Field)
 	// This should fail validation because a required property is missing
 	err = ops.validate(
 		fieldName,
@@ -642,9 +725,9 @@ func TestValidateObjectProperty(t *testing.T) {
 		},
 	)
 	assert.NotNil(t, err)
-	validationError, ok = err.(*ValidationError)
+	validationErrs, ok = GetValidationErrors(err)
 	assert.True(t, ok)
-	assert.Equal(t, fmt.Sprintf("%s.foo", fieldName), validationError.Field)
+	assert.Equal(t, fmt.Sprintf("%s/foo", fieldName), validationErrs[0].Field)
 	// This should fail validation because an unrecognized property is included
 	err = ops.validate(
 		fieldName,
@@ -655,9 +738,9 @@ func TestValidateObjectProperty(t *testing.T) {
 		},
 	)
 	assert.NotNil(t, err)
-	validationError, ok = err.(*ValidationError)
+	validationErrs, ok = GetValidationErrors(err)
 	assert.True(t, ok)
-	assert.Equal(t, fmt.Sprintf("%s.bogus", fieldName), validationError.Field)
+	assert.Equal(t, fmt.Sprintf("%s/bogus", fieldName), validationErrs[0].Field)
 	// This should pass validation
 	err = ops.validate(
 		fieldName,
@@ -687,9 +770,9 @@ func TestValidateObjectProperty(t *testing.T) {
 		},
 	)
 	assert.NotNil(t, err)
-	validationError, ok = err.(*ValidationError)
+	validationErrs, ok = GetValidationErrors(err)
 	assert.True(t, ok)
-	assert.Equal(t, fmt.Sprintf("%s.foo", fieldName), validationError.Field)
+	assert.Equal(t, fmt.Sprintf("%s/foo", fieldName), validationErrs[0].Field)
 	err = ops.validate(
 		fieldName,
 		map[string]interface{}{
@@ -699,9 +782,9 @@ func TestValidateObjectProperty(t *testing.T) {
 	)
 	assert.NotNil(t, err)
 	// This should fail validation because the value of bar is not a multiple of 2
-	validationError, ok = err.(*ValidationError)
+	validationErrs, ok = GetValidationErrors(err)
 	assert.True(t, ok)
-	assert.Equal(t, fmt.Sprintf("%s.bar", fieldName), validationError.Field)
+	assert.Equal(t, fmt.Sprintf("%s/bar", fieldName), validationErrs[0].Field)
 	// This should pass validation
 	err = ops.validate(
 		fieldName,
@@ -724,9 +807,9 @@ func TestValidateObjectProperty(t *testing.T) {
 		},
 	)
 	assert.NotNil(t, err)
-	validationError, ok = err.(*ValidationError)
+	validationErrs, ok = GetValidationErrors(err)
 	assert.True(t, ok)
-	assert.Equal(t, fmt.Sprintf("%s.foo", fieldName), validationError.Field)
+	assert.Equal(t, fmt.Sprintf("%s/foo", fieldName), validationErrs[0].Field)
 	// This should pass validation
 	err = ops.validate(
 		fieldName,
@@ -0,0 +1,107 @@
+package service_test
+
+import (
+	"reflect"
+	"regexp"
+	"testing"
+
+	"github.com/Azure/go-autorest/autorest/azure"
+	osbaAzure "github.com/Azure/open-service-broker-azure/pkg/azure"
+	"github.com/Azure/open-service-broker-azure/pkg/boot"
+	"github.com/Azure/open-service-broker-azure/pkg/service"
+	"github.com/stretchr/testify/assert"
+)
+
+// secretFieldNameRegex matches the names of fields that are likely to hold
+// secrets
+var secretFieldNameRegex = regexp.MustCompile(
+	`(?i)(password|secret|connectionstring|token|key)$`,
+)
+
+var secureStringTypes = map[reflect.Type]bool{
+	reflect.TypeOf(service.SecureString("")):         true,
+	reflect.TypeOf(service.MigratedSecureString("")): true,
+}
+
+// TestDetailsEncryptSecrets verifies that no module persists a secret-looking
+// field of its instance or binding details as a plain string
+func TestDetailsEncryptSecrets(t *testing.T) {
+	// Every module the broker runs is checked. None of the Azure clients are
+	// used, so no real credentials are needed, and no token is requested.
+	modules, err := boot.GetModules(
+		osbaAzure.Config{
+			Environment:    azure.PublicCloud,
+			SubscriptionID: "subscription",
+			TenantID:       "tenant",
+			ClientID:       "client",
+			ClientSecret:   "secret",
+		},
+	)
+	assert.Nil(t, err)
+	assert.NotEmpty(t, modules)
+	for _, module := range modules {
+		var catalog service.Catalog
+		catalog, err = module.GetCatalog()
+		assert.Nil(t, err)
+		for _, svc := range catalog.GetServices() {
+			sm := svc.GetServiceManager()
+			for _, details := range []interface{}{
+				sm.GetEmptyInstanceDetails(),
+				sm.GetEmptyBindingDetails(),
+			} {
+				if details == nil {
+					continue
+				}
+				for _, field := range getUnencryptedSecretFields(
+					reflect.TypeOf(details),
+					map[reflect.Type]bool{},
+				) {
+					t.Errorf(
+						`service "%s" of module "%s" persists unencrypted field "%s"`,
+						svc.GetName(),
+						module.GetName(),
+						field,
+					)
+				}
+			}
+		}
+	}
+}
+
+// getUnencryptedSecretFields walks the given type and returns the
+// qualified names of all string fields that look like secrets but are not
+// SecureStrings
+func getUnencryptedSecretFields(
+	typ reflect.Type,
+	visited map[reflect.Type]bool,
+) []string {
+	switch typ.Kind() {
+	case reflect.Ptr, reflect.Slice, reflect.Array, reflect.Map:
+		return getUnencryptedSecretFields(typ.Elem(), visited)
+	case reflect.Struct:
+	default:
+		return nil
+	}
+	if visited[typ] {
+		return nil
+	}
+	visited[typ] = true
+	fields := []string{}
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		if field.Tag.Get("json") == "-" {
+			continue
+		}
+		if field.Type.Kind() == reflect.String &&
+			!secureStringTypes[field.Type] &&
+			secretFieldNameRegex.MatchString(field.Name) {
+			fields = append(fields, typ.Name()+"."+field.Name)
+			continue
+		}
+		fields = append(
+			fields,
+			getUnencryptedSecretFields(field.Type, visited)...,
+		)
+	}
+	return fields
+}
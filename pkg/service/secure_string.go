@@ -1,35 +1,104 @@
 package service
 
 import (
+	"encoding/base64"
 	"encoding/json"
+	"fmt"
+	"strings"
 
 	"github.com/Azure/open-service-broker-azure/pkg/crypto"
 )
 
+// encryptedValuePrefix marks encrypted values. Values encrypted before this
+// marker was introduced carry no prefix and consist only of the base64 encoded
+// ciphertext. Neither base64 encoding nor the marker's absence is, on its own,
+// evidence that a value is plaintext, so only fields that are known to have
+// once been persisted as plaintext accept unmarked values as-is.
+const encryptedValuePrefix = "encrypted:v1:"
+
 // SecureString is a string that is seamlessly encrypted and decrypted when it
 // is, respectively, marshaled or unmarshaled
 type SecureString string
 
 // MarshalJSON converts a SecureString to JSON, encrypting it in the process
 func (s SecureString) MarshalJSON() ([]byte, error) {
-	encryptedBytes, err := crypto.Encrypt([]byte(string(s)))
+	encryptedStr, err := encrypt(string(s))
 	if err != nil {
 		return nil, err
 	}
-	return json.Marshal(encryptedBytes)
+	return json.Marshal(encryptedStr)
 }
 
 // UnmarshalJSON converts JSON to a SecureString, decrypting it in the process
 func (s *SecureString) UnmarshalJSON(bytes []byte) error {
-	var encryptedBytes []byte
-	err := json.Unmarshal(bytes, &encryptedBytes)
+	var str string
+	if err := json.Unmarshal(bytes, &str); err != nil {
+		return err
+	}
+	decryptedStr, err := decrypt(str, false)
 	if err != nil {
 		return err
 	}
-	decryptedBytes, err := crypto.Decrypt(encryptedBytes)
+	*s = SecureString(decryptedStr)
+	return nil
+}
+
+// MigratedSecureString is a SecureString for a field that was persisted as
+// plaintext before it was converted to a SecureString. It is encrypted exactly
+// as a SecureString is, but when it is unmarshaled, a value lacking the marker
+// that every encrypted value has carried since the conversion is known to be
+// legacy plaintext and is accepted as-is. It will be encrypted the next time
+// the record it belongs to is written.
+type MigratedSecureString string
+
+// MarshalJSON converts a MigratedSecureString to JSON, encrypting it in the
+// process
+func (s MigratedSecureString) MarshalJSON() ([]byte, error) {
+	return SecureString(s).MarshalJSON()
+}
+
+// UnmarshalJSON converts JSON to a MigratedSecureString, decrypting it in the
+// process if it is not legacy plaintext
+func (s *MigratedSecureString) UnmarshalJSON(bytes []byte) error {
+	var str string
+	if err := json.Unmarshal(bytes, &str); err != nil {
+		return err
+	}
+	decryptedStr, err := decrypt(str, true)
 	if err != nil {
 		return err
 	}
-	*s = SecureString(string(decryptedBytes))
+	*s = MigratedSecureString(decryptedStr)
 	return nil
 }
+
+// encrypt encrypts the given string and returns the ciphertext, base64
+// encoded and prefixed with the encrypted value marker
+func encrypt(str string) (string, error) {
+	encryptedBytes, err := crypto.Encrypt([]byte(str))
+	if err != nil {
+		return "", err
+	}
+	return encryptedValuePrefix +
+		base64.StdEncoding.EncodeToString(encryptedBytes), nil
+}
+
+// decrypt base64 decodes and decrypts the given string, with or without the
+// encrypted value marker. If, and only if, acceptPlaintext is true and the
+// marker is absent, the string is legacy plaintext and is returned unaltered.
+func decrypt(str string, acceptPlaintext bool) (string, error) {
+	if !strings.HasPrefix(str, encryptedValuePrefix) && acceptPlaintext {
+		return str, nil
+	}
+	encryptedBytes, err := base64.StdEncoding.DecodeString(
+		strings.TrimPrefix(str, encryptedValuePrefix),
+	)
+	if err != nil {
+		return "", fmt.Errorf("error decoding encrypted value: %s", err)
+	}
+	decryptedBytes, err := crypto.Decrypt(encryptedBytes)
+	if err != nil {
+		return "", fmt.Errorf("error decrypting encrypted value: %s", err)
+	}
+	return string(decryptedBytes), nil
+}
@@ -1,9 +1,13 @@
 package service
 
 import (
+	"encoding/base64"
 	"encoding/json"
+	"strings"
 	"testing"
 
+	"github.com/Azure/open-service-broker-azure/pkg/crypto"
+	"github.com/Azure/open-service-broker-azure/pkg/crypto/aes256"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -18,10 +22,77 @@ func TestMarshalAndUnmarshalJSON(t *testing.T) {
 	err = json.Unmarshal(jsonBytes, &strFromJSON)
 	assert.Nil(t, err)
 	assert.NotEqual(t, origStr, strFromJSON)
+	assert.True(t, strings.HasPrefix(strFromJSON, encryptedValuePrefix))
 	// Unmarshal into a secure string to assert decryption occurs during
 	// unmarshalling
 	var secureStr SecureString
 	err = json.Unmarshal(jsonBytes, &secureStr)
 	assert.Nil(t, err)
 	assert.Equal(t, origSecureStr, secureStr)
+	// Unmarshal into a migrated secure string to assert that a value with the
+	// encrypted value marker is never mistaken for legacy plaintext
+	var migratedSecureStr MigratedSecureString
+	err = json.Unmarshal(jsonBytes, &migratedSecureStr)
+	assert.Nil(t, err)
+	assert.Equal(t, MigratedSecureString(origStr), migratedSecureStr)
+}
+
+func TestUnmarshalJSONEncryptedWithoutMarker(t *testing.T) {
+	// Values encrypted before the encrypted value marker was introduced are
+	// only base64 encoded ciphertext
+	const origStr = "foo"
+	encryptedBytes, err := crypto.Encrypt([]byte(origStr))
+	assert.Nil(t, err)
+	jsonBytes, err := json.Marshal(encryptedBytes)
+	assert.Nil(t, err)
+	var secureStr SecureString
+	err = json.Unmarshal(jsonBytes, &secureStr)
+	assert.Nil(t, err)
+	assert.Equal(t, SecureString(origStr), secureStr)
+}
+
+func TestUnmarshalPlaintextJSON(t *testing.T) {
+	// Values persisted before a field was converted to a SecureString are
+	// plaintext-- including some that happen to be valid base64
+	for _, origStr := range []string{"foo", "Zm9vYmFy"} {
+		jsonBytes, err := json.Marshal(origStr)
+		assert.Nil(t, err)
+		var migratedSecureStr MigratedSecureString
+		err = json.Unmarshal(jsonBytes, &migratedSecureStr)
+		assert.Nil(t, err)
+		assert.Equal(t, MigratedSecureString(origStr), migratedSecureStr)
+		// Fields that have always been encrypted never hold plaintext
+		var secureStr SecureString
+		err = json.Unmarshal(jsonBytes, &secureStr)
+		assert.NotNil(t, err)
+	}
+}
+
+func TestUnmarshalJSONEncryptedWithWrongKey(t *testing.T) {
+	codec, err := aes256.NewCodec(
+		aes256.Config{
+			Key: "AES256Key-WrongKey-1234567890123",
+		},
+	)
+	assert.Nil(t, err)
+	encryptedBytes, err := codec.Encrypt([]byte("foo"))
+	assert.Nil(t, err)
+	encodedStr := base64.StdEncoding.EncodeToString(encryptedBytes)
+	for _, str := range []string{encodedStr, encryptedValuePrefix + encodedStr} {
+		var jsonBytes []byte
+		jsonBytes, err = json.Marshal(str)
+		assert.Nil(t, err)
+		var secureStr SecureString
+		err = json.Unmarshal(jsonBytes, &secureStr)
+		assert.NotNil(t, err)
+		assert.Empty(t, secureStr)
+	}
+	// A value with the encrypted value marker is never legacy plaintext, so it
+	// must be decrypted even if the field was once persisted as plaintext
+	jsonBytes, err := json.Marshal(encryptedValuePrefix + encodedStr)
+	assert.Nil(t, err)
+	var migratedSecureStr MigratedSecureString
+	err = json.Unmarshal(jsonBytes, &migratedSecureStr)
+	assert.NotNil(t, err)
+	assert.Empty(t, migratedSecureStr)
 }
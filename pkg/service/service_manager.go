@@ -1,5 +1,7 @@
 package service
 
+import "context"
+
 // ServiceManager is an interface to be implemented by module components
 // responsible for managing the lifecycle of services and plans thereof
 type ServiceManager interface { // nolint: golint
@@ -20,12 +22,12 @@ type ServiceManager interface { // nolint: golint
 	// can be populated with data during unmarshaling of JSON to a Binding
 	GetEmptyBindingDetails() BindingDetails
 	// Bind synchronously binds to a service
-	Bind(Instance, BindingParameters) (BindingDetails, error)
+	Bind(context.Context, Instance, BindingParameters) (BindingDetails, error)
 	// GetCredentials returns service-specific credentials populated from instance
 	// and binding details
 	GetCredentials(Instance, Binding) (Credentials, error)
 	// Unbind synchronously unbinds from a service
-	Unbind(Instance, Binding) error
+	Unbind(context.Context, Instance, Binding) error
 	// GetDeprovisioner returns a deprovisioner that defines the steps a module
 	// must execute asynchronously to deprovision a service
 	GetDeprovisioner(Plan) (Deprovisioner, error)
@@ -3,6 +3,7 @@ package appinsights
 import (
 	appInsightsSDK "github.com/Azure/azure-sdk-for-go/services/appinsights/mgmt/2015-05-01/insights" // nolint: lll
 	"github.com/Azure/open-service-broker-azure/pkg/azure/arm"
+	"github.com/Azure/open-service-broker-azure/pkg/health"
 	"github.com/Azure/open-service-broker-azure/pkg/service"
 )
 
@@ -35,3 +36,7 @@ func New(
 func (m *module) GetName() string {
 	return "appinsights"
 }
+
+func (m *module) GetHealthChecks() []health.Check {
+	return nil
+}
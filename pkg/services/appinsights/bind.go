@@ -12,6 +12,7 @@ import (
 )
 
 func (s *serviceManager) Bind(
+	_ context.Context,
 	instance service.Instance,
 	_ service.BindingParameters,
 ) (service.BindingDetails, error) {
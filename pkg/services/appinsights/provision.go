@@ -55,7 +55,7 @@ func (s *serviceManager) preProvision(
 }
 
 func (s *serviceManager) deployARMTemplate(
-	_ context.Context,
+	ctx context.Context,
 	instance service.Instance,
 ) (service.InstanceDetails, error) {
 	dt := instance.Details.(*instanceDetails)
@@ -68,6 +68,7 @@ func (s *serviceManager) deployARMTemplate(
 	applicationType :=
 		instance.Plan.GetProperties().Extended["applicationType"].(string)
 	outputs, err := s.armDeployer.Deploy(
+		ctx,
 		dt.ARMDeploymentName,
 		pp.GetString("resourceGroup"),
 		pp.GetString("location"),
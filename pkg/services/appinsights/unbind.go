@@ -8,6 +8,7 @@ import (
 )
 
 func (s *serviceManager) Unbind(
+	_ context.Context,
 	instance service.Instance,
 	binding service.Binding,
 ) error {
@@ -1,10 +1,13 @@
 package cosmosdb
 
 import (
+	"context"
+
 	"github.com/Azure/open-service-broker-azure/pkg/service"
 )
 
 func (c *cosmosAccountManager) Bind(
+	context.Context,
 	service.Instance,
 	service.BindingParameters,
 ) (service.BindingDetails, error) {
@@ -5,6 +5,7 @@ import (
 	"fmt"
 
 	"github.com/Azure/open-service-broker-azure/pkg/generate"
+	brokerLog "github.com/Azure/open-service-broker-azure/pkg/log"
 	"github.com/Azure/open-service-broker-azure/pkg/service"
 	log "github.com/Sirupsen/logrus"
 	uuid "github.com/satori/go.uuid"
@@ -13,7 +14,7 @@ import (
 const enabled = "enabled"
 const disabled = "disabled"
 
-func generateAccountName(location string) string {
+func generateAccountName(ctx context.Context, location string) string {
 	databaseAccountName := uuid.NewV4().String()
 	// CosmosDB currently limits database account names to 50 characters,
 	// which includes location and a - character. Check if we will
@@ -22,10 +23,9 @@ func generateAccountName(location string) string {
 	if effectiveNameLength > 49 {
 		nameLength := 49 - len(location)
 		databaseAccountName = generate.NewIdentifierOfLength(nameLength)
-		logFields := log.Fields{
-			"name":   databaseAccountName,
-			"length": len(databaseAccountName),
-		}
+		logFields := brokerLog.FieldsFromContext(ctx)
+		logFields["name"] = databaseAccountName
+		logFields["length"] = len(databaseAccountName)
 		log.WithFields(logFields).Debug(
 			"returning fallback database account name",
 		)
@@ -34,13 +34,13 @@ func generateAccountName(location string) string {
 }
 
 func (c *cosmosAccountManager) preProvision(
-	_ context.Context,
+	ctx context.Context,
 	instance service.Instance,
 ) (service.InstanceDetails, error) {
 	l := instance.ProvisioningParameters.GetString("location")
 	return &cosmosdbInstanceDetails{
 		ARMDeploymentName:   uuid.NewV4().String(),
-		DatabaseAccountName: generateAccountName(l),
+		DatabaseAccountName: generateAccountName(ctx, l),
 	}, nil
 }
 
@@ -99,12 +99,14 @@ func getTags(pp *service.ProvisioningParameters) map[string]string {
 }
 
 func (c *cosmosAccountManager) deployARMTemplate(
+	ctx context.Context,
 	pp *service.ProvisioningParameters,
 	dt *cosmosdbInstanceDetails,
 	goParams map[string]interface{},
 	tags map[string]string,
 ) (string, string, error) {
 	outputs, err := c.armDeployer.Deploy(
+		ctx,
 		dt.ARMDeploymentName,
 		pp.GetString("resourceGroup"),
 		pp.GetString("location"),
@@ -1,10 +1,13 @@
 package cosmosdb
 
 import (
+	"context"
+
 	"github.com/Azure/open-service-broker-azure/pkg/service"
 )
 
 func (c *cosmosAccountManager) Unbind(
+	_ context.Context,
 	_ service.Instance,
 	_ service.Binding,
 ) error {
@@ -11,11 +11,13 @@ import (
 )
 
 func deleteARMDeployment(
+	ctx context.Context,
 	armDeployer arm.Deployer,
 	pp *service.ProvisioningParameters,
 	dt *cosmosdbInstanceDetails,
 ) error {
 	if err := armDeployer.Delete(
+		ctx,
 		dt.ARMDeploymentName,
 		pp.GetString("resourceGroup"),
 	); err != nil {
@@ -66,10 +68,11 @@ func (c *cosmosAccountManager) GetDeprovisioner(
 }
 
 func (c *cosmosAccountManager) deleteARMDeployment(
-	_ context.Context,
+	ctx context.Context,
 	instance service.Instance,
 ) (service.InstanceDetails, error) {
 	if err := deleteARMDeployment(
+		ctx,
 		c.armDeployer,
 		instance.ProvisioningParameters,
 		instance.Details.(*cosmosdbInstanceDetails),
@@ -43,6 +43,7 @@ func (
 }
 
 func (c *cosmosAccountManager) updateDeployment(
+	ctx context.Context,
 	// up is updating parameters
 	up *service.ProvisioningParameters,
 	dt *cosmosdbInstanceDetails,
@@ -62,6 +63,7 @@ func (c *cosmosAccountManager) updateDeployment(
 		tags[k] = v
 	}
 	err = c.deployUpdatedARMTemplate(
+		ctx,
 		up,
 		dt,
 		p,
@@ -71,12 +73,14 @@ func (c *cosmosAccountManager) updateDeployment(
 }
 
 func (c *cosmosAccountManager) deployUpdatedARMTemplate(
+	ctx context.Context,
 	pp *service.ProvisioningParameters,
 	dt *cosmosdbInstanceDetails,
 	goParams map[string]interface{},
 	tags map[string]string,
 ) error {
 	_, err := c.armDeployer.Update(
+		ctx,
 		dt.ARMDeploymentName,
 		pp.GetString("resourceGroup"),
 		pp.GetString("location"),
@@ -260,13 +260,13 @@ func validateReadLocations(
 		region := regions[i]
 		if !allowedLocations[region] {
 			return service.NewValidationError(
-				fmt.Sprintf("%s.readRegions", context),
+				fmt.Sprintf("%s/readRegions", context),
 				fmt.Sprintf("given read region %s is not allowed", region),
 			)
 		}
 		if occurred[region] {
 			return service.NewValidationError(
-				fmt.Sprintf("%s.readRegions", context),
+				fmt.Sprintf("%s/readRegions", context),
 				fmt.Sprintf(
 					"given read region %s can only occur once",
 					region,
@@ -3,6 +3,7 @@ package cosmosdb
 import (
 	cosmosSDK "github.com/Azure/azure-sdk-for-go/services/cosmos-db/mgmt/2015-04-08/documentdb" // nolint: lll
 	"github.com/Azure/open-service-broker-azure/pkg/azure/arm"
+	"github.com/Azure/open-service-broker-azure/pkg/health"
 	"github.com/Azure/open-service-broker-azure/pkg/service"
 )
 
@@ -71,6 +72,10 @@ func (m *module) GetName() string {
 	return "cosmosdb"
 }
 
+func (m *module) GetHealthChecks() []health.Check {
+	return nil
+}
+
 func (m *module) GetStability() service.Stability {
 	return service.StabilityExperimental
 }
@@ -18,7 +18,7 @@ func (g *graphAccountManager) GetProvisioner(
 }
 
 func (g *graphAccountManager) deployARMTemplate(
-	_ context.Context,
+	ctx context.Context,
 	instance service.Instance,
 ) (service.InstanceDetails, error) {
 	pp := instance.ProvisioningParameters
@@ -35,6 +35,7 @@ func (g *graphAccountManager) deployARMTemplate(
 	tags := getTags(pp)
 	tags["defaultExperience"] = "Graph"
 	fqdn, pk, err := g.cosmosAccountManager.deployARMTemplate(
+		ctx,
 		pp,
 		dt,
 		p,
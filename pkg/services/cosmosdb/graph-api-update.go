@@ -17,10 +17,11 @@ func (
 }
 
 func (g *graphAccountManager) updateARMTemplate(
-	_ context.Context,
+	ctx context.Context,
 	instance service.Instance,
 ) (service.InstanceDetails, error) {
 	if err := g.cosmosAccountManager.updateDeployment(
+		ctx,
 		instance.UpdatingParameters,
 		instance.Details.(*cosmosdbInstanceDetails),
 		"GlobalDocumentDB",
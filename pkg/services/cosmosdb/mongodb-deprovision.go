@@ -19,10 +19,11 @@ func (m *mongoAccountManager) GetDeprovisioner(
 }
 
 func (m *mongoAccountManager) deleteARMDeployment(
-	_ context.Context,
+	ctx context.Context,
 	instance service.Instance,
 ) (service.InstanceDetails, error) {
 	if err := deleteARMDeployment(
+		ctx,
 		m.armDeployer,
 		instance.ProvisioningParameters,
 		instance.Details.(*cosmosdbInstanceDetails),
@@ -20,7 +20,7 @@ func (m *mongoAccountManager) GetProvisioner(
 }
 
 func (m *mongoAccountManager) deployARMTemplate(
-	_ context.Context,
+	ctx context.Context,
 	instance service.Instance,
 ) (service.InstanceDetails, error) {
 
@@ -41,6 +41,7 @@ func (m *mongoAccountManager) deployARMTemplate(
 	}
 	tags := getTags(pp)
 	fqdn, pk, err := m.cosmosAccountManager.deployARMTemplate(
+		ctx,
 		pp,
 		dt,
 		p,
@@ -17,10 +17,11 @@ func (
 }
 
 func (m *mongoAccountManager) updateARMTemplate(
-	_ context.Context,
+	ctx context.Context,
 	instance service.Instance,
 ) (service.InstanceDetails, error) {
 	if err := m.cosmosAccountManager.updateDeployment(
+		ctx,
 		instance.UpdatingParameters,
 		instance.Details.(*cosmosdbInstanceDetails),
 		"MongoDB",
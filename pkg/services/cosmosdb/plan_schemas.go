@@ -193,7 +193,7 @@ func consistencyPolicyValidator(
 		_, ok := valMap["boundedStaleness"].(map[string]interface{})
 		if !ok {
 			return service.NewValidationError(
-				fmt.Sprintf("%s.boundedStaleness", context),
+				fmt.Sprintf("%s/boundedStaleness", context),
 				"field is required",
 			)
 		}
@@ -18,7 +18,7 @@ func (s *sqlAccountManager) GetProvisioner(
 }
 
 func (s *sqlAccountManager) deployARMTemplate(
-	_ context.Context,
+	ctx context.Context,
 	instance service.Instance,
 ) (service.InstanceDetails, error) {
 
@@ -32,6 +32,7 @@ func (s *sqlAccountManager) deployARMTemplate(
 	tags["defaultExperience"] = "DocumentDB"
 
 	fqdn, pk, err := s.cosmosAccountManager.deployARMTemplate(
+		ctx,
 		pp,
 		dt,
 		p,
@@ -17,10 +17,11 @@ func (
 }
 
 func (s *sqlAccountManager) updateARMTemplate(
-	_ context.Context,
+	ctx context.Context,
 	instance service.Instance,
 ) (service.InstanceDetails, error) {
 	if err := s.cosmosAccountManager.updateDeployment(
+		ctx,
 		instance.UpdatingParameters,
 		instance.Details.(*cosmosdbInstanceDetails),
 		"GlobalDocumentDB",
@@ -21,11 +21,12 @@ func (s *sqlAllInOneManager) GetDeprovisioner(
 }
 
 func (s *sqlAllInOneManager) deleteARMDeployment(
-	_ context.Context,
+	ctx context.Context,
 	instance service.Instance,
 ) (service.InstanceDetails, error) {
 	dt := instance.Details.(*sqlAllInOneInstanceDetails)
 	if err := deleteARMDeployment(
+		ctx,
 		s.armDeployer,
 		instance.ProvisioningParameters,
 		&dt.cosmosdbInstanceDetails,
@@ -36,7 +36,7 @@ func (s *sqlAllInOneManager) preProvision(
 }
 
 func (s *sqlAllInOneManager) deployARMTemplate(
-	_ context.Context,
+	ctx context.Context,
 	instance service.Instance,
 ) (service.InstanceDetails, error) {
 
@@ -53,6 +53,7 @@ func (s *sqlAllInOneManager) deployARMTemplate(
 	tags := getTags(pp)
 	tags["defaultExperience"] = "DocumentDB"
 	fqdn, pk, err := s.cosmosAccountManager.deployARMTemplate(
+		ctx,
 		pp,
 		&dt.cosmosdbInstanceDetails,
 		p,
@@ -17,11 +17,12 @@ func (
 }
 
 func (s *sqlAllInOneManager) updateARMTemplate(
-	_ context.Context,
+	ctx context.Context,
 	instance service.Instance,
 ) (service.InstanceDetails, error) {
 	dt := instance.Details.(*sqlAllInOneInstanceDetails)
 	if err := s.cosmosAccountManager.updateDeployment(
+		ctx,
 		instance.UpdatingParameters,
 		&dt.cosmosdbInstanceDetails,
 		"GlobalDocumentDB",
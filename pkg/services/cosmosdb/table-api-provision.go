@@ -18,7 +18,7 @@ func (t *tableAccountManager) GetProvisioner(
 }
 
 func (t *tableAccountManager) deployARMTemplate(
-	_ context.Context,
+	ctx context.Context,
 	instance service.Instance,
 ) (service.InstanceDetails, error) {
 
@@ -32,6 +32,7 @@ func (t *tableAccountManager) deployARMTemplate(
 	tags := getTags(pp)
 	tags["defaultExperience"] = "Table"
 	fqdn, pk, err := t.cosmosAccountManager.deployARMTemplate(
+		ctx,
 		pp,
 		dt,
 		p,
@@ -17,10 +17,11 @@ func (
 }
 
 func (t *tableAccountManager) updateARMTemplate(
-	_ context.Context,
+	ctx context.Context,
 	instance service.Instance,
 ) (service.InstanceDetails, error) {
 	if err := t.cosmosAccountManager.updateDeployment(
+		ctx,
 		instance.UpdatingParameters,
 		instance.Details.(*cosmosdbInstanceDetails),
 		"GlobalDocumentDB",
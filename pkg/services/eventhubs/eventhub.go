@@ -3,6 +3,7 @@ package eventhubs
 import (
 	eventHubSDK "github.com/Azure/azure-sdk-for-go/services/eventhub/mgmt/2017-04-01/eventhub" // nolint: lll
 	"github.com/Azure/open-service-broker-azure/pkg/azure/arm"
+	"github.com/Azure/open-service-broker-azure/pkg/health"
 	"github.com/Azure/open-service-broker-azure/pkg/service"
 )
 
@@ -33,6 +34,10 @@ func (m *module) GetName() string {
 	return "eventhubs"
 }
 
+func (m *module) GetHealthChecks() []health.Check {
+	return nil
+}
+
 func (m *module) GetStability() service.Stability {
 	return service.StabilityExperimental
 }
@@ -3,6 +3,7 @@ package fake
 import (
 	"context"
 
+	"github.com/Azure/open-service-broker-azure/pkg/health"
 	"github.com/Azure/open-service-broker-azure/pkg/service"
 )
 
@@ -57,6 +58,11 @@ func (m *Module) GetName() string {
 	return "fake"
 }
 
+// GetHealthChecks returns checks of any components this module depends upon
+func (m *Module) GetHealthChecks() []health.Check {
+	return nil
+}
+
 // GetStability returns this module's relative stability
 func (m *Module) GetStability() service.Stability {
 	return service.StabilityStable
@@ -104,6 +110,7 @@ func (s *ServiceManager) update(
 
 // Bind synchronously binds to a service
 func (s *ServiceManager) Bind(
+	_ context.Context,
 	instance service.Instance,
 	bindingParameters service.BindingParameters,
 ) (service.BindingDetails, error) {
@@ -121,6 +128,7 @@ func (s *ServiceManager) GetCredentials(
 
 // Unbind synchronously unbinds from a service
 func (s *ServiceManager) Unbind(
+	_ context.Context,
 	instance service.Instance,
 	binding service.Binding,
 ) error {
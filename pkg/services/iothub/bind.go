@@ -1,12 +1,15 @@
 package iothub
 
 import (
+	"context"
+
 	"fmt"
 
 	"github.com/Azure/open-service-broker-azure/pkg/service"
 )
 
 func (i *iotHubManager) Bind(
+	context.Context,
 	service.Instance,
 	service.BindingParameters,
 ) (service.BindingDetails, error) {
@@ -12,6 +12,19 @@ const (
 	planF1 = "free"
 )
 
+// getMonthlyCosts returns the default costs of a plan that is priced at the
+// given number of US dollars per unit per month in the public cloud
+func getMonthlyCosts(usd float64) map[string][]service.PlanCost {
+	return map[string][]service.PlanCost{
+		"AzurePublicCloud": {
+			{
+				Amount: map[string]float64{"usd": usd},
+				Unit:   "MONTHLY",
+			},
+		},
+	}
+}
+
 func (m *module) GetCatalog() (service.Catalog, error) {
 	return service.NewCatalog([]service.Service{
 		service.NewService(
@@ -56,7 +69,8 @@ func (m *module) GetCatalog() (service.Catalog, error) {
 				Name: planB1,
 				Description: "IoT hub Basic B1 Tier - max 400,000 " +
 					"messages per unit per day.",
-				Free: false,
+				Free:             false,
+				EnvironmentCosts: getMonthlyCosts(10),
 				Metadata: service.ServicePlanMetadata{
 					DisplayName: "Basic B1 Tier",
 				},
@@ -71,7 +85,8 @@ func (m *module) GetCatalog() (service.Catalog, error) {
 				Name: planB2,
 				Description: "IoT hub Basic B2 Tier - max 6,000,000 " +
 					"messages per unit per day.",
-				Free: false,
+				Free:             false,
+				EnvironmentCosts: getMonthlyCosts(50),
 				Metadata: service.ServicePlanMetadata{
 					DisplayName: "Basic B2 Tier",
 				},
@@ -86,7 +101,8 @@ func (m *module) GetCatalog() (service.Catalog, error) {
 				Name: planB3,
 				Description: "IoT hub Basic B3 Tier - max 300,000,000 " +
 					"messages per unit per day.",
-				Free: false,
+				Free:             false,
+				EnvironmentCosts: getMonthlyCosts(500),
 				Metadata: service.ServicePlanMetadata{
 					DisplayName: "Basic B3 Tier",
 				},
@@ -101,7 +117,8 @@ func (m *module) GetCatalog() (service.Catalog, error) {
 				Name: planS1,
 				Description: "IoT hub Standard S1 Tier - max 400,000 " +
 					"messages per unit per day.",
-				Free: false,
+				Free:             false,
+				EnvironmentCosts: getMonthlyCosts(25),
 				Metadata: service.ServicePlanMetadata{
 					DisplayName: "Standard S1 Tier",
 				},
@@ -116,7 +133,8 @@ func (m *module) GetCatalog() (service.Catalog, error) {
 				Name: planS2,
 				Description: "IoT hub Standard S2 Tier - max 6,000,000 " +
 					"messages per unit per day.",
-				Free: false,
+				Free:             false,
+				EnvironmentCosts: getMonthlyCosts(250),
 				Metadata: service.ServicePlanMetadata{
 					DisplayName: "Standard S2 Tier",
 				},
@@ -131,7 +149,8 @@ func (m *module) GetCatalog() (service.Catalog, error) {
 				Name: planS3,
 				Description: "IoT hub Standard S3 Tier - max 300,000,000 " +
 					"messages per unit per day.",
-				Free: false,
+				Free:             false,
+				EnvironmentCosts: getMonthlyCosts(2500),
 				Metadata: service.ServicePlanMetadata{
 					DisplayName: "Standard S3 Tier",
 				},
@@ -20,12 +20,13 @@ func (i *iotHubManager) GetDeprovisioner(
 }
 
 func (i *iotHubManager) deleteARMDeployment(
-	_ context.Context,
+	ctx context.Context,
 	instance service.Instance,
 ) (service.InstanceDetails, error) {
 	dt := instance.Details.(*instanceDetails)
 
 	if err := i.armDeployer.Delete(
+		ctx,
 		dt.ARMDeploymentName,
 		instance.ProvisioningParameters.GetString("resourceGroup"),
 	); err != nil {
@@ -3,6 +3,7 @@ package iothub
 import (
 	iotHubSDK "github.com/Azure/azure-sdk-for-go/services/iothub/mgmt/2017-07-01/devices" // nolint: lll
 	"github.com/Azure/open-service-broker-azure/pkg/azure/arm"
+	"github.com/Azure/open-service-broker-azure/pkg/health"
 	"github.com/Azure/open-service-broker-azure/pkg/service"
 )
 
@@ -34,6 +35,10 @@ func (m *module) GetName() string {
 	return "iotHub"
 }
 
+func (m *module) GetHealthChecks() []health.Check {
+	return nil
+}
+
 func (m *module) GetStability() service.Stability {
 	return service.StabilityExperimental
 }
@@ -28,7 +28,7 @@ func (i *iotHubManager) preProvision(
 }
 
 func (i *iotHubManager) deployARMTemplate(
-	_ context.Context,
+	ctx context.Context,
 	instance service.Instance,
 ) (service.InstanceDetails, error) {
 	dt := instance.Details.(*instanceDetails)
@@ -39,6 +39,7 @@ func (i *iotHubManager) deployARMTemplate(
 	}
 
 	outputs, err := i.armDeployer.Deploy(
+		ctx,
 		dt.ARMDeploymentName,
 		instance.ProvisioningParameters.GetString("resourceGroup"),
 		instance.ProvisioningParameters.GetString("location"),
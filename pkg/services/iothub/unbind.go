@@ -1,10 +1,13 @@
 package iothub
 
 import (
+	"context"
+
 	"github.com/Azure/open-service-broker-azure/pkg/service"
 )
 
 func (i *iotHubManager) Unbind(
+	_ context.Context,
 	_ service.Instance,
 	_ service.Binding,
 ) error {
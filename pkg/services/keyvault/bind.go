@@ -1,10 +1,13 @@
 package keyvault
 
 import (
+	"context"
+
 	"github.com/Azure/open-service-broker-azure/pkg/service"
 )
 
 func (s *serviceManager) Bind(
+	context.Context,
 	service.Instance,
 	service.BindingParameters,
 ) (service.BindingDetails, error) {
@@ -20,11 +20,12 @@ func (s *serviceManager) GetDeprovisioner(
 }
 
 func (s *serviceManager) deleteARMDeployment(
-	_ context.Context,
+	ctx context.Context,
 	instance service.Instance,
 ) (service.InstanceDetails, error) {
 	dt := instance.Details.(*instanceDetails)
 	if err := s.armDeployer.Delete(
+		ctx,
 		dt.ARMDeploymentName,
 		instance.ProvisioningParameters.GetString("resourceGroup"),
 	); err != nil {
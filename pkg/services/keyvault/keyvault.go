@@ -3,6 +3,7 @@ package keyvault
 import (
 	keyVaultSDK "github.com/Azure/azure-sdk-for-go/services/keyvault/mgmt/2016-10-01/keyvault" // nolint: lll
 	"github.com/Azure/open-service-broker-azure/pkg/azure/arm"
+	"github.com/Azure/open-service-broker-azure/pkg/health"
 	"github.com/Azure/open-service-broker-azure/pkg/service"
 )
 
@@ -36,6 +37,10 @@ func (m *module) GetName() string {
 	return "keyvault"
 }
 
+func (m *module) GetHealthChecks() []health.Check {
+	return nil
+}
+
 func (m *module) GetStability() service.Stability {
 	return service.StabilityExperimental
 }
@@ -16,6 +16,12 @@ func (
 			"clientId",
 			"clientSecret",
 		},
+		SecureProperties: []string{
+			"clientSecret",
+		},
+		MigratedSecureProperties: []string{
+			"clientSecret",
+		},
 		PropertySchemas: map[string]service.PropertySchema{
 			"resourceGroup": schemas.GetResourceGroupSchema(),
 			"location":      schemas.GetLocationSchema(),
@@ -28,7 +28,7 @@ func (s *serviceManager) preProvision(
 }
 
 func (s *serviceManager) deployARMTemplate(
-	_ context.Context,
+	ctx context.Context,
 	instance service.Instance,
 ) (service.InstanceDetails, error) {
 	dt := instance.Details.(*instanceDetails)
@@ -39,6 +39,7 @@ func (s *serviceManager) deployARMTemplate(
 		tags[k] = tagsObj.GetString(k)
 	}
 	outputs, err := s.armDeployer.Deploy(
+		ctx,
 		dt.ARMDeploymentName,
 		pp.GetString("resourceGroup"),
 		pp.GetString("location"),
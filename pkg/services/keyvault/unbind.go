@@ -1,10 +1,13 @@
 package keyvault
 
 import (
+	"context"
+
 	"github.com/Azure/open-service-broker-azure/pkg/service"
 )
 
 func (s *serviceManager) Unbind(
+	_ context.Context,
 	_ service.Instance,
 	_ service.Binding,
 ) error {
@@ -1,15 +1,19 @@
 package mssql
 
 import (
+	"context"
+
 	"github.com/Azure/open-service-broker-azure/pkg/service"
 )
 
 func (a *allInOneManager) Bind(
+	ctx context.Context,
 	instance service.Instance,
 	_ service.BindingParameters,
 ) (service.BindingDetails, error) {
 	dt := instance.Details.(*allInOneInstanceDetails)
 	return bind(
+		ctx,
 		dt.AdministratorLogin,
 		string(dt.AdministratorLoginPassword),
 		dt.FullyQualifiedDomainName,
@@ -35,7 +35,7 @@ func (a *allInOneManager) preProvision(
 }
 
 func (a *allInOneManager) deployARMTemplate(
-	_ context.Context,
+	ctx context.Context,
 	instance service.Instance,
 ) (service.InstanceDetails, error) {
 	dt := instance.Details.(*allInOneInstanceDetails)
@@ -69,6 +69,7 @@ func (a *allInOneManager) deployARMTemplate(
 		tags[k] = tagsObj.GetString(k)
 	}
 	outputs, err := a.armDeployer.Deploy(
+		ctx,
 		dt.ARMDeploymentName,
 		pp.GetString("resourceGroup"),
 		pp.GetString("location"),
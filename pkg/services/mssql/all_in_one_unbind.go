@@ -1,16 +1,20 @@
 package mssql
 
 import (
+	"context"
+
 	"github.com/Azure/open-service-broker-azure/pkg/service"
 )
 
 func (a *allInOneManager) Unbind(
+	ctx context.Context,
 	instance service.Instance,
 	binding service.Binding,
 ) error {
 	dt := instance.Details.(*allInOneInstanceDetails)
 	bd := binding.Details.(*bindingDetails)
 	return unbind(
+		ctx,
 		dt.AdministratorLogin,
 		string(dt.AdministratorLoginPassword),
 		dt.FullyQualifiedDomainName,
@@ -1,11 +1,14 @@
 package mssql
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
 	"net/url"
 
 	_ "github.com/denisenkom/go-mssqldb" // MS SQL Driver
+
+	"github.com/Azure/open-service-broker-azure/pkg/tracing"
 )
 
 func getDBConnection(
@@ -44,10 +47,21 @@ func getDBConnection(
 }
 
 func validateServerAdmin(
+	ctx context.Context,
 	administratorLogin string,
 	administratorLoginPassword string,
 	fullyQualifiedDomainName string,
-) error {
+) (err error) {
+	_, span := tracing.StartSQLSpan(
+		ctx,
+		"validateServerAdmin",
+		fullyQualifiedDomainName,
+		"master",
+	)
+	defer func() {
+		tracing.EndSpan(span, err)
+	}()
+
 	// connect to master database
 	masterDb, err := getDBConnection(
 		administratorLogin,
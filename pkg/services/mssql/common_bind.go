@@ -1,20 +1,32 @@
 package mssql
 
 import (
+	"context"
 	"fmt"
 	"net/url"
 
 	"github.com/Azure/open-service-broker-azure/pkg/generate"
+	brokerLog "github.com/Azure/open-service-broker-azure/pkg/log"
 	"github.com/Azure/open-service-broker-azure/pkg/service"
-	log "github.com/Sirupsen/logrus"
+	"github.com/Azure/open-service-broker-azure/pkg/tracing"
 )
 
 func bind(
+	ctx context.Context,
 	administratorLogin string,
 	administratorPassword string,
 	fqdn string,
 	databaseName string,
-) (service.BindingDetails, error) {
+) (_ service.BindingDetails, err error) {
+	_, span := tracing.StartSQLSpan(
+		ctx,
+		"bind",
+		fqdn,
+		databaseName,
+	)
+	defer func() {
+		tracing.EndSpan(span, err)
+	}()
 
 	username := generate.NewIdentifier()
 	password := generate.NewPassword()
@@ -40,8 +52,8 @@ func bind(
 	}
 	defer func() {
 		if err != nil {
-			if err = tx.Rollback(); err != nil {
-				log.WithField("error", err).
+			if rollbackErr := tx.Rollback(); rollbackErr != nil {
+				brokerLog.FromContext(ctx).WithField("error", rollbackErr).
 					Error("error rolling back transaction on the new database")
 			}
 		}
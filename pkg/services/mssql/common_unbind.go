@@ -1,16 +1,30 @@
 package mssql
 
 import (
+	"context"
 	"fmt"
+
+	"github.com/Azure/open-service-broker-azure/pkg/tracing"
 )
 
 func unbind(
+	ctx context.Context,
 	administratorLogin string,
 	administratorPassword string,
 	fqdn string,
 	databaseName string,
 	bd *bindingDetails,
-) error {
+) (err error) {
+	_, span := tracing.StartSQLSpan(
+		ctx,
+		"unbind",
+		fqdn,
+		databaseName,
+	)
+	defer func() {
+		tracing.EndSpan(span, err)
+	}()
+
 	// connect to database to drop user
 	db, err := getDBConnection(
 		administratorLogin,
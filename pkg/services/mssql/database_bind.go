@@ -1,16 +1,20 @@
 package mssql
 
 import (
+	"context"
+
 	"github.com/Azure/open-service-broker-azure/pkg/service"
 )
 
 func (d *databaseManager) Bind(
+	ctx context.Context,
 	instance service.Instance,
 	_ service.BindingParameters,
 ) (service.BindingDetails, error) {
 	dt := instance.Details.(*databaseInstanceDetails)
 	pdt := instance.Parent.Details.(*dbmsInstanceDetails)
 	return bind(
+		ctx,
 		pdt.AdministratorLogin,
 		string(pdt.AdministratorLoginPassword),
 		pdt.FullyQualifiedDomainName,
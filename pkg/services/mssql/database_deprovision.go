@@ -20,11 +20,12 @@ func (d *databaseManager) GetDeprovisioner(
 }
 
 func (d *databaseManager) deleteARMDeployment(
-	_ context.Context,
+	ctx context.Context,
 	instance service.Instance,
 ) (service.InstanceDetails, error) {
 	dt := instance.Details.(*databaseInstanceDetails)
 	err := d.armDeployer.Delete(
+		ctx,
 		dt.ARMDeploymentName,
 		instance.Parent.ProvisioningParameters.GetString("resourceGroup"),
 	)
@@ -29,7 +29,7 @@ func (d *databaseManager) preProvision(
 }
 
 func (d *databaseManager) deployARMTemplate(
-	_ context.Context,
+	ctx context.Context,
 	instance service.Instance,
 ) (service.InstanceDetails, error) {
 	dt := instance.Details.(*databaseInstanceDetails)
@@ -55,6 +55,7 @@ func (d *databaseManager) deployARMTemplate(
 	}
 	// No output, so ignore the output
 	_, err = d.armDeployer.Deploy(
+		ctx,
 		dt.ARMDeploymentName,
 		instance.Parent.ProvisioningParameters.GetString("resourceGroup"),
 		instance.Parent.ProvisioningParameters.GetString("location"),
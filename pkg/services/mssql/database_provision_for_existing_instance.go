@@ -52,7 +52,7 @@ func (d *databaseManagerForExistingInstance) getDatabase(
 }
 
 func (d *databaseManagerForExistingInstance) deployARMTemplate(
-	_ context.Context,
+	ctx context.Context,
 	instance service.Instance,
 ) (service.InstanceDetails, error) {
 	dt := instance.Details.(*databaseInstanceDetails)
@@ -71,6 +71,7 @@ func (d *databaseManagerForExistingInstance) deployARMTemplate(
 	}
 	// No output, so ignore the output
 	_, err := d.armDeployer.Deploy(
+		ctx,
 		dt.ARMDeploymentName,
 		instance.Parent.ProvisioningParameters.GetString("resourceGroup"),
 		location,
@@ -1,10 +1,13 @@
 package mssql
 
 import (
+	"context"
+
 	"github.com/Azure/open-service-broker-azure/pkg/service"
 )
 
 func (d *databaseManager) Unbind(
+	ctx context.Context,
 	instance service.Instance,
 	binding service.Binding,
 ) error {
@@ -12,6 +15,7 @@ func (d *databaseManager) Unbind(
 	pdt := instance.Parent.Details.(*dbmsInstanceDetails)
 	bd := binding.Details.(*bindingDetails)
 	return unbind(
+		ctx,
 		pdt.AdministratorLogin,
 		string(pdt.AdministratorLoginPassword),
 		pdt.FullyQualifiedDomainName,
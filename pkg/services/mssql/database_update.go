@@ -23,7 +23,7 @@ func (d *databaseManager) GetUpdater(service.Plan) (service.Updater, error) {
 }
 
 func (d *databaseManager) updateARMTemplate(
-	_ context.Context,
+	ctx context.Context,
 	instance service.Instance,
 ) (service.InstanceDetails, error) {
 	dt := instance.Details.(*databaseInstanceDetails)
@@ -47,6 +47,7 @@ func (d *databaseManager) updateARMTemplate(
 		tags[k] = tagsObj.GetString(k)
 	}
 	_, err = d.armDeployer.Update(
+		ctx,
 		dt.ARMDeploymentName,
 		instance.Parent.ProvisioningParameters.GetString("resourceGroup"),
 		instance.Parent.ProvisioningParameters.GetString("location"),
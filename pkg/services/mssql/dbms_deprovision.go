@@ -20,11 +20,12 @@ func (d *dbmsManager) GetDeprovisioner(
 }
 
 func (d *dbmsManager) deleteARMDeployment(
-	_ context.Context,
+	ctx context.Context,
 	instance service.Instance,
 ) (service.InstanceDetails, error) {
 	dt := instance.Details.(*dbmsInstanceDetails)
 	err := d.armDeployer.Delete(
+		ctx,
 		dt.ARMDeploymentName,
 		instance.ProvisioningParameters.GetString("resourceGroup"),
 	)
@@ -32,7 +32,7 @@ func (d *dbmsManager) preProvision(
 }
 
 func (d *dbmsManager) deployARMTemplate(
-	_ context.Context,
+	ctx context.Context,
 	instance service.Instance,
 ) (service.InstanceDetails, error) {
 	dt := instance.Details.(*dbmsInstanceDetails)
@@ -54,6 +54,7 @@ func (d *dbmsManager) deployARMTemplate(
 		tags[k] = tagsObj.GetString(k)
 	}
 	outputs, err := d.armDeployer.Deploy(
+		ctx,
 		dt.ARMDeploymentName,
 		pp.GetString("resourceGroup"),
 		pp.GetString("location"),
@@ -80,6 +80,7 @@ func (d *dbmsRegisteredManager) validateServer(
 	dt.FullyQualifiedDomainName = *result.FullyQualifiedDomainName
 
 	if err = validateServerAdmin(
+		ctx,
 		dt.AdministratorLogin,
 		string(dt.AdministratorLoginPassword),
 		dt.FullyQualifiedDomainName,
@@ -21,7 +21,7 @@ func (d *dbmsRegisteredManager) GetUpdater(
 }
 
 func (d *dbmsRegisteredManager) updateAdministrator(
-	_ context.Context,
+	ctx context.Context,
 	instance service.Instance,
 ) (service.InstanceDetails, error) {
 	dt := instance.Details.(*dbmsInstanceDetails)
@@ -31,6 +31,7 @@ func (d *dbmsRegisteredManager) updateAdministrator(
 		instance.UpdatingParameters.GetString("administratorLoginPassword")
 
 	if err := validateServerAdmin(
+		ctx,
 		updatedAdministratorLogin,
 		updatedAdministratorLoginPassword,
 		dt.FullyQualifiedDomainName,
@@ -1,10 +1,16 @@
 package mssql
 
 import (
+	"context"
+
 	"github.com/Azure/open-service-broker-azure/pkg/service"
 )
 
 // TODO: Unbind is not valid for DBMS only; determine correct behavior
-func (d *dbmsManager) Unbind(service.Instance, service.Binding) error {
+func (d *dbmsManager) Unbind(
+	context.Context,
+	service.Instance,
+	service.Binding,
+) error {
 	return nil
 }
@@ -20,7 +20,7 @@ func (d *dbmsManager) GetUpdater(service.Plan) (service.Updater, error) {
 }
 
 func (d *dbmsManager) updateARMTemplate(
-	_ context.Context,
+	ctx context.Context,
 	instance service.Instance,
 ) (service.InstanceDetails, error) {
 	dt := instance.Details.(*dbmsInstanceDetails)
@@ -42,6 +42,7 @@ func (d *dbmsManager) updateARMTemplate(
 		tags[k] = tagsObj.GetString(k)
 	}
 	_, err = d.armDeployer.Update(
+		ctx,
 		dt.ARMDeploymentName,
 		pp.GetString("resourceGroup"),
 		pp.GetString("location"),
@@ -4,6 +4,7 @@ import (
 	sqlSDK "github.com/Azure/azure-sdk-for-go/services/preview/sql/mgmt/2017-03-01-preview/sql" // nolint: lll
 	"github.com/Azure/go-autorest/autorest/azure"
 	"github.com/Azure/open-service-broker-azure/pkg/azure/arm"
+	"github.com/Azure/open-service-broker-azure/pkg/health"
 	"github.com/Azure/open-service-broker-azure/pkg/service"
 )
 
@@ -97,3 +98,7 @@ func New(
 func (m *module) GetName() string {
 	return "mssql"
 }
+
+func (m *module) GetHealthChecks() []health.Check {
+	return nil
+}
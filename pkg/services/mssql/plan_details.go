@@ -369,7 +369,7 @@ func validateStorageUpdate(
 	newStorge := up.GetInt64("storage")
 	if newStorge < existingStorage {
 		return service.NewValidationError(
-			"storage",
+			"/storage",
 			fmt.Sprintf(
 				`invalid value: cannot reduce storage from %d to %d`,
 				existingStorage,
@@ -1,10 +1,13 @@
 package mssqldr
 
 import (
+	"context"
+
 	"github.com/Azure/open-service-broker-azure/pkg/service"
 )
 
 func (d *commonDatabasePairManager) Bind(
+	ctx context.Context,
 	instance service.Instance,
 	_ service.BindingParameters,
 ) (service.BindingDetails, error) {
@@ -13,6 +16,7 @@ func (d *commonDatabasePairManager) Bind(
 	// TODO: detect who is the primary role now
 	// assume the roles are not changed
 	return bind(
+		ctx,
 		pdt.PriAdministratorLogin,
 		string(pdt.PriAdministratorLoginPassword),
 		pdt.PriFullyQualifiedDomainName,
@@ -39,12 +39,13 @@ func (d *commonDatabasePairManager) GetDeprovisioner(
 }
 
 func (d *commonDatabasePairManager) deletePriARMDeployment(
-	_ context.Context,
+	ctx context.Context,
 	instance service.Instance,
 ) (service.InstanceDetails, error) {
 	dt := instance.Details.(*databasePairInstanceDetails)
 	ppp := instance.Parent.ProvisioningParameters
 	if err := d.armDeployer.Delete(
+		ctx,
 		dt.PriARMDeploymentName,
 		ppp.GetString("primaryResourceGroup"),
 	); err != nil {
@@ -54,12 +55,13 @@ func (d *commonDatabasePairManager) deletePriARMDeployment(
 }
 
 func (d *commonDatabasePairManager) deleteSecARMDeployment(
-	_ context.Context,
+	ctx context.Context,
 	instance service.Instance,
 ) (service.InstanceDetails, error) {
 	dt := instance.Details.(*databasePairInstanceDetails)
 	ppp := instance.Parent.ProvisioningParameters
 	if err := d.armDeployer.Delete(
+		ctx,
 		dt.SecARMDeploymentName,
 		ppp.GetString("secondaryResourceGroup"),
 	); err != nil {
@@ -69,12 +71,13 @@ func (d *commonDatabasePairManager) deleteSecARMDeployment(
 }
 
 func (d *commonDatabasePairManager) deleteFailoverGroupARMDeployment(
-	_ context.Context,
+	ctx context.Context,
 	instance service.Instance,
 ) (service.InstanceDetails, error) {
 	dt := instance.Details.(*databasePairInstanceDetails)
 	ppp := instance.Parent.ProvisioningParameters
 	if err := d.armDeployer.Delete(
+		ctx,
 		dt.FailoverGroupARMDeploymentName,
 		ppp.GetString("primaryResourceGroup"),
 	); err != nil {
@@ -67,7 +67,7 @@ func (d *commonDatabasePairManager) validateFailoverGroup(
 }
 
 func (d *commonDatabasePairManager) deployPriARMTemplate(
-	_ context.Context,
+	ctx context.Context,
 	instance service.Instance,
 ) (service.InstanceDetails, error) {
 	dt := instance.Details.(*databasePairInstanceDetails)
@@ -81,6 +81,7 @@ func (d *commonDatabasePairManager) deployPriARMTemplate(
 		tags[k] = tagsObj.GetString(k)
 	}
 	if err := deployDatabaseARMTemplate(
+		ctx,
 		&d.armDeployer,
 		dt.PriARMDeploymentName,
 		ppp.GetString("primaryResourceGroup"),
@@ -98,11 +99,12 @@ func (d *commonDatabasePairManager) deployPriARMTemplate(
 }
 
 func (d *commonDatabasePairManager) deployFailoverGroupARMTemplate(
-	_ context.Context,
+	ctx context.Context,
 	instance service.Instance,
 ) (service.InstanceDetails, error) {
 	pp := instance.ProvisioningParameters
 	if err := deployFailoverGroupARMTemplate(
+		ctx,
 		&d.armDeployer,
 		instance,
 	); err != nil {
@@ -114,7 +116,7 @@ func (d *commonDatabasePairManager) deployFailoverGroupARMTemplate(
 }
 
 func (d *commonDatabasePairManager) deployPriARMTemplateForExistingInstance(
-	_ context.Context,
+	ctx context.Context,
 	instance service.Instance,
 ) (service.InstanceDetails, error) {
 	dt := instance.Details.(*databasePairInstanceDetails)
@@ -127,6 +129,7 @@ func (d *commonDatabasePairManager) deployPriARMTemplateForExistingInstance(
 		tags[k] = tagsObj.GetString(k)
 	}
 	if err := deployDatabaseARMTemplateForExistingInstance(
+		ctx,
 		&d.armDeployer,
 		dt.PriARMDeploymentName,
 		ppp.GetString("primaryResourceGroup"),
@@ -141,7 +144,7 @@ func (d *commonDatabasePairManager) deployPriARMTemplateForExistingInstance(
 }
 
 func (d *commonDatabasePairManager) deploySecARMTemplateForExistingInstance(
-	_ context.Context,
+	ctx context.Context,
 	instance service.Instance,
 ) (service.InstanceDetails, error) {
 	dt := instance.Details.(*databasePairInstanceDetails)
@@ -154,6 +157,7 @@ func (d *commonDatabasePairManager) deploySecARMTemplateForExistingInstance(
 		tags[k] = tagsObj.GetString(k)
 	}
 	if err := deployDatabaseARMTemplateForExistingInstance(
+		ctx,
 		&d.armDeployer,
 		dt.SecARMDeploymentName,
 		ppp.GetString("secondaryResourceGroup"),
@@ -170,10 +174,11 @@ func (d *commonDatabasePairManager) deploySecARMTemplateForExistingInstance(
 }
 
 func (d *commonDatabasePairManager) deployFailoverGroupARMTemplateForExistingInstance( // nolint: lll
-	_ context.Context,
+	ctx context.Context,
 	instance service.Instance,
 ) (service.InstanceDetails, error) {
 	if err := deployFailoverGroupARMTemplate(
+		ctx,
 		&d.armDeployer,
 		instance,
 	); err != nil {
@@ -1,10 +1,13 @@
 package mssqldr
 
 import (
+	"context"
+
 	"github.com/Azure/open-service-broker-azure/pkg/service"
 )
 
 func (d *commonDatabasePairManager) Unbind(
+	ctx context.Context,
 	instance service.Instance,
 	binding service.Binding,
 ) error {
@@ -14,6 +17,7 @@ func (d *commonDatabasePairManager) Unbind(
 	// TODO: detect who is the primary role now
 	// assume the roles are not changed
 	return unbind(
+		ctx,
 		pdt.PriAdministratorLogin,
 		string(pdt.PriAdministratorLoginPassword),
 		pdt.PriFullyQualifiedDomainName,
@@ -27,7 +27,7 @@ func (d *commonDatabasePairManager) GetUpdater(
 }
 
 func (d *commonDatabasePairManager) updatePriARMTemplate(
-	_ context.Context,
+	ctx context.Context,
 	instance service.Instance,
 ) (service.InstanceDetails, error) {
 	dt := instance.Details.(*databasePairInstanceDetails)
@@ -40,6 +40,7 @@ func (d *commonDatabasePairManager) updatePriARMTemplate(
 		tags[k] = tagsObj.GetString(k)
 	}
 	err := updateDatabaseARMTemplate(
+		ctx,
 		&d.armDeployer,
 		dt.PriARMDeploymentName,
 		ppp.GetString("primaryResourceGroup"),
@@ -67,7 +68,7 @@ func (d *commonDatabasePairManager) updatePriARMTemplate(
 // further communication with SQL team.
 // nolint: megacheck
 func (d *commonDatabasePairManager) updateSecARMTemplate(
-	_ context.Context,
+	ctx context.Context,
 	instance service.Instance,
 ) (service.InstanceDetails, error) {
 	dt := instance.Details.(*databasePairInstanceDetails)
@@ -80,6 +81,7 @@ func (d *commonDatabasePairManager) updateSecARMTemplate(
 		tags[k] = tagsObj.GetString(k)
 	}
 	err := updateDatabaseARMTemplate(
+		ctx,
 		&d.armDeployer,
 		dt.SecARMDeploymentName,
 		ppp.GetString("secondaryResourceGroup"),
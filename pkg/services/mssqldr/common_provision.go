@@ -168,6 +168,7 @@ func buildDatabaseGoTemplateParameters(
 }
 
 func deployDatabaseARMTemplate(
+	ctx context.Context,
 	armDeployer *arm.Deployer,
 	armDeploymentName string,
 	resourceGroup string,
@@ -190,6 +191,7 @@ func deployDatabaseARMTemplate(
 	goTemplateParams["location"] = location
 	goTemplateParams["serverName"] = serverName
 	_, err = (*armDeployer).Deploy(
+		ctx,
 		armDeploymentName,
 		resourceGroup,
 		location,
@@ -202,6 +204,7 @@ func deployDatabaseARMTemplate(
 }
 
 func deployFailoverGroupARMTemplate(
+	ctx context.Context,
 	armDeployer *arm.Deployer,
 	instance service.Instance,
 ) error {
@@ -220,6 +223,7 @@ func deployFailoverGroupARMTemplate(
 		tags[k] = tagsObj.GetString(k)
 	}
 	_, err := (*armDeployer).Deploy(
+		ctx,
 		dt.FailoverGroupARMDeploymentName,
 		ppp.GetString("primaryResourceGroup"),
 		ppp.GetString("primaryLocation"),
@@ -232,6 +236,7 @@ func deployFailoverGroupARMTemplate(
 }
 
 func deployDatabaseARMTemplateForExistingInstance(
+	ctx context.Context,
 	armDeployer *arm.Deployer,
 	armDeploymentName string,
 	resourceGroup string,
@@ -245,6 +250,7 @@ func deployDatabaseARMTemplateForExistingInstance(
 	goTemplateParams["serverName"] = serverName
 	goTemplateParams["databaseName"] = databaseName
 	_, err := (*armDeployer).Deploy(
+		ctx,
 		armDeploymentName,
 		resourceGroup,
 		location,
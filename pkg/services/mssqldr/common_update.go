@@ -1,11 +1,14 @@
 package mssqldr
 
 import (
+	"context"
+
 	"github.com/Azure/open-service-broker-azure/pkg/azure/arm"
 	"github.com/Azure/open-service-broker-azure/pkg/service"
 )
 
 func updateDatabaseARMTemplate(
+	ctx context.Context,
 	armDeployer *arm.Deployer,
 	armDeploymentName string,
 	resourceGroup string,
@@ -28,6 +31,7 @@ func updateDatabaseARMTemplate(
 	goTemplateParams["location"] = location
 	goTemplateParams["serverName"] = serverName
 	_, err = (*armDeployer).Update(
+		ctx,
 		armDeploymentName,
 		resourceGroup,
 		location,
@@ -1,6 +1,8 @@
 package mssqldr
 
 import (
+	"context"
+
 	"fmt"
 
 	"github.com/Azure/open-service-broker-azure/pkg/service"
@@ -8,6 +10,7 @@ import (
 
 // TODO: Bind is not valid for DBMS only; determine correct behavior
 func (d *dbmsPairRegisteredManager) Bind(
+	context.Context,
 	service.Instance,
 	service.BindingParameters,
 ) (service.BindingDetails, error) {
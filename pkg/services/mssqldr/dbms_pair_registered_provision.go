@@ -62,6 +62,7 @@ func (d *dbmsPairRegisteredManager) validatePriServer(
 		return nil, err
 	}
 	if err = validateServerAdmin(
+		ctx,
 		dt.PriAdministratorLogin,
 		string(dt.PriAdministratorLoginPassword),
 		fqdn,
@@ -90,6 +91,7 @@ func (d *dbmsPairRegisteredManager) validateSecServer(
 		return nil, err
 	}
 	if err = validateServerAdmin(
+		ctx,
 		dt.SecAdministratorLogin,
 		string(dt.SecAdministratorLoginPassword),
 		fqdn,
@@ -1,11 +1,14 @@
 package mssqldr
 
 import (
+	"context"
+
 	"github.com/Azure/open-service-broker-azure/pkg/service"
 )
 
 // TODO: Unbind is not valid for DBMS only; determine correct behavior
 func (d *dbmsPairRegisteredManager) Unbind(
+	context.Context,
 	service.Instance,
 	service.Binding,
 ) error {
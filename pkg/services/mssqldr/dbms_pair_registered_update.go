@@ -21,7 +21,7 @@ func (d *dbmsPairRegisteredManager) GetUpdater(
 }
 
 func (d *dbmsPairRegisteredManager) updateAdministrators(
-	_ context.Context,
+	ctx context.Context,
 	instance service.Instance,
 ) (service.InstanceDetails, error) {
 	dt := instance.Details.(*dbmsPairInstanceDetails)
@@ -31,6 +31,7 @@ func (d *dbmsPairRegisteredManager) updateAdministrators(
 	updatedPriAdministratorLoginPassword :=
 		up.GetString("primaryAdministratorLoginPassword")
 	if err := validateServerAdmin(
+		ctx,
 		updatedPriAdministratorLogin,
 		updatedPriAdministratorLoginPassword,
 		dt.PriFullyQualifiedDomainName,
@@ -42,6 +43,7 @@ func (d *dbmsPairRegisteredManager) updateAdministrators(
 	updatedSecAdministratorLoginPassword :=
 		up.GetString("secondaryAdministratorLoginPassword")
 	if err := validateServerAdmin(
+		ctx,
 		updatedSecAdministratorLogin,
 		updatedSecAdministratorLoginPassword,
 		dt.SecFullyQualifiedDomainName,
@@ -1,15 +1,19 @@
 package mysql
 
 import (
+	"context"
+
 	"github.com/Azure/open-service-broker-azure/pkg/service"
 )
 
 func (a *allInOneManager) Bind(
+	ctx context.Context,
 	instance service.Instance,
 	bp service.BindingParameters,
 ) (service.BindingDetails, error) {
 	dt := instance.Details.(*allInOneInstanceDetails)
 	return createBinding(
+		ctx,
 		bp,
 		isSSLRequired(*instance.ProvisioningParameters),
 		a.sqlDatabaseDNSSuffix,
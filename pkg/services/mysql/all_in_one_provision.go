@@ -41,7 +41,7 @@ func (a *allInOneManager) preProvision(
 }
 
 func (a *allInOneManager) deployARMTemplate(
-	_ context.Context,
+	ctx context.Context,
 	instance service.Instance,
 ) (service.InstanceDetails, error) {
 	dt := instance.Details.(*allInOneInstanceDetails)
@@ -65,6 +65,7 @@ func (a *allInOneManager) deployARMTemplate(
 		tags[k] = tagsObj.GetString(k)
 	}
 	outputs, err := a.armDeployer.Deploy(
+		ctx,
 		dt.ARMDeploymentName,
 		instance.ProvisioningParameters.GetString("resourceGroup"),
 		instance.ProvisioningParameters.GetString("location"),
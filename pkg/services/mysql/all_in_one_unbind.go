@@ -1,16 +1,20 @@
 package mysql
 
 import (
+	"context"
+
 	"github.com/Azure/open-service-broker-azure/pkg/service"
 )
 
 func (a *allInOneManager) Unbind(
+	ctx context.Context,
 	instance service.Instance,
 	binding service.Binding,
 ) error {
 	dt := instance.Details.(*allInOneInstanceDetails)
 	bd := binding.Details.(*bindingDetails)
 	return unbind(
+		ctx,
 		isSSLRequired(*instance.ProvisioningParameters),
 		a.sqlDatabaseDNSSuffix,
 		dt.ServerName,
@@ -24,7 +24,7 @@ func (a *allInOneManager) GetUpdater(service.Plan) (service.Updater, error) {
 }
 
 func (a *allInOneManager) updateARMTemplate(
-	_ context.Context,
+	ctx context.Context,
 	instance service.Instance,
 ) (service.InstanceDetails, error) {
 	dt := instance.Details.(*allInOneInstanceDetails)
@@ -45,6 +45,7 @@ func (a *allInOneManager) updateARMTemplate(
 		tags[k] = tagsObj.GetString(k)
 	}
 	_, err = a.armDeployer.Update(
+		ctx,
 		dt.ARMDeploymentName,
 		instance.UpdatingParameters.GetString("resourceGroup"),
 		instance.UpdatingParameters.GetString("location"),
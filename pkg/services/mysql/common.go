@@ -1,15 +1,17 @@
 package mysql
 
 import (
+	"context"
 	"crypto/tls"
 	"database/sql"
 	"fmt"
 
-	log "github.com/Sirupsen/logrus"
+	brokerLog "github.com/Azure/open-service-broker-azure/pkg/log"
 	"github.com/go-sql-driver/mysql"
 )
 
 func createDBConnection(
+	ctx context.Context,
 	enforceSSL bool,
 	sqlDatabaseDNSSuffix string,
 	server string,
@@ -22,7 +24,7 @@ func createDBConnection(
 	if enforceSSL {
 		serverName := fmt.Sprintf("*.%s", sqlDatabaseDNSSuffix)
 
-		log.WithField(
+		brokerLog.FromContext(ctx).WithField(
 			"serverName", serverName,
 		).Debug("Azure ENV SQLDatabaseDNSSuffix")
 
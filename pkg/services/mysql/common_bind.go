@@ -1,14 +1,17 @@
 package mysql
 
 import (
+	"context"
 	"fmt"
 	"net/url"
 
 	"github.com/Azure/open-service-broker-azure/pkg/generate"
 	"github.com/Azure/open-service-broker-azure/pkg/service"
+	"github.com/Azure/open-service-broker-azure/pkg/tracing"
 )
 
 func createBinding(
+	ctx context.Context,
 	bp service.BindingParameters,
 	enforceSSL bool,
 	dnsSuffix string,
@@ -17,7 +20,16 @@ func createBinding(
 	adminPassword string,
 	fqdn string,
 	databaseName string,
-) (service.BindingDetails, error) {
+) (_ service.BindingDetails, err error) {
+	_, span := tracing.StartSQLSpan(
+		ctx,
+		"createBinding",
+		fqdn,
+		databaseName,
+	)
+	defer func() {
+		tracing.EndSpan(span, err)
+	}()
 
 	userName := bp.GetString("username")
 	if userName == "" {
@@ -26,6 +38,7 @@ func createBinding(
 	password := generate.NewPassword()
 
 	db, err := createDBConnection(
+		ctx,
 		enforceSSL,
 		dnsSuffix,
 		serverName,
@@ -1,10 +1,14 @@
 package mysql
 
 import (
+	"context"
 	"fmt"
+
+	"github.com/Azure/open-service-broker-azure/pkg/tracing"
 )
 
 func unbind(
+	ctx context.Context,
 	enforceSSL bool,
 	sqlDatabaseDNSSuffix string,
 	serverName string,
@@ -13,8 +17,19 @@ func unbind(
 	fullyQualifiedDomainName string,
 	databaseName string,
 	bindingDetails *bindingDetails,
-) error {
+) (err error) {
+	_, span := tracing.StartSQLSpan(
+		ctx,
+		"unbind",
+		fullyQualifiedDomainName,
+		databaseName,
+	)
+	defer func() {
+		tracing.EndSpan(span, err)
+	}()
+
 	db, err := createDBConnection(
+		ctx,
 		enforceSSL,
 		sqlDatabaseDNSSuffix,
 		serverName,
@@ -1,16 +1,20 @@
 package mysql
 
 import (
+	"context"
+
 	"github.com/Azure/open-service-broker-azure/pkg/service"
 )
 
 func (d *databaseManager) Bind(
+	ctx context.Context,
 	instance service.Instance,
 	bp service.BindingParameters,
 ) (service.BindingDetails, error) {
 	pdt := instance.Parent.Details.(*dbmsInstanceDetails)
 	dt := instance.Details.(*databaseInstanceDetails)
 	return createBinding(
+		ctx,
 		bp,
 		isSSLRequired(*instance.Parent.ProvisioningParameters),
 		d.sqlDatabaseDNSSuffix,
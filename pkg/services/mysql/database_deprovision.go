@@ -17,11 +17,12 @@ func (d *databaseManager) GetDeprovisioner(
 }
 
 func (d *databaseManager) deleteARMDeployment(
-	_ context.Context,
+	ctx context.Context,
 	instance service.Instance,
 ) (service.InstanceDetails, error) {
 	dt := instance.Details.(*databaseInstanceDetails)
 	if err := d.armDeployer.Delete(
+		ctx,
 		dt.ARMDeploymentName,
 		instance.Parent.ProvisioningParameters.GetString("resourceGroup"),
 	); err != nil {
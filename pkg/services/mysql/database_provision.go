@@ -29,7 +29,7 @@ func (d *databaseManager) preProvision(
 }
 
 func (d *databaseManager) deployARMTemplate(
-	_ context.Context,
+	ctx context.Context,
 	instance service.Instance,
 ) (service.InstanceDetails, error) {
 	pdt := instance.Parent.Details.(*dbmsInstanceDetails)
@@ -44,6 +44,7 @@ func (d *databaseManager) deployARMTemplate(
 		tags[k] = tagsObj.GetString(k)
 	}
 	_, err := d.armDeployer.Deploy(
+		ctx,
 		dt.ARMDeploymentName,
 		instance.Parent.ProvisioningParameters.GetString("resourceGroup"),
 		instance.Parent.ProvisioningParameters.GetString("location"),
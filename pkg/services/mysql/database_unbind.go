@@ -1,10 +1,13 @@
 package mysql
 
 import (
+	"context"
+
 	"github.com/Azure/open-service-broker-azure/pkg/service"
 )
 
 func (d *databaseManager) Unbind(
+	ctx context.Context,
 	instance service.Instance,
 	binding service.Binding,
 ) error {
@@ -12,6 +15,7 @@ func (d *databaseManager) Unbind(
 	dt := instance.Details.(*databaseInstanceDetails)
 	bd := binding.Details.(*bindingDetails)
 	return unbind(
+		ctx,
 		isSSLRequired(*instance.Parent.ProvisioningParameters),
 		d.sqlDatabaseDNSSuffix,
 		pdt.ServerName,
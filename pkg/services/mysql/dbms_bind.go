@@ -1,12 +1,15 @@
 package mysql
 
 import (
+	"context"
+
 	"fmt"
 
 	"github.com/Azure/open-service-broker-azure/pkg/service"
 )
 
 func (d *dbmsManager) Bind(
+	ctx context.Context,
 	instance service.Instance,
 	_ service.BindingParameters,
 ) (service.BindingDetails, error) {
@@ -1,12 +1,15 @@
 package mysql
 
 import (
+	"context"
+
 	"fmt"
 
 	"github.com/Azure/open-service-broker-azure/pkg/service"
 )
 
 func (v *dbmsManager) Unbind(
+	context.Context,
 	service.Instance,
 	service.Binding,
 ) error {
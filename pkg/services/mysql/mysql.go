@@ -4,6 +4,7 @@ import (
 	mysqlSDK "github.com/Azure/azure-sdk-for-go/services/mysql/mgmt/2017-12-01/mysql" // nolint: lll
 	"github.com/Azure/go-autorest/autorest/azure"
 	"github.com/Azure/open-service-broker-azure/pkg/azure/arm"
+	"github.com/Azure/open-service-broker-azure/pkg/health"
 	"github.com/Azure/open-service-broker-azure/pkg/service"
 )
 
@@ -62,3 +63,7 @@ func New(
 func (m *module) GetName() string {
 	return "mysql"
 }
+
+func (m *module) GetHealthChecks() []health.Check {
+	return nil
+}
@@ -1,15 +1,19 @@
 package postgresql
 
 import (
+	"context"
+
 	"github.com/Azure/open-service-broker-azure/pkg/service"
 )
 
 func (a *allInOneManager) Bind(
+	ctx context.Context,
 	instance service.Instance,
 	_ service.BindingParameters,
 ) (service.BindingDetails, error) {
 	dt := instance.Details.(*allInOneInstanceDetails)
 	bd, err := createBinding(
+		ctx,
 		isSSLRequired(*instance.ProvisioningParameters),
 		dt.AdministratorLogin,
 		dt.ServerName,
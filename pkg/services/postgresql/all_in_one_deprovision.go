@@ -20,12 +20,13 @@ func (a *allInOneManager) GetDeprovisioner(
 }
 
 func (a *allInOneManager) deleteARMDeployment(
-	_ context.Context,
+	ctx context.Context,
 	instance service.Instance,
 ) (service.InstanceDetails, error) {
 	dt := instance.Details.(*allInOneInstanceDetails)
 	if err :=
 		a.armDeployer.Delete(
+			ctx,
 			dt.ARMDeploymentName,
 			instance.ProvisioningParameters.GetString("resourceGroup"),
 		); err != nil {
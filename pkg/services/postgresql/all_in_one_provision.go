@@ -42,7 +42,7 @@ func (a *allInOneManager) preProvision(
 }
 
 func (a *allInOneManager) deployARMTemplate(
-	_ context.Context,
+	ctx context.Context,
 	instance service.Instance,
 ) (service.InstanceDetails, error) {
 	dt := instance.Details.(*allInOneInstanceDetails)
@@ -66,6 +66,7 @@ func (a *allInOneManager) deployARMTemplate(
 		tags[k] = tagsObj.GetString(k)
 	}
 	outputs, err := a.armDeployer.Deploy(
+		ctx,
 		dt.ARMDeploymentName,
 		instance.ProvisioningParameters.GetString("resourceGroup"),
 		instance.ProvisioningParameters.GetString("location"),
@@ -89,11 +90,12 @@ func (a *allInOneManager) deployARMTemplate(
 }
 
 func (a *allInOneManager) setupDatabase(
-	_ context.Context,
+	ctx context.Context,
 	instance service.Instance,
 ) (service.InstanceDetails, error) {
 	dt := instance.Details.(*allInOneInstanceDetails)
 	err := setupDatabase(
+		ctx,
 		isSSLRequired(*instance.ProvisioningParameters),
 		dt.AdministratorLogin,
 		dt.ServerName,
@@ -108,13 +110,14 @@ func (a *allInOneManager) setupDatabase(
 }
 
 func (a *allInOneManager) createExtensions(
-	_ context.Context,
+	ctx context.Context,
 	instance service.Instance,
 ) (service.InstanceDetails, error) {
 	dt := instance.Details.(*allInOneInstanceDetails)
 	extensions := instance.ProvisioningParameters.GetStringArray("extensions")
 	if len(extensions) > 0 {
 		err := createExtensions(
+			ctx,
 			isSSLRequired(*instance.ProvisioningParameters),
 			dt.AdministratorLogin,
 			dt.ServerName,
@@ -1,23 +1,36 @@
 package postgresql
 
 import (
+	"context"
 	"fmt"
 	"net/url"
 
 	"github.com/Azure/open-service-broker-azure/pkg/generate"
 	"github.com/Azure/open-service-broker-azure/pkg/service"
+	"github.com/Azure/open-service-broker-azure/pkg/tracing"
 
-	log "github.com/Sirupsen/logrus"
+	brokerLog "github.com/Azure/open-service-broker-azure/pkg/log"
 )
 
 func createBinding(
+	ctx context.Context,
 	enforceSSL bool,
 	administratorLogin string,
 	serverName string,
 	administratorLoginPassword string,
 	fullyQualifiedDomainName string,
 	databaseName string,
-) (*bindingDetails, error) {
+) (_ *bindingDetails, err error) {
+	_, span := tracing.StartSQLSpan(
+		ctx,
+		"createBinding",
+		fullyQualifiedDomainName,
+		primaryDB,
+	)
+	defer func() {
+		tracing.EndSpan(span, err)
+	}()
+
 	roleName := generate.NewIdentifier()
 	password := generate.NewPassword()
 
@@ -40,8 +53,9 @@ func createBinding(
 	}
 	defer func() {
 		if err != nil {
-			if err = tx.Rollback(); err != nil {
-				log.WithField("error", err).Error("error rolling back transaction")
+			if rollbackErr := tx.Rollback(); rollbackErr != nil {
+				brokerLog.FromContext(ctx).WithField("error", rollbackErr).
+					Error("error rolling back transaction")
 			}
 		}
 	}()
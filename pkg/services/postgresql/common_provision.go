@@ -6,8 +6,9 @@ import (
 
 	postgresSDK "github.com/Azure/azure-sdk-for-go/services/postgresql/mgmt/2017-12-01/postgresql" // nolint: lll
 	"github.com/Azure/open-service-broker-azure/pkg/generate"
+	brokerLog "github.com/Azure/open-service-broker-azure/pkg/log"
 	"github.com/Azure/open-service-broker-azure/pkg/service"
-	log "github.com/Sirupsen/logrus"
+	"github.com/Azure/open-service-broker-azure/pkg/tracing"
 	uuid "github.com/satori/go.uuid"
 )
 
@@ -121,13 +122,24 @@ func generateDBMSInstanceDetails(
 }
 
 func setupDatabase(
+	ctx context.Context,
 	enforceSSL bool,
 	administratorLogin string,
 	serverName string,
 	administratorLoginPassword string,
 	fullyQualifiedDomainName string,
 	dbName string,
-) error {
+) (err error) {
+	_, span := tracing.StartSQLSpan(
+		ctx,
+		"setupDatabase",
+		fullyQualifiedDomainName,
+		primaryDB,
+	)
+	defer func() {
+		tracing.EndSpan(span, err)
+	}()
+
 	db, err := getDBConnection(
 		enforceSSL,
 		administratorLogin,
@@ -147,8 +159,9 @@ func setupDatabase(
 	}
 	defer func() {
 		if err != nil {
-			if err = tx.Rollback(); err != nil {
-				log.WithField("error", err).Error("error rolling back transaction")
+			if rollbackErr := tx.Rollback(); rollbackErr != nil {
+				brokerLog.FromContext(ctx).WithField("error", rollbackErr).
+					Error("error rolling back transaction")
 			}
 		}
 	}()
@@ -193,6 +206,7 @@ func setupDatabase(
 }
 
 func createExtensions(
+	ctx context.Context,
 	enforceSSL bool,
 	administratorLogin string,
 	serverName string,
@@ -200,7 +214,17 @@ func createExtensions(
 	fullyQualifiedDomainName string,
 	dbName string,
 	extensions []string,
-) error {
+) (err error) {
+	_, span := tracing.StartSQLSpan(
+		ctx,
+		"createExtensions",
+		fullyQualifiedDomainName,
+		dbName,
+	)
+	defer func() {
+		tracing.EndSpan(span, err)
+	}()
+
 	db, err := getDBConnection(
 		enforceSSL,
 		administratorLogin,
@@ -220,8 +244,9 @@ func createExtensions(
 	}
 	defer func() {
 		if err != nil {
-			if err = tx.Rollback(); err != nil {
-				log.WithField("error", err).Error("error rolling back transaction")
+			if rollbackErr := tx.Rollback(); rollbackErr != nil {
+				brokerLog.FromContext(ctx).WithField("error", rollbackErr).
+					Error("error rolling back transaction")
 			}
 		}
 	}()
@@ -1,17 +1,31 @@
 package postgresql
 
 import (
+	"context"
 	"fmt"
+
+	"github.com/Azure/open-service-broker-azure/pkg/tracing"
 )
 
 func unbind(
+	ctx context.Context,
 	enforceSSL bool,
 	administratorLogin string,
 	serverName string,
 	administratorLoginPassword string,
 	fullyQualifiedDomainName string,
 	loginName string,
-) error {
+) (err error) {
+	_, span := tracing.StartSQLSpan(
+		ctx,
+		"unbind",
+		fullyQualifiedDomainName,
+		primaryDB,
+	)
+	defer func() {
+		tracing.EndSpan(span, err)
+	}()
+
 	db, err := getDBConnection(
 		enforceSSL,
 		administratorLogin,
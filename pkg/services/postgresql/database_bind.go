@@ -1,16 +1,20 @@
 package postgresql
 
 import (
+	"context"
+
 	"github.com/Azure/open-service-broker-azure/pkg/service"
 )
 
 func (d *databaseManager) Bind(
+	ctx context.Context,
 	instance service.Instance,
 	_ service.BindingParameters,
 ) (service.BindingDetails, error) {
 	pdt := instance.Parent.Details.(*dbmsInstanceDetails)
 	dt := instance.Details.(*databaseInstanceDetails)
 	bd, err := createBinding(
+		ctx,
 		isSSLRequired(*instance.Parent.ProvisioningParameters),
 		pdt.AdministratorLogin,
 		pdt.ServerName,
@@ -31,7 +31,7 @@ func (d *databaseManager) preProvision(
 }
 
 func (d *databaseManager) deployARMTemplate(
-	_ context.Context,
+	ctx context.Context,
 	instance service.Instance,
 ) (service.InstanceDetails, error) {
 	pdt := instance.Parent.Details.(*dbmsInstanceDetails)
@@ -46,6 +46,7 @@ func (d *databaseManager) deployARMTemplate(
 		tags[k] = tagsObj.GetString(k)
 	}
 	_, err := d.armDeployer.Deploy(
+		ctx,
 		dt.ARMDeploymentName,
 		instance.Parent.ProvisioningParameters.GetString("resourceGroup"),
 		instance.Parent.ProvisioningParameters.GetString("location"),
@@ -61,12 +62,13 @@ func (d *databaseManager) deployARMTemplate(
 }
 
 func (d *databaseManager) setupDatabase(
-	_ context.Context,
+	ctx context.Context,
 	instance service.Instance,
 ) (service.InstanceDetails, error) {
 	pdt := instance.Parent.Details.(*dbmsInstanceDetails)
 	dt := instance.Details.(*databaseInstanceDetails)
 	err := setupDatabase(
+		ctx,
 		isSSLRequired(*instance.Parent.ProvisioningParameters),
 		pdt.AdministratorLogin,
 		pdt.ServerName,
@@ -81,7 +83,7 @@ func (d *databaseManager) setupDatabase(
 }
 
 func (d *databaseManager) createExtensions(
-	_ context.Context,
+	ctx context.Context,
 	instance service.Instance,
 ) (service.InstanceDetails, error) {
 	pdt := instance.Parent.Details.(*dbmsInstanceDetails)
@@ -89,6 +91,7 @@ func (d *databaseManager) createExtensions(
 	extensions := instance.ProvisioningParameters.GetStringArray("extensions")
 	if len(extensions) > 0 {
 		err := createExtensions(
+			ctx,
 			isSSLRequired(*instance.Parent.ProvisioningParameters),
 			pdt.AdministratorLogin,
 			pdt.ServerName,
@@ -1,16 +1,20 @@
 package postgresql
 
 import (
+	"context"
+
 	"github.com/Azure/open-service-broker-azure/pkg/service"
 )
 
 func (d *databaseManager) Unbind(
+	ctx context.Context,
 	instance service.Instance,
 	binding service.Binding,
 ) error {
 	pdt := instance.Parent.Details.(*dbmsInstanceDetails)
 	bd := binding.Details.(*bindingDetails)
 	return unbind(
+		ctx,
 		isSSLRequired(*instance.Parent.ProvisioningParameters),
 		pdt.AdministratorLogin,
 		pdt.ServerName,
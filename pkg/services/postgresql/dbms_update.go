@@ -24,7 +24,7 @@ func (d *dbmsManager) GetUpdater(service.Plan) (service.Updater, error) {
 }
 
 func (d *dbmsManager) updateARMTemplate(
-	_ context.Context,
+	ctx context.Context,
 	instance service.Instance,
 ) (service.InstanceDetails, error) {
 	dt := instance.Details.(*dbmsInstanceDetails)
@@ -45,6 +45,7 @@ func (d *dbmsManager) updateARMTemplate(
 		tags[k] = tagsObj.GetString(k)
 	}
 	_, err = d.armDeployer.Update(
+		ctx,
 		dt.ARMDeploymentName,
 		instance.UpdatingParameters.GetString("resourceGroup"),
 		instance.UpdatingParameters.GetString("location"),
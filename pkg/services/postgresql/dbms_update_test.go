@@ -91,5 +91,5 @@ func TestValidateStorageDecreaseFails(t *testing.T) {
 	assert.NotNil(t, err)
 	v, ok := err.(*service.ValidationError)
 	assert.True(t, ok)
-	assert.Equal(t, "storage", v.Field)
+	assert.Equal(t, "/storage", v.Field)
 }
@@ -3,6 +3,7 @@ package postgresql
 import (
 	postgresSDK "github.com/Azure/azure-sdk-for-go/services/postgresql/mgmt/2017-12-01/postgresql" // nolint: lll
 	"github.com/Azure/open-service-broker-azure/pkg/azure/arm"
+	"github.com/Azure/open-service-broker-azure/pkg/health"
 	"github.com/Azure/open-service-broker-azure/pkg/service"
 )
 
@@ -61,3 +62,7 @@ func New(
 func (m *module) GetName() string {
 	return "postgresql"
 }
+
+func (m *module) GetHealthChecks() []health.Check {
+	return nil
+}
@@ -1,6 +1,8 @@
 package rediscache
 
 import (
+	"context"
+
 	"fmt"
 	"net/url"
 
@@ -8,6 +10,7 @@ import (
 )
 
 func (s *serviceManager) Bind(
+	context.Context,
 	service.Instance,
 	service.BindingParameters,
 ) (service.BindingDetails, error) {
@@ -91,7 +91,10 @@ func (pd planDetail) getProvisioningParamsSchema() service.InputParametersSchema
 					CustomPropertyValidator: ipValidator,
 				},
 			},
-			CustomPropertyValidator: subnetSettingsValidator,
+			// subnetIP can be provided only when subnetId is provided
+			Dependencies: map[string]service.PropertySchema{
+				"subnetIP": service.PropertyDependency{"subnetId"},
+			},
 		}
 	}
 	return ips
@@ -161,18 +164,3 @@ func ipValidator(context, value string) error {
 	}
 	return nil
 }
-
-func subnetSettingsValidator(
-	context string,
-	value map[string]interface{},
-) error {
-	_, idOccured := value["subnetId"]
-	_, ipOccured := value["subnetIP"]
-	if !idOccured && ipOccured {
-		return service.NewValidationError(
-			context,
-			"subnetIP can be provided only when subnetId is provided",
-		)
-	}
-	return nil
-}
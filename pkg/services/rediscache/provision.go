@@ -31,7 +31,7 @@ func (s *serviceManager) preProvision(
 }
 
 func (s *serviceManager) deployARMTemplate(
-	_ context.Context,
+	ctx context.Context,
 	instance service.Instance,
 ) (service.InstanceDetails, error) {
 	dt := instance.Details.(*instanceDetails)
@@ -43,6 +43,7 @@ func (s *serviceManager) deployARMTemplate(
 	}
 
 	outputs, err := s.armDeployer.Deploy(
+		ctx,
 		dt.ARMDeploymentName,
 		instance.ProvisioningParameters.GetString("resourceGroup"),
 		instance.ProvisioningParameters.GetString("location"),
@@ -3,6 +3,7 @@ package rediscache
 import (
 	redisSDK "github.com/Azure/azure-sdk-for-go/services/redis/mgmt/2017-10-01/redis" // nolint: lll
 	"github.com/Azure/open-service-broker-azure/pkg/azure/arm"
+	"github.com/Azure/open-service-broker-azure/pkg/health"
 	"github.com/Azure/open-service-broker-azure/pkg/service"
 )
 
@@ -33,6 +34,10 @@ func (m *module) GetName() string {
 	return "rediscache"
 }
 
+func (m *module) GetHealthChecks() []health.Check {
+	return nil
+}
+
 func (m *module) GetStability() service.Stability {
 	return service.StabilityExperimental
 }
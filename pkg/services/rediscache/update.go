@@ -42,7 +42,7 @@ func (s *serviceManager) GetUpdater(service.Plan) (service.Updater, error) {
 }
 
 func (s *serviceManager) updateARMTemplate(
-	_ context.Context,
+	ctx context.Context,
 	instance service.Instance,
 ) (service.InstanceDetails, error) {
 	dt := instance.Details.(*instanceDetails)
@@ -54,6 +54,7 @@ func (s *serviceManager) updateARMTemplate(
 	}
 
 	_, err := s.armDeployer.Update(
+		ctx,
 		dt.ARMDeploymentName,
 		instance.ProvisioningParameters.GetString("resourceGroup"),
 		instance.ProvisioningParameters.GetString("location"),
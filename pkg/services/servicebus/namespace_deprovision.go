@@ -18,11 +18,12 @@ func (nm *namespaceManager) GetDeprovisioner(
 }
 
 func (nm *namespaceManager) deleteARMDeployment(
-	_ context.Context,
+	ctx context.Context,
 	instance service.Instance,
 ) (service.InstanceDetails, error) {
 	dt := instance.Details.(*namespaceInstanceDetails)
 	if err := nm.armDeployer.Delete(
+		ctx,
 		dt.ARMDeploymentName,
 		instance.ProvisioningParameters.GetString("resourceGroup"),
 	); err != nil {
@@ -28,7 +28,7 @@ func (nm *namespaceManager) preProvision(
 }
 
 func (nm *namespaceManager) deployARMTemplate(
-	_ context.Context,
+	ctx context.Context,
 	instance service.Instance,
 ) (service.InstanceDetails, error) {
 	dt := instance.Details.(*namespaceInstanceDetails)
@@ -38,6 +38,7 @@ func (nm *namespaceManager) deployARMTemplate(
 		tags[k] = tagsObj.GetString(k)
 	}
 	outputs, err := nm.armDeployer.Deploy(
+		ctx,
 		dt.ARMDeploymentName,
 		instance.ProvisioningParameters.GetString("resourceGroup"),
 		instance.ProvisioningParameters.GetString("location"),
@@ -10,6 +10,7 @@ import (
 )
 
 func (tm *topicManager) Bind(
+	_ context.Context,
 	instance service.Instance,
 	bindingParameters service.BindingParameters,
 ) (service.BindingDetails, error) {
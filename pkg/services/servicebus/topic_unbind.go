@@ -7,6 +7,7 @@ import (
 )
 
 func (tm *topicManager) Unbind(
+	_ context.Context,
 	instance service.Instance,
 	binding service.Binding,
 ) error {
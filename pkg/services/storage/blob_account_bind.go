@@ -1,12 +1,15 @@
 package storage
 
 import (
+	"context"
+
 	"fmt"
 
 	"github.com/Azure/open-service-broker-azure/pkg/service"
 )
 
 func (b *blobAccountManager) Bind(
+	context.Context,
 	service.Instance,
 	service.BindingParameters,
 ) (service.BindingDetails, error) {
@@ -21,7 +24,7 @@ func (b *blobAccountManager) GetCredentials(
 	dt := instance.Details.(*instanceDetails)
 	credential := credentials{
 		StorageAccountName:         dt.StorageAccountName,
-		AccessKey:                  dt.AccessKey,
+		AccessKey:                  string(dt.AccessKey),
 		PrimaryBlobServiceEndPoint: fmt.Sprintf("https://%s.blob.core.windows.net/", dt.StorageAccountName),
 	}
 	return credential, nil
@@ -42,7 +42,7 @@ func (b *blobAllInOneManager) createBlobContainer(
 
 	if err := createBlobContainer(
 		dt.StorageAccountName,
-		dt.AccessKey,
+		string(dt.AccessKey),
 		dt.ContainerName,
 	); err != nil {
 		return nil, err
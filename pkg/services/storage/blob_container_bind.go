@@ -1,12 +1,15 @@
 package storage
 
 import (
+	"context"
+
 	"fmt"
 
 	"github.com/Azure/open-service-broker-azure/pkg/service"
 )
 
 func (b *blobContainerManager) Bind(
+	context.Context,
 	service.Instance,
 	service.BindingParameters,
 ) (service.BindingDetails, error) {
@@ -21,7 +24,7 @@ func (b *blobContainerManager) GetCredentials(
 	dt := instance.Details.(*instanceDetails)
 	credential := credentials{
 		StorageAccountName:         dt.StorageAccountName,
-		AccessKey:                  dt.AccessKey,
+		AccessKey:                  string(dt.AccessKey),
 		ContainerName:              dt.ContainerName,
 		PrimaryBlobServiceEndPoint: fmt.Sprintf("https://%s.blob.core.windows.net/", dt.StorageAccountName),
 	}
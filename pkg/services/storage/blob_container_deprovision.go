@@ -21,7 +21,7 @@ func (b *blobContainerManager) deleteBlobContainer(
 	dt := instance.Details.(*instanceDetails)
 	if err := deleteBlobContainer(
 		dt.StorageAccountName,
-		dt.AccessKey,
+		string(dt.AccessKey),
 		dt.ContainerName,
 	); err != nil {
 		return nil, err
@@ -31,7 +31,7 @@ func (b *blobContainerManager) checkNameAvailability(
 	pdt := instance.Parent.Details.(*instanceDetails)
 	client, _ := storage.NewBasicClient(
 		pdt.StorageAccountName,
-		pdt.AccessKey,
+		string(pdt.AccessKey),
 	)
 	blobCli := client.GetBlobService()
 	response, err := blobCli.ListContainers(storage.ListContainersParameters{})
@@ -71,7 +71,7 @@ func (b *blobContainerManager) createBlobContainer(
 	dt := instance.Details.(*instanceDetails)
 	if err := createBlobContainer(
 		dt.StorageAccountName,
-		dt.AccessKey,
+		string(dt.AccessKey),
 		dt.ContainerName,
 	); err != nil {
 		return nil, err
@@ -30,7 +30,7 @@ func (s *storageManager) preProvision(
 }
 
 func (s *storageManager) deployARMTemplate(
-	_ context.Context,
+	ctx context.Context,
 	instance service.Instance,
 ) (service.InstanceDetails, error) {
 	dt := instance.Details.(*instanceDetails)
@@ -43,6 +43,7 @@ func (s *storageManager) deployARMTemplate(
 	}
 
 	outputs, err := s.armDeployer.Deploy(
+		ctx,
 		dt.ARMDeploymentName,
 		instance.ProvisioningParameters.GetString("resourceGroup"),
 		instance.ProvisioningParameters.GetString("location"),
@@ -62,6 +63,6 @@ func (s *storageManager) deployARMTemplate(
 			err,
 		)
 	}
-	dt.AccessKey = accessKey
+	dt.AccessKey = service.MigratedSecureString(accessKey)
 	return dt, nil
 }
@@ -1,9 +1,15 @@
 package storage
 
 import (
+	"context"
+
 	"github.com/Azure/open-service-broker-azure/pkg/service"
 )
 
-func (s *storageManager) Unbind(service.Instance, service.Binding) error {
+func (s *storageManager) Unbind(
+	context.Context,
+	service.Instance,
+	service.Binding,
+) error {
 	return nil
 }
@@ -31,7 +31,7 @@ func (s *storageManager) GetUpdater(service.Plan) (service.Updater, error) {
 }
 
 func (s *storageManager) updateARMTemplate(
-	_ context.Context,
+	ctx context.Context,
 	instance service.Instance,
 ) (service.InstanceDetails, error) {
 	dt := instance.Details.(*instanceDetails)
@@ -42,6 +42,7 @@ func (s *storageManager) updateARMTemplate(
 		tags[k] = tagsObj.GetString(k)
 	}
 	_, err := s.armDeployer.Update(
+		ctx,
 		dt.ARMDeploymentName,
 		up.GetString("resourceGroup"),
 		up.GetString("location"),
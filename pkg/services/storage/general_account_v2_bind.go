@@ -1,12 +1,15 @@
 package storage
 
 import (
+	"context"
+
 	"fmt"
 
 	"github.com/Azure/open-service-broker-azure/pkg/service"
 )
 
 func (*generalPurposeV2Manager) Bind(
+	context.Context,
 	service.Instance,
 	service.BindingParameters,
 ) (service.BindingDetails, error) {
@@ -21,7 +24,7 @@ func (gpv2m *generalPurposeV2Manager) GetCredentials(
 	dt := instance.Details.(*instanceDetails)
 	credential := credentials{
 		StorageAccountName:          dt.StorageAccountName,
-		AccessKey:                   dt.AccessKey,
+		AccessKey:                   string(dt.AccessKey),
 		ContainerName:               dt.ContainerName,
 		PrimaryBlobServiceEndPoint:  fmt.Sprintf("https://%s.blob.core.windows.net/", dt.StorageAccountName),
 		PrimaryTableServiceEndPoint: fmt.Sprintf("https://%s.table.core.windows.net/", dt.StorageAccountName),
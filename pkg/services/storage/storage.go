@@ -3,6 +3,7 @@ package storage
 import (
 	storageSDK "github.com/Azure/azure-sdk-for-go/services/storage/mgmt/2017-10-01/storage" // nolint: lll
 	"github.com/Azure/open-service-broker-azure/pkg/azure/arm"
+	"github.com/Azure/open-service-broker-azure/pkg/health"
 	"github.com/Azure/open-service-broker-azure/pkg/service"
 )
 
@@ -61,3 +62,7 @@ func New(
 func (m *module) GetName() string {
 	return "storage"
 }
+
+func (m *module) GetHealthChecks() []health.Check {
+	return nil
+}
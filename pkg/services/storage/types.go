@@ -3,10 +3,10 @@ package storage
 import "github.com/Azure/open-service-broker-azure/pkg/service"
 
 type instanceDetails struct {
-	ARMDeploymentName  string `json:"armDeployment"`
-	StorageAccountName string `json:"storageAccountName"`
-	ContainerName      string `json:"containerName"`
-	AccessKey          string `json:"accessKey"`
+	ARMDeploymentName  string                       `json:"armDeployment"`
+	StorageAccountName string                       `json:"storageAccountName"`
+	ContainerName      string                       `json:"containerName"`
+	AccessKey          service.MigratedSecureString `json:"accessKey"`
 }
 
 type credentials struct {
@@ -1,10 +1,13 @@
 package textanalytics
 
 import (
+	"context"
+
 	"github.com/Azure/open-service-broker-azure/pkg/service"
 )
 
 func (s *serviceManager) Bind(
+	context.Context,
 	service.Instance,
 	service.BindingParameters,
 ) (service.BindingDetails, error) {
@@ -17,7 +20,7 @@ func (s *serviceManager) GetCredentials(
 ) (service.Credentials, error) {
 	dt := instance.Details.(*instanceDetails)
 	return &credentials{
-		TextAnalyticsKey:  dt.TextAnalyticsKey,
+		TextAnalyticsKey:  string(dt.TextAnalyticsKey),
 		Endpoint:          dt.Endpoint,
 		TextAnalyticsName: dt.TextAnalyticsName,
 	}, nil
@@ -28,7 +28,7 @@ func (s *serviceManager) preProvision(
 }
 
 func (s *serviceManager) deployARMTemplate(
-	_ context.Context,
+	ctx context.Context,
 	instance service.Instance,
 ) (service.InstanceDetails, error) {
 	dt := instance.Details.(*instanceDetails)
@@ -39,7 +39,7 @@ func (s *serviceManager) deployARMTemplate(
 	}
 
 	outputs, err := s.armDeployer.Deploy(
-
+		ctx,
 		dt.ARMDeploymentName,
 		instance.ProvisioningParameters.GetString("resourceGroup"),
 		instance.ProvisioningParameters.GetString("location"),
@@ -57,13 +57,14 @@ func (s *serviceManager) deployARMTemplate(
 	}
 	var ok bool
 
-	dt.TextAnalyticsKey, ok = outputs["cognitivekey"].(string)
+	textAnalyticsKey, ok := outputs["cognitivekey"].(string)
 	if !ok {
 		return nil, fmt.Errorf(
 			"error retrieving key from deployment: %s",
 			err,
 		)
 	}
+	dt.TextAnalyticsKey = service.MigratedSecureString(textAnalyticsKey)
 
 	dt.Endpoint, ok = outputs["endpoint"].(string)
 	if !ok {
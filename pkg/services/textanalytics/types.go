@@ -3,10 +3,10 @@ package textanalytics
 import "github.com/Azure/open-service-broker-azure/pkg/service"
 
 type instanceDetails struct {
-	ARMDeploymentName string `json:"armDeployment"`
-	TextAnalyticsName string `json:"textAnalyticsName"`
-	TextAnalyticsKey  string `json:"textAnalyticsKey"`
-	Endpoint          string `json:"textAnalyticsEndpoint"`
+	ARMDeploymentName string                       `json:"armDeployment"`
+	TextAnalyticsName string                       `json:"textAnalyticsName"`
+	TextAnalyticsKey  service.MigratedSecureString `json:"textAnalyticsKey"`
+	Endpoint          string                       `json:"textAnalyticsEndpoint"`
 }
 
 type credentials struct {
@@ -0,0 +1,35 @@
+package bolt
+
+import (
+	"time"
+
+	"github.com/kelseyhightower/envconfig"
+)
+
+const envconfigPrefix = "STORAGE"
+
+// Config represents configuration options for the Bolt-based implementation
+// of the Store interface
+type Config struct {
+	BoltPath string `envconfig:"BOLT_PATH" required:"true"`
+	// BoltTimeout is the amount of time to wait for an exclusive lock on the
+	// database file. Only one process may have the file open at a time.
+	BoltTimeout time.Duration `envconfig:"BOLT_TIMEOUT"`
+}
+
+// NewConfigWithDefaults returns a Config object with default values already
+// applied. Callers are then free to set custom values for the remaining fields
+// and/or override default values.
+func NewConfigWithDefaults() Config {
+	return Config{
+		BoltTimeout: 10 * time.Second,
+	}
+}
+
+// GetConfigFromEnvironment returns configuration derived from environment
+// variables
+func GetConfigFromEnvironment() (Config, error) {
+	c := NewConfigWithDefaults()
+	err := envconfig.Process(envconfigPrefix, &c)
+	return c, err
+}
@@ -0,0 +1,47 @@
+package bolt
+
+import (
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Azure/open-service-broker-azure/pkg/crypto"
+	"github.com/Azure/open-service-broker-azure/pkg/crypto/noop"
+	"github.com/Azure/open-service-broker-azure/pkg/services/fake"
+)
+
+var (
+	testStore *store
+	config    Config
+)
+
+func TestMain(m *testing.M) {
+	if err := crypto.InitializeGlobalCodec(noop.NewCodec()); err != nil {
+		log.Fatal(err)
+	}
+	fakeModule, err := fake.New()
+	if err != nil {
+		log.Fatal(err)
+	}
+	fakeCatalog, err := fakeModule.GetCatalog()
+	if err != nil {
+		log.Fatal(err)
+	}
+	dir, err := ioutil.TempDir("", "osba-bolt-test")
+	if err != nil {
+		log.Fatal(err)
+	}
+	config = NewConfigWithDefaults()
+	config.BoltPath = filepath.Join(dir, "broker.db")
+	str, err := NewStore(fakeCatalog, config)
+	if err != nil {
+		log.Fatal(err)
+	}
+	testStore = str.(*store)
+	code := m.Run()
+	testStore.db.Close() // nolint: errcheck
+	os.RemoveAll(dir)    // nolint: errcheck
+	os.Exit(code)
+}
@@ -0,0 +1,508 @@
+package bolt
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/Azure/open-service-broker-azure/pkg/service"
+	"github.com/Azure/open-service-broker-azure/pkg/storage"
+	bbolt "github.com/coreos/bbolt"
+)
+
+var (
+	instancesBucket             = []byte("instances")
+	instanceAliasesBucket       = []byte("instances:aliases")
+	instanceAliasChildrenBucket = []byte("instances:aliases:children")
+	bindingsBucket              = []byte("bindings")
+	deletedInstancesBucket      = []byte("deleted:instances")
+	deletedBindingsBucket       = []byte("deleted:bindings")
+)
+
+// store is a Bolt-based implementation of the Store interface. Bolt permits
+// any number of concurrent read-only transactions, but serializes read-write
+// transactions, so the store is safe for concurrent use without any
+// additional locking of its own.
+type store struct {
+	db      *bbolt.DB
+	catalog service.Catalog
+}
+
+// NewStore returns a new Bolt-based implementation of the Store interface.
+// All data is persisted to a single file on local disk, which makes this
+// implementation suitable only for single-node deployments of the broker.
+func NewStore(
+	catalog service.Catalog,
+	config Config,
+) (storage.Store, error) {
+	if err := os.MkdirAll(filepath.Dir(config.BoltPath), 0700); err != nil {
+		return nil, fmt.Errorf(
+			`error creating directory for database file "%s": %s`,
+			config.BoltPath,
+			err,
+		)
+	}
+	db, err := bbolt.Open(
+		config.BoltPath,
+		0600,
+		&bbolt.Options{Timeout: config.BoltTimeout},
+	)
+	if err != nil {
+		return nil, fmt.Errorf(
+			`error opening database file "%s": %s`,
+			config.BoltPath,
+			err,
+		)
+	}
+	err = db.Update(func(tx *bbolt.Tx) error {
+		for _, bucket := range [][]byte{
+			instancesBucket,
+			instanceAliasesBucket,
+			instanceAliasChildrenBucket,
+			bindingsBucket,
+			deletedInstancesBucket,
+			deletedBindingsBucket,
+		} {
+			if _, err := tx.CreateBucketIfNotExists(bucket); err != nil {
+				return fmt.Errorf(`error creating bucket "%s": %s`, bucket, err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		db.Close() // nolint: errcheck
+		return nil, err
+	}
+	return &store{
+		db:      db,
+		catalog: catalog,
+	}, nil
+}
+
+func (s *store) WriteInstance(instance service.Instance) error {
+	json, err := instance.ToJSON()
+	if err != nil {
+		return err
+	}
+	err = s.db.Update(func(tx *bbolt.Tx) error {
+		instanceID := []byte(instance.InstanceID)
+		if err := tx.Bucket(instancesBucket).Put(instanceID, json); err != nil {
+			return err
+		}
+		if instance.Alias != "" {
+			if err := tx.Bucket(instanceAliasesBucket).Put(
+				[]byte(instance.Alias),
+				instanceID,
+			); err != nil {
+				return err
+			}
+		}
+		if instance.ParentAlias != "" {
+			children, err := tx.Bucket(instanceAliasChildrenBucket).
+				CreateBucketIfNotExists([]byte(instance.ParentAlias))
+			if err != nil {
+				return err
+			}
+			// The children bucket is used as a set, so only keys are meaningful
+			if err := children.Put(instanceID, []byte{}); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf(
+			`error writing instance "%s": %s`,
+			instance.InstanceID,
+			err,
+		)
+	}
+	return nil
+}
+
+func (s *store) GetInstance(instanceID string) (service.Instance, bool, error) {
+	bytes, err := s.get(instancesBucket, instanceID)
+	if err != nil {
+		return service.Instance{}, false, err
+	}
+	if bytes == nil {
+		return service.Instance{}, false, nil
+	}
+	return s.getInstanceFromJSON(bytes)
+}
+
+func (s *store) getInstanceFromJSON(
+	bytes []byte,
+) (service.Instance, bool, error) {
+	instance, err := service.NewInstanceFromJSON(bytes, nil, nil)
+	if err != nil {
+		return instance, false, err
+	}
+	svc, ok := s.catalog.GetService(instance.ServiceID)
+	if !ok {
+		return instance,
+			false,
+			fmt.Errorf(
+				`service not found in catalog for service ID "%s"`,
+				instance.ServiceID,
+			)
+	}
+	plan, ok := svc.GetPlan(instance.PlanID)
+	if !ok {
+		return instance,
+			false,
+			fmt.Errorf(
+				`plan not found for planID "%s" for service "%s" in the catalog`,
+				instance.PlanID,
+				instance.ServiceID,
+			)
+	}
+	pps := plan.GetSchemas().ServiceInstances.ProvisioningParametersSchema
+	instance, err = service.NewInstanceFromJSON(
+		bytes,
+		svc.GetServiceManager().GetEmptyInstanceDetails(),
+		&pps,
+	)
+	instance.Service = svc
+	instance.Plan = plan
+	if instance.ParentAlias != "" {
+		parent, ok, err := s.GetInstanceByAlias(instance.ParentAlias)
+		if err != nil {
+			return instance, false, fmt.Errorf(
+				`error retrieving parent with alias "%s" for instance "%s"`,
+				instance.ParentAlias,
+				instance.InstanceID,
+			)
+		}
+		if ok {
+			instance.Parent = &parent
+		}
+	}
+	return instance, err == nil, err
+}
+
+func (s *store) GetInstances() ([]service.Instance, error) {
+	values, err := s.getAll(instancesBucket)
+	if err != nil {
+		return nil, err
+	}
+	instances := []service.Instance{}
+	for _, bytes := range values {
+		instance, _, err := s.getInstanceFromJSON(bytes)
+		if err != nil {
+			return nil, err
+		}
+		instances = append(instances, instance)
+	}
+	return instances, nil
+}
+
+func (s *store) GetInstanceByAlias(
+	alias string,
+) (service.Instance, bool, error) {
+	instanceID, err := s.get(instanceAliasesBucket, alias)
+	if err != nil {
+		return service.Instance{}, false, err
+	}
+	if instanceID == nil {
+		return service.Instance{}, false, nil
+	}
+	return s.GetInstance(string(instanceID))
+}
+
+func (s *store) DeleteInstance(instanceID string) (bool, error) {
+	var ok bool
+	// The instance is read and tombstoned in a single read-write transaction so
+	// that concurrent writes to the same instance cannot be lost or resurrected
+	err := s.db.Update(func(tx *bbolt.Tx) error {
+		key := []byte(instanceID)
+		instances := tx.Bucket(instancesBucket)
+		bytes := instances.Get(key)
+		if bytes == nil {
+			return nil
+		}
+		ok = true
+		instance := struct {
+			Alias       string `json:"alias"`
+			ParentAlias string `json:"parentAlias"`
+		}{}
+		if err := json.Unmarshal(bytes, &instance); err != nil {
+			return err
+		}
+		tombstone, err := markDeleted(bytes)
+		if err != nil {
+			return err
+		}
+		if err = instances.Delete(key); err != nil {
+			return err
+		}
+		if err = tx.Bucket(deletedInstancesBucket).Put(key, tombstone); err != nil {
+			return err
+		}
+		if instance.Alias != "" {
+			if err = tx.Bucket(instanceAliasesBucket).Delete(
+				[]byte(instance.Alias),
+			); err != nil {
+				return err
+			}
+		}
+		if instance.ParentAlias != "" {
+			children := tx.Bucket(instanceAliasChildrenBucket).
+				Bucket([]byte(instance.ParentAlias))
+			if children != nil {
+				return children.Delete(key)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return false, fmt.Errorf(
+			`error deleting instance "%s": %s`,
+			instanceID,
+			err,
+		)
+	}
+	return ok, nil
+}
+
+func (s *store) GetDeletedInstances() ([]service.Instance, error) {
+	values, err := s.getAll(deletedInstancesBucket)
+	if err != nil {
+		return nil, err
+	}
+	instances := []service.Instance{}
+	for _, bytes := range values {
+		instance, _, err := s.getInstanceFromJSON(bytes)
+		if err != nil {
+			return nil, err
+		}
+		instances = append(instances, instance)
+	}
+	sort.Slice(instances, func(i, j int) bool {
+		return instances[i].Deleted.After(*instances[j].Deleted)
+	})
+	return instances, nil
+}
+
+func (s *store) GetInstanceChildCountByAlias(alias string) (int64, error) {
+	var count int64
+	err := s.db.View(func(tx *bbolt.Tx) error {
+		children := tx.Bucket(instanceAliasChildrenBucket).Bucket([]byte(alias))
+		if children == nil {
+			return nil
+		}
+		count = int64(children.Stats().KeyN)
+		return nil
+	})
+	return count, err
+}
+
+func (s *store) WriteBinding(binding service.Binding) error {
+	json, err := binding.ToJSON()
+	if err != nil {
+		return err
+	}
+	err = s.db.Update(func(tx *bbolt.Tx) error {
+		return tx.Bucket(bindingsBucket).Put([]byte(binding.BindingID), json)
+	})
+	if err != nil {
+		return fmt.Errorf(
+			`error writing binding "%s": %s`,
+			binding.BindingID,
+			err,
+		)
+	}
+	return nil
+}
+
+func (s *store) GetBinding(bindingID string) (service.Binding, bool, error) {
+	bytes, err := s.get(bindingsBucket, bindingID)
+	if err != nil {
+		return service.Binding{}, false, err
+	}
+	if bytes == nil {
+		return service.Binding{}, false, nil
+	}
+	return s.getBindingFromJSON(bytes)
+}
+
+func (s *store) GetBindings() ([]service.Binding, error) {
+	values, err := s.getAll(bindingsBucket)
+	if err != nil {
+		return nil, err
+	}
+	bindings := []service.Binding{}
+	for _, bytes := range values {
+		binding, _, err := s.getBindingFromJSON(bytes)
+		if err != nil {
+			return nil, err
+		}
+		bindings = append(bindings, binding)
+	}
+	return bindings, nil
+}
+
+func (s *store) getBindingFromJSON(
+	bytes []byte,
+) (service.Binding, bool, error) {
+	binding, err := service.NewBindingFromJSON(bytes, nil, nil)
+	if err != nil {
+		return binding, false, err
+	}
+	instance, ok, err := s.GetInstance(binding.InstanceID)
+	if err != nil {
+		return binding, false, err
+	}
+	// Now that we have schema for binding params, take a second pass at getting a
+	// binding from the JSON
+	if ok {
+		bps := instance.Plan.GetSchemas().ServiceBindings.BindingParametersSchema
+		binding, err = service.NewBindingFromJSON(
+			bytes,
+			instance.Service.GetServiceManager().GetEmptyBindingDetails(),
+			&bps,
+		)
+	}
+	return binding, err == nil, err
+}
+
+func (s *store) DeleteBinding(bindingID string) (bool, error) {
+	var ok bool
+	// The binding is read and tombstoned in a single read-write transaction so
+	// that concurrent writes to the same binding cannot be lost or resurrected
+	err := s.db.Update(func(tx *bbolt.Tx) error {
+		key := []byte(bindingID)
+		bindings := tx.Bucket(bindingsBucket)
+		bytes := bindings.Get(key)
+		if bytes == nil {
+			return nil
+		}
+		ok = true
+		tombstone, err := markDeleted(bytes)
+		if err != nil {
+			return err
+		}
+		if err = bindings.Delete(key); err != nil {
+			return err
+		}
+		return tx.Bucket(deletedBindingsBucket).Put(key, tombstone)
+	})
+	if err != nil {
+		return false, fmt.Errorf(
+			`error deleting binding "%s": %s`,
+			bindingID,
+			err,
+		)
+	}
+	return ok, nil
+}
+
+func (s *store) PurgeDeleted(deletedBefore time.Time) (int64, error) {
+	var count int64
+	err := s.db.Update(func(tx *bbolt.Tx) error {
+		for _, bucketName := range [][]byte{
+			deletedInstancesBucket,
+			deletedBindingsBucket,
+		} {
+			bucket := tx.Bucket(bucketName)
+			// Keys can't be safely deleted while iterating with ForEach, so
+			// collect them first
+			keys := [][]byte{}
+			err := bucket.ForEach(func(k, v []byte) error {
+				record := struct {
+					Deleted time.Time `json:"deleted"`
+				}{}
+				if err := json.Unmarshal(v, &record); err != nil {
+					return fmt.Errorf(
+						`error reading deleted record "%s": %s`,
+						k,
+						err,
+					)
+				}
+				if record.Deleted.Before(deletedBefore) {
+					keys = append(keys, k)
+				}
+				return nil
+			})
+			if err != nil {
+				return err
+			}
+			for _, k := range keys {
+				if err := bucket.Delete(k); err != nil {
+					return err
+				}
+				count++
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, fmt.Errorf("error purging deleted records: %s", err)
+	}
+	return count, nil
+}
+
+func (s *store) TestConnection(ctx context.Context) error {
+	// Bolt is embedded, so this can't block on the network, but it can block
+	// while the database file is being remapped
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return s.db.View(func(tx *bbolt.Tx) error {
+		if tx.Bucket(instancesBucket) == nil {
+			return fmt.Errorf(`bucket "%s" does not exist`, instancesBucket)
+		}
+		return nil
+	})
+}
+
+// markDeleted returns a copy of the given JSON-encoded instance or binding
+// with its deleted timestamp set to the current time. The record is
+// manipulated as raw JSON so that it can be tombstoned within a read-write
+// transaction without decrypting it or resolving its service and plan.
+func markDeleted(bytes []byte) ([]byte, error) {
+	record := map[string]json.RawMessage{}
+	if err := json.Unmarshal(bytes, &record); err != nil {
+		return nil, err
+	}
+	deleted, err := json.Marshal(time.Now())
+	if err != nil {
+		return nil, err
+	}
+	record["deleted"] = deleted
+	return json.Marshal(record)
+}
+
+// get retrieves the value stored under the given key in the given bucket. If
+// no such value exists, a nil slice is returned. Since byte slices returned by
+// Bolt are only valid for the life of the transaction that produced them, the
+// value is copied before it is returned.
+func (s *store) get(bucket []byte, key string) ([]byte, error) {
+	var value []byte
+	err := s.db.View(func(tx *bbolt.Tx) error {
+		if v := tx.Bucket(bucket).Get([]byte(key)); v != nil {
+			value = make([]byte, len(v))
+			copy(value, v)
+		}
+		return nil
+	})
+	return value, err
+}
+
+// getAll retrieves copies of all values stored in the given bucket
+func (s *store) getAll(bucket []byte) ([][]byte, error) {
+	values := [][]byte{}
+	err := s.db.View(func(tx *bbolt.Tx) error {
+		return tx.Bucket(bucket).ForEach(func(_, v []byte) error {
+			value := make([]byte, len(v))
+			copy(value, v)
+			values = append(values, value)
+			return nil
+		})
+	})
+	return values, err
+}
@@ -0,0 +1,328 @@
+package bolt
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Azure/open-service-broker-azure/pkg/service"
+	"github.com/Azure/open-service-broker-azure/pkg/services/fake"
+	uuid "github.com/satori/go.uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWriteInstance(t *testing.T) {
+	instance := getTestInstance()
+	// First assert that the instance doesn't exist in the database
+	bytes, err := testStore.get(instancesBucket, instance.InstanceID)
+	assert.Nil(t, err)
+	assert.Nil(t, bytes)
+	// Store the instance
+	err = testStore.WriteInstance(instance)
+	assert.Nil(t, err)
+	// Assert that the instance is now in the database
+	bytes, err = testStore.get(instancesBucket, instance.InstanceID)
+	assert.Nil(t, err)
+	assert.NotNil(t, bytes)
+}
+
+func TestWriteInstanceWithAlias(t *testing.T) {
+	instance := getTestInstance()
+	instance.Alias = uuid.NewV4().String()
+	// Store the instance
+	err := testStore.WriteInstance(instance)
+	assert.Nil(t, err)
+	// Assert that the alias now refers to the instance
+	instanceID, err := testStore.get(instanceAliasesBucket, instance.Alias)
+	assert.Nil(t, err)
+	assert.Equal(t, instance.InstanceID, string(instanceID))
+}
+
+func TestWriteInstanceWithParent(t *testing.T) {
+	instance := getTestInstance()
+	instance.ParentAlias = uuid.NewV4().String()
+	// First assert the parent has no children
+	count, err := testStore.GetInstanceChildCountByAlias(instance.ParentAlias)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(0), count)
+	// Store the instance-- twice, to ensure children are counted only once
+	err = testStore.WriteInstance(instance)
+	assert.Nil(t, err)
+	err = testStore.WriteInstance(instance)
+	assert.Nil(t, err)
+	// Assert that the parent now has one child
+	count, err = testStore.GetInstanceChildCountByAlias(instance.ParentAlias)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1), count)
+}
+
+func TestGetNonExistingInstance(t *testing.T) {
+	_, ok, err := testStore.GetInstance(uuid.NewV4().String())
+	assert.False(t, ok)
+	assert.Nil(t, err)
+}
+
+func TestGetExistingInstance(t *testing.T) {
+	instance := getTestInstance()
+	err := testStore.WriteInstance(instance)
+	assert.Nil(t, err)
+	// Retrieve the instance
+	retrievedInstance, ok, err := testStore.GetInstance(instance.InstanceID)
+	// Assert that the retrieval was successful
+	assert.Nil(t, err)
+	assert.True(t, ok)
+	// Blank out a few fields before we compare
+	retrievedInstance.Service = nil
+	retrievedInstance.Plan = nil
+	assert.Equal(t, instance, retrievedInstance)
+}
+
+func TestGetExistingInstanceWithParent(t *testing.T) {
+	parentInstance := getTestInstance()
+	parentInstance.Alias = uuid.NewV4().String()
+	err := testStore.WriteInstance(parentInstance)
+	assert.Nil(t, err)
+	instance := getTestInstance()
+	instance.ParentAlias = parentInstance.Alias
+	instance.Parent = &parentInstance
+	err = testStore.WriteInstance(instance)
+	assert.Nil(t, err)
+	// Retrieve the child instance
+	retrievedInstance, ok, err := testStore.GetInstance(instance.InstanceID)
+	// Assert that the retrieval was successful
+	assert.Nil(t, err)
+	assert.True(t, ok)
+	// Blank out a few fields before we compare
+	retrievedInstance.Service = nil
+	retrievedInstance.Parent.Service = nil
+	retrievedInstance.Plan = nil
+	retrievedInstance.Parent.Plan = nil
+	assert.Equal(t, instance, retrievedInstance)
+}
+
+func TestGetInstances(t *testing.T) {
+	instance := getTestInstance()
+	err := testStore.WriteInstance(instance)
+	assert.Nil(t, err)
+	instances, err := testStore.GetInstances()
+	assert.Nil(t, err)
+	var found bool
+	for _, retrievedInstance := range instances {
+		if retrievedInstance.InstanceID == instance.InstanceID {
+			found = true
+		}
+	}
+	assert.True(t, found)
+}
+
+func TestGetNonExistingInstanceByAlias(t *testing.T) {
+	_, ok, err := testStore.GetInstanceByAlias(uuid.NewV4().String())
+	assert.False(t, ok)
+	assert.Nil(t, err)
+}
+
+func TestGetExistingInstanceByAlias(t *testing.T) {
+	instance := getTestInstance()
+	instance.Alias = uuid.NewV4().String()
+	err := testStore.WriteInstance(instance)
+	assert.Nil(t, err)
+	// Retrieve the instance by alias
+	retrievedInstance, ok, err := testStore.GetInstanceByAlias(instance.Alias)
+	// Assert that the retrieval was successful
+	assert.Nil(t, err)
+	assert.True(t, ok)
+	// Blank out a few fields before we compare
+	retrievedInstance.Service = nil
+	retrievedInstance.Plan = nil
+	assert.Equal(t, instance, retrievedInstance)
+}
+
+func TestDeleteNonExistingInstance(t *testing.T) {
+	ok, err := testStore.DeleteInstance(uuid.NewV4().String())
+	assert.False(t, ok)
+	assert.Nil(t, err)
+}
+
+func TestDeleteExistingInstanceWithAliasAndParent(t *testing.T) {
+	instance := getTestInstance()
+	instance.Alias = uuid.NewV4().String()
+	instance.ParentAlias = uuid.NewV4().String()
+	err := testStore.WriteInstance(instance)
+	assert.Nil(t, err)
+	// Delete the instance
+	ok, err := testStore.DeleteInstance(instance.InstanceID)
+	// Assert that the delete was successful
+	assert.True(t, ok)
+	assert.Nil(t, err)
+	bytes, err := testStore.get(instancesBucket, instance.InstanceID)
+	assert.Nil(t, err)
+	assert.Nil(t, bytes)
+	// Assert that the alias is also gone
+	bytes, err = testStore.get(instanceAliasesBucket, instance.Alias)
+	assert.Nil(t, err)
+	assert.Nil(t, bytes)
+	// And that the parent no longer counts the instance as a child
+	count, err := testStore.GetInstanceChildCountByAlias(instance.ParentAlias)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(0), count)
+	// But the deleted instance is retained
+	bytes, err = testStore.get(deletedInstancesBucket, instance.InstanceID)
+	assert.Nil(t, err)
+	assert.NotNil(t, bytes)
+}
+
+func TestGetDeletedInstances(t *testing.T) {
+	instance := getTestInstance()
+	err := testStore.WriteInstance(instance)
+	assert.Nil(t, err)
+	ok, err := testStore.DeleteInstance(instance.InstanceID)
+	assert.True(t, ok)
+	assert.Nil(t, err)
+	// The deleted instance is no longer visible to normal lookups
+	_, ok, err = testStore.GetInstance(instance.InstanceID)
+	assert.False(t, ok)
+	assert.Nil(t, err)
+	// But is listed as deleted
+	deletedInstances, err := testStore.GetDeletedInstances()
+	assert.Nil(t, err)
+	var found bool
+	for _, deletedInstance := range deletedInstances {
+		if deletedInstance.InstanceID == instance.InstanceID {
+			found = true
+			assert.NotNil(t, deletedInstance.Deleted)
+		}
+	}
+	assert.True(t, found)
+}
+
+func TestPurgeDeleted(t *testing.T) {
+	instance := getTestInstance()
+	err := testStore.WriteInstance(instance)
+	assert.Nil(t, err)
+	binding := getTestBinding()
+	binding.InstanceID = instance.InstanceID
+	err = testStore.WriteBinding(binding)
+	assert.Nil(t, err)
+	_, err = testStore.DeleteBinding(binding.BindingID)
+	assert.Nil(t, err)
+	_, err = testStore.DeleteInstance(instance.InstanceID)
+	assert.Nil(t, err)
+	// Nothing is purged if it was deleted after the cutoff
+	_, err = testStore.PurgeDeleted(time.Now().Add(-time.Hour))
+	assert.Nil(t, err)
+	bytes, err := testStore.get(deletedInstancesBucket, instance.InstanceID)
+	assert.Nil(t, err)
+	assert.NotNil(t, bytes)
+	// Everything is purged if it was deleted before the cutoff
+	count, err := testStore.PurgeDeleted(time.Now().Add(time.Hour))
+	assert.Nil(t, err)
+	assert.True(t, count >= 2)
+	bytes, err = testStore.get(deletedInstancesBucket, instance.InstanceID)
+	assert.Nil(t, err)
+	assert.Nil(t, bytes)
+	bytes, err = testStore.get(deletedBindingsBucket, binding.BindingID)
+	assert.Nil(t, err)
+	assert.Nil(t, bytes)
+}
+
+func TestGetInstanceChildCountByAliasConcurrently(t *testing.T) {
+	const count = 20
+	parentAlias := uuid.NewV4().String()
+	wg := sync.WaitGroup{}
+	for i := 0; i < count; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			instance := getTestInstance()
+			instance.ParentAlias = parentAlias
+			assert.Nil(t, testStore.WriteInstance(instance))
+		}()
+	}
+	wg.Wait()
+	children, err := testStore.GetInstanceChildCountByAlias(parentAlias)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(count), children)
+}
+
+func TestWriteAndGetBinding(t *testing.T) {
+	binding := getTestBinding()
+	// First assert that the binding doesn't exist
+	_, ok, err := testStore.GetBinding(binding.BindingID)
+	assert.False(t, ok)
+	assert.Nil(t, err)
+	// Store the binding
+	err = testStore.WriteBinding(binding)
+	assert.Nil(t, err)
+	// Retrieve the binding
+	retrievedBinding, ok, err := testStore.GetBinding(binding.BindingID)
+	assert.True(t, ok)
+	assert.Nil(t, err)
+	assert.Equal(t, binding, retrievedBinding)
+}
+
+func TestGetBindings(t *testing.T) {
+	binding := getTestBinding()
+	err := testStore.WriteBinding(binding)
+	assert.Nil(t, err)
+	bindings, err := testStore.GetBindings()
+	assert.Nil(t, err)
+	var found bool
+	for _, retrievedBinding := range bindings {
+		if retrievedBinding.BindingID == binding.BindingID {
+			found = true
+		}
+	}
+	assert.True(t, found)
+}
+
+func TestDeleteNonExistingBinding(t *testing.T) {
+	ok, err := testStore.DeleteBinding(uuid.NewV4().String())
+	assert.False(t, ok)
+	assert.Nil(t, err)
+}
+
+func TestDeleteExistingBinding(t *testing.T) {
+	binding := getTestBinding()
+	err := testStore.WriteBinding(binding)
+	assert.Nil(t, err)
+	// Delete the binding
+	ok, err := testStore.DeleteBinding(binding.BindingID)
+	// Assert that the delete was successful
+	assert.True(t, ok)
+	assert.Nil(t, err)
+	bytes, err := testStore.get(bindingsBucket, binding.BindingID)
+	assert.Nil(t, err)
+	assert.Nil(t, bytes)
+	// But the deleted binding is retained
+	bytes, err = testStore.get(deletedBindingsBucket, binding.BindingID)
+	assert.Nil(t, err)
+	assert.NotNil(t, bytes)
+}
+
+func TestTestConnection(t *testing.T) {
+	assert.Nil(t, testStore.TestConnection(context.Background()))
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	assert.Equal(t, context.Canceled, testStore.TestConnection(ctx))
+}
+
+func getTestInstance() service.Instance {
+	return service.Instance{
+		InstanceID:   uuid.NewV4().String(),
+		ServiceID:    fake.ServiceID,
+		PlanID:       fake.StandardPlanID,
+		Status:       service.InstanceStateProvisioned,
+		StatusReason: "",
+	}
+}
+
+func getTestBinding() service.Binding {
+	return service.Binding{
+		BindingID:    uuid.NewV4().String(),
+		InstanceID:   uuid.NewV4().String(),
+		ServiceID:    fake.ServiceID,
+		Status:       service.BindingStateBound,
+		StatusReason: "",
+	}
+}
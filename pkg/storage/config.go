@@ -0,0 +1,51 @@
+package storage
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/kelseyhightower/envconfig"
+)
+
+const envconfigPrefix = "STORAGE"
+
+// Config represents configuration options for selecting a Store
+// implementation and for the retention of deleted records
+type Config struct {
+	StorageType string `envconfig:"TYPE" default:"REDIS"`
+	// DeletedRetention is how long deleted instances and bindings are retained
+	// before they are purged
+	DeletedRetention time.Duration `envconfig:"DELETED_RETENTION"`
+	// PurgeInterval is how often deleted instances and bindings that have
+	// exceeded the retention period are purged
+	PurgeInterval time.Duration `envconfig:"PURGE_INTERVAL"`
+}
+
+// NewConfigWithDefaults returns a Config object with default values already
+// applied. Callers are then free to set custom values for the remaining fields
+// and/or override default values.
+func NewConfigWithDefaults() Config {
+	return Config{
+		DeletedRetention: 30 * 24 * time.Hour,
+		PurgeInterval:    time.Hour,
+	}
+}
+
+// GetConfigFromEnvironment returns configuration derived from environment
+// variables
+func GetConfigFromEnvironment() (Config, error) {
+	c := NewConfigWithDefaults()
+	err := envconfig.Process(envconfigPrefix, &c)
+	if err != nil {
+		return c, err
+	}
+	c.StorageType = strings.ToUpper(c.StorageType)
+	if c.PurgeInterval <= 0 {
+		return c, fmt.Errorf(
+			"environment variable %s_PURGE_INTERVAL must be a positive duration",
+			envconfigPrefix,
+		)
+	}
+	return c, nil
+}
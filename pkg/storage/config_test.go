@@ -0,0 +1,28 @@
+package storage
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetConfigWithPurgeInterval(t *testing.T) {
+	err := os.Setenv("STORAGE_PURGE_INTERVAL", "5m")
+	assert.Nil(t, err)
+	defer os.Unsetenv("STORAGE_PURGE_INTERVAL") // nolint: errcheck
+	c, err := GetConfigFromEnvironment()
+	assert.Nil(t, err)
+	assert.Equal(t, 5*time.Minute, c.PurgeInterval)
+}
+
+func TestGetConfigWithNonPositivePurgeInterval(t *testing.T) {
+	for _, interval := range []string{"0", "-1h"} {
+		err := os.Setenv("STORAGE_PURGE_INTERVAL", interval)
+		assert.Nil(t, err)
+		_, err = GetConfigFromEnvironment()
+		assert.NotNil(t, err, interval)
+	}
+	os.Unsetenv("STORAGE_PURGE_INTERVAL") // nolint: errcheck
+}
@@ -1,20 +1,32 @@
 package memory
 
 import (
+	"context"
 	"fmt"
+	"sort"
 	"sync"
+	"time"
 
 	"github.com/Azure/open-service-broker-azure/pkg/service"
 	"github.com/Azure/open-service-broker-azure/pkg/storage"
 )
 
 type store struct {
-	catalog                       service.Catalog
-	instances                     map[string][]byte
-	instanceAliases               map[string]string
-	bindings                      map[string][]byte
-	instanceAliasChildCounts      map[string]int64
-	instanceAliasChildCountsMutex sync.Mutex
+	catalog                  service.Catalog
+	instances                map[string][]byte
+	instanceAliases          map[string]string
+	bindings                 map[string][]byte
+	instanceAliasChildCounts map[string]int64
+	deletedInstances         map[string]tombstone
+	deletedBindings          map[string]tombstone
+	// mutex guards all of the maps above
+	mutex sync.RWMutex
+}
+
+// tombstone is a deleted record awaiting purge
+type tombstone struct {
+	json    []byte
+	deleted time.Time
 }
 
 // NewStore returns a new memory-based implementation of the storage.Store used
@@ -26,6 +38,8 @@ func NewStore(catalog service.Catalog) storage.Store {
 		instanceAliases:          make(map[string]string),
 		bindings:                 make(map[string][]byte),
 		instanceAliasChildCounts: make(map[string]int64),
+		deletedInstances:         make(map[string]tombstone),
+		deletedBindings:          make(map[string]tombstone),
 	}
 }
 
@@ -34,13 +48,13 @@ func (s *store) WriteInstance(instance service.Instance) error {
 	if err != nil {
 		return err
 	}
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
 	s.instances[instance.InstanceID] = json
 	if instance.Alias != "" {
 		s.instanceAliases[instance.Alias] = instance.InstanceID
 	}
 	if instance.ParentAlias != "" {
-		s.instanceAliasChildCountsMutex.Lock()
-		defer s.instanceAliasChildCountsMutex.Unlock()
 		s.instanceAliasChildCounts[instance.ParentAlias]++
 	}
 	return nil
@@ -51,10 +65,20 @@ func (s *store) GetInstance(instanceID string) (
 	bool,
 	error,
 ) {
+	s.mutex.RLock()
 	json, ok := s.instances[instanceID]
+	s.mutex.RUnlock()
 	if !ok {
 		return service.Instance{}, false, nil
 	}
+	return s.getInstanceFromJSON(json)
+}
+
+func (s *store) getInstanceFromJSON(json []byte) (
+	service.Instance,
+	bool,
+	error,
+) {
 	instance, err := service.NewInstanceFromJSON(json, nil, nil)
 	if err != nil {
 		return instance, false, err
@@ -89,12 +113,32 @@ func (s *store) GetInstance(instanceID string) (
 	return instance, err == nil, err
 }
 
+func (s *store) GetInstances() ([]service.Instance, error) {
+	s.mutex.RLock()
+	jsons := make([][]byte, 0, len(s.instances))
+	for _, json := range s.instances {
+		jsons = append(jsons, json)
+	}
+	s.mutex.RUnlock()
+	instances := make([]service.Instance, len(jsons))
+	for i, json := range jsons {
+		instance, _, err := s.getInstanceFromJSON(json)
+		if err != nil {
+			return nil, err
+		}
+		instances[i] = instance
+	}
+	return instances, nil
+}
+
 func (s *store) GetInstanceByAlias(alias string) (
 	service.Instance,
 	bool,
 	error,
 ) {
+	s.mutex.RLock()
 	instanceID, ok := s.instanceAliases[alias]
+	s.mutex.RUnlock()
 	if !ok {
 		return service.Instance{}, false, nil
 	}
@@ -109,21 +153,56 @@ func (s *store) DeleteInstance(instanceID string) (bool, error) {
 	if !ok {
 		return false, nil
 	}
+	deleted := time.Now()
+	instance.Deleted = &deleted
+	json, err := instance.ToJSON()
+	if err != nil {
+		return false, err
+	}
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if _, ok := s.instances[instanceID]; !ok {
+		// Another caller deleted the instance in the meantime
+		return false, nil
+	}
 	delete(s.instances, instanceID)
+	s.deletedInstances[instanceID] = tombstone{
+		json:    json,
+		deleted: deleted,
+	}
 	if instance.Alias != "" {
 		delete(s.instanceAliases, instance.Alias)
 	}
 	if instance.ParentAlias != "" {
-		s.instanceAliasChildCountsMutex.Lock()
-		defer s.instanceAliasChildCountsMutex.Unlock()
 		s.instanceAliasChildCounts[instance.ParentAlias]--
 	}
 	return true, nil
 }
 
+func (s *store) GetDeletedInstances() ([]service.Instance, error) {
+	s.mutex.RLock()
+	tombstones := make([]tombstone, 0, len(s.deletedInstances))
+	for _, t := range s.deletedInstances {
+		tombstones = append(tombstones, t)
+	}
+	s.mutex.RUnlock()
+	sort.Slice(tombstones, func(i, j int) bool {
+		return tombstones[i].deleted.After(tombstones[j].deleted)
+	})
+	instances := make([]service.Instance, len(tombstones))
+	for i, t := range tombstones {
+		instance, _, err := s.getInstanceFromJSON(t.json)
+		if err != nil {
+			return nil, err
+		}
+		instances[i] = instance
+	}
+	return instances, nil
+}
+
 func (s *store) GetInstanceChildCountByAlias(alias string) (int64, error) {
-	s.instanceAliasChildCountsMutex.Lock()
-	defer s.instanceAliasChildCountsMutex.Unlock()
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
 	return s.instanceAliasChildCounts[alias], nil
 }
 
@@ -132,15 +211,45 @@ func (s *store) WriteBinding(binding service.Binding) error {
 	if err != nil {
 		return err
 	}
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
 	s.bindings[binding.BindingID] = json
 	return nil
 }
 
 func (s *store) GetBinding(bindingID string) (service.Binding, bool, error) {
+	s.mutex.RLock()
 	json, ok := s.bindings[bindingID]
+	s.mutex.RUnlock()
 	if !ok {
 		return service.Binding{}, false, nil
 	}
+	return s.getBindingFromJSON(json)
+}
+
+func (s *store) GetBindings() ([]service.Binding, error) {
+	s.mutex.RLock()
+	jsons := make([][]byte, 0, len(s.bindings))
+	for _, json := range s.bindings {
+		jsons = append(jsons, json)
+	}
+	s.mutex.RUnlock()
+	bindings := make([]service.Binding, len(jsons))
+	for i, json := range jsons {
+		binding, _, err := s.getBindingFromJSON(json)
+		if err != nil {
+			return nil, err
+		}
+		bindings[i] = binding
+	}
+	return bindings, nil
+}
+
+func (s *store) getBindingFromJSON(json []byte) (
+	service.Binding,
+	bool,
+	error,
+) {
 	binding, err := service.NewBindingFromJSON(json, nil, nil)
 	if err != nil {
 		return binding, false, err
@@ -163,14 +272,51 @@ func (s *store) GetBinding(bindingID string) (service.Binding, bool, error) {
 }
 
 func (s *store) DeleteBinding(bindingID string) (bool, error) {
-	_, ok := s.bindings[bindingID]
+	binding, ok, err := s.GetBinding(bindingID)
+	if err != nil {
+		return false, err
+	}
 	if !ok {
 		return false, nil
 	}
+	deleted := time.Now()
+	binding.Deleted = &deleted
+	json, err := binding.ToJSON()
+	if err != nil {
+		return false, err
+	}
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if _, ok := s.bindings[bindingID]; !ok {
+		// Another caller deleted the binding in the meantime
+		return false, nil
+	}
 	delete(s.bindings, bindingID)
+	s.deletedBindings[bindingID] = tombstone{
+		json:    json,
+		deleted: deleted,
+	}
 	return true, nil
 }
 
-func (s *store) TestConnection() error {
+func (s *store) PurgeDeleted(deletedBefore time.Time) (int64, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	var count int64
+	for _, tombstones := range []map[string]tombstone{
+		s.deletedInstances,
+		s.deletedBindings,
+	} {
+		for id, t := range tombstones {
+			if t.deleted.Before(deletedBefore) {
+				delete(tombstones, id)
+				count++
+			}
+		}
+	}
+	return count, nil
+}
+
+func (s *store) TestConnection(context.Context) error {
 	return nil
 }
@@ -0,0 +1,63 @@
+package storage
+
+import (
+	"context"
+	"time"
+
+	log "github.com/Sirupsen/logrus"
+)
+
+// Purger is an interface to be implemented by components that periodically
+// purge deleted instances and bindings from a Store once they have exceeded
+// their retention period
+type Purger interface {
+	// Run causes the purger to purge deleted records on an interval. It blocks
+	// until the context passed to it has been canceled. Run always returns a
+	// non-nil error.
+	Run(context.Context) error
+}
+
+type purger struct {
+	store     Store
+	retention time.Duration
+	interval  time.Duration
+}
+
+// NewPurger returns a new Purger for the given Store
+func NewPurger(store Store, config Config) Purger {
+	return &purger{
+		store:     store,
+		retention: config.DeletedRetention,
+		interval:  config.PurgeInterval,
+	}
+}
+
+func (p *purger) Run(ctx context.Context) error {
+	ticker := time.NewTicker(p.interval)
+	defer ticker.Stop()
+	for {
+		p.purge()
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			log.Debug("context canceled; purger shutting down")
+			return ctx.Err()
+		}
+	}
+}
+
+func (p *purger) purge() {
+	deletedBefore := time.Now().Add(-p.retention)
+	count, err := p.store.PurgeDeleted(deletedBefore)
+	if err != nil {
+		// Log the error, but don't return it. We'll try again on the next tick.
+		log.WithField("error", err).Error("error purging deleted records")
+		return
+	}
+	if count > 0 {
+		log.WithFields(log.Fields{
+			"count":         count,
+			"deletedBefore": deletedBefore,
+		}).Info("purged deleted records")
+	}
+}
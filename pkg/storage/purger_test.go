@@ -0,0 +1,64 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeStore is a Store whose PurgeDeleted function reports the calls it
+// receives on a channel, dropping any that the channel has no room for.
+// Calling any other function causes a panic.
+type fakeStore struct {
+	Store
+	purgeCalls chan time.Time
+	err        error
+}
+
+func (f *fakeStore) PurgeDeleted(deletedBefore time.Time) (int64, error) {
+	select {
+	case f.purgeCalls <- deletedBefore:
+	default:
+	}
+	return 1, f.err
+}
+
+func TestPurgerPurgesOnInterval(t *testing.T) {
+	store := &fakeStore{
+		purgeCalls: make(chan time.Time, 1),
+		// Errors are logged, but shouldn't stop the purger
+		err: errors.New("purge failed"),
+	}
+	config := NewConfigWithDefaults()
+	config.DeletedRetention = time.Hour
+	config.PurgeInterval = time.Millisecond
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	errCh := make(chan error)
+	go func() {
+		errCh <- NewPurger(store, config).Run(ctx)
+	}()
+	for i := 0; i < 3; i++ {
+		select {
+		case deletedBefore := <-store.purgeCalls:
+			assert.WithinDuration(
+				t,
+				time.Now().Add(-time.Hour),
+				deletedBefore,
+				time.Minute,
+			)
+		case <-time.After(5 * time.Second):
+			t.Fatal("timed out waiting for purger to purge deleted records")
+		}
+	}
+	cancel()
+	select {
+	case err := <-errCh:
+		assert.Equal(t, context.Canceled, err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for purger to shut down")
+	}
+}
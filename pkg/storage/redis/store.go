@@ -1,21 +1,30 @@
 package redis
 
 import (
-	"crypto/tls"
+	"context"
 	"fmt"
+	"strconv"
+	"time"
 
+	osbaRedis "github.com/Azure/open-service-broker-azure/pkg/redis"
 	"github.com/Azure/open-service-broker-azure/pkg/service"
 	"github.com/Azure/open-service-broker-azure/pkg/storage"
 	"github.com/go-redis/redis"
 )
 
+// defaultClusterKeyTag is the hash tag applied to all keys when using Redis
+// Cluster and no prefix has been configured
+const defaultClusterKeyTag = "osba"
+
 type store struct {
-	redisClient *redis.Client
+	redisClient redis.UniversalClient
 	catalog     service.Catalog
 
-	prefix       string
-	instanceList string
-	bindingList  string
+	prefix              string
+	instanceList        string
+	bindingList         string
+	deletedInstanceList string
+	deletedBindingList  string
 }
 
 // NewStore returns a new Redis-based implementation of the Store interface
@@ -23,23 +32,21 @@ func NewStore(
 	catalog service.Catalog,
 	config Config,
 ) (storage.Store, error) {
-	redisOpts := &redis.Options{
-		Addr:       fmt.Sprintf("%s:%d", config.RedisHost, config.RedisPort),
-		Password:   config.RedisPassword,
-		DB:         config.RedisDB,
-		MaxRetries: 5,
-	}
-	if config.RedisEnableTLS {
-		redisOpts.TLSConfig = &tls.Config{
-			ServerName: config.RedisHost,
-		}
+	redisClient, prefix, err := osbaRedis.NewClient(
+		config.Config,
+		defaultClusterKeyTag,
+	)
+	if err != nil {
+		return nil, err
 	}
 	return &store{
-		redisClient:  redis.NewClient(redisOpts),
-		catalog:      catalog,
-		prefix:       config.RedisPrefix,
-		instanceList: wrapKey(config.RedisPrefix, "instances"),
-		bindingList:  wrapKey(config.RedisPrefix, "bindings"),
+		redisClient:         redisClient,
+		catalog:             catalog,
+		prefix:              prefix,
+		instanceList:        wrapKey(prefix, "instances"),
+		bindingList:         wrapKey(prefix, "bindings"),
+		deletedInstanceList: wrapKey(prefix, "deleted:instances"),
+		deletedBindingList:  wrapKey(prefix, "deleted:bindings"),
 	}, nil
 }
 
@@ -72,7 +79,10 @@ func (s *store) WriteInstance(instance service.Instance) error {
 }
 
 func (s *store) GetInstance(instanceID string) (service.Instance, bool, error) {
-	key := s.getInstanceKey(instanceID)
+	return s.getInstance(s.getInstanceKey(instanceID))
+}
+
+func (s *store) getInstance(key string) (service.Instance, bool, error) {
 	strCmd := s.redisClient.Get(key)
 	if err := strCmd.Err(); err == redis.Nil {
 		return service.Instance{}, false, nil
@@ -130,6 +140,24 @@ func (s *store) GetInstance(instanceID string) (service.Instance, bool, error) {
 	return instance, err == nil, err
 }
 
+func (s *store) GetInstances() ([]service.Instance, error) {
+	keys, err := s.redisClient.SMembers(s.instanceList).Result()
+	if err != nil {
+		return nil, err
+	}
+	instances := []service.Instance{}
+	for _, key := range keys {
+		instance, ok, err := s.getInstance(key)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			instances = append(instances, instance)
+		}
+	}
+	return instances, nil
+}
+
 func (s *store) GetInstanceByAlias(
 	alias string,
 ) (service.Instance, bool, error) {
@@ -155,9 +183,25 @@ func (s *store) DeleteInstance(instanceID string) (bool, error) {
 	if !ok {
 		return false, nil
 	}
+	deleted := time.Now()
+	instance.Deleted = &deleted
+	json, err := instance.ToJSON()
+	if err != nil {
+		return false, err
+	}
 	key := s.getInstanceKey(instanceID)
 	pipeline := s.redisClient.TxPipeline()
 	pipeline.Del(key)
+	// Retain a copy of the deleted instance, indexed by time of deletion, until
+	// it is purged
+	pipeline.Set(s.getDeletedInstanceKey(instanceID), json, 0)
+	pipeline.ZAdd(
+		s.deletedInstanceList,
+		redis.Z{
+			Score:  float64(deleted.Unix()),
+			Member: instanceID,
+		},
+	)
 
 	if instance.Alias != "" {
 		aliasKey := s.getInstanceAliasKey(instance.Alias)
@@ -179,6 +223,28 @@ func (s *store) DeleteInstance(instanceID string) (bool, error) {
 	return true, nil
 }
 
+func (s *store) GetDeletedInstances() ([]service.Instance, error) {
+	instanceIDs, err := s.redisClient.ZRevRange(
+		s.deletedInstanceList,
+		0,
+		-1,
+	).Result()
+	if err != nil {
+		return nil, err
+	}
+	instances := []service.Instance{}
+	for _, instanceID := range instanceIDs {
+		instance, ok, err := s.getInstance(s.getDeletedInstanceKey(instanceID))
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			instances = append(instances, instance)
+		}
+	}
+	return instances, nil
+}
+
 func (s *store) GetInstanceChildCountByAlias(alias string) (int64, error) {
 	aliasChildrenKey := s.getInstanceAliasChildrenKey(alias)
 	return s.redisClient.SCard(aliasChildrenKey).Result()
@@ -188,6 +254,10 @@ func (s *store) getInstanceKey(instanceID string) string {
 	return wrapKey(s.prefix, fmt.Sprintf("instances:%s", instanceID))
 }
 
+func (s *store) getDeletedInstanceKey(instanceID string) string {
+	return wrapKey(s.prefix, fmt.Sprintf("deleted:instances:%s", instanceID))
+}
+
 func (s *store) getInstanceAliasKey(alias string) string {
 	return wrapKey(s.prefix, fmt.Sprintf("instances:aliases:%s", alias))
 }
@@ -217,7 +287,28 @@ func (s *store) WriteBinding(binding service.Binding) error {
 }
 
 func (s *store) GetBinding(bindingID string) (service.Binding, bool, error) {
-	key := s.getBindingKey(bindingID)
+	return s.getBinding(s.getBindingKey(bindingID))
+}
+
+func (s *store) GetBindings() ([]service.Binding, error) {
+	keys, err := s.redisClient.SMembers(s.bindingList).Result()
+	if err != nil {
+		return nil, err
+	}
+	bindings := []service.Binding{}
+	for _, key := range keys {
+		binding, ok, err := s.getBinding(key)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			bindings = append(bindings, binding)
+		}
+	}
+	return bindings, nil
+}
+
+func (s *store) getBinding(key string) (service.Binding, bool, error) {
 	strCmd := s.redisClient.Get(key)
 	if err := strCmd.Err(); err == redis.Nil {
 		return service.Binding{}, false, nil
@@ -250,18 +341,34 @@ func (s *store) GetBinding(bindingID string) (service.Binding, bool, error) {
 }
 
 func (s *store) DeleteBinding(bindingID string) (bool, error) {
-	key := s.getBindingKey(bindingID)
-	strCmd := s.redisClient.Get(key)
-	if err := strCmd.Err(); err == redis.Nil {
+	binding, ok, err := s.GetBinding(bindingID)
+	if err != nil {
+		return false, err
+	}
+	if !ok {
 		return false, nil
-	} else if err != nil {
+	}
+	deleted := time.Now()
+	binding.Deleted = &deleted
+	json, err := binding.ToJSON()
+	if err != nil {
 		return false, err
 	}
-
+	key := s.getBindingKey(bindingID)
 	pipeline := s.redisClient.TxPipeline()
 	pipeline.Del(key)
 	pipeline.SRem(s.bindingList, key)
-	_, err := pipeline.Exec()
+	// Retain a copy of the deleted binding, indexed by time of deletion, until
+	// it is purged
+	pipeline.Set(s.getDeletedBindingKey(bindingID), json, 0)
+	pipeline.ZAdd(
+		s.deletedBindingList,
+		redis.Z{
+			Score:  float64(deleted.Unix()),
+			Member: bindingID,
+		},
+	)
+	_, err = pipeline.Exec()
 	if err != nil {
 		return false, fmt.Errorf(
 			`error deleting binding "%s": %s`,
@@ -276,8 +383,56 @@ func (s *store) getBindingKey(bindingID string) string {
 	return wrapKey(s.prefix, fmt.Sprintf("bindings:%s", bindingID))
 }
 
-func (s *store) TestConnection() error {
-	return s.redisClient.Ping().Err()
+func (s *store) getDeletedBindingKey(bindingID string) string {
+	return wrapKey(s.prefix, fmt.Sprintf("deleted:bindings:%s", bindingID))
+}
+
+func (s *store) PurgeDeleted(deletedBefore time.Time) (int64, error) {
+	var count int64
+	for _, deleted := range []struct {
+		list   string
+		getKey func(string) string
+	}{
+		{
+			list:   s.deletedInstanceList,
+			getKey: s.getDeletedInstanceKey,
+		},
+		{
+			list:   s.deletedBindingList,
+			getKey: s.getDeletedBindingKey,
+		},
+	} {
+		ids, err := s.redisClient.ZRangeByScore(
+			deleted.list,
+			redis.ZRangeBy{
+				Min: "-inf",
+				// The "(" prefix makes the upper bound exclusive
+				Max: "(" + strconv.FormatInt(deletedBefore.Unix(), 10),
+			},
+		).Result()
+		if err != nil {
+			return count, fmt.Errorf("error purging deleted records: %s", err)
+		}
+		if len(ids) == 0 {
+			continue
+		}
+		pipeline := s.redisClient.TxPipeline()
+		members := make([]interface{}, len(ids))
+		for i, id := range ids {
+			pipeline.Del(deleted.getKey(id))
+			members[i] = id
+		}
+		pipeline.ZRem(deleted.list, members...)
+		if _, err := pipeline.Exec(); err != nil {
+			return count, fmt.Errorf("error purging deleted records: %s", err)
+		}
+		count += int64(len(ids))
+	}
+	return count, nil
+}
+
+func (s *store) TestConnection(ctx context.Context) error {
+	return osbaRedis.Ping(ctx, s.redisClient)
 }
 
 func wrapKey(prefix, key string) string {
@@ -5,6 +5,7 @@ import (
 	"log"
 	"os"
 	"testing"
+	"time"
 
 	"github.com/Azure/open-service-broker-azure/pkg/service"
 	"github.com/Azure/open-service-broker-azure/pkg/services/fake"
@@ -32,6 +33,9 @@ func init() {
 	fakeServiceManager = fakeModule.ServiceManager
 	config = NewConfigWithDefaults()
 	config.RedisHost = os.Getenv("STORAGE_REDIS_HOST")
+	if config.RedisHost == "" {
+		config.RedisHost = "localhost"
+	}
 	config.RedisPrefix = uuid.NewV4().String()
 	str, err := NewStore(
 		fakeCatalog,
@@ -275,6 +279,36 @@ func TestDeleteExistingInstance(t *testing.T) {
 	assert.Nil(t, boolCmd.Err())
 	found, _ := boolCmd.Result()
 	assert.False(t, found)
+	// Assert that the deleted instance is retained
+	strCmd = testStore.redisClient.Get(
+		testStore.getDeletedInstanceKey(instance.InstanceID),
+	)
+	assert.Nil(t, strCmd.Err())
+	floatCmd := testStore.redisClient.ZScore(
+		testStore.deletedInstanceList,
+		instance.InstanceID,
+	)
+	assert.Nil(t, floatCmd.Err())
+}
+
+func TestPurgeDeleted(t *testing.T) {
+	instance := getTestInstance()
+	err := testStore.WriteInstance(instance)
+	assert.Nil(t, err)
+	_, err = testStore.DeleteInstance(instance.InstanceID)
+	assert.Nil(t, err)
+	deletedKey := testStore.getDeletedInstanceKey(instance.InstanceID)
+	// Nothing is purged if it was deleted after the cutoff
+	_, err = testStore.PurgeDeleted(time.Now().Add(-time.Hour))
+	assert.Nil(t, err)
+	strCmd := testStore.redisClient.Get(deletedKey)
+	assert.Nil(t, strCmd.Err())
+	// Everything is purged if it was deleted before the cutoff
+	count, err := testStore.PurgeDeleted(time.Now().Add(time.Hour))
+	assert.Nil(t, err)
+	assert.True(t, count >= 1)
+	strCmd = testStore.redisClient.Get(deletedKey)
+	assert.Equal(t, redis.Nil, strCmd.Err())
 }
 
 func TestDeleteExistingInstanceWithAlias(t *testing.T) {
@@ -459,6 +493,11 @@ func TestGetBindingKey(t *testing.T) {
 	assert.Equal(t, expected, testStore.getBindingKey(rawKey))
 }
 
+func TestWrapKeyWithClusterPrefix(t *testing.T) {
+	// All of the store's keys must share a hash tag
+	assert.Equal(t, "{foo}:instances:bar", wrapKey("{foo}", "instances:bar"))
+}
+
 func getTestInstance() service.Instance {
 	return service.Instance{
 		InstanceID:   uuid.NewV4().String(),
@@ -0,0 +1,49 @@
+package storage
+
+import (
+	"fmt"
+
+	log "github.com/Sirupsen/logrus"
+)
+
+// Reencrypt reads and then rewrites every instance and binding in the given
+// Store. Since all sensitive details are decrypted when read and encrypted
+// when written using the global codec, this has the effect of re-encrypting
+// everything using whatever key the global codec is currently configured to
+// encrypt with. This permits keys to be rotated. To avoid overwriting changes
+// made concurrently by a running broker, this should only be used while no
+// broker is running against the same Store.
+//
+// Deleted instances and bindings are not rewritten. Keys that were used to
+// encrypt them should be retained until they have been purged.
+func Reencrypt(store Store) error {
+	instances, err := store.GetInstances()
+	if err != nil {
+		return fmt.Errorf("error retrieving instances: %s", err)
+	}
+	for _, instance := range instances {
+		if err := store.WriteInstance(instance); err != nil {
+			return fmt.Errorf(
+				`error re-encrypting instance "%s": %s`,
+				instance.InstanceID,
+				err,
+			)
+		}
+	}
+	log.WithField("count", len(instances)).Info("re-encrypted instances")
+	bindings, err := store.GetBindings()
+	if err != nil {
+		return fmt.Errorf("error retrieving bindings: %s", err)
+	}
+	for _, binding := range bindings {
+		if err := store.WriteBinding(binding); err != nil {
+			return fmt.Errorf(
+				`error re-encrypting binding "%s": %s`,
+				binding.BindingID,
+				err,
+			)
+		}
+	}
+	log.WithField("count", len(bindings)).Info("re-encrypted bindings")
+	return nil
+}
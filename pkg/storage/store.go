@@ -1,6 +1,11 @@
 package storage
 
-import "github.com/Azure/open-service-broker-azure/pkg/service"
+import (
+	"context"
+	"time"
+
+	"github.com/Azure/open-service-broker-azure/pkg/service"
+)
 
 // Store is an interface to be implemented by types capable of handling
 // persistence for other broker-related types
@@ -10,23 +15,36 @@ type Store interface {
 	// GetInstance retrieves a persisted instance from the underlying storage by
 	// instance id
 	GetInstance(instanceID string) (service.Instance, bool, error)
+	// GetInstances retrieves all persisted instances from the underlying storage
+	GetInstances() ([]service.Instance, error)
 	// GetInstanceByID retrieves a persisted instance from the underlying storage
 	// by alias
 	GetInstanceByAlias(alias string) (service.Instance, bool, error)
 	// GetInstanceChildCountByAlias returns the number of child instances
 	GetInstanceChildCountByAlias(alias string) (int64, error)
 	// DeleteInstance deletes a persisted instance from the underlying storage by
-	// instance id
+	// instance id. Deleted instances are no longer visible to any of the other
+	// instance retrieval functions, but are retained until purged.
 	DeleteInstance(instanceID string) (bool, error)
+	// GetDeletedInstances retrieves all deleted instances that have not yet been
+	// purged from the underlying storage, most recently deleted first
+	GetDeletedInstances() ([]service.Instance, error)
 	// WriteBinding persists the given binding to the underlying storage
 	WriteBinding(binding service.Binding) error
 	// GetBinding retrieves a persisted instance from the underlying storage by
 	// binding id
 	GetBinding(bindingID string) (service.Binding, bool, error)
+	// GetBindings retrieves all persisted bindings from the underlying storage
+	GetBindings() ([]service.Binding, error)
 	// DeleteBinding deletes a persisted binding from the underlying storage by
-	// binding id
+	// binding id. Deleted bindings are no longer visible to GetBinding, but are
+	// retained until purged.
 	DeleteBinding(bindingID string) (bool, error)
+	// PurgeDeleted permanently removes all instances and bindings that were
+	// deleted before the given time from the underlying storage and returns the
+	// number of records removed
+	PurgeDeleted(deletedBefore time.Time) (int64, error)
 	// TestConnection tests the connection to the underlying database (if there
-	// is one)
-	TestConnection() error
+	// is one), respecting cancelation of the given context
+	TestConnection(ctx context.Context) error
 }
@@ -0,0 +1,8 @@
+package storage
+
+const (
+	// Redis represents Redis-based storage
+	Redis = "REDIS"
+	// Bolt represents storage in an embedded, file-based Bolt database
+	Bolt = "BOLT"
+)
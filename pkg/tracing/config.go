@@ -0,0 +1,55 @@
+package tracing
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/kelseyhightower/envconfig"
+)
+
+const envconfigPrefix = "TRACING"
+
+// Config represents configuration options for tracing
+type Config struct {
+	// Exporter is one of NONE, OTLP or STDOUT
+	Exporter string `envconfig:"EXPORTER" default:"NONE"`
+	// SampleRate is the fraction of traces, between 0 and 1, that are sampled
+	SampleRate float64 `envconfig:"SAMPLE_RATE"`
+	// ServiceName identifies the broker in exported traces
+	ServiceName string `envconfig:"SERVICE_NAME"`
+	// OTLPEndpoint is the URL to which the OTLP exporter posts spans
+	OTLPEndpoint string `envconfig:"OTLP_ENDPOINT"`
+}
+
+// NewConfigWithDefaults returns a Config object with default values already
+// applied. Callers are then free to set custom values for the remaining fields
+// and/or override default values.
+func NewConfigWithDefaults() Config {
+	return Config{
+		SampleRate:   1,
+		ServiceName:  "open-service-broker-azure",
+		OTLPEndpoint: "http://localhost:4318/v1/traces",
+	}
+}
+
+// GetConfigFromEnvironment returns configuration derived from environment
+// variables
+func GetConfigFromEnvironment() (Config, error) {
+	c := NewConfigWithDefaults()
+	err := envconfig.Process(envconfigPrefix, &c)
+	if err != nil {
+		return c, err
+	}
+	c.Exporter = strings.ToUpper(c.Exporter)
+	if c.Exporter == OTLP {
+		u, err := url.Parse(c.OTLPEndpoint)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+			return c, fmt.Errorf(
+				"environment variable %s_OTLP_ENDPOINT must be an http or https URL",
+				envconfigPrefix,
+			)
+		}
+	}
+	return c, nil
+}
@@ -0,0 +1,24 @@
+package tracing
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetConfigWithOTLPExporter(t *testing.T) {
+	err := os.Setenv("TRACING_EXPORTER", "otlp")
+	assert.Nil(t, err)
+	defer os.Unsetenv("TRACING_EXPORTER") // nolint: errcheck
+	c, err := GetConfigFromEnvironment()
+	assert.Nil(t, err)
+	assert.Equal(t, OTLP, c.Exporter)
+	assert.Equal(t, "http://localhost:4318/v1/traces", c.OTLPEndpoint)
+
+	err = os.Setenv("TRACING_OTLP_ENDPOINT", "localhost:4318")
+	assert.Nil(t, err)
+	defer os.Unsetenv("TRACING_OTLP_ENDPOINT") // nolint: errcheck
+	_, err = GetConfigFromEnvironment()
+	assert.NotNil(t, err)
+}
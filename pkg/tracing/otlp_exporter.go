@@ -0,0 +1,279 @@
+package tracing
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"time"
+
+	log "github.com/Sirupsen/logrus"
+	"go.opencensus.io/trace"
+)
+
+const (
+	otlpQueueSize     = 2048
+	otlpMaxBatchSize  = 512
+	otlpFlushInterval = 5 * time.Second
+	otlpScopeName     = "github.com/Azure/open-service-broker-azure"
+)
+
+// OTLP span kinds and status codes. These differ from their OpenCensus
+// counterparts.
+const (
+	otlpSpanKindInternal = 1
+	otlpSpanKindServer   = 2
+	otlpSpanKindClient   = 3
+	otlpStatusCodeError  = 2
+)
+
+// otlpExporter is an Exporter that sends completed spans, in batches, to an
+// OpenTelemetry collector or any other receiver that implements the OTLP/HTTP
+// protocol using its JSON encoding. Spans are queued by ExportSpan and sent by
+// a single background goroutine so that tracing never blocks the code being
+// traced. If the queue is full, spans are dropped. This stands in for the
+// OpenTelemetry SDK's exporter, which can't be built with the version of Go
+// OSBA uses. See docs/tracing.md for its limitations.
+type otlpExporter struct {
+	endpoint    string
+	serviceName string
+	httpClient  *http.Client
+	spans       chan *trace.SpanData
+	stopCh      chan struct{}
+	doneCh      chan struct{}
+}
+
+func newOTLPExporter(endpoint string, serviceName string) *otlpExporter {
+	o := &otlpExporter{
+		endpoint:    endpoint,
+		serviceName: serviceName,
+		httpClient: &http.Client{
+			Timeout: 10 * time.Second,
+		},
+		spans:  make(chan *trace.SpanData, otlpQueueSize),
+		stopCh: make(chan struct{}),
+		doneCh: make(chan struct{}),
+	}
+	go o.run()
+	return o
+}
+
+func (o *otlpExporter) ExportSpan(sd *trace.SpanData) {
+	select {
+	case o.spans <- sd:
+	default:
+		log.WithField("span", sd.Name).Warn("OTLP span queue full; dropping span")
+	}
+}
+
+// Stop sends any queued spans and stops the background goroutine
+func (o *otlpExporter) Stop() {
+	close(o.stopCh)
+	<-o.doneCh
+}
+
+func (o *otlpExporter) run() {
+	defer close(o.doneCh)
+	ticker := time.NewTicker(otlpFlushInterval)
+	defer ticker.Stop()
+	batch := make([]*trace.SpanData, 0, otlpMaxBatchSize)
+	flush := func() {
+		if len(batch) > 0 {
+			if err := o.send(batch); err != nil {
+				log.WithFields(log.Fields{
+					"spans": len(batch),
+					"error": err,
+				}).Error("error exporting spans")
+			}
+			batch = batch[:0]
+		}
+	}
+	for {
+		select {
+		case sd := <-o.spans:
+			batch = append(batch, sd)
+			if len(batch) == otlpMaxBatchSize {
+				flush()
+			}
+		case <-ticker.C:
+			flush()
+		case <-o.stopCh:
+			for {
+				select {
+				case sd := <-o.spans:
+					batch = append(batch, sd)
+					if len(batch) == otlpMaxBatchSize {
+						flush()
+					}
+				default:
+					flush()
+					return
+				}
+			}
+		}
+	}
+}
+
+func (o *otlpExporter) send(batch []*trace.SpanData) error {
+	body, err := json.Marshal(o.newTracesRequest(batch))
+	if err != nil {
+		return fmt.Errorf("error marshaling spans: %s", err)
+	}
+	req, err := http.NewRequest(http.MethodPost, o.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := o.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close() // nolint: errcheck
+	// Drain the body so the connection can be reused
+	io.Copy(ioutil.Discard, resp.Body) // nolint: errcheck
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf(
+			"OTLP receiver at %s responded with status %d",
+			o.endpoint,
+			resp.StatusCode,
+		)
+	}
+	return nil
+}
+
+// The following types mirror the JSON encoding of the OTLP
+// ExportTraceServiceRequest message and the messages it contains
+
+type otlpTracesRequest struct {
+	ResourceSpans []otlpResourceSpans `json:"resourceSpans"`
+}
+
+type otlpResourceSpans struct {
+	Resource   otlpResource     `json:"resource"`
+	ScopeSpans []otlpScopeSpans `json:"scopeSpans"`
+}
+
+type otlpResource struct {
+	Attributes []otlpKeyValue `json:"attributes"`
+}
+
+type otlpScopeSpans struct {
+	Scope otlpScope  `json:"scope"`
+	Spans []otlpSpan `json:"spans"`
+}
+
+type otlpScope struct {
+	Name string `json:"name"`
+}
+
+type otlpSpan struct {
+	TraceID           string         `json:"traceId"`
+	SpanID            string         `json:"spanId"`
+	ParentSpanID      string         `json:"parentSpanId,omitempty"`
+	Name              string         `json:"name"`
+	Kind              int            `json:"kind"`
+	StartTimeUnixNano string         `json:"startTimeUnixNano"`
+	EndTimeUnixNano   string         `json:"endTimeUnixNano"`
+	Attributes        []otlpKeyValue `json:"attributes,omitempty"`
+	Status            otlpStatus     `json:"status"`
+}
+
+type otlpStatus struct {
+	Code    int    `json:"code,omitempty"`
+	Message string `json:"message,omitempty"`
+}
+
+type otlpKeyValue struct {
+	Key   string       `json:"key"`
+	Value otlpAnyValue `json:"value"`
+}
+
+// otlpAnyValue holds exactly one non-nil field. 64 bit integers are encoded as
+// strings, as the protobuf JSON mapping requires.
+type otlpAnyValue struct {
+	StringValue *string  `json:"stringValue,omitempty"`
+	BoolValue   *bool    `json:"boolValue,omitempty"`
+	IntValue    *string  `json:"intValue,omitempty"`
+	DoubleValue *float64 `json:"doubleValue,omitempty"`
+}
+
+func (o *otlpExporter) newTracesRequest(
+	batch []*trace.SpanData,
+) otlpTracesRequest {
+	spans := make([]otlpSpan, len(batch))
+	for i, sd := range batch {
+		spans[i] = newOTLPSpan(sd)
+	}
+	return otlpTracesRequest{
+		ResourceSpans: []otlpResourceSpans{
+			{
+				Resource: otlpResource{
+					Attributes: []otlpKeyValue{
+						newOTLPKeyValue("service.name", o.serviceName),
+					},
+				},
+				ScopeSpans: []otlpScopeSpans{
+					{
+						Scope: otlpScope{Name: otlpScopeName},
+						Spans: spans,
+					},
+				},
+			},
+		},
+	}
+}
+
+func newOTLPSpan(sd *trace.SpanData) otlpSpan {
+	span := otlpSpan{
+		TraceID:           sd.TraceID.String(),
+		SpanID:            sd.SpanID.String(),
+		Name:              sd.Name,
+		StartTimeUnixNano: strconv.FormatInt(sd.StartTime.UnixNano(), 10),
+		EndTimeUnixNano:   strconv.FormatInt(sd.EndTime.UnixNano(), 10),
+	}
+	if sd.ParentSpanID != (trace.SpanID{}) {
+		span.ParentSpanID = sd.ParentSpanID.String()
+	}
+	switch sd.SpanKind {
+	case trace.SpanKindServer:
+		span.Kind = otlpSpanKindServer
+	case trace.SpanKindClient:
+		span.Kind = otlpSpanKindClient
+	default:
+		span.Kind = otlpSpanKindInternal
+	}
+	// OpenCensus uses gRPC status codes, in which 0 means OK. OTLP has no
+	// equivalent of the other codes, so any of them is reported as an error.
+	if sd.Code != trace.StatusCodeOK {
+		span.Status = otlpStatus{
+			Code:    otlpStatusCodeError,
+			Message: sd.Message,
+		}
+	}
+	for key, value := range sd.Attributes {
+		span.Attributes = append(span.Attributes, newOTLPKeyValue(key, value))
+	}
+	return span
+}
+
+func newOTLPKeyValue(key string, value interface{}) otlpKeyValue {
+	kv := otlpKeyValue{Key: key}
+	switch v := value.(type) {
+	case string:
+		kv.Value.StringValue = &v
+	case bool:
+		kv.Value.BoolValue = &v
+	case int64:
+		s := strconv.FormatInt(v, 10)
+		kv.Value.IntValue = &s
+	case float64:
+		kv.Value.DoubleValue = &v
+	default:
+		s := fmt.Sprintf("%v", v)
+		kv.Value.StringValue = &s
+	}
+	return kv
+}
@@ -0,0 +1,190 @@
+package tracing
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.opencensus.io/trace"
+)
+
+func TestOTLPExporterSendsQueuedSpansOnStop(t *testing.T) {
+	requests := make(chan otlpTracesRequest, 1)
+	server := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, http.MethodPost, r.Method)
+			assert.Equal(t, "/v1/traces", r.URL.Path)
+			assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+			req := otlpTracesRequest{}
+			assert.Nil(t, json.NewDecoder(r.Body).Decode(&req))
+			requests <- req
+		}),
+	)
+	defer server.Close()
+	e := newOTLPExporter(server.URL+"/v1/traces", "test-service")
+	start := time.Now()
+	e.ExportSpan(
+		&trace.SpanData{
+			SpanContext: trace.SpanContext{
+				TraceID: trace.TraceID{1},
+				SpanID:  trace.SpanID{2},
+			},
+			ParentSpanID: trace.SpanID{3},
+			SpanKind:     trace.SpanKindClient,
+			Name:         "arm deploy",
+			StartTime:    start,
+			EndTime:      start.Add(time.Second),
+			Status: trace.Status{
+				Code:    trace.StatusCodeUnknown,
+				Message: "boom",
+			},
+			Attributes: map[string]interface{}{
+				"deployment": "foo",
+				"attempt":    int64(2),
+			},
+		},
+	)
+	e.Stop()
+	var req otlpTracesRequest
+	select {
+	case req = <-requests:
+	default:
+		t.Fatal("spans were not sent before Stop returned")
+	}
+	assert.Len(t, req.ResourceSpans, 1)
+	rs := req.ResourceSpans[0]
+	assert.Equal(t, "service.name", rs.Resource.Attributes[0].Key)
+	assert.Equal(t, "test-service", *rs.Resource.Attributes[0].Value.StringValue)
+	assert.Len(t, rs.ScopeSpans, 1)
+	assert.Len(t, rs.ScopeSpans[0].Spans, 1)
+	span := rs.ScopeSpans[0].Spans[0]
+	assert.Equal(t, trace.TraceID{1}.String(), span.TraceID)
+	assert.Equal(t, trace.SpanID{2}.String(), span.SpanID)
+	assert.Equal(t, trace.SpanID{3}.String(), span.ParentSpanID)
+	assert.Equal(t, "arm deploy", span.Name)
+	assert.Equal(t, otlpSpanKindClient, span.Kind)
+	assert.Equal(
+		t,
+		strconv.FormatInt(start.UnixNano(), 10),
+		span.StartTimeUnixNano,
+	)
+	assert.Equal(t, otlpStatusCodeError, span.Status.Code)
+	assert.Equal(t, "boom", span.Status.Message)
+	attrs := map[string]otlpAnyValue{}
+	for _, kv := range span.Attributes {
+		attrs[kv.Key] = kv.Value
+	}
+	assert.Equal(t, "foo", *attrs["deployment"].StringValue)
+	assert.Equal(t, "2", *attrs["attempt"].IntValue)
+}
+
+// TestOTLPExporterPayloadConformsToOTLPJSON verifies the exporter's payload
+// against the field names and encodings of the OTLP/HTTP JSON protocol, rather
+// than against the exporter's own types. In particular, trace and span IDs must
+// be lowercase hex, not base64 as in the generic protobuf JSON mapping, and
+// 64 bit integers, including *TimeUnixNano, must be strings.
+func TestOTLPExporterPayloadConformsToOTLPJSON(t *testing.T) {
+	bodies := make(chan []byte, 1)
+	server := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body, err := ioutil.ReadAll(r.Body)
+			assert.Nil(t, err)
+			bodies <- body
+		}),
+	)
+	defer server.Close()
+	e := newOTLPExporter(server.URL, "test-service")
+	start := time.Unix(1544712660, 300000000)
+	e.ExportSpan(
+		&trace.SpanData{
+			SpanContext: trace.SpanContext{
+				TraceID: trace.TraceID{
+					0x5b, 0x8e, 0xff, 0xf7, 0x98, 0x03, 0x81, 0x03,
+					0xd2, 0x69, 0xb6, 0x33, 0x81, 0x3f, 0xc6, 0x0c,
+				},
+				SpanID: trace.SpanID{
+					0xee, 0xe1, 0x9b, 0x7e, 0xc3, 0xc1, 0xb1, 0x74,
+				},
+			},
+			ParentSpanID: trace.SpanID{
+				0xee, 0xe1, 0x9b, 0x7e, 0xc3, 0xc1, 0xb1, 0x73,
+			},
+			SpanKind:  trace.SpanKindServer,
+			Name:      "GET /v2/catalog",
+			StartTime: start,
+			EndTime:   start.Add(1500 * time.Millisecond),
+			Status: trace.Status{
+				Code:    trace.StatusCodeUnknown,
+				Message: "boom",
+			},
+			Attributes: map[string]interface{}{
+				"http.status_code": int64(500),
+			},
+		},
+	)
+	e.Stop()
+	var body []byte
+	select {
+	case body = <-bodies:
+	default:
+		t.Fatal("spans were not sent before Stop returned")
+	}
+	assert.JSONEq(
+		t,
+		`{
+			"resourceSpans": [
+				{
+					"resource": {
+						"attributes": [
+							{
+								"key": "service.name",
+								"value": {"stringValue": "test-service"}
+							}
+						]
+					},
+					"scopeSpans": [
+						{
+							"scope": {
+								"name": "github.com/Azure/open-service-broker-azure"
+							},
+							"spans": [
+								{
+									"traceId": "5b8efff798038103d269b633813fc60c",
+									"spanId": "eee19b7ec3c1b174",
+									"parentSpanId": "eee19b7ec3c1b173",
+									"name": "GET /v2/catalog",
+									"kind": 2,
+									"startTimeUnixNano": "1544712660300000000",
+									"endTimeUnixNano": "1544712661800000000",
+									"attributes": [
+										{
+											"key": "http.status_code",
+											"value": {"intValue": "500"}
+										}
+									],
+									"status": {"code": 2, "message": "boom"}
+								}
+							]
+						}
+					]
+				}
+			]
+		}`,
+		string(body),
+	)
+}
+
+func TestOTLPExporterDropsSpansWhenQueueIsFull(t *testing.T) {
+	e := &otlpExporter{
+		spans: make(chan *trace.SpanData, 1),
+	}
+	e.ExportSpan(&trace.SpanData{Name: "first"})
+	// This must not block
+	e.ExportSpan(&trace.SpanData{Name: "second"})
+	assert.Equal(t, "first", (<-e.spans).Name)
+}
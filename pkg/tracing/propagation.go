@@ -0,0 +1,40 @@
+package tracing
+
+import (
+	"context"
+	"encoding/base64"
+
+	"go.opencensus.io/trace"
+	"go.opencensus.io/trace/propagation"
+)
+
+// taskArgKey is the key under which a span context is stored in the args of
+// an async task
+const taskArgKey = "traceContext"
+
+// InjectIntoTaskArgs stores the context's span context, if any, in the given
+// async task args so that work done while executing the task can be traced as
+// part of the same trace
+func InjectIntoTaskArgs(ctx context.Context, args map[string]string) {
+	span := trace.FromContext(ctx)
+	if span == nil {
+		return
+	}
+	args[taskArgKey] = base64.StdEncoding.EncodeToString(
+		propagation.Binary(span.SpanContext()),
+	)
+}
+
+// ExtractFromTaskArgs returns the span context stored in the given async task
+// args, if any
+func ExtractFromTaskArgs(args map[string]string) (trace.SpanContext, bool) {
+	encoded, ok := args[taskArgKey]
+	if !ok {
+		return trace.SpanContext{}, false
+	}
+	b, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return trace.SpanContext{}, false
+	}
+	return propagation.FromBinary(b)
+}
@@ -0,0 +1,36 @@
+package tracing
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.opencensus.io/trace"
+)
+
+func TestInjectIntoTaskArgsWithoutSpan(t *testing.T) {
+	args := map[string]string{}
+	InjectIntoTaskArgs(context.Background(), args)
+	assert.Empty(t, args)
+	_, ok := ExtractFromTaskArgs(args)
+	assert.False(t, ok)
+}
+
+func TestTaskArgsRoundTrip(t *testing.T) {
+	ctx, span := trace.StartSpan(
+		context.Background(),
+		"test",
+		trace.WithSampler(trace.AlwaysSample()),
+	)
+	defer span.End()
+	args := map[string]string{}
+	InjectIntoTaskArgs(ctx, args)
+	sc, ok := ExtractFromTaskArgs(args)
+	assert.True(t, ok)
+	assert.Equal(t, span.SpanContext(), sc)
+}
+
+func TestExtractFromTaskArgsWithInvalidValue(t *testing.T) {
+	_, ok := ExtractFromTaskArgs(map[string]string{taskArgKey: "not base64!"})
+	assert.False(t, ok)
+}
@@ -0,0 +1,78 @@
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"go.opencensus.io/trace"
+)
+
+// Exporter is an interface to be implemented by components that export
+// completed spans and must be flushed before the process exits
+type Exporter interface {
+	trace.Exporter
+	// Stop flushes any buffered spans and releases the exporter's resources
+	Stop()
+}
+
+// Initialize configures the global tracer to sample traces at the configured
+// rate and to export them using the configured exporter. The returned
+// Exporter should be stopped before the process exits. If no exporter is
+// configured, a nil Exporter is returned and no traces are sampled.
+func Initialize(config Config) (Exporter, error) {
+	var exporter Exporter
+	switch config.Exporter {
+	case NONE:
+		trace.ApplyConfig(trace.Config{DefaultSampler: trace.NeverSample()})
+		return nil, nil
+	case OTLP:
+		exporter = newOTLPExporter(config.OTLPEndpoint, config.ServiceName)
+	case STDOUT:
+		exporter = newWriterExporter(os.Stdout)
+	default:
+		return nil, fmt.Errorf(`unrecognized trace exporter "%s"`, config.Exporter)
+	}
+	trace.ApplyConfig(
+		trace.Config{
+			DefaultSampler: trace.ProbabilitySampler(config.SampleRate),
+		},
+	)
+	trace.RegisterExporter(exporter)
+	return exporter, nil
+}
+
+// EndSpan records the outcome of the work represented by the given span and
+// ends it
+func EndSpan(span *trace.Span, err error) {
+	if err != nil {
+		span.SetStatus(
+			trace.Status{
+				Code:    trace.StatusCodeUnknown,
+				Message: err.Error(),
+			},
+		)
+	}
+	span.End()
+}
+
+// StartSQLSpan starts a child span of the span carried by ctx, if any, to
+// represent SQL work of the given kind performed against the named database on
+// the given server
+func StartSQLSpan(
+	ctx context.Context,
+	operation string,
+	server string,
+	database string,
+) (context.Context, *trace.Span) {
+	ctx, span := trace.StartSpan(
+		ctx,
+		"sql "+operation,
+		trace.WithSpanKind(trace.SpanKindClient),
+	)
+	span.AddAttributes(
+		trace.StringAttribute("server", server),
+		trace.StringAttribute("database", database),
+	)
+	return ctx, span
+}
@@ -0,0 +1,11 @@
+package tracing
+
+const (
+	// NONE represents no trace exporter
+	NONE = "NONE"
+	// OTLP represents exporting traces to an OpenTelemetry collector, or any
+	// other receiver, using the OTLP/HTTP protocol
+	OTLP = "OTLP"
+	// STDOUT represents writing traces to stdout for local debugging
+	STDOUT = "STDOUT"
+)
@@ -0,0 +1,59 @@
+package tracing
+
+import (
+	"encoding/json"
+	"io"
+	"sync"
+	"time"
+
+	log "github.com/Sirupsen/logrus"
+	"go.opencensus.io/trace"
+)
+
+// writerExporter is an Exporter that writes each completed span to an
+// io.Writer as a single line of JSON. It is intended for local debugging.
+type writerExporter struct {
+	mutex   sync.Mutex
+	encoder *json.Encoder
+}
+
+type writtenSpan struct {
+	TraceID      string                 `json:"traceId"`
+	SpanID       string                 `json:"spanId"`
+	ParentSpanID string                 `json:"parentSpanId,omitempty"`
+	Name         string                 `json:"name"`
+	Start        time.Time              `json:"start"`
+	Duration     string                 `json:"duration"`
+	StatusCode   int32                  `json:"statusCode"`
+	Status       string                 `json:"status,omitempty"`
+	Attributes   map[string]interface{} `json:"attributes,omitempty"`
+}
+
+func newWriterExporter(w io.Writer) *writerExporter {
+	return &writerExporter{
+		encoder: json.NewEncoder(w),
+	}
+}
+
+func (w *writerExporter) ExportSpan(sd *trace.SpanData) {
+	span := writtenSpan{
+		TraceID:    sd.TraceID.String(),
+		SpanID:     sd.SpanID.String(),
+		Name:       sd.Name,
+		Start:      sd.StartTime,
+		Duration:   sd.EndTime.Sub(sd.StartTime).String(),
+		StatusCode: sd.Code,
+		Status:     sd.Message,
+		Attributes: sd.Attributes,
+	}
+	if sd.ParentSpanID != (trace.SpanID{}) {
+		span.ParentSpanID = sd.ParentSpanID.String()
+	}
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	if err := w.encoder.Encode(span); err != nil {
+		log.WithField("error", err).Error("error writing span")
+	}
+}
+
+func (w *writerExporter) Stop() {}
@@ -0,0 +1,63 @@
+package tracing
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.opencensus.io/trace"
+)
+
+func TestWriterExporterExportSpan(t *testing.T) {
+	buf := &bytes.Buffer{}
+	e := newWriterExporter(buf)
+	start := time.Now()
+	e.ExportSpan(
+		&trace.SpanData{
+			SpanContext: trace.SpanContext{
+				TraceID: trace.TraceID{1},
+				SpanID:  trace.SpanID{2},
+			},
+			ParentSpanID: trace.SpanID{3},
+			Name:         "arm deploy",
+			StartTime:    start,
+			EndTime:      start.Add(time.Second),
+			Status: trace.Status{
+				Code:    trace.StatusCodeUnknown,
+				Message: "boom",
+			},
+			Attributes: map[string]interface{}{"deployment": "foo"},
+		},
+	)
+	span := writtenSpan{}
+	assert.Nil(t, json.Unmarshal(buf.Bytes(), &span))
+	assert.Equal(t, trace.TraceID{1}.String(), span.TraceID)
+	assert.Equal(t, trace.SpanID{2}.String(), span.SpanID)
+	assert.Equal(t, trace.SpanID{3}.String(), span.ParentSpanID)
+	assert.Equal(t, "arm deploy", span.Name)
+	assert.Equal(t, "1s", span.Duration)
+	assert.Equal(t, int32(trace.StatusCodeUnknown), span.StatusCode)
+	assert.Equal(t, "boom", span.Status)
+	assert.Equal(t, "foo", span.Attributes["deployment"])
+}
+
+func TestEndSpanRecordsError(t *testing.T) {
+	buf := &bytes.Buffer{}
+	e := newWriterExporter(buf)
+	trace.RegisterExporter(e)
+	defer trace.UnregisterExporter(e)
+	_, span := trace.StartSpan(
+		context.Background(),
+		"test",
+		trace.WithSampler(trace.AlwaysSample()),
+	)
+	EndSpan(span, errors.New("boom"))
+	s := writtenSpan{}
+	assert.Nil(t, json.Unmarshal(buf.Bytes(), &s))
+	assert.Equal(t, "test", s.Name)
+	assert.Equal(t, "boom", s.Status)
+}
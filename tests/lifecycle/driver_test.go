@@ -30,7 +30,9 @@ func TestServices(t *testing.T) {
 	catalogConfig.EnableMigrationServices = true
 	catalogConfig.EnableDRServices = true
 
-	catalog, err := boot.GetCatalog(catalogConfig, azureConfig)
+	modules, err := boot.GetModules(azureConfig)
+	assert.Nil(t, err)
+	catalog, err := boot.GetCatalog(catalogConfig, modules)
 	assert.Nil(t, err)
 
 	testCases, err := getTestCases()
@@ -234,7 +234,7 @@ func (s serviceLifecycleTestCase) execute(
 
 		// Bind
 		var bd service.BindingDetails
-		bd, err = serviceManager.Bind(instance, bp)
+		bd, err = serviceManager.Bind(ctx, instance, bp)
 		if err != nil {
 			return err
 		}
@@ -264,7 +264,7 @@ func (s serviceLifecycleTestCase) execute(
 		}
 
 		// Unbind
-		if err = serviceManager.Unbind(instance, binding); err != nil {
+		if err = serviceManager.Unbind(ctx, instance, binding); err != nil {
 			return err
 		}
 	}